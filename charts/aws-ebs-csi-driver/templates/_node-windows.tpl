@@ -96,6 +96,9 @@ spec:
             {{- if .Values.node.otelTracing }}
             - --enable-otel-tracing=true
             {{- end}}
+            {{- if .Values.node.otelMetrics }}
+            - --enable-otel-metrics=true
+            {{- end }}
             {{- if .Values.node.windowsHostProcess }}
             - --windows-host-process=true
             {{- end }}
@@ -113,7 +116,7 @@ spec:
             {{- if .Values.proxy.http_proxy }}
             {{- include "aws-ebs-csi-driver.http-proxy" . | nindent 12 }}
             {{- end }}
-            {{- with .Values.node.otelTracing }}
+            {{- with (.Values.node.otelTracing | default .Values.node.otelMetrics) }}
             - name: OTEL_SERVICE_NAME
               value: {{ .otelServiceName }}
             - name: OTEL_EXPORTER_OTLP_ENDPOINT
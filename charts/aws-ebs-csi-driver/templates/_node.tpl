@@ -56,6 +56,9 @@ spec:
         {{- include "aws-ebs-csi-driver.daemonset-tolerations" . | nindent 8 }}
         {{- end }}
       hostNetwork: {{ .Values.node.hostNetwork }}
+      {{- if .Values.node.hostPID }}
+      hostPID: true
+      {{- end }}
       {{- with .Values.node.securityContext }}
       securityContext:
         {{- toYaml . | nindent 8 }}
@@ -89,6 +92,26 @@ spec:
             {{- if .Values.node.legacyXFS }}
             - --legacy-xfs=true
             {{- end}}
+            {{- if .Values.node.nvmeMetricsKubernetesLabels }}
+            - --enable-nvme-metrics-kubernetes-labels=true
+            {{- end}}
+            {{- if .Values.node.reconcileMountsOnStartup }}
+            - --reconcile-mounts-on-startup=true
+            {{- end}}
+            {{- if .Values.node.lazyUnmountWithoutWriters }}
+            - --lazy-unmount-without-writers=true
+            {{- end}}
+            {{- with .Values.node.metadataCacheFile }}
+            - --metadata-cache-file={{ . }}
+            {{- end }}
+            {{- if .Values.node.asyncFormatMinSizeGiB }}
+            - --async-format-min-size-gib={{ .Values.node.asyncFormatMinSizeGiB }}
+            - --max-concurrent-formats={{ .Values.node.maxConcurrentFormats }}
+            {{- end}}
+            {{- if .Values.ephemeralVolumes.enabled }}
+            - --ephemeral-volumes-endpoint=https://ebs-csi-controller-ephemeral-volumes.{{ .Release.Namespace }}.svc:{{ .Values.ephemeralVolumes.port }}
+            - --ephemeral-volumes-ca-file=/var/run/secrets/ebs.csi.aws.com/ephemeral-volumes/ca.crt
+            {{- end }}
             {{- with .Values.node.loggingFormat }}
             - --logging-format={{ . }}
             {{- end }}
@@ -100,6 +123,9 @@ spec:
             {{- if .Values.node.otelTracing }}
             - --enable-otel-tracing=true
             {{- end}}
+            {{- if .Values.node.otelMetrics }}
+            - --enable-otel-metrics=true
+            {{- end }}
             {{- range .Values.node.additionalArgs }}
             - {{ . }}
             {{- end }}
@@ -113,7 +139,7 @@ spec:
             {{- if .Values.proxy.http_proxy }}
             {{- include "aws-ebs-csi-driver.http-proxy" . | nindent 12 }}
             {{- end }}
-            {{- with .Values.node.otelTracing }}
+            {{- with (.Values.node.otelTracing | default .Values.node.otelMetrics) }}
             - name: OTEL_SERVICE_NAME
               value: {{ .otelServiceName }}
             - name: OTEL_EXPORTER_OTLP_ENDPOINT
@@ -151,6 +177,15 @@ spec:
               mountPath: /etc/selinux/config
               readOnly: true
             {{- end }}
+            {{- if .Values.node.volumeIOLimits }}
+            - name: cgroup-dir
+              mountPath: /sys/fs/cgroup
+            {{- end }}
+            {{- if .Values.ephemeralVolumes.enabled }}
+            - name: ephemeral-volumes-token
+              mountPath: /var/run/secrets/ebs.csi.aws.com/ephemeral-volumes
+              readOnly: true
+            {{- end }}
           {{- with .Values.node.volumeMounts }}
           {{- toYaml . | nindent 12 }}
           {{- end }}
@@ -315,6 +350,26 @@ spec:
             path: /etc/selinux/config
             type: File
         {{- end }}
+        {{- if .Values.node.volumeIOLimits }}
+        - name: cgroup-dir
+          hostPath:
+            path: /sys/fs/cgroup
+            type: Directory
+        {{- end }}
+        {{- if .Values.ephemeralVolumes.enabled }}
+        - name: ephemeral-volumes-token
+          projected:
+            sources:
+              - serviceAccountToken:
+                  audience: ebs.csi.aws.com/ephemeral-volumes
+                  expirationSeconds: 3600
+                  path: token
+              - secret:
+                  name: {{ required "ephemeralVolumes.tlsSecret is required to enable ephemeralVolumes" .Values.ephemeralVolumes.tlsSecret }}
+                  items:
+                    - key: ca.crt
+                      path: ca.crt
+        {{- end }}
         - name: probe-dir
           {{- if .Values.node.probeDirVolume }}
           {{- toYaml .Values.node.probeDirVolume | nindent 10 }}
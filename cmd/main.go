@@ -20,10 +20,13 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/cmd/hooks"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/cmd/nvmestats"
 	cloudPkg "github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud"
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud/metadata"
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/driver"
@@ -33,6 +36,7 @@ import (
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
 	"github.com/prometheus/client_golang/prometheus"
 	flag "github.com/spf13/pflag"
+	otelmetric "go.opentelemetry.io/otel/sdk/metric"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/component-base/featuregate"
 	logsapi "k8s.io/component-base/logs/api/v1"
@@ -56,6 +60,8 @@ func main() {
 		args     = os.Args[1:]
 		cmd      = string(driver.AllMode)
 		options  = driver.Options{}
+
+		nvmeStatsOptions = nvmestats.Options{}
 	)
 
 	var (
@@ -81,6 +87,9 @@ func main() {
 
 	options.Mode = driver.Mode(cmd)
 	options.AddFlags(fs)
+	if cmd == nvmestats.Command {
+		nvmeStatsOptions.AddFlags(fs)
+	}
 
 	plugin := plugin.GetPlugin()
 	if plugin != nil {
@@ -116,6 +125,26 @@ func main() {
 		os.Exit(0)
 	}
 
+	// nvme-stats only reads the log page of a local device, so it runs
+	// before any metadata, cloud or Kubernetes client initialization
+	if cmd == nvmestats.Command {
+		if nvmeStatsOptions.Device == "" {
+			nvmeStatsOptions.Device = fs.Arg(0)
+		}
+		if err := nvmeStatsOptions.Validate(); err != nil {
+			klog.ErrorS(err, "Invalid nvme-stats options")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		err = nvmestats.Run(ctx, nvmeStatsOptions, os.Stdout)
+		stop()
+		if err != nil {
+			klog.ErrorS(err, "failed to collect NVMe statistics")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+		klog.FlushAndExit(klog.ExitFlushTimeout, 0)
+	}
+
 	// Start tracing as soon as possible
 	if options.EnableOtelTracing {
 		exporter, exporterErr := driver.InitOtelTracing()
@@ -136,10 +165,22 @@ func main() {
 	var r *metrics.MetricRecorder
 	var registry *prometheus.Registry
 	// Create registry object so it's ready to pass to the plugin
-	if options.HTTPEndpoint != "" {
+	if options.HTTPEndpoint != "" || options.EnableOtelMetrics {
 		r, registry = metrics.InitializeRecorder(options.DeprecatedMetrics)
+	}
+	if options.HTTPEndpoint != "" {
 		r.InitializeMetricsHandler(options.HTTPEndpoint, "/metrics", options.MetricsCertFile, options.MetricsKeyFile)
 	}
+	if options.EnableOtelMetrics {
+		meterProvider, meterProviderErr := driver.InitOtelMetrics(registry)
+		if meterProviderErr != nil {
+			klog.ErrorS(meterProviderErr, "failed to initialize otel metrics")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+		// Deferred functions do not run because main exits through klog.FlushAndExit,
+		// so the pending metrics are exported when the driver is asked to terminate
+		go shutdownOtelMetricsOnSignal(meterProvider)
+	}
 
 	var cloud cloudPkg.Cloud
 	var k8sClient kubernetes.Interface
@@ -147,7 +188,9 @@ func main() {
 	cfg := metadata.MetadataServiceConfig{
 		MetadataSources: options.MetadataSources,
 		IMDSClient:      metadata.DefaultIMDSClient,
+		EC2Client:       metadata.DefaultEC2Client,
 		K8sAPIClient:    metadata.DefaultKubernetesAPIClient(options.Kubeconfig),
+		CacheFile:       options.MetadataCacheFile,
 	}
 
 	if _, ok := metadataRequiredModes[cmd]; ok {
@@ -229,7 +272,7 @@ func main() {
 			klog.FlushAndExit(klog.ExitFlushTimeout, 0)
 		}
 	default:
-		klog.Errorf("Unknown driver mode %s: Expected %s, %s, %s, %s, or pre-stop-hook", cmd, driver.ControllerMode, driver.NodeMode, driver.AllMode, driver.MetadataLabelerMode)
+		klog.Errorf("Unknown driver mode %s: Expected %s, %s, %s, %s, pre-stop-hook, or %s", cmd, driver.ControllerMode, driver.NodeMode, driver.AllMode, driver.MetadataLabelerMode, nvmestats.Command)
 		klog.FlushAndExit(klog.ExitFlushTimeout, 0)
 	}
 
@@ -244,11 +287,24 @@ func main() {
 			r.InitializeAsyncEC2Metrics(60 * time.Second /* Don't emit metrics for detaches that take < 60s */)
 		}
 		if options.Mode == driver.NodeMode || options.Mode == driver.AllMode {
-			r.InitializeNVME(options.CsiMountPointPath, md.GetInstanceID())
+			var resolver metrics.VolumeResolver
+			if options.EnableNVMeMetricsKubernetesLabels {
+				if k8sClient == nil {
+					klog.ErrorS(nil, "Kubernetes client is required for --enable-nvme-metrics-kubernetes-labels")
+					klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+				}
+				kubernetesResolver, resolverErr := metrics.NewKubernetesVolumeResolver(context.Background(), k8sClient, util.GetDriverName())
+				if resolverErr != nil {
+					klog.ErrorS(resolverErr, "failed to initialize NVMe metrics volume resolver")
+					klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+				}
+				resolver = kubernetesResolver
+			}
+			r.InitializeNVME(options.CsiMountPointPath, md.GetInstanceID(), resolver, options.NVMeMetricsNativeHistograms)
 		}
 	}
 
-	m, err := mounter.NewNodeMounter(options.WindowsHostProcess)
+	m, err := mounter.NewNodeMounter(context.Background(), options.WindowsHostProcess, options.DeviceWatcherTimeout)
 	if err != nil {
 		klog.ErrorS(err, "failed to create node mounter")
 		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
@@ -264,3 +320,18 @@ func main() {
 		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
 	}
 }
+
+// shutdownOtelMetricsOnSignal waits for SIGINT or SIGTERM, shuts down meterProvider to export
+// the pending metrics and exits.
+func shutdownOtelMetricsOnSignal(meterProvider *otelmetric.MeterProvider) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	<-ctx.Done()
+	stop()
+	klog.InfoS("Received termination signal, exporting the pending otel metrics")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := meterProvider.Shutdown(shutdownCtx); err != nil {
+		klog.ErrorS(err, "could not shutdown otel meter provider")
+	}
+	cancel()
+	klog.FlushAndExit(klog.ExitFlushTimeout, 0)
+}
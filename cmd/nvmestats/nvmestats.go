@@ -0,0 +1,148 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package nvmestats implements the nvme-stats subcommand, which samples the EBS
+// NVMe log page of a single volume and prints its performance statistics,
+// similar to iostat.
+package nvmestats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	flag "github.com/spf13/pflag"
+)
+
+// Command is the name of the subcommand.
+const Command = "nvme-stats"
+
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+)
+
+// Options contains the options of the nvme-stats subcommand.
+type Options struct {
+	// Device is the NVMe device path (e.g. /dev/nvme1n1) or EBS volume ID to sample.
+	Device string
+	// Interval is the time between two samples.
+	Interval time.Duration
+	// Count is the number of samples to print. 0 samples until interrupted.
+	Count int
+	// Output is the output format, either table or json.
+	Output string
+}
+
+func (o *Options) AddFlags(f *flag.FlagSet) {
+	f.StringVar(&o.Device, "device", "", "NVMe device path (e.g. /dev/nvme1n1) or EBS volume ID (e.g. vol-0123456789abcdef0) to sample. May also be passed as the first positional argument.")
+	f.DurationVar(&o.Interval, "interval", time.Second, "Time between two samples.")
+	f.IntVar(&o.Count, "count", 0, "Number of samples to print. If 0, samples are printed until interrupted.")
+	f.StringVar(&o.Output, "output", OutputTable, "Output format. One of: table, json.")
+}
+
+func (o *Options) Validate() error {
+	if o.Device == "" {
+		return errors.New("a device path or volume ID must be provided")
+	}
+	if o.Interval <= 0 {
+		return errors.New("interval must be positive")
+	}
+	if o.Count < 0 {
+		return errors.New("count must not be negative")
+	}
+	if o.Output != OutputTable && o.Output != OutputJSON {
+		return fmt.Errorf("invalid output format %q: expected %s or %s", o.Output, OutputTable, OutputJSON)
+	}
+	return nil
+}
+
+// Sample holds the statistics of a volume over one sampling interval.
+type Sample struct {
+	Time     time.Time `json:"time"`
+	Device   string    `json:"device"`
+	VolumeID string    `json:"volumeID"`
+
+	ReadOpsPerSecond    float64 `json:"readOpsPerSecond"`
+	WriteOpsPerSecond   float64 `json:"writeOpsPerSecond"`
+	ReadBytesPerSecond  float64 `json:"readBytesPerSecond"`
+	WriteBytesPerSecond float64 `json:"writeBytesPerSecond"`
+	QueueLength         uint64  `json:"queueLength"`
+
+	ReadLatencyAverageSeconds  float64            `json:"readLatencyAverageSeconds"`
+	WriteLatencyAverageSeconds float64            `json:"writeLatencyAverageSeconds"`
+	ReadLatencyPercentiles     LatencyPercentiles `json:"readLatencyPercentiles"`
+	WriteLatencyPercentiles    LatencyPercentiles `json:"writeLatencyPercentiles"`
+
+	// Time, in seconds, during the interval that demand exceeded the volume's
+	// provisioned performance or the instance's maximum performance.
+	EBSIOPSExceededSeconds       float64 `json:"ebsIOPSExceededSeconds"`
+	EBSThroughputExceededSeconds float64 `json:"ebsThroughputExceededSeconds"`
+	EC2IOPSExceededSeconds       float64 `json:"ec2IOPSExceededSeconds"`
+	EC2ThroughputExceededSeconds float64 `json:"ec2ThroughputExceededSeconds"`
+}
+
+// LatencyPercentiles are latency percentiles, in seconds, estimated from the log page histograms.
+type LatencyPercentiles struct {
+	P50 float64 `json:"p50Seconds"`
+	P90 float64 `json:"p90Seconds"`
+	P99 float64 `json:"p99Seconds"`
+}
+
+const (
+	bytesInMebibyte      = 1 << 20
+	secondsInMillisecond = 1e-3
+)
+
+// sampleWriter writes samples to out in the configured output format.
+type sampleWriter struct {
+	out           io.Writer
+	format        string
+	headerWritten bool
+}
+
+func (w *sampleWriter) write(s Sample) error {
+	if w.format == OutputJSON {
+		return json.NewEncoder(w.out).Encode(s)
+	}
+
+	if !w.headerWritten {
+		if _, err := fmt.Fprintf(w.out, "%-8s %-21s %9s %9s %9s %9s %6s %8s %8s %8s %8s %8s %8s %8s %8s %8s %8s %8s %8s\n",
+			"time", "volume", "r/s", "w/s", "rMiB/s", "wMiB/s", "qlen",
+			"r_avg", "r_p50", "r_p90", "r_p99", "w_avg", "w_p50", "w_p90", "w_p99",
+			"ebs_iops", "ebs_tp", "ec2_iops", "ec2_tp"); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+	// Latencies are printed in milliseconds, exceeded times in seconds
+	_, err := fmt.Fprintf(w.out, "%-8s %-21s %9.1f %9.1f %9.2f %9.2f %6d %8.3f %8.3f %8.3f %8.3f %8.3f %8.3f %8.3f %8.3f %8.3f %8.3f %8.3f %8.3f\n",
+		s.Time.Format(time.TimeOnly), s.VolumeID,
+		s.ReadOpsPerSecond, s.WriteOpsPerSecond,
+		s.ReadBytesPerSecond/bytesInMebibyte, s.WriteBytesPerSecond/bytesInMebibyte,
+		s.QueueLength,
+		s.ReadLatencyAverageSeconds/secondsInMillisecond,
+		s.ReadLatencyPercentiles.P50/secondsInMillisecond,
+		s.ReadLatencyPercentiles.P90/secondsInMillisecond,
+		s.ReadLatencyPercentiles.P99/secondsInMillisecond,
+		s.WriteLatencyAverageSeconds/secondsInMillisecond,
+		s.WriteLatencyPercentiles.P50/secondsInMillisecond,
+		s.WriteLatencyPercentiles.P90/secondsInMillisecond,
+		s.WriteLatencyPercentiles.P99/secondsInMillisecond,
+		s.EBSIOPSExceededSeconds, s.EBSThroughputExceededSeconds,
+		s.EC2IOPSExceededSeconds, s.EC2ThroughputExceededSeconds)
+	return err
+}
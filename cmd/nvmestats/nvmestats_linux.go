@@ -0,0 +1,169 @@
+//go:build linux
+
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nvmestats
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/metrics"
+	"k8s.io/klog/v2"
+)
+
+// Run samples the EBS log page of the configured device every interval and
+// writes the statistics of each interval to out, until Count samples were
+// written or ctx is done.
+func Run(ctx context.Context, o Options, out io.Writer) error {
+	devices, err := metrics.ListEBSDevices()
+	if err != nil {
+		return fmt.Errorf("failed to list EBS devices: %w", err)
+	}
+	devicePath, volumeID, err := resolveDevice(o.Device, devices)
+	if err != nil {
+		return err
+	}
+	klog.V(4).InfoS("Sampling NVMe log page", "devicePath", devicePath, "volumeID", volumeID, "interval", o.Interval)
+
+	prev, err := metrics.ReadEBSMetrics(devicePath)
+	if err != nil {
+		return fmt.Errorf("failed to read log page of %s: %w", devicePath, err)
+	}
+	prevTime := time.Now()
+
+	w := &sampleWriter{out: out, format: o.Output}
+	ticker := time.NewTicker(o.Interval)
+	defer ticker.Stop()
+
+	for i := 0; o.Count == 0 || i < o.Count; i++ {
+		var now time.Time
+		select {
+		case <-ctx.Done():
+			return nil
+		case now = <-ticker.C:
+		}
+
+		cur, err := metrics.ReadEBSMetrics(devicePath)
+		if err != nil {
+			return fmt.Errorf("failed to read log page of %s: %w", devicePath, err)
+		}
+
+		s := newSample(prev, cur, now.Sub(prevTime))
+		s.Time = now
+		s.Device = devicePath
+		s.VolumeID = volumeID
+		if err := w.write(s); err != nil {
+			return fmt.Errorf("failed to write sample: %w", err)
+		}
+
+		prev, prevTime = cur, now
+	}
+	return nil
+}
+
+// resolveDevice returns the device path and volume ID of device, which is either
+// an NVMe device path or an EBS volume ID. devices maps device paths to volume IDs.
+func resolveDevice(device string, devices map[string]string) (string, string, error) {
+	if strings.HasPrefix(device, "vol") {
+		volumeID := device
+		if !strings.HasPrefix(volumeID, "vol-") {
+			volumeID = "vol-" + volumeID[3:]
+		}
+		for devicePath, id := range devices {
+			if id == volumeID {
+				return devicePath, volumeID, nil
+			}
+		}
+		return "", "", fmt.Errorf("volume %s is not attached to this node", volumeID)
+	}
+
+	devicePath := device
+	if !strings.HasPrefix(devicePath, "/dev/") {
+		devicePath = "/dev/" + devicePath
+	}
+	volumeID, ok := devices[devicePath]
+	if !ok {
+		return "", "", fmt.Errorf("%s is not an EBS NVMe device", devicePath)
+	}
+	return devicePath, volumeID, nil
+}
+
+// newSample computes the statistics of the interval of length elapsed between the log pages prev and cur.
+func newSample(prev, cur metrics.EBSMetrics, elapsed time.Duration) Sample {
+	delta := metrics.EBSMetricsDelta(prev, cur)
+	seconds := elapsed.Seconds()
+	if seconds <= 0 {
+		seconds = 1
+	}
+
+	return Sample{
+		ReadOpsPerSecond:    float64(delta.ReadOps) / seconds,
+		WriteOpsPerSecond:   float64(delta.WriteOps) / seconds,
+		ReadBytesPerSecond:  float64(delta.ReadBytes) / seconds,
+		WriteBytesPerSecond: float64(delta.WriteBytes) / seconds,
+		QueueLength:         cur.QueueLength,
+
+		ReadLatencyAverageSeconds:  metrics.AverageSeconds(delta.TotalReadTime, delta.ReadOps),
+		WriteLatencyAverageSeconds: metrics.AverageSeconds(delta.TotalWriteTime, delta.WriteOps),
+		ReadLatencyPercentiles:     latencyPercentiles(delta.ReadLatency),
+		WriteLatencyPercentiles:    latencyPercentiles(delta.WriteLatency),
+
+		EBSIOPSExceededSeconds:       float64(delta.EBSIOPSExceeded) / metrics.MicrosecondsInSeconds,
+		EBSThroughputExceededSeconds: float64(delta.EBSThroughputExceeded) / metrics.MicrosecondsInSeconds,
+		EC2IOPSExceededSeconds:       float64(delta.EC2IOPSExceeded) / metrics.MicrosecondsInSeconds,
+		EC2ThroughputExceededSeconds: float64(delta.EC2ThroughputExceeded) / metrics.MicrosecondsInSeconds,
+	}
+}
+
+func latencyPercentiles(hist metrics.Histogram) LatencyPercentiles {
+	return LatencyPercentiles{
+		P50: percentile(hist, 0.5),
+		P90: percentile(hist, 0.9),
+		P99: percentile(hist, 0.99),
+	}
+}
+
+// percentile estimates the q-quantile, in seconds, of the latencies counted in hist,
+// interpolating linearly within the bin that contains it.
+func percentile(hist metrics.Histogram, q float64) float64 {
+	binCount := min(hist.BinCount, uint64(len(hist.Bins)))
+
+	var total uint64
+	for i := range binCount {
+		total += hist.Bins[i].Count
+	}
+	if total == 0 {
+		return 0
+	}
+
+	rank := q * float64(total)
+	var cumulative uint64
+	for i := range binCount {
+		bin := hist.Bins[i]
+		if bin.Count == 0 {
+			continue
+		}
+		if float64(cumulative+bin.Count) >= rank {
+			fraction := (rank - float64(cumulative)) / float64(bin.Count)
+			return (float64(bin.Lower) + fraction*float64(bin.Upper-bin.Lower)) / metrics.MicrosecondsInSeconds
+		}
+		cumulative += bin.Count
+	}
+	return float64(hist.Bins[binCount-1].Upper) / metrics.MicrosecondsInSeconds
+}
@@ -0,0 +1,106 @@
+//go:build linux
+
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nvmestats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveDevice(t *testing.T) {
+	devices := map[string]string{
+		"/dev/nvme1n1": "vol-11111111111111111",
+		"/dev/nvme2n1": "vol-22222222222222222",
+	}
+
+	testCases := []struct {
+		name           string
+		device         string
+		wantDevicePath string
+		wantVolumeID   string
+		wantErr        bool
+	}{
+		{name: "volume ID", device: "vol-22222222222222222", wantDevicePath: "/dev/nvme2n1", wantVolumeID: "vol-22222222222222222"},
+		{name: "volume ID without dash", device: "vol22222222222222222", wantDevicePath: "/dev/nvme2n1", wantVolumeID: "vol-22222222222222222"},
+		{name: "device path", device: "/dev/nvme1n1", wantDevicePath: "/dev/nvme1n1", wantVolumeID: "vol-11111111111111111"},
+		{name: "device name", device: "nvme1n1", wantDevicePath: "/dev/nvme1n1", wantVolumeID: "vol-11111111111111111"},
+		{name: "volume not attached", device: "vol-33333333333333333", wantErr: true},
+		{name: "not an EBS device", device: "/dev/nvme0n1", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			devicePath, volumeID, err := resolveDevice(tc.device, devices)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantDevicePath, devicePath)
+			assert.Equal(t, tc.wantVolumeID, volumeID)
+		})
+	}
+}
+
+func TestNewSample(t *testing.T) {
+	prev := metrics.EBSMetrics{ReadOps: 100, ReadBytes: 1 << 20, TotalReadTime: 50000, EBSIOPSExceeded: 1000}
+	prev.ReadLatency.BinCount = 2
+	prev.ReadLatency.Bins[0] = metrics.HistogramBin{Lower: 0, Upper: 1000, Count: 100}
+	prev.ReadLatency.Bins[1] = metrics.HistogramBin{Lower: 1000, Upper: 2000}
+
+	cur := prev
+	cur.ReadOps = 300
+	cur.ReadBytes = 3 << 20
+	cur.TotalReadTime = 250000
+	cur.EBSIOPSExceeded = 501000
+	cur.QueueLength = 4
+	cur.ReadLatency.Bins[0].Count = 200
+	cur.ReadLatency.Bins[1].Count = 100
+
+	s := newSample(prev, cur, 2*time.Second)
+	assert.InDelta(t, 100, s.ReadOpsPerSecond, 1e-9)
+	assert.InDelta(t, 1<<20, s.ReadBytesPerSecond, 1e-9)
+	assert.Zero(t, s.WriteOpsPerSecond)
+	assert.Equal(t, uint64(4), s.QueueLength)
+	assert.InDelta(t, 0.001, s.ReadLatencyAverageSeconds, 1e-9)
+	assert.InDelta(t, 0.5, s.EBSIOPSExceededSeconds, 1e-9)
+	// 100 operations in (0, 1ms] and 100 in (1ms, 2ms]
+	assert.InDelta(t, 0.001, s.ReadLatencyPercentiles.P50, 1e-9)
+	assert.InDelta(t, 0.0018, s.ReadLatencyPercentiles.P90, 1e-9)
+
+	// Counters reset between samples are reported as is
+	s = newSample(cur, prev, time.Second)
+	assert.InDelta(t, 100, s.ReadOpsPerSecond, 1e-9)
+}
+
+func TestPercentile(t *testing.T) {
+	var hist metrics.Histogram
+	assert.Zero(t, percentile(hist, 0.5))
+
+	hist.BinCount = 3
+	hist.Bins[0] = metrics.HistogramBin{Lower: 0, Upper: 100, Count: 0}
+	hist.Bins[1] = metrics.HistogramBin{Lower: 100, Upper: 200, Count: 10}
+	hist.Bins[2] = metrics.HistogramBin{Lower: 200, Upper: 400, Count: 10}
+
+	assert.InDelta(t, 0.0001, percentile(hist, 0), 1e-12)
+	assert.InDelta(t, 0.00015, percentile(hist, 0.25), 1e-12)
+	assert.InDelta(t, 0.0002, percentile(hist, 0.5), 1e-12)
+	assert.InDelta(t, 0.0004, percentile(hist, 1), 1e-12)
+}
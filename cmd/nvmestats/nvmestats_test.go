@@ -0,0 +1,80 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nvmestats
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionsValidate(t *testing.T) {
+	valid := Options{Device: "vol-11111111111111111", Interval: time.Second, Output: OutputTable}
+	require.NoError(t, valid.Validate())
+
+	testCases := map[string]func(o *Options){
+		"missing device": func(o *Options) { o.Device = "" },
+		"zero interval":  func(o *Options) { o.Interval = 0 },
+		"negative count": func(o *Options) { o.Count = -1 },
+		"unknown output": func(o *Options) { o.Output = "yaml" },
+	}
+	for name, mutate := range testCases {
+		t.Run(name, func(t *testing.T) {
+			o := valid
+			mutate(&o)
+			require.Error(t, o.Validate())
+		})
+	}
+}
+
+func TestSampleWriter(t *testing.T) {
+	s := Sample{
+		Time:                   time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+		Device:                 "/dev/nvme1n1",
+		VolumeID:               "vol-11111111111111111",
+		ReadOpsPerSecond:       100,
+		ReadBytesPerSecond:     2 << 20,
+		ReadLatencyPercentiles: LatencyPercentiles{P99: 0.004},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		var out bytes.Buffer
+		w := &sampleWriter{out: &out, format: OutputTable}
+		require.NoError(t, w.write(s))
+		require.NoError(t, w.write(s))
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		require.Len(t, lines, 3)
+		assert.True(t, strings.HasPrefix(lines[0], "time"))
+		fields := strings.Fields(lines[1])
+		assert.Equal(t, []string{"12:00:00", "vol-11111111111111111", "100.0", "0.0", "2.00"}, fields[:5])
+		assert.Equal(t, "4.000", fields[10])
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		w := &sampleWriter{out: &out, format: OutputJSON}
+		require.NoError(t, w.write(s))
+
+		var got Sample
+		require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+		assert.Equal(t, s, got)
+	})
+}
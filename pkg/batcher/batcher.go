@@ -27,7 +27,7 @@
 // Add a task and receive its result:
 //
 //	resultChan := make(chan batcher.BatchResult)
-//	b.AddTask(myTask, resultChan)
+//	b.AddTask(ctx, myTask, resultChan)
 //	result := <-resultChan
 //
 // Key Components:
@@ -38,14 +38,28 @@
 // Task Duplication:
 // Batcher identifies tasks by content. For multiple identical tasks, each has a unique result channel.
 // This distinction ensures that identical tasks return their results to the appropriate callers.
+//
+// Tracing:
+// Each batch execution is traced in a span linked to the spans of the contexts its tasks were added with.
+// The span context of the batch execution is returned with the result of each task, so that callers can
+// link their spans to it in return.
 package batcher
 
 import (
+	"context"
+	"strings"
 	"time"
 
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/klog/v2"
 )
 
+var tracer = otel.Tracer("github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/batcher")
+
 // Batcher manages the batching and execution of tasks. It collects tasks up to a specified limit (maxEntries) or
 // waits for a defined duration (maxDelay) before triggering a batch execution. The actual task execution
 // logic is provided by the execFunc, which processes tasks and returns their corresponding results. Tasks are
@@ -53,12 +67,18 @@ import (
 type Batcher[InputType comparable, ResultType any] struct {
 	// execFunc is the function responsible for executing a batch of tasks.
 	// It returns a map associating each task with its result.
-	execFunc func(inputs []InputType) (map[InputType]ResultType, error)
+	execFunc func(ctx context.Context, inputs []InputType) (map[InputType]ResultType, error)
 
 	// pendingTasks holds the tasks that are waiting to be executed in a batch.
 	// Each task is associated with one or more result channels to account for duplicates.
 	pendingTasks map[InputType][]chan BatchResult[ResultType]
 
+	// pendingLinks holds the links to the spans of the tasks that are waiting to be executed in a batch.
+	pendingLinks []trace.Link
+
+	// pendingRequestIDs holds the IDs of the CSI requests of the tasks that are waiting to be executed in a batch.
+	pendingRequestIDs []string
+
 	// taskChan is the channel through which new tasks are added to the Batcher.
 	taskChan chan taskEntry[InputType, ResultType]
 
@@ -75,6 +95,8 @@ type Batcher[InputType comparable, ResultType any] struct {
 type BatchResult[ResultType any] struct {
 	Result ResultType
 	Err    error
+	// SpanContext is the span context of the batch execution of the task.
+	SpanContext trace.SpanContext
 }
 
 // taskEntry represents a single task waiting to be batched and its associated result channel.
@@ -82,12 +104,14 @@ type BatchResult[ResultType any] struct {
 type taskEntry[InputType comparable, ResultType any] struct {
 	task       InputType
 	resultChan chan BatchResult[ResultType]
+	link       trace.Link
+	requestID  string
 }
 
 // New creates and returns a Batcher configured with the specified maxEntries and maxDelay parameters.
 // Upon instantiation, it immediately launches the internal task manager as a goroutine to oversee batch operations.
 // The provided execFunc is used to execute batch requests.
-func New[InputType comparable, ResultType any](entries int, delay time.Duration, fn func(inputs []InputType) (map[InputType]ResultType, error)) *Batcher[InputType, ResultType] {
+func New[InputType comparable, ResultType any](entries int, delay time.Duration, fn func(ctx context.Context, inputs []InputType) (map[InputType]ResultType, error)) *Batcher[InputType, ResultType] {
 	klog.V(7).InfoS("New: initializing Batcher", "maxEntries", entries, "maxDelay", delay)
 
 	b := &Batcher[InputType, ResultType]{
@@ -102,10 +126,11 @@ func New[InputType comparable, ResultType any](entries int, delay time.Duration,
 	return b
 }
 
-// AddTask adds a new task to the Batcher's queue.
-func (b *Batcher[InputType, ResultType]) AddTask(t InputType, resultChan chan BatchResult[ResultType]) {
+// AddTask adds a new task to the Batcher's queue. The span of ctx, if any, is linked to the span of the batch execution,
+// and the ID of the CSI request of ctx, if any, is passed to the batch execution.
+func (b *Batcher[InputType, ResultType]) AddTask(ctx context.Context, t InputType, resultChan chan BatchResult[ResultType]) {
 	klog.V(7).InfoS("AddTask: queueing task", "task", t)
-	b.taskChan <- taskEntry[InputType, ResultType]{task: t, resultChan: resultChan}
+	b.taskChan <- taskEntry[InputType, ResultType]{task: t, resultChan: resultChan, link: trace.LinkFromContext(ctx), requestID: util.RequestID(ctx)}
 }
 
 // taskManager runs as a goroutine, continuously managing the Batcher's internal state.
@@ -116,8 +141,10 @@ func (b *Batcher[InputType, ResultType]) taskManager() {
 
 	exec := func() {
 		timerCh = nil
-		go b.execute(b.pendingTasks)
+		go b.execute(b.pendingTasks, b.pendingLinks, b.pendingRequestIDs)
 		b.pendingTasks = make(map[InputType][]chan BatchResult[ResultType])
+		b.pendingLinks = nil
+		b.pendingRequestIDs = nil
 	}
 
 	for {
@@ -133,6 +160,12 @@ func (b *Batcher[InputType, ResultType]) taskManager() {
 				b.pendingTasks[t.task] = make([]chan BatchResult[ResultType], 0)
 			}
 			b.pendingTasks[t.task] = append(b.pendingTasks[t.task], t.resultChan)
+			if t.link.SpanContext.IsSampled() {
+				b.pendingLinks = append(b.pendingLinks, t.link)
+			}
+			if t.requestID != "" {
+				b.pendingRequestIDs = append(b.pendingRequestIDs, t.requestID)
+			}
 
 			if len(b.pendingTasks) == 1 {
 				klog.V(7).InfoS("taskManager: starting maxDelay timer")
@@ -149,23 +182,37 @@ func (b *Batcher[InputType, ResultType]) taskManager() {
 
 // execute is called by taskManager to execute a batch of tasks.
 // It calls the Batcher's internal execFunc and then sends the results of each task to its corresponding result channels.
-func (b *Batcher[InputType, ResultType]) execute(pendingTasks map[InputType][]chan BatchResult[ResultType]) {
+// The request ID of the batch execution is the comma-separated list of the request IDs of its tasks.
+func (b *Batcher[InputType, ResultType]) execute(pendingTasks map[InputType][]chan BatchResult[ResultType], links []trace.Link, requestIDs []string) {
 	batch := make([]InputType, 0, len(pendingTasks))
 	for task := range pendingTasks {
 		batch = append(batch, task)
 	}
 
+	// The batch execution is only traced if one of its tasks is traced
+	ctx, span := context.Background(), trace.SpanFromContext(context.Background())
+	if len(links) > 0 {
+		ctx, span = tracer.Start(ctx, "Batcher.execute", trace.WithLinks(links...),
+			trace.WithAttributes(attribute.Int("batch.size", len(batch))))
+	}
+	defer span.End()
+	if len(requestIDs) > 0 {
+		ctx = util.WithRequestID(ctx, strings.Join(requestIDs, ","))
+	}
+
 	klog.V(7).InfoS("execute: calling execFunc", "batchSize", len(batch))
-	resultsMap, err := b.execFunc(batch)
+	resultsMap, err := b.execFunc(ctx, batch)
 	if err != nil {
 		klog.ErrorS(err, "execute: error executing batch")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 	}
 
 	klog.V(7).InfoS("execute: sending batch results", "batch", batch)
 	for _, task := range batch {
 		r := resultsMap[task]
 		for _, ch := range pendingTasks[task] {
-			ch <- BatchResult[ResultType]{Result: r, Err: err}
+			ch <- BatchResult[ResultType]{Result: r, Err: err, SpanContext: span.SpanContext()}
 		}
 	}
 	klog.V(7).InfoS("execute: finished execution", "batchSize", len(batch))
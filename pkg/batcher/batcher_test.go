@@ -15,11 +15,18 @@
 package batcher
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 const (
@@ -27,7 +34,7 @@ const (
 	slowMaxDelay    = 5 * defaultMaxDelay
 )
 
-func mockExecution(inputs []string) (map[string]string, error) {
+func mockExecution(_ context.Context, inputs []string) (map[string]string, error) {
 	results := make(map[string]string)
 	for _, input := range inputs {
 		results[input] = input
@@ -35,7 +42,7 @@ func mockExecution(inputs []string) (map[string]string, error) {
 	return results, nil
 }
 
-func mockExecutionWithError(inputs []string) (map[string]string, error) {
+func mockExecutionWithError(_ context.Context, inputs []string) (map[string]string, error) {
 	results := make(map[string]string)
 	for _, input := range inputs {
 		results[input] = input
@@ -47,7 +54,7 @@ func TestBatcher(t *testing.T) {
 	t.Parallel()
 	type testCase struct {
 		name         string
-		mockFunc     func(inputs []string) (map[string]string, error)
+		mockFunc     func(ctx context.Context, inputs []string) (map[string]string, error)
 		maxEntries   int
 		maxDelay     time.Duration
 		tasks        []string
@@ -143,7 +150,7 @@ func TestBatcher(t *testing.T) {
 					defer wg.Done()
 					task := fmt.Sprintf("task%d", taskNum)
 					resultChans[taskNum] = make(chan BatchResult[string], 1)
-					b.AddTask(task, resultChans[taskNum])
+					b.AddTask(context.Background(), task, resultChans[taskNum])
 				}(i)
 			}
 
@@ -182,7 +189,7 @@ func TestBatcherConcurrentTaskAdditions(t *testing.T) {
 			defer wg.Done()
 			task := fmt.Sprintf("task%d", taskNum)
 			resultChans[taskNum] = make(chan BatchResult[string], 1)
-			b.AddTask(task, resultChans[taskNum])
+			b.AddTask(context.Background(), task, resultChans[taskNum])
 		}(i)
 	}
 
@@ -199,3 +206,69 @@ func TestBatcherConcurrentTaskAdditions(t *testing.T) {
 		}
 	}
 }
+
+func TestBatcherTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	oldTracer := tracer
+	tracer = tp.Tracer("test")
+	defer func() { tracer = oldTracer }()
+
+	b := New(3, defaultMaxDelay, mockExecution)
+	var wg sync.WaitGroup
+	results := make([]BatchResult[string], 3)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, span := tp.Tracer("test").Start(context.Background(), fmt.Sprintf("caller-%d", i))
+			defer span.End()
+			ch := make(chan BatchResult[string])
+			b.AddTask(ctx, fmt.Sprintf("task-%d", i), ch)
+			results[i] = <-ch
+		}()
+	}
+	wg.Wait()
+
+	var executions []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == "Batcher.execute" {
+			executions = append(executions, span)
+		}
+	}
+	if len(executions) != 1 {
+		t.Fatalf("Expected 1 batch execution span, got %d", len(executions))
+	}
+	if len(executions[0].Links()) != len(results) {
+		t.Errorf("Expected batch execution span to link %d callers, got %d", len(results), len(executions[0].Links()))
+	}
+	for i, r := range results {
+		if !r.SpanContext.Equal(executions[0].SpanContext()) {
+			t.Errorf("Expected result %d to carry the span context of the batch execution", i)
+		}
+	}
+}
+
+func TestBatcherRequestIDs(t *testing.T) {
+	var requestIDs []string
+	b := New(2, defaultMaxDelay, func(ctx context.Context, inputs []string) (map[string]string, error) {
+		requestIDs = strings.Split(util.RequestID(ctx), ",")
+		return mockExecution(ctx, inputs)
+	})
+	var wg sync.WaitGroup
+	for i := range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ch := make(chan BatchResult[string])
+			b.AddTask(util.WithRequestID(context.Background(), fmt.Sprintf("request-%d", i)), fmt.Sprintf("task-%d", i), ch)
+			<-ch
+		}()
+	}
+	wg.Wait()
+
+	slices.Sort(requestIDs)
+	if !slices.Equal(requestIDs, []string{"request-0", "request-1"}) {
+		t.Errorf("Expected the batch execution to carry the request IDs of its tasks, got %v", requestIDs)
+	}
+}
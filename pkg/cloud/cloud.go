@@ -22,6 +22,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"maps"
 	"math"
 	"os"
 	"regexp"
@@ -46,6 +47,8 @@ import (
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/metrics"
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/plugin"
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 )
@@ -128,6 +131,8 @@ const (
 	SnapshotNameTagKey = "CSIVolumeSnapshotName"
 	// KubernetesTagKeyPrefix is the prefix of the key value that is reserved for Kubernetes.
 	KubernetesTagKeyPrefix = "kubernetes.io"
+	// maxResourceTags is the maximum number of tags of an EC2 resource.
+	maxResourceTags = 50
 )
 
 // Tags that depend on driver name (initialized in NewCloud).
@@ -138,6 +143,10 @@ var (
 	AllowAutoIOPSIncreaseOnModifyKey string
 	// IOPSPerGBKey represents the tag key for IOPS per GB.
 	IOPSPerGBKey string
+	// DeferredModificationKey is the tag key of the target of a deferred volume modification.
+	DeferredModificationKey string
+	// RequestIDTagKey is the tag key of the ID of the CSI request that created a volume or a snapshot.
+	RequestIDTagKey string
 )
 
 // Batcher.
@@ -163,6 +172,15 @@ const (
 const (
 	// maxInstancesDescribed is the maximum number of instances described in each EC2 Describe Instances call.
 	maxInstancesDescribed = 1000
+
+	// maxInstanceTypesDescribed is the maximum number of instance types described in each EC2 Describe Instance Types call.
+	maxInstanceTypesDescribed = 100
+
+	// maxFilterValues is the maximum number of values of a filter of EC2 Describe calls.
+	maxFilterValues = 200
+
+	// maxTaggedResourcesPerRequest is the maximum number of resources of each EC2 CreateTags and DeleteTags call.
+	maxTaggedResourcesPerRequest = 1000
 )
 
 var (
@@ -204,6 +222,13 @@ var (
 
 	// ErrLimitExceeded is returned if a user exceeds a quota.
 	ErrLimitExceeded = errors.New("limit exceeded")
+
+	// ErrAttachmentStuck is returned if an attachment was stuck attaching and has been detached.
+	ErrAttachmentStuck = errors.New("attachment stuck")
+
+	// errVolumeOptimizing is returned when a volume cannot be modified because its last modification
+	// is in optimizing state.
+	errVolumeOptimizing = errors.New("volume is in OPTIMIZING state")
 )
 
 // Set during build time via -ldflags.
@@ -251,6 +276,17 @@ type Disk struct {
 	OutpostArn         string
 	KmsKeyID           string
 	Attachments        []string
+	// IOPS is populated by CreateDisk, with the IOPS requested for the volume after they were
+	// capped to the limits of its type and size, or 0 if no IOPS were requested, and by GetDiskByID.
+	IOPS int32
+	// Encrypted, VolumeType and Throughput are only populated by GetDiskByID.
+	Encrypted  bool
+	VolumeType string
+	Throughput int32
+	// Tags is only populated by GetDiskByID and ListDisks.
+	Tags map[string]string
+	// CreateTime is only populated by ListDisks.
+	CreateTime time.Time
 }
 
 // DiskOptions represents parameters to create an EBS volume.
@@ -311,6 +347,9 @@ type Snapshot struct {
 	Size           int32
 	CreationTime   time.Time
 	ReadyToUse     bool
+	// Failed is true if the snapshot is in the error state, it never becomes ready to use.
+	Failed bool
+	Tags   map[string]string
 }
 
 // ListSnapshotsResponse is the container for our snapshots along with a pagination token to pass back to the caller.
@@ -425,6 +464,8 @@ func initVariables() {
 	AwsEbsDriverTagKey = util.GetDriverName() + "/cluster"
 	AllowAutoIOPSIncreaseOnModifyKey = util.GetDriverName() + "/AllowAutoIOPSIncreaseOnModify"
 	IOPSPerGBKey = util.GetDriverName() + "/IOPSPerGb"
+	DeferredModificationKey = util.GetDriverName() + "/DeferredModification"
+	RequestIDTagKey = util.GetDriverName() + "/request-id"
 }
 
 // NewCloud returns a new instance of AWS cloud
@@ -452,6 +493,7 @@ func NewCloud(region string, awsSdkDebugLog bool, userAgentExtra string, batchin
 
 	ec2Options := func(o *ec2.Options) {
 		o.APIOptions = append(o.APIOptions,
+			TraceRequestsMiddleware(),
 			RecordRequestsMiddleware(deprecatedMetrics),
 			LogServerErrorsMiddleware(), // This middlware should always be last so it sees an unmangled error
 		)
@@ -530,29 +572,29 @@ func newBatcherManager(svc util.EC2API) *batcherManager {
 	likelyNotFoundSnapshotIDs := expiringcache.New[string, struct{}](cacheForgetDelay)
 
 	return &batcherManager{
-		volumeIDBatcher: batcher.New(500, batchMaxDelay, func(ids []string) (map[string]*types.Volume, error) {
-			return execBatchDescribeVolumes(svc, ids, volumeIDBatcher, likelyNotFoundVolumeIDs)
+		volumeIDBatcher: batcher.New(500, batchMaxDelay, func(ctx context.Context, ids []string) (map[string]*types.Volume, error) {
+			return execBatchDescribeVolumes(ctx, svc, ids, volumeIDBatcher, likelyNotFoundVolumeIDs)
 		}),
-		volumeTagBatcher: batcher.New(500, batchMaxDelay, func(names []string) (map[string]*types.Volume, error) {
-			return execBatchDescribeVolumes(svc, names, volumeTagBatcher, likelyNotFoundVolumeIDs)
+		volumeTagBatcher: batcher.New(500, batchMaxDelay, func(ctx context.Context, names []string) (map[string]*types.Volume, error) {
+			return execBatchDescribeVolumes(ctx, svc, names, volumeTagBatcher, likelyNotFoundVolumeIDs)
 		}),
-		instanceIDBatcher: batcher.New(50, batchMaxDelay, func(ids []string) (map[string]*types.Instance, error) {
-			return execBatchDescribeInstances(svc, ids, likelyNotFoundInstanceIDs)
+		instanceIDBatcher: batcher.New(50, batchMaxDelay, func(ctx context.Context, ids []string) (map[string]*types.Instance, error) {
+			return execBatchDescribeInstances(ctx, svc, ids, likelyNotFoundInstanceIDs)
 		}),
-		snapshotIDBatcher: batcher.New(1000, batchMaxDelay, func(ids []string) (map[string]*types.Snapshot, error) {
-			return execBatchDescribeSnapshots(svc, ids, snapshotIDBatcher, likelyNotFoundSnapshotIDs)
+		snapshotIDBatcher: batcher.New(1000, batchMaxDelay, func(ctx context.Context, ids []string) (map[string]*types.Snapshot, error) {
+			return execBatchDescribeSnapshots(ctx, svc, ids, snapshotIDBatcher, likelyNotFoundSnapshotIDs)
 		}),
-		snapshotTagBatcher: batcher.New(1000, batchMaxDelay, func(names []string) (map[string]*types.Snapshot, error) {
-			return execBatchDescribeSnapshots(svc, names, snapshotTagBatcher, likelyNotFoundSnapshotIDs)
+		snapshotTagBatcher: batcher.New(1000, batchMaxDelay, func(ctx context.Context, names []string) (map[string]*types.Snapshot, error) {
+			return execBatchDescribeSnapshots(ctx, svc, names, snapshotTagBatcher, likelyNotFoundSnapshotIDs)
 		}),
-		volumeModificationIDBatcher: batcher.New(500, batchMaxDelay, func(names []string) (map[string]*types.VolumeModification, error) {
-			return execBatchDescribeVolumesModifications(svc, names)
+		volumeModificationIDBatcher: batcher.New(500, batchMaxDelay, func(ctx context.Context, names []string) (map[string]*types.VolumeModification, error) {
+			return execBatchDescribeVolumesModifications(ctx, svc, names)
 		}),
-		volumeStatusIDBatcherSlow: batcher.New(1000, slowVolumeStatusBatchMaxDelay, func(ids []string) (map[string]*types.VolumeStatusItem, error) {
-			return execBatchDescribeVolumeStatus(svc, ids)
+		volumeStatusIDBatcherSlow: batcher.New(1000, slowVolumeStatusBatchMaxDelay, func(ctx context.Context, ids []string) (map[string]*types.VolumeStatusItem, error) {
+			return execBatchDescribeVolumeStatus(ctx, svc, ids)
 		}),
-		volumeStatusIDBatcherFast: batcher.New(1000, fastVolumeStatusBatchMaxDelay, func(ids []string) (map[string]*types.VolumeStatusItem, error) {
-			return execBatchDescribeVolumeStatus(svc, ids)
+		volumeStatusIDBatcherFast: batcher.New(1000, fastVolumeStatusBatchMaxDelay, func(ctx context.Context, ids []string) (map[string]*types.VolumeStatusItem, error) {
+			return execBatchDescribeVolumeStatus(ctx, svc, ids)
 		}),
 	}
 }
@@ -572,7 +614,7 @@ func removeLikelyBadIds(cache expiringcache.ExpiringCache[string, struct{}], inp
 }
 
 // execBatchDescribeVolumes executes a batched DescribeVolumes API call depending on the type of batcher.
-func execBatchDescribeVolumes(svc util.EC2API, input []string, batcher volumeBatcherType, cache expiringcache.ExpiringCache[string, struct{}]) (map[string]*types.Volume, error) {
+func execBatchDescribeVolumes(ctx context.Context, svc util.EC2API, input []string, batcher volumeBatcherType, cache expiringcache.ExpiringCache[string, struct{}]) (map[string]*types.Volume, error) {
 	goodVolumes, badVolumes := removeLikelyBadIds(cache, input)
 
 	var request *ec2.DescribeVolumesInput
@@ -600,7 +642,7 @@ func execBatchDescribeVolumes(svc util.EC2API, input []string, batcher volumeBat
 		return nil, errors.New("execBatchDescribeVolumes: unsupported request type")
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), batchDescribeTimeout)
+	ctx, cancel := context.WithTimeout(ctx, batchDescribeTimeout)
 	defer cancel()
 
 	var resp []types.Volume
@@ -648,7 +690,7 @@ func execBatchDescribeVolumes(svc util.EC2API, input []string, batcher volumeBat
 
 // batchDescribeVolumes processes a DescribeVolumes request. Depending on the request,
 // it determines the appropriate batcher to use, queues the task, and waits for the result.
-func (c *cloud) batchDescribeVolumes(request *ec2.DescribeVolumesInput) (*types.Volume, error) {
+func (c *cloud) batchDescribeVolumes(ctx context.Context, request *ec2.DescribeVolumesInput) (*types.Volume, error) {
 	var b *batcher.Batcher[string, *types.Volume]
 	var task string
 
@@ -665,11 +707,7 @@ func (c *cloud) batchDescribeVolumes(request *ec2.DescribeVolumesInput) (*types.
 		return nil, fmt.Errorf("%w: batchDescribeVolumes: request: %v", ErrInvalidRequest, request)
 	}
 
-	ch := make(chan batcher.BatchResult[*types.Volume])
-
-	b.AddTask(task, ch)
-
-	r := <-ch
+	r := awaitBatchResult(ctx, "DescribeVolumes", b, task)
 
 	if r.Err != nil {
 		return nil, r.Err
@@ -738,7 +776,7 @@ func (c *cloud) CreateDisk(ctx context.Context, volumeName string, diskOptions *
 	}
 	tagSpec := types.TagSpecification{
 		ResourceType: types.ResourceTypeVolume,
-		Tags:         tags,
+		Tags:         withRequestIDTag(ctx, tags),
 	}
 
 	zone := diskOptions.AvailabilityZone
@@ -878,7 +916,7 @@ func (c *cloud) CreateDisk(ctx context.Context, volumeName string, diskOptions *
 
 	klog.V(7).InfoS("CreateDisk: volume created successfully", "volumeName", volumeName, "volume", volume)
 
-	return &Disk{CapacityGiB: size, VolumeID: volumeID, AvailabilityZone: zone, SnapshotID: diskOptions.SnapshotID, SourceVolumeID: diskOptions.SourceVolumeID, OutpostArn: outpostArn}, nil
+	return &Disk{CapacityGiB: size, VolumeID: volumeID, AvailabilityZone: zone, SnapshotID: diskOptions.SnapshotID, SourceVolumeID: diskOptions.SourceVolumeID, OutpostArn: outpostArn, IOPS: iops}, nil
 }
 
 func (c *cloud) createCloneHelper(ctx context.Context, input *ec2.CopyVolumesInput, iops int32, throughput int32) (int32, string, string, error) {
@@ -941,13 +979,13 @@ func (c *cloud) createVolumeHelper(ctx context.Context, diskOptions *DiskOptions
 }
 
 // execBatchDescribeVolumesModifications executes a batched DescribeVolumesModifications API call.
-func execBatchDescribeVolumesModifications(svc util.EC2API, input []string) (map[string]*types.VolumeModification, error) {
+func execBatchDescribeVolumesModifications(ctx context.Context, svc util.EC2API, input []string) (map[string]*types.VolumeModification, error) {
 	klog.V(7).InfoS("execBatchDescribeVolumeModifications", "volumeIds", input)
 	request := &ec2.DescribeVolumesModificationsInput{
 		VolumeIds: input,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), batchDescribeTimeout)
+	ctx, cancel := context.WithTimeout(ctx, batchDescribeTimeout)
 	defer cancel()
 
 	resp, err := describeVolumesModifications(ctx, svc, request)
@@ -967,7 +1005,7 @@ func execBatchDescribeVolumesModifications(svc util.EC2API, input []string) (map
 }
 
 // batchDescribeVolumesModifications processes a DescribeVolumesModifications request by queuing the task and waiting for the result.
-func (c *cloud) batchDescribeVolumesModifications(request *ec2.DescribeVolumesModificationsInput) (*types.VolumeModification, error) {
+func (c *cloud) batchDescribeVolumesModifications(ctx context.Context, request *ec2.DescribeVolumesModificationsInput) (*types.VolumeModification, error) {
 	var task string
 
 	if len(request.VolumeIds) == 1 && request.VolumeIds[0] != "" {
@@ -976,12 +1014,7 @@ func (c *cloud) batchDescribeVolumesModifications(request *ec2.DescribeVolumesMo
 		return nil, fmt.Errorf("%w: batchDescribeVolumesModifications: invalid request, request: %v", ErrInvalidRequest, request)
 	}
 
-	ch := make(chan batcher.BatchResult[*types.VolumeModification])
-
-	b := c.bm.volumeModificationIDBatcher
-	b.AddTask(task, ch)
-
-	r := <-ch
+	r := awaitBatchResult(ctx, "DescribeVolumesModifications", c.bm.volumeModificationIDBatcher, task)
 
 	if r.Err != nil {
 		return nil, r.Err
@@ -991,35 +1024,43 @@ func (c *cloud) batchDescribeVolumesModifications(request *ec2.DescribeVolumesMo
 
 // ModifyTags adds, updates, and deletes tags for the specified EBS volume.
 func (c *cloud) ModifyTags(ctx context.Context, volumeID string, tagOptions ModifyTagsOptions) error {
-	if len(tagOptions.TagsToDelete) > 0 {
-		deleteTagsInput := &ec2.DeleteTagsInput{
-			Resources: []string{volumeID},
-			Tags:      make([]types.Tag, 0, len(tagOptions.TagsToDelete)),
-		}
-		for _, tagKey := range tagOptions.TagsToDelete {
-			deleteTagsInput.Tags = append(deleteTagsInput.Tags, types.Tag{Key: aws.String(tagKey)})
-		}
-		_, deleteErr := c.ec2.DeleteTags(ctx, deleteTagsInput)
-		if deleteErr != nil {
-			klog.ErrorS(deleteErr, "failed to delete tags", "volumeID", volumeID)
-			return deleteErr
-		}
-	}
-	if len(tagOptions.TagsToAdd) > 0 {
-		createTagsInput := &ec2.CreateTagsInput{
-			Resources: []string{volumeID},
-			Tags:      make([]types.Tag, 0, len(tagOptions.TagsToAdd)),
-		}
-		for k, v := range tagOptions.TagsToAdd {
-			createTagsInput.Tags = append(createTagsInput.Tags, types.Tag{
-				Key:   aws.String(k),
-				Value: aws.String(v),
-			})
+	return c.ModifyResourceTags(ctx, []string{volumeID}, tagOptions)
+}
+
+// ModifyResourceTags adds, updates, and deletes the same tags for the specified EBS volumes and snapshots.
+// The resources are tagged in batches of up to maxTaggedResourcesPerRequest per request.
+func (c *cloud) ModifyResourceTags(ctx context.Context, resourceIDs []string, tagOptions ModifyTagsOptions) error {
+	for batch := range slices.Chunk(resourceIDs, maxTaggedResourcesPerRequest) {
+		if len(tagOptions.TagsToDelete) > 0 {
+			deleteTagsInput := &ec2.DeleteTagsInput{
+				Resources: batch,
+				Tags:      make([]types.Tag, 0, len(tagOptions.TagsToDelete)),
+			}
+			for _, tagKey := range tagOptions.TagsToDelete {
+				deleteTagsInput.Tags = append(deleteTagsInput.Tags, types.Tag{Key: aws.String(tagKey)})
+			}
+			_, deleteErr := c.ec2.DeleteTags(ctx, deleteTagsInput)
+			if deleteErr != nil {
+				klog.ErrorS(deleteErr, "failed to delete tags", "resourceIDs", batch)
+				return deleteErr
+			}
 		}
-		_, addErr := c.ec2.CreateTags(ctx, createTagsInput)
-		if addErr != nil {
-			klog.ErrorS(addErr, "failed to create tags", "volumeID", volumeID)
-			return addErr
+		if len(tagOptions.TagsToAdd) > 0 {
+			createTagsInput := &ec2.CreateTagsInput{
+				Resources: batch,
+				Tags:      make([]types.Tag, 0, len(tagOptions.TagsToAdd)),
+			}
+			for k, v := range tagOptions.TagsToAdd {
+				createTagsInput.Tags = append(createTagsInput.Tags, types.Tag{
+					Key:   aws.String(k),
+					Value: aws.String(v),
+				})
+			}
+			_, addErr := c.ec2.CreateTags(ctx, createTagsInput)
+			if addErr != nil {
+				klog.ErrorS(addErr, "failed to create tags", "resourceIDs", batch)
+				return addErr
+			}
 		}
 	}
 	return nil
@@ -1048,9 +1089,29 @@ func (c *cloud) ResizeOrModifyDisk(ctx context.Context, volumeID string, newSize
 		return 0, err
 	}
 
+	// A modification that was deferred earlier is applied together with this one
+	deferred, deferredValue := deferredModificationFromTags(volumeID, volume.Tags)
+	if deferred != nil {
+		newSizeGiB, options = deferred.merge(newSizeGiB, options)
+	}
+	target := deferredModification{sizeGiB: newSizeGiB}
+	if options != nil {
+		target.options = *options
+	}
+	if deferred != nil && time.Now().Before(deferred.until) {
+		return 0, c.deferModification(ctx, volumeID, deferredValue, target, deferred.until)
+	}
+	// done clears the deferred modification once the volume was modified to a target that includes it
+	done := func(volumeSize int32, err error) (int32, error) {
+		if err == nil && deferred != nil {
+			c.clearDeferredModification(ctx, volumeID)
+		}
+		return volumeSize, err
+	}
+
 	needsModification, volumeSize, err := c.validateVolumeState(ctx, volumeID, newSizeGiB, *volume.Size, options)
 	if err != nil || !needsModification {
-		return volumeSize, err
+		return done(volumeSize, err)
 	}
 
 	if options.IOPS > 0 && options.IOPSPerGB > 0 {
@@ -1060,7 +1121,7 @@ func (c *cloud) ResizeOrModifyDisk(ctx context.Context, volumeID string, newSize
 	req := &ec2.ModifyVolumeInput{
 		VolumeId: aws.String(volumeID),
 	}
-	if newSizeBytes != 0 {
+	if newSizeGiB != 0 {
 		req.Size = aws.Int32(newSizeGiB)
 	}
 	volTypeToUse := volume.VolumeType
@@ -1111,14 +1172,21 @@ func (c *cloud) ResizeOrModifyDisk(ctx context.Context, volumeID string, newSize
 	}
 
 	needsModification, volumeSize, err = c.validateModifyVolume(ctx, volumeID, newSizeGiB, options, *volume)
+	if errors.Is(err, errVolumeOptimizing) {
+		return 0, c.deferModification(ctx, volumeID, deferredValue, target, time.Now().Add(deferredModificationRetryDelay))
+	}
 	if err != nil || !needsModification {
-		return volumeSize, err
+		return done(volumeSize, err)
 	}
 
 	response, err := c.ec2.ModifyVolume(ctx, req, func(o *ec2.Options) {
 		o.Retryer = c.rm.modifyVolumeRetryer
 	})
 	if err != nil {
+		if isAWSErrorIncorrectModificationState(err) {
+			klog.V(4).InfoS("Volume cannot be modified yet", "volumeID", volumeID, "err", err)
+			return 0, c.deferModification(ctx, volumeID, deferredValue, target, nextModificationTime(err))
+		}
 		if isAWSErrorInvalidParameter(err) {
 			// Wrap error to preserve original message from AWS as to why this was an invalid argument
 			return 0, fmt.Errorf("%w: %w", ErrInvalidArgument, err)
@@ -1140,7 +1208,7 @@ func (c *cloud) ResizeOrModifyDisk(ctx context.Context, volumeID string, newSize
 		}
 	}
 	// Perform one final check on the volume
-	return c.checkDesiredState(ctx, volumeID, newSizeGiB, options)
+	return done(c.checkDesiredState(ctx, volumeID, newSizeGiB, options))
 }
 
 func (c *cloud) DeleteDisk(ctx context.Context, volumeID string) (bool, error) {
@@ -1157,7 +1225,7 @@ func (c *cloud) DeleteDisk(ctx context.Context, volumeID string) (bool, error) {
 }
 
 // execBatchDescribeInstances executes a batched DescribeInstances API call.
-func execBatchDescribeInstances(svc util.EC2API, input []string, cache expiringcache.ExpiringCache[string, struct{}]) (map[string]*types.Instance, error) {
+func execBatchDescribeInstances(ctx context.Context, svc util.EC2API, input []string, cache expiringcache.ExpiringCache[string, struct{}]) (map[string]*types.Instance, error) {
 	goodInstances, badInstances := removeLikelyBadIds(cache, input)
 
 	klog.V(7).InfoS("execBatchDescribeInstances", "instanceIds", goodInstances)
@@ -1165,7 +1233,7 @@ func execBatchDescribeInstances(svc util.EC2API, input []string, cache expiringc
 		InstanceIds: goodInstances,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), batchDescribeTimeout)
+	ctx, cancel := context.WithTimeout(ctx, batchDescribeTimeout)
 	defer cancel()
 
 	var resp []types.Instance
@@ -1211,7 +1279,7 @@ func execBatchDescribeInstances(svc util.EC2API, input []string, cache expiringc
 }
 
 // batchDescribeInstances processes a DescribeInstances request by queuing the task and waiting for the result.
-func (c *cloud) batchDescribeInstances(request *ec2.DescribeInstancesInput) (*types.Instance, error) {
+func (c *cloud) batchDescribeInstances(ctx context.Context, request *ec2.DescribeInstancesInput) (*types.Instance, error) {
 	var task string
 
 	if len(request.InstanceIds) == 1 && request.InstanceIds[0] != "" {
@@ -1220,12 +1288,7 @@ func (c *cloud) batchDescribeInstances(request *ec2.DescribeInstancesInput) (*ty
 		return nil, fmt.Errorf("%w: batchDescribeInstances: request: %v", ErrInvalidRequest, request)
 	}
 
-	ch := make(chan batcher.BatchResult[*types.Instance])
-
-	b := c.bm.instanceIDBatcher
-	b.AddTask(task, ch)
-
-	r := <-ch
+	r := awaitBatchResult(ctx, "DescribeInstances", c.bm.instanceIDBatcher, task)
 
 	if r.Err != nil {
 		return nil, r.Err
@@ -1520,20 +1583,20 @@ func (c *cloud) IsVolumeInitialized(ctx context.Context, volumeID string) (bool,
 	switch {
 	// Case 1: We've never called DVS for volume. Call DVS ASAP.
 	case !ok:
-		volumeStatusItem, err = c.describeVolumeStatus(volumeID, true /* callASAP */)
+		volumeStatusItem, err = c.describeVolumeStatus(ctx, volumeID, true /* callASAP */)
 	// Case 2: We already know volume is initialized. Don't call DVS.
 	case volInit.initialized:
 		return true, nil
 	// Case 3: We know volume is initializing, but there is no SLA. Call DVS eventually during next slow batch.
 	case volInit.estimatedInitializationTime.IsZero():
-		volumeStatusItem, err = c.describeVolumeStatus(volumeID, false /* callASAP */)
+		volumeStatusItem, err = c.describeVolumeStatus(ctx, volumeID, false /* callASAP */)
 	// Case 4: We have an estimated time for initialization. Wait to call DVS again until then unless RPC ctx is done.
 	case !volInit.initialized:
 		util.WaitUntilTimeOrContext(ctx, volInit.estimatedInitializationTime)
 		if err := ctx.Err(); err != nil {
 			return false, err
 		}
-		volumeStatusItem, err = c.describeVolumeStatus(volumeID, true /* callASAP */)
+		volumeStatusItem, err = c.describeVolumeStatus(ctx, volumeID, true /* callASAP */)
 	}
 	if err != nil {
 		return false, err
@@ -1576,13 +1639,13 @@ func isVolumeStatusInitializing(vsi types.VolumeStatusItem) bool {
 	return false
 }
 
-func execBatchDescribeVolumeStatus(svc util.EC2API, input []string) (map[string]*types.VolumeStatusItem, error) {
+func execBatchDescribeVolumeStatus(ctx context.Context, svc util.EC2API, input []string) (map[string]*types.VolumeStatusItem, error) {
 	klog.V(7).InfoS("execBatchDescribeVolumeStatus", "volumeIds", input)
 	request := &ec2.DescribeVolumeStatusInput{
 		VolumeIds: input,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), batchDescribeTimeout)
+	ctx, cancel := context.WithTimeout(ctx, batchDescribeTimeout)
 	defer cancel()
 
 	var volumeStatusItems []types.VolumeStatusItem
@@ -1613,18 +1676,14 @@ func execBatchDescribeVolumeStatus(svc util.EC2API, input []string) (map[string]
 
 // describeVolumeStatus will return the VolumeStatusItem associated with volumeID from EC2 DescribeVolumeStatus
 // Set callASAP to true if you need status within seconds (Otherwise it may take minutes).
-func (c *cloud) describeVolumeStatus(volumeID string, callASAP bool) (*types.VolumeStatusItem, error) {
-	ch := make(chan batcher.BatchResult[*types.VolumeStatusItem])
-
+func (c *cloud) describeVolumeStatus(ctx context.Context, volumeID string, callASAP bool) (*types.VolumeStatusItem, error) {
 	var b *batcher.Batcher[string, *types.VolumeStatusItem]
 	if callASAP {
 		b = c.bm.volumeStatusIDBatcherFast
 	} else {
 		b = c.bm.volumeStatusIDBatcherSlow
 	}
-	b.AddTask(volumeID, ch)
-
-	r := <-ch
+	r := awaitBatchResult(ctx, "DescribeVolumeStatus", b, volumeID)
 
 	if r.Err != nil {
 		return nil, r.Err
@@ -1706,7 +1765,7 @@ func (c *cloud) WaitForAttachmentState(ctx context.Context, expectedState types.
 					klog.ErrorS(err, "WaitForAttachmentState: failed to detach stuck volume", "volumeID", volumeID, "instanceID", expectedInstance)
 					return false, err
 				}
-				return false, fmt.Errorf("%q stuck in attaching state for longer than %v", volumeID, stuckAttachingTimeout)
+				return false, fmt.Errorf("%w: %q stuck in attaching state for longer than %v", ErrAttachmentStuck, volumeID, stuckAttachingTimeout)
 			}
 
 			device := aws.ToString(attachment.Device)
@@ -1759,7 +1818,17 @@ func (c *cloud) WaitForAttachmentState(ctx context.Context, expectedState types.
 		return false, nil
 	}
 
-	return attachment, wait.ExponentialBackoffWithContext(ctx, c.vwp.attachmentBackoff, verifyVolumeFunc)
+	ctx, span := startSpan(ctx, "WaitForAttachmentState", trace.WithAttributes(
+		attribute.String("volume.id", volumeID),
+		attribute.String("instance.id", expectedInstance),
+		attribute.String("attachment.state", string(expectedState)),
+	))
+	defer span.End()
+	err := wait.ExponentialBackoffWithContext(ctx, c.vwp.attachmentBackoff, tracePolls("WaitForAttachmentState", verifyVolumeFunc))
+	if err != nil {
+		recordSpanError(span, err)
+	}
+	return attachment, err
 }
 
 func (c *cloud) GetDiskByName(ctx context.Context, name string, capacityBytes int64) (*Disk, error) {
@@ -1807,6 +1876,14 @@ func (c *cloud) GetDiskByID(ctx context.Context, volumeID string) (*Disk, error)
 		OutpostArn:       aws.ToString(volume.OutpostArn),
 		Attachments:      getVolumeAttachmentsList(*volume),
 		KmsKeyID:         aws.ToString(volume.KmsKeyId),
+		IOPS:             aws.ToInt32(volume.Iops),
+		Encrypted:        aws.ToBool(volume.Encrypted),
+		VolumeType:       string(volume.VolumeType),
+		Throughput:       aws.ToInt32(volume.Throughput),
+		Tags:             make(map[string]string, len(volume.Tags)),
+	}
+	for _, tag := range volume.Tags {
+		disk.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
 	}
 
 	if volume.Size != nil {
@@ -1816,6 +1893,48 @@ func (c *cloud) GetDiskByID(ctx context.Context, volumeID string) (*Disk, error)
 	return disk, nil
 }
 
+// ListDisks returns the disks with all the given tags. An empty tag value matches any value.
+func (c *cloud) ListDisks(ctx context.Context, tags map[string]string) ([]*Disk, error) {
+	request := &ec2.DescribeVolumesInput{}
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		value := tags[key]
+		if value == "" {
+			request.Filters = append(request.Filters, types.Filter{
+				Name:   aws.String("tag-key"),
+				Values: []string{key},
+			})
+		} else {
+			request.Filters = append(request.Filters, types.Filter{
+				Name:   aws.String("tag:" + key),
+				Values: []string{value},
+			})
+		}
+	}
+
+	volumes, err := describeVolumes(ctx, c.ec2, request)
+	if err != nil {
+		return nil, err
+	}
+
+	disks := make([]*Disk, 0, len(volumes))
+	for _, volume := range volumes {
+		disk := &Disk{
+			VolumeID:         aws.ToString(volume.VolumeId),
+			CapacityGiB:      aws.ToInt32(volume.Size),
+			AvailabilityZone: aws.ToString(volume.AvailabilityZone),
+			OutpostArn:       aws.ToString(volume.OutpostArn),
+			Attachments:      getVolumeAttachmentsList(volume),
+			Tags:             make(map[string]string, len(volume.Tags)),
+			CreateTime:       aws.ToTime(volume.CreateTime),
+		}
+		for _, tag := range volume.Tags {
+			disk.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+		disks = append(disks, disk)
+	}
+	return disks, nil
+}
+
 func (c *cloud) GetVolumeIDByNodeAndDevice(ctx context.Context, nodeID string, deviceName string) (string, error) {
 	instance, err := c.getInstance(ctx, nodeID)
 	if err != nil {
@@ -1864,7 +1983,7 @@ func getInstanceIDFromAssociatedResource(arn string) (string, error) {
 }
 
 // execBatchDescribeSnapshots executes a batched DescribeSnapshots API call depending on the type of batcher.
-func execBatchDescribeSnapshots(svc util.EC2API, input []string, batcher snapshotBatcherType, cache expiringcache.ExpiringCache[string, struct{}]) (map[string]*types.Snapshot, error) {
+func execBatchDescribeSnapshots(ctx context.Context, svc util.EC2API, input []string, batcher snapshotBatcherType, cache expiringcache.ExpiringCache[string, struct{}]) (map[string]*types.Snapshot, error) {
 	goodSnapshots, badSnapshots := removeLikelyBadIds(cache, input)
 
 	var request *ec2.DescribeSnapshotsInput
@@ -1892,7 +2011,7 @@ func execBatchDescribeSnapshots(svc util.EC2API, input []string, batcher snapsho
 		return nil, errors.New("execBatchDescribeSnapshots: unsupported request type")
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), batchDescribeTimeout)
+	ctx, cancel := context.WithTimeout(ctx, batchDescribeTimeout)
 	defer cancel()
 
 	var resp []types.Snapshot
@@ -1939,7 +2058,7 @@ func execBatchDescribeSnapshots(svc util.EC2API, input []string, batcher snapsho
 
 // batchDescribeSnapshots processes a DescribeSnapshots request. Depending on the request,
 // it determines the appropriate batcher to use, queues the task, and waits for the result.
-func (c *cloud) batchDescribeSnapshots(request *ec2.DescribeSnapshotsInput) (*types.Snapshot, error) {
+func (c *cloud) batchDescribeSnapshots(ctx context.Context, request *ec2.DescribeSnapshotsInput) (*types.Snapshot, error) {
 	var b *batcher.Batcher[string, *types.Snapshot]
 	var task string
 
@@ -1956,11 +2075,7 @@ func (c *cloud) batchDescribeSnapshots(request *ec2.DescribeSnapshotsInput) (*ty
 		return nil, fmt.Errorf("%w: batchDescribeSnapshots: request: %v", ErrInvalidRequest, request)
 	}
 
-	ch := make(chan batcher.BatchResult[*types.Snapshot])
-
-	b.AddTask(task, ch)
-
-	r := <-ch
+	r := awaitBatchResult(ctx, "DescribeSnapshots", b, task)
 
 	if r.Err != nil {
 		return nil, r.Err
@@ -2006,7 +2121,7 @@ func (c *cloud) CreateSnapshot(ctx context.Context, volumeID string, snapshotOpt
 	}
 	tagSpec := types.TagSpecification{
 		ResourceType: types.ResourceTypeSnapshot,
-		Tags:         tags,
+		Tags:         withRequestIDTag(ctx, tags),
 	}
 	request = &ec2.CreateSnapshotInput{
 		VolumeId:          aws.String(volumeID),
@@ -2035,9 +2150,20 @@ func (c *cloud) CreateSnapshot(ctx context.Context, volumeID string, snapshotOpt
 		Size:           *res.VolumeSize,
 		CreationTime:   aws.ToTime(res.StartTime),
 		ReadyToUse:     res.State == types.SnapshotStateCompleted,
+		Failed:         res.State == types.SnapshotStateError,
 	}, nil
 }
 
+// withRequestIDTag returns the tags of a volume or snapshot created for the CSI request of ctx, with
+// the ID of the request if there is room left for it. Batched requests have no single ID to tag.
+func withRequestIDTag(ctx context.Context, tags []types.Tag) []types.Tag {
+	requestID := util.RequestID(ctx)
+	if requestID == "" || strings.Contains(requestID, ",") || len(tags) >= maxResourceTags {
+		return tags
+	}
+	return append(tags, types.Tag{Key: aws.String(RequestIDTagKey), Value: aws.String(requestID)})
+}
+
 func (c *cloud) LockSnapshot(ctx context.Context, lockOptions *SnapshotLockOptions) error {
 	lockSnapshotInput := ec2.LockSnapshotInput{
 		SnapshotId:     lockOptions.SnapshotId,
@@ -2151,12 +2277,17 @@ func (c *cloud) ec2SnapshotResponseToStruct(ec2Snapshot types.Snapshot) *Snapsho
 		SourceVolumeID: aws.ToString(ec2Snapshot.VolumeId),
 		Size:           snapshotSize,
 		CreationTime:   *ec2Snapshot.StartTime,
+		Tags:           make(map[string]string, len(ec2Snapshot.Tags)),
+	}
+	for _, tag := range ec2Snapshot.Tags {
+		snapshot.Tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
 	}
 	if ec2Snapshot.State == types.SnapshotStateCompleted {
 		snapshot.ReadyToUse = true
 	} else {
 		snapshot.ReadyToUse = false
 	}
+	snapshot.Failed = ec2Snapshot.State == types.SnapshotStateError
 
 	return snapshot
 }
@@ -2254,7 +2385,7 @@ func (c *cloud) getVolume(ctx context.Context, request *ec2.DescribeVolumesInput
 		}
 		return &volumes[0], nil
 	} else {
-		return c.batchDescribeVolumes(request)
+		return c.batchDescribeVolumes(ctx, request)
 	}
 }
 
@@ -2302,7 +2433,7 @@ func (c *cloud) getInstance(ctx context.Context, nodeID string) (*types.Instance
 
 		return &instances[0], nil
 	} else {
-		return c.batchDescribeInstances(request)
+		return c.batchDescribeInstances(ctx, request)
 	}
 }
 
@@ -2359,6 +2490,70 @@ func (c *cloud) getInstancesPatchingBatch(ctx context.Context, nodeIDs []string)
 	return instances, nil
 }
 
+// GetInstanceTypesPatching returns the instance type info of each instance type in `instanceTypes`. The instance
+// types are described in batches of size up to `maxInstanceTypesDescribed`.
+func (c *cloud) GetInstanceTypesPatching(ctx context.Context, instanceTypes []string) ([]types.InstanceTypeInfo, error) {
+	var allInstanceTypes []types.InstanceTypeInfo
+
+	for i := 0; i < len(instanceTypes); i += maxInstanceTypesDescribed {
+		end := min(i+maxInstanceTypesDescribed, len(instanceTypes))
+
+		request := &ec2.DescribeInstanceTypesInput{}
+		for _, instanceType := range instanceTypes[i:end] {
+			request.InstanceTypes = append(request.InstanceTypes, types.InstanceType(instanceType))
+		}
+		for {
+			response, err := c.ec2.DescribeInstanceTypes(ctx, request)
+			if err != nil {
+				return nil, fmt.Errorf("error describing AWS instance types: %w", err)
+			}
+			allInstanceTypes = append(allInstanceTypes, response.InstanceTypes...)
+
+			if response.NextToken == nil {
+				break
+			}
+			request.NextToken = response.NextToken
+		}
+	}
+
+	return allInstanceTypes, nil
+}
+
+// GetAttachedVolumesPatching returns the volumes attached to each node ID in `nodeIDs` and uses pagination
+// to get volumes for large clusters. The nodes are filtered on in batches of size up to `maxFilterValues`.
+func (c *cloud) GetAttachedVolumesPatching(ctx context.Context, nodeIDs []string) ([]*types.Volume, error) {
+	var allVolumes []*types.Volume
+
+	for i := 0; i < len(nodeIDs); i += maxFilterValues {
+		end := min(i+maxFilterValues, len(nodeIDs))
+
+		request := &ec2.DescribeVolumesInput{
+			Filters: []types.Filter{
+				{
+					Name:   aws.String("attachment.instance-id"),
+					Values: nodeIDs[i:end],
+				},
+			},
+		}
+		for {
+			response, err := c.ec2.DescribeVolumes(ctx, request)
+			if err != nil {
+				return nil, fmt.Errorf("error listing AWS volumes: %w", err)
+			}
+			for _, volume := range response.Volumes {
+				allVolumes = append(allVolumes, &volume)
+			}
+
+			if response.NextToken == nil {
+				break
+			}
+			request.NextToken = response.NextToken
+		}
+	}
+
+	return allVolumes, nil
+}
+
 func describeSnapshots(ctx context.Context, svc util.EC2API, request *ec2.DescribeSnapshotsInput) ([]types.Snapshot, error) {
 	var snapshots []types.Snapshot
 	var nextToken *string
@@ -2392,7 +2587,7 @@ func (c *cloud) getSnapshot(ctx context.Context, request *ec2.DescribeSnapshotsI
 		}
 		return &snapshots[0], nil
 	} else {
-		return c.batchDescribeSnapshots(request)
+		return c.batchDescribeSnapshots(ctx, request)
 	}
 }
 
@@ -2637,6 +2832,13 @@ func isAWSErrorVolumeModificationSizeLimitExceeded(err error) bool {
 	return isAWSError(err, "VolumeModificationSizeLimitExceeded")
 }
 
+// isAWSErrorIncorrectModificationState checks if the error is an IncorrectModificationState error.
+// This error is reported when a volume cannot be modified yet, because it was modified too many times
+// recently or its last modification is not completed.
+func isAWSErrorIncorrectModificationState(err error) bool {
+	return isAWSError(err, "IncorrectModificationState")
+}
+
 // isAWSErrorVolumeLimitExceeded checks if the error is a VolumeLimitExceeded error.
 // This error is reported when the limit on the amount of volume storage is exceeded.
 func isAWSErrorVolumeLimitExceeded(err error) bool {
@@ -2699,7 +2901,10 @@ func (c *cloud) checkDesiredState(ctx context.Context, volumeID string, desiredS
 
 // waitForVolumeModification waits for a volume modification to finish.
 func (c *cloud) waitForVolumeModification(ctx context.Context, volumeID string) error {
-	waitErr := wait.ExponentialBackoff(c.vwp.modificationBackoff, func() (bool, error) {
+	ctx, span := startSpan(ctx, "waitForVolumeModification", trace.WithAttributes(attribute.String("volume.id", volumeID)))
+	defer span.End()
+
+	poll := tracePolls("waitForVolumeModification", func(ctx context.Context) (bool, error) {
 		m, err := c.getLatestVolumeModification(ctx, volumeID, true)
 		// Consider volumes that have never been modified as done
 		if err != nil && errors.Is(err, ErrVolumeNotBeingModified) {
@@ -2715,8 +2920,12 @@ func (c *cloud) waitForVolumeModification(ctx context.Context, volumeID string)
 
 		return false, nil
 	})
+	waitErr := wait.ExponentialBackoff(c.vwp.modificationBackoff, func() (bool, error) {
+		return poll(ctx)
+	})
 
 	if waitErr != nil {
+		recordSpanError(span, waitErr)
 		return waitErr
 	}
 
@@ -2770,7 +2979,7 @@ func (c *cloud) getLatestVolumeModification(ctx context.Context, volumeID string
 
 		return &volumeMods[len(volumeMods)-1], nil
 	} else {
-		return c.batchDescribeVolumesModifications(request)
+		return c.batchDescribeVolumesModifications(ctx, request)
 	}
 }
 
@@ -2915,7 +3124,7 @@ func (c *cloud) validateModifyVolume(ctx context.Context, volumeID string, newSi
 	}
 
 	if latestMod != nil && string(latestMod.ModificationState) == string(types.VolumeModificationStateOptimizing) {
-		return true, 0, fmt.Errorf("volume %q in OPTIMIZING state, cannot currently modify", volumeID)
+		return true, 0, fmt.Errorf("%w, cannot currently modify volume %q", errVolumeOptimizing, volumeID)
 	}
 
 	return true, 0, nil
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"math"
 	"reflect"
 	"strings"
@@ -282,7 +283,7 @@ func executeDescribeVolumesTest(t *testing.T, c *cloud, volumeIDs, volumeNames [
 		e[i] = make(chan error, 1)
 		go func(resultCh chan *types.Volume, errCh chan error) {
 			defer wg.Done()
-			volume, err := c.batchDescribeVolumes(request)
+			volume, err := c.batchDescribeVolumes(context.Background(), request)
 			if err != nil {
 				errCh <- err
 				return
@@ -397,7 +398,7 @@ func executeDescribeInstancesTest(t *testing.T, c *cloud, instanceIDs []string,
 
 		go func(resultCh chan types.Instance, errCh chan error) {
 			defer wg.Done()
-			instance, err := c.batchDescribeInstances(request)
+			instance, err := c.batchDescribeInstances(context.Background(), request)
 			if err != nil {
 				errCh <- err
 				return
@@ -582,7 +583,7 @@ func executeDescribeSnapshotsTest(t *testing.T, c *cloud, snapshotIDs, snapshotN
 
 		go func(resultCh chan *types.Snapshot, errCh chan error) {
 			defer wg.Done()
-			snapshot, err := c.batchDescribeSnapshots(request)
+			snapshot, err := c.batchDescribeSnapshots(context.Background(), request)
 			if err != nil {
 				errCh <- err
 				return
@@ -793,7 +794,7 @@ func executeDescribeVolumesModificationsTest(t *testing.T, c *cloud, volumeIDs [
 
 		go func(resultCh chan types.VolumeModification, errCh chan error) {
 			defer wg.Done()
-			volumeModification, err := c.batchDescribeVolumesModifications(request)
+			volumeModification, err := c.batchDescribeVolumesModifications(context.Background(), request)
 			if err != nil {
 				errCh <- err
 				return
@@ -1484,6 +1485,7 @@ func TestCreateDisk(t *testing.T) {
 				VolumeID:         "vol-test",
 				CapacityGiB:      4,
 				AvailabilityZone: defaultZone,
+				IOPS:             200,
 			},
 			expCreateVolumeInput: &ec2.CreateVolumeInput{
 				Iops: aws.Int32(200),
@@ -2012,6 +2014,9 @@ func TestCreateDisk(t *testing.T) {
 					if tc.expDisk.OutpostArn != disk.OutpostArn {
 						t.Fatalf("CreateDisk() failed: expected outpoustArn %q, got %q", tc.expDisk.OutpostArn, disk.OutpostArn)
 					}
+					if tc.expDisk.IOPS != 0 && tc.expDisk.IOPS != disk.IOPS {
+						t.Fatalf("CreateDisk() failed: expected IOPS %d, got %d", tc.expDisk.IOPS, disk.IOPS)
+					}
 				}
 			}
 
@@ -3030,6 +3035,48 @@ func TestGetDiskByID(t *testing.T) {
 	}
 }
 
+func TestListDisks(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := NewMockEC2API(mockCtrl)
+	c := newCloud(mockEC2)
+
+	expectedInput := &ec2.DescribeVolumesInput{
+		Filters: []types.Filter{
+			{Name: aws.String("tag-key"), Values: []string{"ebs.csi.aws.com/ephemeral-pod-uid"}},
+			{Name: aws.String("tag:kubernetes.io/cluster/test"), Values: []string{"owned"}},
+		},
+	}
+	mockEC2.EXPECT().DescribeVolumes(gomock.Any(), gomock.Eq(expectedInput)).Return(&ec2.DescribeVolumesOutput{
+		Volumes: []types.Volume{
+			{
+				VolumeId:         aws.String("vol-test-1234"),
+				Size:             aws.Int32(10),
+				AvailabilityZone: aws.String(expZone),
+				Attachments: []types.VolumeAttachment{
+					{InstanceId: aws.String("i-1234"), State: types.VolumeAttachmentStateAttached},
+				},
+				Tags: []types.Tag{
+					{Key: aws.String("ebs.csi.aws.com/ephemeral-pod-uid"), Value: aws.String("pod-uid")},
+				},
+			},
+		},
+	}, nil)
+
+	disks, err := c.ListDisks(t.Context(), map[string]string{
+		"kubernetes.io/cluster/test":        "owned",
+		"ebs.csi.aws.com/ephemeral-pod-uid": "",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*Disk{
+		{
+			VolumeID:         "vol-test-1234",
+			CapacityGiB:      10,
+			AvailabilityZone: expZone,
+			Attachments:      []string{"i-1234"},
+			Tags:             map[string]string{"ebs.csi.aws.com/ephemeral-pod-uid": "pod-uid"},
+		},
+	}, disks)
+}
 func TestGetInstanceIDFromHyperPodNode(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -3127,6 +3174,7 @@ func TestCreateSnapshot(t *testing.T) {
 		name            string
 		snapshotName    string
 		snapshotOptions *SnapshotOptions
+		requestID       string
 		expSnapshot     *Snapshot
 		expErr          error
 	}{
@@ -3167,6 +3215,24 @@ func TestCreateSnapshot(t *testing.T) {
 			},
 			expErr: nil,
 		},
+		{
+			name:         "success: request ID",
+			snapshotName: "snap-test-name",
+			snapshotOptions: &SnapshotOptions{
+				Tags: map[string]string{
+					SnapshotNameTagKey: "snap-test-name",
+					AwsEbsDriverTagKey: "true",
+				},
+			},
+			requestID: "request-1",
+			expSnapshot: &Snapshot{
+				SnapshotID:     "snap-test-name",
+				SourceVolumeID: "snap-test-volume",
+				Size:           10,
+				ReadyToUse:     true,
+			},
+			expErr: nil,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -3176,6 +3242,11 @@ func TestCreateSnapshot(t *testing.T) {
 			c := newCloud(mockEC2)
 
 			ctx := t.Context()
+			expTags := maps.Clone(tc.snapshotOptions.Tags)
+			if tc.requestID != "" {
+				ctx = util.WithRequestID(ctx, tc.requestID)
+				expTags[RequestIDTagKey] = tc.requestID
+			}
 
 			mockEC2.EXPECT().CreateSnapshot(testutil.AnyContext(), testutil.EC2Input(&ec2.CreateSnapshotInput{}), testutil.EC2Options()).DoAndReturn(
 				func(ctx context.Context, input *ec2.CreateSnapshotInput, optFns ...func(*ec2.Options)) (*ec2.CreateSnapshotOutput, error) {
@@ -3188,7 +3259,10 @@ func TestCreateSnapshot(t *testing.T) {
 					if len(input.TagSpecifications) != 1 {
 						t.Errorf("Unexpected number of TagSpecifications. Expected: 1, Actual: %d", len(input.TagSpecifications))
 					} else {
-						for expectedTagKey, expectedTagValue := range tc.snapshotOptions.Tags {
+						if len(input.TagSpecifications[0].Tags) != len(expTags) {
+							t.Errorf("Unexpected number of tags. Expected: %d, Actual: %d", len(expTags), len(input.TagSpecifications[0].Tags))
+						}
+						for expectedTagKey, expectedTagValue := range expTags {
 							found := false
 							for _, actualTag := range input.TagSpecifications[0].Tags {
 								if aws.ToString(actualTag.Key) == expectedTagKey && aws.ToString(actualTag.Value) == expectedTagValue {
@@ -3775,6 +3849,150 @@ func TestResizeOrModifyDisk(t *testing.T) {
 	}
 }
 
+func TestResizeOrModifyDiskDeferred(t *testing.T) {
+	future := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+	volume := func(tags ...types.Tag) types.Volume {
+		return types.Volume{
+			VolumeId:         aws.String("vol-test"),
+			Size:             aws.Int32(1),
+			Iops:             aws.Int32(3000),
+			AvailabilityZone: aws.String(defaultZone),
+			VolumeType:       types.VolumeTypeGp3,
+			Tags:             tags,
+		}
+	}
+	deferredTag := func(value string) types.Tag {
+		return types.Tag{Key: aws.String(DeferredModificationKey), Value: aws.String(value)}
+	}
+
+	testCases := []struct {
+		name              string
+		existingVolume    types.Volume
+		modification      *types.VolumeModification
+		modifyVolumeError error
+		reqSizeGiB        int32
+		modifyDiskOptions *ModifyDiskOptions
+		// expDeferred is the expected target in the DeferredModificationKey tag, nil if the tag is not written
+		expDeferred *deferredModification
+		expUntil    time.Time
+		expCleared  bool
+	}{
+		{
+			name:              "modification rate exceeded",
+			existingVolume:    volume(),
+			modifyVolumeError: &smithy.GenericAPIError{Code: "IncorrectModificationState", Message: "You've reached the maximum modification rate per volume limit. You can modify this volume again after 2099-01-01T00:00:00Z."},
+			reqSizeGiB:        2,
+			modifyDiskOptions: &ModifyDiskOptions{},
+			expDeferred:       &deferredModification{sizeGiB: 2},
+			expUntil:          future,
+		},
+		{
+			name:              "modification rate exceeded without next modification time",
+			existingVolume:    volume(),
+			modifyVolumeError: &smithy.GenericAPIError{Code: "IncorrectModificationState", Message: "You've reached the maximum modification rate per volume limit."},
+			modifyDiskOptions: &ModifyDiskOptions{IOPS: 4000},
+			expDeferred:       &deferredModification{options: ModifyDiskOptions{IOPS: 4000}},
+			expUntil:          time.Now().Add(deferredModificationRetryDelay),
+		},
+		{
+			name:              "last modification optimizing",
+			existingVolume:    volume(),
+			modification:      &types.VolumeModification{VolumeId: aws.String("vol-test"), ModificationState: types.VolumeModificationStateOptimizing},
+			modifyDiskOptions: &ModifyDiskOptions{Throughput: 250},
+			expDeferred:       &deferredModification{options: ModifyDiskOptions{Throughput: 250}},
+			expUntil:          time.Now().Add(deferredModificationRetryDelay),
+		},
+		{
+			name:              "queued onto deferred modification",
+			existingVolume:    volume(deferredTag("size=2&until=2099-01-01T00%3A00%3A00Z")),
+			modifyDiskOptions: &ModifyDiskOptions{IOPS: 4000},
+			expDeferred:       &deferredModification{sizeGiB: 2, options: ModifyDiskOptions{IOPS: 4000}},
+			expUntil:          future,
+		},
+		{
+			name:              "same modification as deferred one",
+			existingVolume:    volume(deferredTag("size=2&until=2099-01-01T00%3A00%3A00Z")),
+			reqSizeGiB:        2,
+			modifyDiskOptions: &ModifyDiskOptions{},
+			expUntil:          future,
+		},
+		{
+			name:              "applies deferred modification",
+			existingVolume:    volume(deferredTag("size=2&until=2000-01-01T00%3A00%3A00Z")),
+			modifyDiskOptions: &ModifyDiskOptions{IOPS: 4000},
+			expCleared:        true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			mockEC2 := NewMockEC2API(mockCtrl)
+			c := newCloud(mockEC2)
+
+			mockEC2.EXPECT().DescribeVolumes(testutil.AnyContext(), testutil.EC2Input(&ec2.DescribeVolumesInput{})).Return(
+				&ec2.DescribeVolumesOutput{Volumes: []types.Volume{tc.existingVolume}}, nil)
+			describeModifications := &ec2.DescribeVolumesModificationsOutput{}
+			if tc.modification != nil {
+				describeModifications.VolumesModifications = []types.VolumeModification{*tc.modification}
+			}
+			mockEC2.EXPECT().DescribeVolumesModifications(testutil.AnyContext(), testutil.EC2Input(&ec2.DescribeVolumesModificationsInput{}), testutil.EC2Options()).Return(describeModifications, nil).AnyTimes()
+			mockEC2.EXPECT().DescribeTags(testutil.AnyContext(), testutil.EC2Input(&ec2.DescribeTagsInput{})).Return(&ec2.DescribeTagsOutput{}, nil).AnyTimes()
+			mockEC2.EXPECT().CreateVolume(testutil.AnyContext(), testutil.EC2Input(&ec2.CreateVolumeInput{}), testutil.EC2Options()).Return(nil, &smithy.GenericAPIError{Code: "DryRunOperation"}).AnyTimes()
+			if tc.modifyVolumeError != nil {
+				mockEC2.EXPECT().ModifyVolume(testutil.AnyContext(), testutil.EC2Input(&ec2.ModifyVolumeInput{}), testutil.EC2Options()).Return(nil, tc.modifyVolumeError)
+			}
+
+			var deferredTag string
+			if tc.expDeferred != nil {
+				mockEC2.EXPECT().CreateTags(testutil.AnyContext(), testutil.EC2Input(&ec2.CreateTagsInput{})).DoAndReturn(
+					func(_ context.Context, input *ec2.CreateTagsInput, _ ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error) {
+						require.Len(t, input.Tags, 1)
+						assert.Equal(t, DeferredModificationKey, aws.ToString(input.Tags[0].Key))
+						deferredTag = aws.ToString(input.Tags[0].Value)
+						return &ec2.CreateTagsOutput{}, nil
+					})
+			}
+			if tc.expCleared {
+				mockEC2.EXPECT().ModifyVolume(testutil.AnyContext(), testutil.EC2Input(&ec2.ModifyVolumeInput{}), testutil.EC2Options()).DoAndReturn(
+					func(_ context.Context, input *ec2.ModifyVolumeInput, _ ...func(*ec2.Options)) (*ec2.ModifyVolumeOutput, error) {
+						assert.Equal(t, int32(2), aws.ToInt32(input.Size))
+						assert.Equal(t, int32(4000), aws.ToInt32(input.Iops))
+						return &ec2.ModifyVolumeOutput{VolumeModification: &types.VolumeModification{ModificationState: types.VolumeModificationStateCompleted}}, nil
+					})
+				modifiedVolume := tc.existingVolume
+				modifiedVolume.Size = aws.Int32(2)
+				modifiedVolume.Iops = aws.Int32(4000)
+				mockEC2.EXPECT().DescribeVolumes(testutil.AnyContext(), testutil.EC2Input(&ec2.DescribeVolumesInput{})).Return(
+					&ec2.DescribeVolumesOutput{Volumes: []types.Volume{modifiedVolume}}, nil)
+				mockEC2.EXPECT().DeleteTags(testutil.AnyContext(), &ec2.DeleteTagsInput{
+					Resources: []string{"vol-test"},
+					Tags:      []types.Tag{{Key: aws.String(DeferredModificationKey)}},
+				}).Return(&ec2.DeleteTagsOutput{}, nil)
+			}
+
+			newSize, err := c.ResizeOrModifyDisk(t.Context(), "vol-test", util.GiBToBytes(tc.reqSizeGiB), tc.modifyDiskOptions)
+			if tc.expCleared {
+				require.NoError(t, err)
+				assert.Equal(t, int32(2), newSize)
+				return
+			}
+
+			var deferredErr *ModificationDeferredError
+			require.ErrorAs(t, err, &deferredErr)
+			assert.Equal(t, "vol-test", deferredErr.VolumeID)
+			assert.WithinDuration(t, tc.expUntil, deferredErr.Until, time.Second)
+			if tc.expDeferred != nil {
+				deferred, parseErr := parseDeferredModification(deferredTag)
+				require.NoError(t, parseErr)
+				assert.Equal(t, deferredErr.Until, deferred.until)
+				deferred.until = time.Time{}
+				assert.Equal(t, tc.expDeferred, deferred)
+			}
+		})
+	}
+}
+
 func TestModifyTags(t *testing.T) {
 	validTagsToAddInput := map[string]string{
 		"key1": "value1",
@@ -3883,6 +4101,36 @@ func TestModifyTags(t *testing.T) {
 	}
 }
 
+func TestModifyResourceTags(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	mockEC2 := NewMockEC2API(mockCtrl)
+	c := newCloud(mockEC2)
+
+	resourceIDs := make([]string, 0, maxTaggedResourcesPerRequest+1)
+	for i := range maxTaggedResourcesPerRequest + 1 {
+		resourceIDs = append(resourceIDs, fmt.Sprintf("snap-%d", i))
+	}
+	var deleted, created []int
+	mockEC2.EXPECT().DeleteTags(testutil.AnyContext(), testutil.EC2Input(&ec2.DeleteTagsInput{})).DoAndReturn(
+		func(_ context.Context, input *ec2.DeleteTagsInput, _ ...func(*ec2.Options)) (*ec2.DeleteTagsOutput, error) {
+			deleted = append(deleted, len(input.Resources))
+			return &ec2.DeleteTagsOutput{}, nil
+		}).Times(2)
+	mockEC2.EXPECT().CreateTags(testutil.AnyContext(), testutil.EC2Input(&ec2.CreateTagsInput{})).DoAndReturn(
+		func(_ context.Context, input *ec2.CreateTagsInput, _ ...func(*ec2.Options)) (*ec2.CreateTagsOutput, error) {
+			created = append(created, len(input.Resources))
+			return &ec2.CreateTagsOutput{}, nil
+		}).Times(2)
+
+	err := c.ModifyResourceTags(t.Context(), resourceIDs, ModifyTagsOptions{
+		TagsToAdd:    map[string]string{"team": "storage"},
+		TagsToDelete: []string{"old"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{maxTaggedResourcesPerRequest, 1}, deleted)
+	assert.Equal(t, []int{maxTaggedResourcesPerRequest, 1}, created)
+}
+
 func TestGetSnapshotByName(t *testing.T) {
 	testCases := []struct {
 		name            string
@@ -3982,6 +4230,7 @@ func TestGetSnapshotByID(t *testing.T) {
 	testCases := []struct {
 		name        string
 		snapshotID  string
+		state       types.SnapshotState
 		expSnapshot *Snapshot
 		expErr      error
 	}{
@@ -3997,6 +4246,18 @@ func TestGetSnapshotByID(t *testing.T) {
 			},
 			expErr: nil,
 		},
+		{
+			name:       "success: error state",
+			snapshotID: "snap-test-name",
+			state:      types.SnapshotStateError,
+			expSnapshot: &Snapshot{
+				SnapshotID:     "snap-test-name",
+				SourceVolumeID: "snap-test-volume",
+				Size:           10,
+				CreationTime:   time.Now(),
+				Failed:         true,
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -4005,12 +4266,16 @@ func TestGetSnapshotByID(t *testing.T) {
 			mockEC2 := NewMockEC2API(mockCtrl)
 			c := newCloud(mockEC2)
 
+			state := tc.state
+			if state == "" {
+				state = types.SnapshotStateCompleted
+			}
 			ec2snapshot := types.Snapshot{
 				SnapshotId: aws.String(tc.snapshotID),
 				VolumeId:   aws.String(tc.expSnapshot.SourceVolumeID),
 				VolumeSize: aws.Int32(tc.expSnapshot.Size),
 				StartTime:  aws.Time(tc.expSnapshot.CreationTime),
-				State:      types.SnapshotStateCompleted,
+				State:      state,
 			}
 
 			ctx := t.Context()
@@ -4044,6 +4309,9 @@ func TestGetSnapshotByID(t *testing.T) {
 				if snapshot.ReadyToUse != tc.expSnapshot.ReadyToUse {
 					t.Fatalf("GetSnapshotByID() failed: expected ready to use %t, got %t", tc.expSnapshot.ReadyToUse, snapshot.ReadyToUse)
 				}
+				if snapshot.Failed != tc.expSnapshot.Failed {
+					t.Fatalf("GetSnapshotByID() failed: expected failed %t, got %t", tc.expSnapshot.Failed, snapshot.Failed)
+				}
 			}
 
 			mockCtrl.Finish()
@@ -4334,6 +4602,7 @@ func TestWaitForAttachmentState(t *testing.T) {
 		expectedDevice     string
 		alreadyAssigned    bool
 		expectError        bool
+		expectedErr        error
 		associatedResource *string
 		expectedCardIndex  *int32
 	}{
@@ -4471,6 +4740,7 @@ func TestWaitForAttachmentState(t *testing.T) {
 			expectedDevice:   defaultPath,
 			alreadyAssigned:  false,
 			expectError:      true,
+			expectedErr:      ErrAttachmentStuck,
 		},
 		{
 			name:              "success: attached with card index",
@@ -4614,6 +4884,9 @@ func TestWaitForAttachmentState(t *testing.T) {
 				if err == nil {
 					t.Fatal("WaitForAttachmentState() failed: expected error, got nothing")
 				}
+				if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+					t.Fatalf("WaitForAttachmentState() failed: expected error %v, got %v", tc.expectedErr, err)
+				}
 			} else {
 				if err != nil {
 					t.Fatalf("WaitForAttachmentState() failed: expected no error, got %v", err)
@@ -4747,11 +5020,11 @@ func TestIsVolumeInitialized(t *testing.T) {
 				ec2:                   mockEC2,
 				volumeInitializations: volInitCache,
 				bm: &batcherManager{
-					volumeStatusIDBatcherFast: batcher.New(500, 0, func(ids []string) (map[string]*types.VolumeStatusItem, error) {
-						return execBatchDescribeVolumeStatus(mockEC2, ids)
+					volumeStatusIDBatcherFast: batcher.New(500, 0, func(ctx context.Context, ids []string) (map[string]*types.VolumeStatusItem, error) {
+						return execBatchDescribeVolumeStatus(ctx, mockEC2, ids)
 					}),
-					volumeStatusIDBatcherSlow: batcher.New(500, testInitializationSleep, func(ids []string) (map[string]*types.VolumeStatusItem, error) {
-						return execBatchDescribeVolumeStatus(mockEC2, ids) // TODO remove test sleeps once Go 1.25 releases with testing/synctest package
+					volumeStatusIDBatcherSlow: batcher.New(500, testInitializationSleep, func(ctx context.Context, ids []string) (map[string]*types.VolumeStatusItem, error) {
+						return execBatchDescribeVolumeStatus(ctx, mockEC2, ids) // TODO remove test sleeps once Go 1.25 releases with testing/synctest package
 					}),
 				},
 			}
@@ -5560,7 +5833,7 @@ func TestExecBatchCaching(t *testing.T) {
 			VolumeIds: []string{"vol-0c7e1a5f8b2d4c6939", "vol-0f8a2c4e6b9d1e5787"},
 		})).Return(nil, errors.New("InvalidVolume.NotFound: vol-0c7e1a5f8b2d4c6939")).Times(1)
 
-		_, err := execBatchDescribeVolumes(mockEC2, []string{"vol-0f8a2c4e6b9d1e5787", "vol-0c7e1a5f8b2d4c6939"}, volumeIDBatcher, cache)
+		_, err := execBatchDescribeVolumes(context.Background(), mockEC2, []string{"vol-0f8a2c4e6b9d1e5787", "vol-0c7e1a5f8b2d4c6939"}, volumeIDBatcher, cache)
 		require.Error(t, err)
 		_, exists := cache.Get("vol-0c7e1a5f8b2d4c6939")
 		assert.True(t, exists, "vol-0c7e1a5f8b2d4c6939 should be cached after error")
@@ -5584,7 +5857,7 @@ func TestExecBatchCaching(t *testing.T) {
 			Volumes: []types.Volume{{VolumeId: aws.String("vol-0c7e1a5f8b2d4c6939")}},
 		}, nil).Times(1)
 
-		result, err := execBatchDescribeVolumes(mockEC2, []string{"vol-0f8a2c4e6b9d1e5787", "vol-0c7e1a5f8b2d4c6939"}, volumeIDBatcher, cache)
+		result, err := execBatchDescribeVolumes(context.Background(), mockEC2, []string{"vol-0f8a2c4e6b9d1e5787", "vol-0c7e1a5f8b2d4c6939"}, volumeIDBatcher, cache)
 		require.NoError(t, err)
 		assert.Len(t, result, 2)
 		_, exists := cache.Get("vol-0c7e1a5f8b2d4c6939")
@@ -5601,7 +5874,7 @@ func TestExecBatchCaching(t *testing.T) {
 			InstanceIds: []string{"i-0c7e1a5f8b2d4c939", "i-0f8a2c4e6b9d1e787"},
 		})).Return(nil, errors.New("InvalidInstanceID.NotFound: i-0c7e1a5f8b2d4c939")).Times(1)
 
-		_, err := execBatchDescribeInstances(mockEC2, []string{"i-0f8a2c4e6b9d1e787", "i-0c7e1a5f8b2d4c939"}, cache)
+		_, err := execBatchDescribeInstances(context.Background(), mockEC2, []string{"i-0f8a2c4e6b9d1e787", "i-0c7e1a5f8b2d4c939"}, cache)
 		require.Error(t, err)
 		_, exists := cache.Get("i-0c7e1a5f8b2d4c939")
 		assert.True(t, exists, "i-0c7e1a5f8b2d4c939 should be cached after error")
@@ -5625,7 +5898,7 @@ func TestExecBatchCaching(t *testing.T) {
 			Reservations: []types.Reservation{{Instances: []types.Instance{{InstanceId: aws.String("i-0c7e1a5f8b2d4c939")}}}},
 		}, nil).Times(1)
 
-		result, err := execBatchDescribeInstances(mockEC2, []string{"i-0f8a2c4e6b9d1e787", "i-0c7e1a5f8b2d4c939"}, cache)
+		result, err := execBatchDescribeInstances(context.Background(), mockEC2, []string{"i-0f8a2c4e6b9d1e787", "i-0c7e1a5f8b2d4c939"}, cache)
 		require.NoError(t, err)
 		assert.Len(t, result, 2)
 		_, exists := cache.Get("i-0c7e1a5f8b2d4c939")
@@ -5642,7 +5915,7 @@ func TestExecBatchCaching(t *testing.T) {
 			SnapshotIds: []string{"snap-0c7e1a5f8b2d4c939", "snap-0f8a2c4e6b9d1e787"},
 		})).Return(nil, errors.New("InvalidSnapshot.NotFound: snap-0c7e1a5f8b2d4c939")).Times(1)
 
-		_, err := execBatchDescribeSnapshots(mockEC2, []string{"snap-0f8a2c4e6b9d1e787", "snap-0c7e1a5f8b2d4c939"}, snapshotIDBatcher, cache)
+		_, err := execBatchDescribeSnapshots(context.Background(), mockEC2, []string{"snap-0f8a2c4e6b9d1e787", "snap-0c7e1a5f8b2d4c939"}, snapshotIDBatcher, cache)
 		require.Error(t, err)
 		_, exists := cache.Get("snap-0c7e1a5f8b2d4c939")
 		assert.True(t, exists, "snap-0c7e1a5f8b2d4c939 should be cached after error")
@@ -5666,7 +5939,7 @@ func TestExecBatchCaching(t *testing.T) {
 			Snapshots: []types.Snapshot{{SnapshotId: aws.String("snap-0c7e1a5f8b2d4c939")}},
 		}, nil).Times(1)
 
-		result, err := execBatchDescribeSnapshots(mockEC2, []string{"snap-0f8a2c4e6b9d1e787", "snap-0c7e1a5f8b2d4c939"}, snapshotIDBatcher, cache)
+		result, err := execBatchDescribeSnapshots(context.Background(), mockEC2, []string{"snap-0f8a2c4e6b9d1e787", "snap-0c7e1a5f8b2d4c939"}, snapshotIDBatcher, cache)
 		require.NoError(t, err)
 		assert.Len(t, result, 2)
 		_, exists := cache.Get("snap-0c7e1a5f8b2d4c939")
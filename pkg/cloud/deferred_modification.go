@@ -0,0 +1,185 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"k8s.io/klog/v2"
+)
+
+// deferredModificationRetryDelay is how long a modification is deferred when EBS does not tell when
+// the volume can be modified again.
+const deferredModificationRetryDelay = time.Hour
+
+// nextModificationTimeRegex matches the time of the next allowed modification in the error EC2
+// returns when a volume was modified too many times recently.
+var nextModificationTimeRegex = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z`)
+
+// ModificationDeferredError is returned by ResizeOrModifyDisk when EBS does not allow the volume to
+// be modified before Until. The target of the modification is recorded in the DeferredModificationKey
+// tag of the volume, and applied by the first ResizeOrModifyDisk of the volume after Until.
+type ModificationDeferredError struct {
+	VolumeID string
+	Until    time.Time
+}
+
+func (e *ModificationDeferredError) Error() string {
+	return fmt.Sprintf("modification of volume %q deferred until %s, because EBS does not allow the volume to be modified again yet",
+		e.VolumeID, e.Until.UTC().Format(time.RFC3339))
+}
+
+// deferredModification is the target of a deferred volume modification.
+type deferredModification struct {
+	sizeGiB int32
+	options ModifyDiskOptions
+	until   time.Time
+}
+
+// parseDeferredModification parses the value of the DeferredModificationKey tag of a volume.
+func parseDeferredModification(value string) (*deferredModification, error) {
+	values, err := url.ParseQuery(value)
+	if err != nil {
+		return nil, err
+	}
+	parseInt32 := func(key string) (int32, error) {
+		if values.Get(key) == "" {
+			return 0, nil
+		}
+		i, err := strconv.ParseInt(values.Get(key), 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q: %w", key, values.Get(key), err)
+		}
+		return int32(i), nil
+	}
+
+	m := &deferredModification{options: ModifyDiskOptions{VolumeType: values.Get("type")}}
+	if m.sizeGiB, err = parseInt32("size"); err != nil {
+		return nil, err
+	}
+	if m.options.IOPS, err = parseInt32("iops"); err != nil {
+		return nil, err
+	}
+	if m.options.IOPSPerGB, err = parseInt32("iopsPerGB"); err != nil {
+		return nil, err
+	}
+	if m.options.Throughput, err = parseInt32("throughput"); err != nil {
+		return nil, err
+	}
+	if m.until, err = time.Parse(time.RFC3339, values.Get("until")); err != nil {
+		return nil, fmt.Errorf("invalid until %q: %w", values.Get("until"), err)
+	}
+	return m, nil
+}
+
+// String returns the value of the DeferredModificationKey tag of m.
+func (m *deferredModification) String() string {
+	values := url.Values{}
+	setInt32 := func(key string, i int32) {
+		if i != 0 {
+			values.Set(key, strconv.FormatInt(int64(i), 10))
+		}
+	}
+	setInt32("size", m.sizeGiB)
+	setInt32("iops", m.options.IOPS)
+	setInt32("iopsPerGB", m.options.IOPSPerGB)
+	setInt32("throughput", m.options.Throughput)
+	if m.options.VolumeType != "" {
+		values.Set("type", m.options.VolumeType)
+	}
+	values.Set("until", m.until.UTC().Format(time.RFC3339))
+	return values.Encode()
+}
+
+// merge returns the target of a modification to sizeGiB and options that also applies m.
+// The values of sizeGiB and options take precedence over the ones of m, except that the volume is
+// never shrunk.
+func (m *deferredModification) merge(sizeGiB int32, options *ModifyDiskOptions) (int32, *ModifyDiskOptions) {
+	var merged ModifyDiskOptions
+	if options != nil {
+		merged = *options
+	}
+	if merged.IOPS == 0 && merged.IOPSPerGB == 0 {
+		merged.IOPS = m.options.IOPS
+		merged.IOPSPerGB = m.options.IOPSPerGB
+	}
+	if merged.Throughput == 0 {
+		merged.Throughput = m.options.Throughput
+	}
+	if merged.VolumeType == "" {
+		merged.VolumeType = m.options.VolumeType
+	}
+	return max(sizeGiB, m.sizeGiB), &merged
+}
+
+// deferredModificationFromTags returns the deferred modification recorded in the tags of a volume,
+// or nil if there is none, and the value of its tag. A tag that cannot be parsed is ignored.
+func deferredModificationFromTags(volumeID string, tags []types.Tag) (*deferredModification, string) {
+	for _, tag := range tags {
+		if aws.ToString(tag.Key) != DeferredModificationKey {
+			continue
+		}
+		value := aws.ToString(tag.Value)
+		m, err := parseDeferredModification(value)
+		if err != nil {
+			klog.InfoS("Ignoring invalid deferred modification of volume", "volumeID", volumeID, "value", value, "err", err)
+			return nil, value
+		}
+		return m, value
+	}
+	return nil, ""
+}
+
+// nextModificationTime returns the time at which a volume can be modified again, according to the
+// error EC2 returned for its modification.
+func nextModificationTime(err error) time.Time {
+	if match := nextModificationTimeRegex.FindString(err.Error()); match != "" {
+		if t, parseErr := time.Parse(time.RFC3339, match); parseErr == nil && time.Now().Before(t) {
+			return t
+		}
+	}
+	return time.Now().Add(deferredModificationRetryDelay)
+}
+
+// deferModification records target in the DeferredModificationKey tag of the volume, unless the tag
+// already has this value, and returns a ModificationDeferredError.
+func (c *cloud) deferModification(ctx context.Context, volumeID string, currentValue string, target deferredModification, until time.Time) error {
+	target.until = until.UTC().Truncate(time.Second)
+	if value := target.String(); value != currentValue {
+		klog.InfoS("Deferring volume modification", "volumeID", volumeID, "target", value)
+		if err := c.ModifyTags(ctx, volumeID, ModifyTagsOptions{TagsToAdd: map[string]string{DeferredModificationKey: value}}); err != nil {
+			return fmt.Errorf("could not defer modification of volume %q: %w", volumeID, err)
+		}
+	}
+	return &ModificationDeferredError{VolumeID: volumeID, Until: target.until}
+}
+
+// clearDeferredModification removes the DeferredModificationKey tag of a volume after its deferred
+// modification was applied. A failure is only logged, as applying the modification again is a no-op.
+func (c *cloud) clearDeferredModification(ctx context.Context, volumeID string) {
+	klog.InfoS("Applied deferred volume modification", "volumeID", volumeID)
+	if err := c.ModifyTags(ctx, volumeID, ModifyTagsOptions{TagsToDelete: []string{DeferredModificationKey}}); err != nil {
+		klog.ErrorS(err, "Could not clear deferred modification of volume", "volumeID", volumeID)
+	}
+}
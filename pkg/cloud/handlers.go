@@ -26,6 +26,7 @@ import (
 	"github.com/aws/smithy-go"
 	"github.com/aws/smithy-go/middleware"
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/metrics"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
 	"k8s.io/klog/v2"
 )
 
@@ -75,25 +76,41 @@ func LogServerErrorsMiddleware() func(*middleware.Stack) error {
 	return func(stack *middleware.Stack) error {
 		return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("LogServerErrorsMiddleware", func(ctx context.Context, input middleware.FinalizeInput, next middleware.FinalizeHandler) (output middleware.FinalizeOutput, metadata middleware.Metadata, err error) {
 			output, metadata, err = next.HandleFinalize(ctx, input)
+			keysAndValues := requestKeysAndValues(ctx, metadata)
 			if err != nil {
 				var apiErr smithy.APIError
 				if errors.As(err, &apiErr) {
 					if _, isThrottleError := retry.DefaultThrottleErrorCodes[apiErr.ErrorCode()]; isThrottleError {
 						// Only log throttle errors under a high verbosity as we expect to see many of them
 						// under normal bursty/high-TPS workloads
-						klog.V(4).ErrorS(apiErr, "Throttle error from AWS API")
+						klog.V(4).ErrorS(apiErr, "Throttle error from AWS API", keysAndValues...)
 					} else {
-						klog.V(3).ErrorS(apiErr, "Error from AWS API")
+						klog.V(3).ErrorS(apiErr, "Error from AWS API", keysAndValues...)
 					}
 				} else {
-					klog.ErrorS(err, "Unknown error attempting to contact AWS API")
+					klog.ErrorS(err, "Unknown error attempting to contact AWS API", keysAndValues...)
 				}
+			} else {
+				klog.V(5).InfoS("AWS API request succeeded", keysAndValues...)
 			}
 			return output, metadata, err
 		}), middleware.After)
 	}
 }
 
+// requestKeysAndValues returns the log keys and values identifying an AWS API request: its operation,
+// the ID AWS assigned to it and the ID of the CSI request it was made for, if any.
+func requestKeysAndValues(ctx context.Context, metadata middleware.Metadata) []any {
+	keysAndValues := []any{"operation", awsmiddleware.GetOperationName(ctx)}
+	if awsRequestID, ok := awsmiddleware.GetRequestIDMetadata(metadata); ok {
+		keysAndValues = append(keysAndValues, "awsRequestID", awsRequestID)
+	}
+	if requestID := util.RequestID(ctx); requestID != "" {
+		keysAndValues = append(keysAndValues, "requestID", requestID)
+	}
+	return keysAndValues
+}
+
 func createLabels(ctx context.Context) map[string]string {
 	operationName := awsmiddleware.GetOperationName(ctx)
 	if operationName == "" {
@@ -27,11 +27,13 @@ type Cloud interface {
 	AttachDisk(ctx context.Context, volumeID string, nodeID string) (devicePath string, err error)
 	DetachDisk(ctx context.Context, volumeID string, nodeID string) (err error)
 	ModifyTags(ctx context.Context, volumeID string, tagOptions ModifyTagsOptions) (err error)
+	ModifyResourceTags(ctx context.Context, resourceIDs []string, tagOptions ModifyTagsOptions) (err error)
 	ResizeOrModifyDisk(ctx context.Context, volumeID string, newSizeBytes int64, options *ModifyDiskOptions) (newSize int32, err error)
 	WaitForAttachmentState(ctx context.Context, expectedState types.VolumeAttachmentState, volumeID string, expectedInstance string, expectedDevice string, alreadyAssigned bool, expectedCardIndex *int32) (*types.VolumeAttachment, error)
 	IsVolumeInitialized(ctx context.Context, volumeID string) (bool, error)
 	GetDiskByName(ctx context.Context, name string, capacityBytes int64) (disk *Disk, err error)
 	GetDiskByID(ctx context.Context, volumeID string) (disk *Disk, err error)
+	ListDisks(ctx context.Context, tags map[string]string) (disks []*Disk, err error)
 	GetVolumeIDByNodeAndDevice(ctx context.Context, nodeID string, deviceName string) (volumeID string, err error)
 	CreateSnapshot(ctx context.Context, volumeID string, snapshotOptions *SnapshotOptions) (snapshot *Snapshot, err error)
 	DeleteSnapshot(ctx context.Context, snapshotID string) (success bool, err error)
@@ -42,5 +44,7 @@ type Cloud interface {
 	AvailabilityZones(ctx context.Context) (map[string]struct{}, error)
 	DryRun(ctx context.Context) error
 	GetInstancesPatching(ctx context.Context, nodeIDs []string) ([]*types.Instance, error)
+	GetInstanceTypesPatching(ctx context.Context, instanceTypes []string) ([]types.InstanceTypeInfo, error)
+	GetAttachedVolumesPatching(ctx context.Context, nodeIDs []string) ([]*types.Volume, error)
 	LockSnapshot(ctx context.Context, lockOptions *SnapshotLockOptions) (err error)
 }
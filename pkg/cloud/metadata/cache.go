@@ -0,0 +1,171 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/metrics"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+)
+
+// bootIDPath is the file holding the ID of the current boot of the host, which is not namespaced.
+var bootIDPath = "/proc/sys/kernel/random/boot_id"
+
+// cachedInstanceIDTimeout is the timeout of the verification of the instance ID of cached metadata by IMDS.
+const cachedInstanceIDTimeout = 10 * time.Second
+
+// cachedMetadata is the content of the metadata cache file.
+type cachedMetadata struct {
+	BootID                 string `json:"bootID"`
+	InstanceID             string `json:"instanceID"`
+	InstanceType           string `json:"instanceType"`
+	Region                 string `json:"region"`
+	AvailabilityZone       string `json:"availabilityZone"`
+	NumAttachedENIs        int    `json:"numAttachedENIs"`
+	NumBlockDeviceMappings int    `json:"numBlockDeviceMappings"`
+	OutpostArn             string `json:"outpostArn,omitempty"`
+}
+
+func currentBootID() (string, error) {
+	bootID, err := os.ReadFile(bootIDPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read boot ID: %w", err)
+	}
+	return strings.TrimSpace(string(bootID)), nil
+}
+
+// saveMetadataCache writes m to path, tied to the current boot of the host.
+func saveMetadataCache(path string, m *Metadata) error {
+	bootID, err := currentBootID()
+	if err != nil {
+		return err
+	}
+	cache := cachedMetadata{
+		BootID:                 bootID,
+		InstanceID:             m.InstanceID,
+		InstanceType:           m.InstanceType,
+		Region:                 m.Region,
+		AvailabilityZone:       m.AvailabilityZone,
+		NumAttachedENIs:        m.NumAttachedENIs,
+		NumBlockDeviceMappings: m.NumBlockDeviceMappings,
+	}
+	if len(m.OutpostArn.Resource) > 0 {
+		cache.OutpostArn = m.OutpostArn.String()
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first, so that a crash never leaves a truncated cache
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create metadata cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write metadata cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write metadata cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write metadata cache file: %w", err)
+	}
+	return nil
+}
+
+// loadMetadataCache reads the Metadata cached in path. It fails if the cache was written during
+// another boot of the host, as the instance type, ENIs and volumes may have changed since.
+func loadMetadataCache(path string) (*Metadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata cache file: %w", err)
+	}
+	var cache cachedMetadata
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata cache file %s: %w", path, err)
+	}
+	bootID, err := currentBootID()
+	if err != nil {
+		return nil, err
+	}
+	if cache.BootID != bootID {
+		return nil, fmt.Errorf("metadata cache file %s of instance %s was written during boot %s, not the current boot %s", path, cache.InstanceID, cache.BootID, bootID)
+	}
+
+	m := &Metadata{
+		InstanceID:             cache.InstanceID,
+		InstanceType:           cache.InstanceType,
+		Region:                 cache.Region,
+		AvailabilityZone:       cache.AvailabilityZone,
+		NumAttachedENIs:        cache.NumAttachedENIs,
+		NumBlockDeviceMappings: cache.NumBlockDeviceMappings,
+	}
+	if cache.OutpostArn != "" {
+		m.OutpostArn, err = arn.Parse(cache.OutpostArn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse outpost arn of metadata cache file %s: %w", path, err)
+		}
+	}
+	return m, nil
+}
+
+// verifyCachedInstanceID checks the instance ID of the Metadata cached in m against the
+// instance ID reported by IMDS or by the Node, when they are reachable even though they could not
+// provide all the metadata. The cache is only trusted if no reachable source disagrees with it.
+func verifyCachedInstanceID(cfg MetadataServiceConfig, m *Metadata) error {
+	if cfg.IMDSClient != nil && os.Getenv("AWS_EC2_METADATA_DISABLED") != "true" && !util.IsHyperPodNode(os.Getenv("CSI_NODE_NAME")) {
+		if svc, err := cfg.IMDSClient(); err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), cachedInstanceIDTimeout)
+			defer cancel()
+			if output, err := svc.GetInstanceIdentityDocument(ctx, &imds.GetInstanceIdentityDocumentInput{}); err == nil {
+				if id := output.InstanceIdentityDocument.InstanceID; id != m.InstanceID {
+					return fmt.Errorf("cached metadata of instance %s does not match instance %s reported by IMDS", m.InstanceID, id)
+				}
+				return nil
+			}
+		}
+	}
+	if cfg.K8sAPIClient != nil {
+		if clientset, err := cfg.K8sAPIClient(); err == nil {
+			if node, err := getNode(clientset); err == nil {
+				if id, err := ParseProviderID(node); err == nil && id != m.InstanceID {
+					return fmt.Errorf("cached metadata of instance %s does not match instance %s of node %s", m.InstanceID, id, node.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// recordCachedMetadata reports through a metric whether the driver runs on cached metadata.
+func recordCachedMetadata(cached bool) {
+	value := 0.0
+	if cached {
+		value = 1
+	}
+	metrics.Recorder().SetGauge(metrics.CachedMetadata, metrics.CachedMetadataHelpText, value, map[string]string{})
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func setBootID(t *testing.T, bootID string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "boot_id")
+	require.NoError(t, os.WriteFile(path, []byte(bootID+"\n"), 0o600))
+	oldBootIDPath := bootIDPath
+	bootIDPath = path
+	t.Cleanup(func() { bootIDPath = oldBootIDPath })
+}
+
+func TestMetadataCache(t *testing.T) {
+	outpostArn, err := arn.Parse("arn:aws:outposts:us-west-2:123456789012:op-0123456789abcdef0")
+	require.NoError(t, err)
+	m := &Metadata{
+		InstanceID:             "i-1234567890abcdef0",
+		InstanceType:           "c5.xlarge",
+		Region:                 "us-west-2",
+		AvailabilityZone:       "us-west-2a",
+		NumAttachedENIs:        2,
+		NumBlockDeviceMappings: 1,
+		OutpostArn:             outpostArn,
+	}
+	cacheFile := filepath.Join(t.TempDir(), "instance-metadata.json")
+
+	setBootID(t, "boot-1")
+	require.NoError(t, saveMetadataCache(cacheFile, m))
+
+	cached, err := loadMetadataCache(cacheFile)
+	require.NoError(t, err)
+	assert.Equal(t, m.InstanceID, cached.InstanceID)
+	assert.Equal(t, m.InstanceType, cached.InstanceType)
+	assert.Equal(t, m.Region, cached.Region)
+	assert.Equal(t, m.AvailabilityZone, cached.AvailabilityZone)
+	assert.Equal(t, m.NumAttachedENIs, cached.NumAttachedENIs)
+	assert.Equal(t, m.NumBlockDeviceMappings, cached.NumBlockDeviceMappings)
+	assert.Equal(t, m.OutpostArn, cached.OutpostArn)
+
+	setBootID(t, "boot-2")
+	_, err = loadMetadataCache(cacheFile)
+	require.ErrorContains(t, err, "not the current boot boot-2")
+
+	_, err = loadMetadataCache(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
+
+func TestNewMetadataServiceCacheFile(t *testing.T) {
+	t.Setenv("CSI_NODE_NAME", "test-node")
+	setBootID(t, "boot-1")
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+			Labels: map[string]string{
+				corev1.LabelInstanceTypeStable: "c5.xlarge",
+				corev1.LabelTopologyRegion:     "us-west-2",
+				corev1.LabelTopologyZone:       "us-west-2a",
+			},
+		},
+		Spec: corev1.NodeSpec{
+			ProviderID: "aws:///us-west-2a/i-1234567890abcdef0",
+		},
+	}
+
+	k8sAvailable := true
+	cfg := MetadataServiceConfig{
+		MetadataSources: []string{SourceK8s},
+		K8sAPIClient: func() (kubernetes.Interface, error) {
+			if !k8sAvailable {
+				return nil, errors.New("K8s API error")
+			}
+			return fake.NewClientset(node), nil
+		},
+		CacheFile: filepath.Join(t.TempDir(), "instance-metadata.json"),
+	}
+
+	// Without a cache file, startup fails when no metadata source is available
+	k8sAvailable = false
+	_, err := NewMetadataService(cfg, "")
+	require.Error(t, err)
+
+	// A successful startup persists the metadata
+	k8sAvailable = true
+	m, err := NewMetadataService(cfg, "")
+	require.NoError(t, err)
+	assert.False(t, m.IsCached())
+
+	// A later startup without metadata source runs on the persisted metadata
+	k8sAvailable = false
+	m, err = NewMetadataService(cfg, "")
+	require.NoError(t, err)
+	assert.True(t, m.IsCached())
+	assert.Equal(t, "i-1234567890abcdef0", m.GetInstanceID())
+	assert.Equal(t, "c5.xlarge", m.GetInstanceType())
+	assert.Equal(t, "us-west-2a", m.GetAvailabilityZone())
+
+	require.Error(t, m.UpdateMetadata())
+	assert.True(t, m.IsCached())
+
+	// The metadata is refreshed once a metadata source is available again
+	k8sAvailable = true
+	require.NoError(t, m.UpdateMetadata())
+	assert.False(t, m.IsCached())
+	assert.Equal(t, "i-1234567890abcdef0", m.GetInstanceID())
+
+	// The cache is only used if the Node, reachable but missing metadata, is the cached instance
+	delete(node.Labels, corev1.LabelTopologyZone)
+	m, err = NewMetadataService(cfg, "")
+	require.NoError(t, err)
+	assert.True(t, m.IsCached())
+	node.Spec.ProviderID = "aws:///us-west-2a/i-0fedcba0987654321"
+	_, err = NewMetadataService(cfg, "")
+	require.Error(t, err)
+
+	// The cache is not used after a reboot
+	setBootID(t, "boot-2")
+	k8sAvailable = false
+	_, err = NewMetadataService(cfg, "")
+	require.Error(t, err)
+}
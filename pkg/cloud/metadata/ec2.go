@@ -0,0 +1,191 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/plugin"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+// EC2Client returns a client of the EC2 API in region.
+type EC2Client func(region string) (util.EC2API, error)
+
+// DefaultEC2Client returns the EC2 client of the plugin, if any, or the default EC2 client.
+var DefaultEC2Client = func(region string) (util.EC2API, error) {
+	cfg, err := config.LoadDefaultConfig(context.Background(), config.WithRegion(region))
+	if err != nil {
+		return nil, err
+	}
+	ec2Options := func(o *ec2.Options) {
+		if endpoint := os.Getenv("AWS_EC2_ENDPOINT"); endpoint != "" {
+			o.BaseEndpoint = &endpoint
+		}
+	}
+	if p := plugin.GetPlugin(); p != nil {
+		if svc := p.GetEC2Client(cfg, ec2Options); svc != nil {
+			return svc, nil
+		}
+	}
+	return ec2.NewFromConfig(cfg, ec2Options), nil
+}
+
+// EC2InstanceInfo returns the Metadata of the node the driver runs on from the EC2 API. The instance ID
+// is parsed from the providerID of the node and the region, if empty, is read from its topology label.
+func EC2InstanceInfo(clientset kubernetes.Interface, ec2Client EC2Client, region string) (*Metadata, error) {
+	node, err := getNode(clientset)
+	if err != nil {
+		return nil, err
+	}
+
+	instanceID, err := ParseProviderID(node)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(instanceID, "i-") {
+		return nil, fmt.Errorf("node %s is not an EC2 instance: %s", node.Name, instanceID)
+	}
+
+	if region == "" {
+		val, ok := node.GetLabels()[corev1.LabelTopologyRegion]
+		if !ok {
+			return nil, errors.New("could not retrieve region from topology label")
+		}
+		region = val
+	}
+
+	svc, err := ec2Client(region)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create EC2 client: %w", err)
+	}
+
+	instance, err := describeInstance(svc, instanceID)
+	if err != nil {
+		return nil, err
+	}
+	if instance.Placement == nil || aws.ToString(instance.Placement.AvailabilityZone) == "" {
+		return nil, errors.New("could not get valid EC2 availability zone")
+	}
+
+	instanceInfo := Metadata{
+		InstanceID:             instanceID,
+		InstanceType:           string(instance.InstanceType),
+		Region:                 region,
+		AvailabilityZone:       aws.ToString(instance.Placement.AvailabilityZone),
+		NumAttachedENIs:        numAttachedENIs(instance),
+		NumBlockDeviceMappings: numNonCSIBlockDeviceMappings(instance, node),
+		EC2Client:              svc,
+		K8sAPIClient:           clientset,
+	}
+
+	if outpostArn := aws.ToString(instance.OutpostArn); outpostArn != "" {
+		klog.InfoS("Running in an outpost environment with arn", "outpostArn", outpostArn)
+		outpostArn = strings.ReplaceAll(outpostArn, "outpost/", "")
+		parsedArn, err := arn.Parse(outpostArn)
+		if err != nil {
+			klog.InfoS("Failed to parse the outpost arn", "outpostArn", outpostArn)
+		} else {
+			klog.InfoS("Using outpost arn", "parsedArn", parsedArn)
+			instanceInfo.OutpostArn = parsedArn
+		}
+	}
+
+	return &instanceInfo, nil
+}
+
+// ec2ENIsVolumes returns the number of ENIs and block device mappings of instanceID from the EC2 API.
+func ec2ENIsVolumes(svc util.EC2API, clientset kubernetes.Interface, instanceID string) (int, int, error) {
+	node, err := getNode(clientset)
+	if err != nil {
+		return 0, 0, err
+	}
+	instance, err := describeInstance(svc, instanceID)
+	if err != nil {
+		return 0, 0, err
+	}
+	return numAttachedENIs(instance), numNonCSIBlockDeviceMappings(instance, node), nil
+}
+
+func getNode(clientset kubernetes.Interface) (*corev1.Node, error) {
+	nodeName := os.Getenv("CSI_NODE_NAME")
+	if nodeName == "" {
+		return nil, errors.New("CSI_NODE_NAME env var not set")
+	}
+	node, err := clientset.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error getting Node %v: %w", nodeName, err)
+	}
+	return node, nil
+}
+
+func describeInstance(svc util.EC2API, instanceID string) (*types.Instance, error) {
+	output, err := svc.DescribeInstances(context.TODO(), &ec2.DescribeInstancesInput{InstanceIds: []string{instanceID}})
+	if err != nil {
+		return nil, fmt.Errorf("could not describe EC2 instance %s: %w", instanceID, err)
+	}
+	if len(output.Reservations) != 1 || len(output.Reservations[0].Instances) != 1 {
+		return nil, fmt.Errorf("could not find EC2 instance %s", instanceID)
+	}
+	return &output.Reservations[0].Instances[0], nil
+}
+
+func numAttachedENIs(instance *types.Instance) int {
+	if len(instance.NetworkInterfaces) == 0 {
+		// All instances have at least 1 attached ENI
+		return 1
+	}
+	return len(instance.NetworkInterfaces)
+}
+
+// numNonCSIBlockDeviceMappings returns the number of EBS volumes attached to instance, apart from
+// its root volume and the volumes attached by the driver, which are listed in the status of node.
+func numNonCSIBlockDeviceMappings(instance *types.Instance, node *corev1.Node) int {
+	if len(instance.BlockDeviceMappings) == 0 {
+		return 0
+	}
+	csiVolumes := make(map[string]struct{}, len(node.Status.VolumesAttached))
+	prefix := "kubernetes.io/csi/" + util.GetDriverName() + "^"
+	for _, attached := range node.Status.VolumesAttached {
+		if volumeID, ok := strings.CutPrefix(string(attached.Name), prefix); ok {
+			csiVolumes[volumeID] = struct{}{}
+		}
+	}
+
+	nonCSIVolumes := 0
+	for _, mapping := range instance.BlockDeviceMappings {
+		if mapping.Ebs != nil {
+			if _, ok := csiVolumes[aws.ToString(mapping.Ebs.VolumeId)]; ok {
+				continue
+			}
+		}
+		nonCSIVolumes++
+	}
+	// -1 for root volume because we eventually add this back in when calculating allocatable count in getVolumesLimit()
+	return nonCSIVolumes - 1
+}
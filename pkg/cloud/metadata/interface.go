@@ -31,6 +31,7 @@ type MetadataService interface {
 	GetNumBlockDeviceMappings() int
 	GetOutpostArn() arn.ARN
 	UpdateMetadata() error
+	IsCached() bool
 }
 
 type IMDS interface {
@@ -105,15 +105,9 @@ func DefaultKubernetesAPIClient(kubeconfig string) KubernetesAPIClient {
 }
 
 func KubernetesAPIInstanceInfo(clientset kubernetes.Interface, metadataLabeler bool) (*Metadata, error) {
-	nodeName := os.Getenv("CSI_NODE_NAME")
-	if nodeName == "" {
-		return nil, errors.New("CSI_NODE_NAME env var not set")
-	}
-
-	// get node with k8s API
-	node, err := clientset.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	node, err := getNode(clientset)
 	if err != nil {
-		return nil, fmt.Errorf("error getting Node %v: %w", nodeName, err)
+		return nil, err
 	}
 
 	numAttachedENIs := 1        // Default: All nodes have at least 1 attached ENI
@@ -160,7 +154,7 @@ func KubernetesAPIInstanceInfo(clientset kubernetes.Interface, metadataLabeler b
 		backoffErr := wait.ExponentialBackoffWithContext(ctx, backoff, func(ctx context.Context) (bool, error) {
 			if numAttachedENIs, numBlockDeviceMappings, err = getEC2ENIsVolumes(node); err != nil {
 				klog.ErrorS(err, "get ENI and volume labels failed, retrying...")
-				node, err = clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+				node, err = clientset.CoreV1().Nodes().Get(ctx, node.Name, metav1.GetOptions{})
 				//nolint: nilerr // Want to catch retry all errs until context times out
 				if err != nil {
 					return false, nil
@@ -176,7 +170,7 @@ func KubernetesAPIInstanceInfo(clientset kubernetes.Interface, metadataLabeler b
 		}
 	}
 
-	instanceID, err := parseProviderID(node)
+	instanceID, err := ParseProviderID(node)
 	if err != nil {
 		return nil, err
 	}
@@ -251,7 +245,8 @@ func getLabelAsInt(node *corev1.Node, label string, defaultValue int) (int, erro
 	return result, nil
 }
 
-func parseProviderID(node *corev1.Node) (string, error) {
+// ParseProviderID returns the EC2 instance ID (or HyperPod node ID) of node from its providerID.
+func ParseProviderID(node *corev1.Node) (string, error) {
 	providerID := node.Spec.ProviderID
 	if providerID == "" {
 		return "", errors.New("node providerID empty, cannot parse")
@@ -18,11 +18,14 @@ import (
 	"context"
 	json "encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/kubernetes-csi/csi-lib-utils/leaderelection"
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud"
@@ -58,11 +61,42 @@ var (
 
 	// ENIsLabel is the label name for the number of ENIs on a node.
 	ENIsLabel string
+
+	// MaxEBSBandwidthLabel is the label name for the maximum EBS bandwidth of the instance type of a node, in Mbps.
+	MaxEBSBandwidthLabel string
+
+	// MaxEBSIOPSLabel is the label name for the maximum EBS IOPS of the instance type of a node.
+	MaxEBSIOPSLabel string
+
+	// EBSCardsLabel is the label name for the number of EBS cards of a node.
+	EBSCardsLabel string
+
+	// ProvisionedIOPSLabel is the label name for the sum of the IOPS of the volumes attached to a node.
+	ProvisionedIOPSLabel string
+
+	// ProvisionedThroughputLabel is the label name for the sum of the throughput of the volumes attached to a node, in MiB/s.
+	ProvisionedThroughputLabel string
 )
 
 type enisVolumes struct {
 	ENIs    int
 	Volumes int
+	// EBS is nil if the EBS performance of the node could not be retrieved.
+	EBS *ebsPerformance
+}
+
+// ebsPerformance is the EBS performance of the instance type of a node and the share of it used by the
+// volumes attached to the node.
+type ebsPerformance struct {
+	// MaxBandwidthMbps and MaxIOPS are 0 if the instance type is not EBS-optimized.
+	MaxBandwidthMbps int
+	MaxIOPS          int
+	// CardVolumes is the number of volumes attached to each EBS card, by card index.
+	CardVolumes []int
+	// ProvisionedIOPS and ProvisionedThroughputMiBps are the sums of the IOPS and throughput reported by
+	// EC2 for the attached volumes. EC2 only reports the throughput of gp3 volumes.
+	ProvisionedIOPS            int
+	ProvisionedThroughputMiBps int
 }
 
 // initVariables initializes variables that depend on driver name.
@@ -71,9 +105,19 @@ func initVariables() {
 	once.Do(func() {
 		VolumesLabel = util.GetDriverName() + "/non-csi-ebs-volumes-count"
 		ENIsLabel = util.GetDriverName() + "/enis-count"
+		MaxEBSBandwidthLabel = util.GetDriverName() + "/max-ebs-bandwidth-mbps"
+		MaxEBSIOPSLabel = util.GetDriverName() + "/max-ebs-iops"
+		EBSCardsLabel = util.GetDriverName() + "/ebs-cards-count"
+		ProvisionedIOPSLabel = util.GetDriverName() + "/provisioned-iops"
+		ProvisionedThroughputLabel = util.GetDriverName() + "/provisioned-throughput-mibps"
 	})
 }
 
+// EBSCardVolumesLabel returns the label name for the number of volumes attached to the EBS card cardIndex of a node.
+func EBSCardVolumesLabel(cardIndex int) string {
+	return util.GetDriverName() + "/ebs-card-" + strconv.Itoa(cardIndex) + "-volumes-count"
+}
+
 // ContinuousUpdateLabelsLeaderElection uses leader election so that only one controller pod calls continuousUpdateLabels().
 func ContinuousUpdateLabelsLeaderElection(clientset kubernetes.Interface, cloud cloud.Cloud, updateTime time.Duration) error {
 	initVariables()
@@ -235,11 +279,12 @@ func updateMetadataEC2(ctx context.Context, kubeclient kubernetes.Interface, clo
 	return nil
 }
 
-// getMetadata calls the EC2 API to get the number of ENIs and non-CSI managed volumes attached to each node.
+// getMetadata calls the EC2 API to get the number of ENIs and non-CSI managed volumes attached to each node,
+// and the EBS performance of each node.
 func getMetadata(ctx context.Context, cloud cloud.Cloud, nodes *v1.NodeList, pvInformer cache.SharedIndexInformer) (map[string]enisVolumes, error) {
 	nodeIds := make([]string, 0, len(nodes.Items))
 	for _, node := range nodes.Items {
-		id, err := parseProviderID(&node)
+		id, err := ParseProviderID(&node)
 		if err != nil {
 			return nil, err
 		}
@@ -268,10 +313,95 @@ func getMetadata(ctx context.Context, cloud cloud.Cloud, nodes *v1.NodeList, pvI
 		enisVolumesMap[*instance.InstanceId] = enisVolumes{ENIs: numAttachedENIs, Volumes: numBlockDeviceMappings}
 	}
 
+	// The ENI and volume counts are patched even if the EBS performance is unavailable,
+	// the EBS performance labels are then left as they are until the next update
+	ebsPerformances, err := getEBSPerformance(ctx, cloud, respList)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get EBS performance of nodes")
+	}
+	for instanceID, performance := range ebsPerformances {
+		if ev, ok := enisVolumesMap[instanceID]; ok {
+			ev.EBS = performance
+			enisVolumesMap[instanceID] = ev
+		}
+	}
+
 	return enisVolumesMap, nil
 }
 
-// patchNodes patches the labels of each node to have the number of ENIs and non-CSI managed volumes attached to each node.
+// getEBSPerformance calls the EC2 API to get the EBS performance of the instance type of each instance,
+// and the number of volumes attached to each EBS card and the IOPS and throughput of the volumes attached to each instance.
+func getEBSPerformance(ctx context.Context, cloud cloud.Cloud, instances []*ec2types.Instance) (map[string]*ebsPerformance, error) {
+	if len(instances) == 0 {
+		return nil, nil
+	}
+
+	instanceIDs := make([]string, 0, len(instances))
+	instanceTypesSet := make(map[string]struct{})
+	for _, instance := range instances {
+		instanceIDs = append(instanceIDs, *instance.InstanceId)
+		if instance.InstanceType != "" {
+			instanceTypesSet[string(instance.InstanceType)] = struct{}{}
+		}
+	}
+
+	ebsInfos := make(map[string]*ec2types.EbsInfo, len(instanceTypesSet))
+	if len(instanceTypesSet) > 0 {
+		instanceTypes, err := cloud.GetInstanceTypesPatching(ctx, slices.Sorted(maps.Keys(instanceTypesSet)))
+		if err != nil {
+			return nil, err
+		}
+		for _, instanceType := range instanceTypes {
+			ebsInfos[string(instanceType.InstanceType)] = instanceType.EbsInfo
+		}
+	}
+
+	volumes, err := cloud.GetAttachedVolumesPatching(ctx, instanceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	performances := make(map[string]*ebsPerformance, len(instances))
+	for _, instance := range instances {
+		performance := &ebsPerformance{CardVolumes: []int{0}}
+		if ebsInfo := ebsInfos[string(instance.InstanceType)]; ebsInfo != nil {
+			if ebsInfo.EbsOptimizedInfo != nil {
+				performance.MaxBandwidthMbps = int(aws.ToInt32(ebsInfo.EbsOptimizedInfo.MaximumBandwidthInMbps))
+				performance.MaxIOPS = int(aws.ToInt32(ebsInfo.EbsOptimizedInfo.MaximumIops))
+			}
+			if cards := int(aws.ToInt32(ebsInfo.MaximumEbsCards)); cards > 1 {
+				performance.CardVolumes = make([]int, cards)
+			}
+		}
+		for _, mapping := range instance.BlockDeviceMappings {
+			if mapping.Ebs == nil {
+				continue
+			}
+			// Instances with a single EBS card do not report the card index of their volumes
+			cardIndex := int(aws.ToInt32(mapping.Ebs.EbsCardIndex))
+			for len(performance.CardVolumes) <= cardIndex {
+				performance.CardVolumes = append(performance.CardVolumes, 0)
+			}
+			performance.CardVolumes[cardIndex]++
+		}
+		performances[*instance.InstanceId] = performance
+	}
+
+	for _, volume := range volumes {
+		for _, attachment := range volume.Attachments {
+			// Multi-Attach volumes count towards each instance they are attached to
+			if performance, ok := performances[aws.ToString(attachment.InstanceId)]; ok {
+				performance.ProvisionedIOPS += int(aws.ToInt32(volume.Iops))
+				performance.ProvisionedThroughputMiBps += int(aws.ToInt32(volume.Throughput))
+			}
+		}
+	}
+
+	return performances, nil
+}
+
+// patchNodes patches the labels of each node to have the number of ENIs and non-CSI managed volumes attached to each node,
+// and the EBS performance of each node.
 func patchNodes(ctx context.Context, nodes *v1.NodeList, enisVolumeMap map[string]enisVolumes, clientset kubernetes.Interface, patchFails int) error {
 	numWorkers := min(len(nodes.Items), numWorkersPatchLabels)
 	if numWorkers == 0 {
@@ -308,7 +438,7 @@ func patchNodes(ctx context.Context, nodes *v1.NodeList, enisVolumeMap map[strin
 }
 
 func patchSingleNode(ctx context.Context, node v1.Node, enisVolumeMap map[string]enisVolumes, clientset kubernetes.Interface) error {
-	instanceID, err := parseProviderID(&node)
+	instanceID, err := ParseProviderID(&node)
 	if err != nil {
 		klog.Error(err, "Could not get instanceID", "node", node.Name)
 		return err
@@ -319,6 +449,20 @@ func patchSingleNode(ctx context.Context, node v1.Node, enisVolumeMap map[string
 	numBlockDeviceMappings := enisVolumeMap[instanceID].Volumes
 	newNode.Labels[VolumesLabel] = strconv.Itoa(numBlockDeviceMappings)
 	newNode.Labels[ENIsLabel] = strconv.Itoa(numAttachedENIs)
+	if performance := enisVolumeMap[instanceID].EBS; performance != nil {
+		if performance.MaxBandwidthMbps > 0 {
+			newNode.Labels[MaxEBSBandwidthLabel] = strconv.Itoa(performance.MaxBandwidthMbps)
+		}
+		if performance.MaxIOPS > 0 {
+			newNode.Labels[MaxEBSIOPSLabel] = strconv.Itoa(performance.MaxIOPS)
+		}
+		newNode.Labels[EBSCardsLabel] = strconv.Itoa(len(performance.CardVolumes))
+		for cardIndex, volumes := range performance.CardVolumes {
+			newNode.Labels[EBSCardVolumesLabel(cardIndex)] = strconv.Itoa(volumes)
+		}
+		newNode.Labels[ProvisionedIOPSLabel] = strconv.Itoa(performance.ProvisionedIOPS)
+		newNode.Labels[ProvisionedThroughputLabel] = strconv.Itoa(performance.ProvisionedThroughputMiBps)
+	}
 
 	oldData, err := json.Marshal(node)
 	if err != nil {
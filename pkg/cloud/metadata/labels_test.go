@@ -17,13 +17,14 @@ package metadata
 import (
 	"context"
 	"errors"
-	"maps"
+	"reflect"
 	"slices"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/golang/mock/gomock"
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud"
@@ -62,7 +63,7 @@ func TestGetMetadata(t *testing.T) {
 				makeInstance("i-001", 2, []string{"vol-001", "vol-002"}),
 			},
 			want: map[string]enisVolumes{
-				"i-001": {ENIs: 2, Volumes: 1},
+				"i-001": {ENIs: 2, Volumes: 1, EBS: &ebsPerformance{CardVolumes: []int{2}}},
 			},
 		},
 		{
@@ -76,8 +77,8 @@ func TestGetMetadata(t *testing.T) {
 				makeInstance("i-002", 3, []string{"vol-002", "vol-003", "vol-004"}),
 			},
 			want: map[string]enisVolumes{
-				"i-001": {ENIs: 1, Volumes: 0},
-				"i-002": {ENIs: 3, Volumes: 2},
+				"i-001": {ENIs: 1, Volumes: 0, EBS: &ebsPerformance{CardVolumes: []int{1}}},
+				"i-002": {ENIs: 3, Volumes: 2, EBS: &ebsPerformance{CardVolumes: []int{3}}},
 			},
 		},
 		{
@@ -92,7 +93,7 @@ func TestGetMetadata(t *testing.T) {
 				makeInstance("i-001", 1, []string{"vol-001", "vol-002"}),
 			},
 			want: map[string]enisVolumes{
-				"i-001": {ENIs: 1, Volumes: 0},
+				"i-001": {ENIs: 1, Volumes: 0, EBS: &ebsPerformance{CardVolumes: []int{2}}},
 			},
 		},
 		{
@@ -107,7 +108,7 @@ func TestGetMetadata(t *testing.T) {
 				makeInstance("i-001", 1, []string{"vol-001", "vol-002"}),
 			},
 			want: map[string]enisVolumes{
-				"i-001": {ENIs: 1, Volumes: 0},
+				"i-001": {ENIs: 1, Volumes: 0, EBS: &ebsPerformance{CardVolumes: []int{2}}},
 			},
 		},
 		{
@@ -130,7 +131,7 @@ func TestGetMetadata(t *testing.T) {
 			nodeList := &corev1.NodeList{Items: tt.nodes}
 			expectedNodeIDs := make([]string, 0, len(tt.nodes))
 			for _, node := range tt.nodes {
-				if id, err := parseProviderID(&node); err == nil && strings.HasPrefix(id, "i-") {
+				if id, err := ParseProviderID(&node); err == nil && strings.HasPrefix(id, "i-") {
 					expectedNodeIDs = append(expectedNodeIDs, id)
 				}
 			}
@@ -138,6 +139,7 @@ func TestGetMetadata(t *testing.T) {
 				mockCloud.EXPECT().GetInstancesPatching(ctx, expectedNodeIDs).
 					Return(tt.instances, tt.cloudErr).Times(1)
 			}
+			mockCloud.EXPECT().GetAttachedVolumesPatching(ctx, gomock.Any()).Return(nil, nil).AnyTimes()
 
 			pvInformer := setupPVInformer(t, tt.pvs)
 
@@ -147,13 +149,61 @@ func TestGetMetadata(t *testing.T) {
 				t.Errorf("getMetadata() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !tt.wantErr && !maps.Equal(got, tt.want) {
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("getMetadata() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+func TestGetEBSPerformance(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	singleCard := makeInstance("i-001", 1, []string{"vol-001", "vol-002"})
+	singleCard.InstanceType = "m5.large"
+	multiCard := makeInstance("i-002", 1, []string{"vol-003", "vol-004", "vol-005"})
+	multiCard.InstanceType = "r8gb.48xlarge"
+	multiCard.BlockDeviceMappings[1].Ebs.EbsCardIndex = aws.Int32(1)
+	multiCard.BlockDeviceMappings[2].Ebs.EbsCardIndex = aws.Int32(1)
+
+	mockCloud := cloud.NewMockCloud(ctrl)
+	mockCloud.EXPECT().GetInstanceTypesPatching(ctx, []string{"m5.large", "r8gb.48xlarge"}).Return([]types.InstanceTypeInfo{
+		{
+			InstanceType: "m5.large",
+			EbsInfo: &types.EbsInfo{
+				EbsOptimizedInfo: &types.EbsOptimizedInfo{MaximumBandwidthInMbps: aws.Int32(4750), MaximumIops: aws.Int32(18750)},
+			},
+		},
+		{
+			InstanceType: "r8gb.48xlarge",
+			EbsInfo: &types.EbsInfo{
+				EbsOptimizedInfo: &types.EbsOptimizedInfo{MaximumBandwidthInMbps: aws.Int32(300000), MaximumIops: aws.Int32(1440000)},
+				MaximumEbsCards:  aws.Int32(2),
+			},
+		},
+	}, nil)
+	mockCloud.EXPECT().GetAttachedVolumesPatching(ctx, []string{"i-001", "i-002"}).Return([]*types.Volume{
+		makeVolume("vol-001", 3000, 125, "i-001"),
+		makeVolume("vol-002", 100, 0, "i-001"),
+		makeVolume("vol-003", 16000, 1000, "i-002"),
+		makeVolume("vol-004", 64000, 0, "i-002", "i-001"),
+	}, nil)
+
+	got, err := getEBSPerformance(ctx, mockCloud, []*types.Instance{singleCard, multiCard})
+	if err != nil {
+		t.Fatalf("getEBSPerformance() error = %v", err)
+	}
+	want := map[string]*ebsPerformance{
+		"i-001": {MaxBandwidthMbps: 4750, MaxIOPS: 18750, CardVolumes: []int{2}, ProvisionedIOPS: 67100, ProvisionedThroughputMiBps: 125},
+		"i-002": {MaxBandwidthMbps: 300000, MaxIOPS: 1440000, CardVolumes: []int{1, 2}, ProvisionedIOPS: 80000, ProvisionedThroughputMiBps: 1000},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getEBSPerformance() = %v, want %v", got, want)
+	}
+}
+
 func TestPatchSingleNode(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -161,6 +211,7 @@ func TestPatchSingleNode(t *testing.T) {
 		metadata    map[string]enisVolumes
 		wantENIs    string
 		wantVolumes string
+		wantEBS     map[string]string
 		wantErr     bool
 	}{
 		{
@@ -172,6 +223,30 @@ func TestPatchSingleNode(t *testing.T) {
 			wantENIs:    "3",
 			wantVolumes: "5",
 		},
+		{
+			name: "patch node with EBS performance",
+			node: makeNode("i-001", "aws:///us-west-2a/i-001"),
+			metadata: map[string]enisVolumes{
+				"i-001": {ENIs: 1, Volumes: 0, EBS: &ebsPerformance{
+					MaxBandwidthMbps:           4750,
+					MaxIOPS:                    18750,
+					CardVolumes:                []int{1, 2},
+					ProvisionedIOPS:            6000,
+					ProvisionedThroughputMiBps: 250,
+				}},
+			},
+			wantENIs:    "1",
+			wantVolumes: "0",
+			wantEBS: map[string]string{
+				MaxEBSBandwidthLabel:       "4750",
+				MaxEBSIOPSLabel:            "18750",
+				EBSCardsLabel:              "2",
+				EBSCardVolumesLabel(0):     "1",
+				EBSCardVolumesLabel(1):     "2",
+				ProvisionedIOPSLabel:       "6000",
+				ProvisionedThroughputLabel: "250",
+			},
+		},
 		{
 			name: "invalid provider ID",
 			node: makeNode("i-001", "invalid"),
@@ -202,6 +277,11 @@ func TestPatchSingleNode(t *testing.T) {
 				if got := node.Labels[VolumesLabel]; got != tt.wantVolumes {
 					t.Errorf("Volumes label = %v, want %v", got, tt.wantVolumes)
 				}
+				for label, want := range tt.wantEBS {
+					if got := node.Labels[label]; got != want {
+						t.Errorf("%s label = %v, want %v", label, got, want)
+					}
+				}
 			}
 		})
 	}
@@ -395,6 +475,7 @@ func TestUpdateMetadataEC2(t *testing.T) {
 			} else {
 				instances := []*types.Instance{makeInstance("i-001", tt.metadata["i-001"].ENIs, []string{"vol-001", "vol-002", "vol-003", "vol-004"})}
 				mockCloud.EXPECT().GetInstancesPatching(ctx, []string{"i-001"}).Return(instances, nil)
+				mockCloud.EXPECT().GetAttachedVolumesPatching(ctx, []string{"i-001"}).Return(nil, nil)
 			}
 
 			err := updateMetadataEC2(ctx, clientset, mockCloud, nodeList, pvInformer)
@@ -448,6 +529,7 @@ func TestPatchNewNodes(t *testing.T) {
 
 			instances := []*types.Instance{makeInstance("i-001", tt.metadata["i-001"].ENIs, []string{"vol-001", "vol-002"})}
 			mockCloud.EXPECT().GetInstancesPatching(ctx, []string{"i-001"}).Return(instances, nil).MinTimes(1)
+			mockCloud.EXPECT().GetAttachedVolumesPatching(ctx, []string{"i-001"}).Return(nil, nil).MinTimes(1)
 
 			patched := make(chan struct{})
 			clientset.PrependReactor("patch", "nodes", func(action clienttesting.Action) (bool, runtime.Object, error) {
@@ -518,6 +600,20 @@ func makeInstance(id string, numENIs int, volumeIDs []string) *types.Instance {
 	}
 }
 
+func makeVolume(id string, iops, throughput int32, instanceIDs ...string) *types.Volume {
+	attachments := make([]types.VolumeAttachment, len(instanceIDs))
+	for i, instanceID := range instanceIDs {
+		attachments[i] = types.VolumeAttachment{InstanceId: aws.String(instanceID)}
+	}
+
+	return &types.Volume{
+		VolumeId:    aws.String(id),
+		Iops:        aws.Int32(iops),
+		Throughput:  aws.Int32(throughput),
+		Attachments: attachments,
+	}
+}
+
 func makeCSIPV(name, volumeHandle string) corev1.PersistentVolume {
 	return corev1.PersistentVolume{
 		ObjectMeta: metav1.ObjectMeta{Name: name},
@@ -19,6 +19,7 @@ package metadata
 import (
 	"fmt"
 	"os"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
@@ -36,17 +37,32 @@ type Metadata struct {
 	NumBlockDeviceMappings int
 	OutpostArn             arn.ARN
 	IMDSClient             IMDS
+	EC2Client              util.EC2API
 	K8sAPIClient           kubernetes.Interface
+
+	// mu protects the fields above from concurrent updates by UpdateMetadata.
+	mu sync.RWMutex
+	// cacheFile is the file the metadata is persisted to, if any.
+	cacheFile string
+	// cachedFrom is the configuration the metadata sources are retried with when the metadata
+	// was loaded from cacheFile, nil otherwise.
+	cachedFrom *MetadataServiceConfig
+	region     string
 }
 
 type MetadataServiceConfig struct {
 	MetadataSources []string
 	IMDSClient      IMDSClient
+	EC2Client       EC2Client
 	K8sAPIClient    KubernetesAPIClient
+	// CacheFile is the file the last retrieved Metadata is persisted to and loaded
+	// from when no metadata source is available. Caching is disabled if empty.
+	CacheFile string
 }
 
 const (
 	SourceIMDS            = "imds"
+	SourceEC2             = "ec2"
 	SourceMetadataLabeler = "metadata-labeler"
 	SourceK8s             = "kubernetes"
 )
@@ -60,7 +76,44 @@ var _ MetadataService = &Metadata{}
 
 // NewMetadataService retrieves instance Metadata from one of the clients in MetadataServiceConfig.
 // It tries each client included in MetadataServiceConfig.MetadataSources in order until one succeeds.
+// If none succeeds, it falls back to the Metadata persisted to MetadataServiceConfig.CacheFile, if any.
 func NewMetadataService(cfg MetadataServiceConfig, region string) (MetadataService, error) {
+	metadata, err := retrieveMetadata(cfg, region)
+	if cfg.CacheFile == "" {
+		if err != nil {
+			return nil, err
+		}
+		return metadata, nil
+	}
+
+	if err == nil {
+		metadata.cacheFile = cfg.CacheFile
+		if saveErr := saveMetadataCache(cfg.CacheFile, metadata); saveErr != nil {
+			klog.ErrorS(saveErr, "Failed to persist instance metadata", "cacheFile", cfg.CacheFile)
+		}
+		recordCachedMetadata(false)
+		return metadata, nil
+	}
+
+	klog.V(2).InfoS("Attempting to load instance metadata from cache file", "cacheFile", cfg.CacheFile)
+	metadata, cacheErr := loadMetadataCache(cfg.CacheFile)
+	if cacheErr != nil {
+		klog.ErrorS(cacheErr, "Loading cached metadata failed")
+		return nil, err
+	}
+	if verifyErr := verifyCachedInstanceID(cfg, metadata); verifyErr != nil {
+		klog.ErrorS(verifyErr, "Not running on cached metadata")
+		return nil, err
+	}
+	klog.InfoS("All metadata sources are unavailable, running on cached metadata", "cacheFile", cfg.CacheFile, "instanceID", metadata.InstanceID)
+	metadata.cacheFile = cfg.CacheFile
+	metadata.cachedFrom = &cfg
+	metadata.region = region
+	recordCachedMetadata(true)
+	return metadata.overrideRegion(region), nil
+}
+
+func retrieveMetadata(cfg MetadataServiceConfig, region string) (*Metadata, error) {
 	for _, source := range cfg.MetadataSources {
 		switch source {
 		case SourceIMDS:
@@ -80,6 +133,14 @@ func NewMetadataService(cfg MetadataServiceConfig, region string) (MetadataServi
 				}
 				klog.ErrorS(err, "Retrieving IMDS metadata failed")
 			}
+		case SourceEC2:
+			klog.V(2).InfoS("Attempting to retrieve instance metadata from EC2 API")
+			metadata, err := retrieveEC2Metadata(cfg.K8sAPIClient, cfg.EC2Client, region)
+			if err == nil {
+				klog.V(2).InfoS("Retrieved metadata from EC2 API")
+				return metadata, nil
+			}
+			klog.ErrorS(err, "Retrieving EC2 metadata failed")
 		case SourceMetadataLabeler:
 			klog.V(2).InfoS("Attempting to retrieve instance metadata from metadata labeler")
 			metadata, err := retrieveK8sMetadata(cfg.K8sAPIClient, true)
@@ -106,24 +167,71 @@ func NewMetadataService(cfg MetadataServiceConfig, region string) (MetadataServi
 }
 
 // UpdateMetadata refreshes metadata cache based upon driver startup metadata source.
+// Metadata loaded from the cache file is refreshed from the metadata sources instead.
 func (m *Metadata) UpdateMetadata() error {
+	m.mu.RLock()
+	cachedFrom, imdsClient, ec2Client, k8sAPIClient := m.cachedFrom, m.IMDSClient, m.EC2Client, m.K8sAPIClient
+	m.mu.RUnlock()
+
 	switch {
-	case m.IMDSClient != nil:
+	case cachedFrom != nil:
+		updatedMetadata, err := retrieveMetadata(*cachedFrom, m.region)
+		if err != nil {
+			return fmt.Errorf("failed to update cached metadata: %w", err)
+		}
+		klog.InfoS("Metadata sources are available again, no longer running on cached metadata")
+		m.mu.Lock()
+		m.InstanceID = updatedMetadata.InstanceID
+		m.InstanceType = updatedMetadata.InstanceType
+		m.Region = updatedMetadata.Region
+		m.AvailabilityZone = updatedMetadata.AvailabilityZone
+		m.NumAttachedENIs = updatedMetadata.NumAttachedENIs
+		m.NumBlockDeviceMappings = updatedMetadata.NumBlockDeviceMappings
+		m.OutpostArn = updatedMetadata.OutpostArn
+		m.IMDSClient = updatedMetadata.IMDSClient
+		m.EC2Client = updatedMetadata.EC2Client
+		m.K8sAPIClient = updatedMetadata.K8sAPIClient
+		m.cachedFrom = nil
+		m.overrideRegion(m.region)
+		m.mu.Unlock()
+		recordCachedMetadata(false)
+	case imdsClient != nil:
 		// We do not refresh blockDeviceMappings because IMDS only reports data from instance start (As of April 2025)
-		attachedENIs, err := getAttachedENIs(m.IMDSClient)
+		attachedENIs, err := getAttachedENIs(imdsClient)
 		if err != nil {
 			return fmt.Errorf("failed to update ENI count via IMDS metadata source: %w", err)
 		}
+		m.mu.Lock()
 		m.NumAttachedENIs = attachedENIs
-	case m.K8sAPIClient != nil:
-		updatedMetadata, err := KubernetesAPIInstanceInfo(m.K8sAPIClient, true /* metadataLabeler */)
+		m.mu.Unlock()
+	case ec2Client != nil:
+		attachedENIs, blockDeviceMappings, err := ec2ENIsVolumes(ec2Client, k8sAPIClient, m.GetInstanceID())
+		if err != nil {
+			return fmt.Errorf("failed to update ENI and Block Device count via EC2 metadata source: %w", err)
+		}
+		m.mu.Lock()
+		m.NumAttachedENIs = attachedENIs
+		m.NumBlockDeviceMappings = blockDeviceMappings
+		m.mu.Unlock()
+	case k8sAPIClient != nil:
+		updatedMetadata, err := KubernetesAPIInstanceInfo(k8sAPIClient, true /* metadataLabeler */)
 		if updatedMetadata == nil || err != nil {
 			return fmt.Errorf("failed to update ENI and Block Device count via metadataLabeler source: %w", err)
 		}
+		m.mu.Lock()
 		m.NumAttachedENIs = updatedMetadata.NumAttachedENIs
 		m.NumBlockDeviceMappings = updatedMetadata.NumBlockDeviceMappings
+		m.mu.Unlock()
 	}
 
+	if m.cacheFile != "" {
+		m.mu.RLock()
+		err := saveMetadataCache(m.cacheFile, m)
+		m.mu.RUnlock()
+		if err != nil {
+			klog.ErrorS(err, "Failed to persist instance metadata", "cacheFile", m.cacheFile)
+		}
+	}
 	return nil
 }
 
@@ -136,6 +244,14 @@ func retrieveIMDSMetadata(imdsClient IMDSClient) (*Metadata, error) {
 	return IMDSInstanceInfo(svc)
 }
 
+func retrieveEC2Metadata(k8sAPIClient KubernetesAPIClient, ec2Client EC2Client, region string) (*Metadata, error) {
+	clientset, err := k8sAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return EC2InstanceInfo(clientset, ec2Client, region)
+}
+
 func retrieveK8sMetadata(k8sAPIClient KubernetesAPIClient, metadataLabeler bool) (*Metadata, error) {
 	clientset, err := k8sAPIClient()
 	if err != nil {
@@ -160,42 +276,64 @@ func (m *Metadata) overrideRegion(region string) *Metadata {
 
 // GetInstanceID returns the instance identification.
 func (m *Metadata) GetInstanceID() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	return m.InstanceID
 }
 
 // GetInstanceType returns the instance type.
 func (m *Metadata) GetInstanceType() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	return m.InstanceType
 }
 
 // GetRegion returns the region which the instance is in.
 func (m *Metadata) GetRegion() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	return m.Region
 }
 
 // GetAvailabilityZone returns the Availability Zone which the instance is in.
 func (m *Metadata) GetAvailabilityZone() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	return m.AvailabilityZone
 }
 
 // GetNumAttachedENIs returns the number of attached ENIs.
 func (m *Metadata) GetNumAttachedENIs() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	return m.NumAttachedENIs
 }
 
 // GetNumBlockDeviceMappings returns the number of block device mappings.
 func (m *Metadata) GetNumBlockDeviceMappings() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	return m.NumBlockDeviceMappings
 }
 
 // GetOutpostArn returns outpost arn if instance is running on an outpost. empty otherwise.
 func (m *Metadata) GetOutpostArn() arn.ARN {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 	return m.OutpostArn
 }
 
+// IsCached returns true if the metadata was loaded from the cache file because no metadata
+// source was available, and has not been refreshed from a metadata source since.
+func (m *Metadata) IsCached() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cachedFrom != nil
+}
+
 // InvalidSourceErr returns an error message when a metadata source is invalid.
 func InvalidSourceErr(sources []string, invalidSource string) error {
-	return fmt.Errorf("invalid source: argument --metadata-sources=%s included invalid option '%s', comma-separated string MUST only include tokens like '%s' or '%s'", sources, invalidSource, SourceIMDS, SourceK8s)
+	return fmt.Errorf("invalid source: argument --metadata-sources=%s included invalid option '%s', comma-separated string MUST only include tokens like '%s', '%s' or '%s'", sources, invalidSource, SourceIMDS, SourceEC2, SourceK8s)
 }
 
 func sourcesUnavailableErr(metadataSources []string) error {
@@ -23,10 +23,15 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud"
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/testutil"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
@@ -833,6 +838,183 @@ func TestMetadataLabelerInstanceInfo(t *testing.T) {
 	}
 }
 
+func TestEC2InstanceInfo(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-node",
+			Labels: map[string]string{
+				corev1.LabelTopologyRegion: "us-west-2",
+			},
+		},
+		Spec: corev1.NodeSpec{
+			ProviderID: "aws:///us-west-2a/i-1234567890abcdef0",
+		},
+		Status: corev1.NodeStatus{
+			VolumesAttached: []corev1.AttachedVolume{
+				{Name: corev1.UniqueVolumeName("kubernetes.io/csi/" + util.GetDriverName() + "^vol-csi")},
+			},
+		},
+	}
+	instance := types.Instance{
+		InstanceId:   aws.String("i-1234567890abcdef0"),
+		InstanceType: types.InstanceTypeC5Xlarge,
+		Placement:    &types.Placement{AvailabilityZone: aws.String("us-west-2a")},
+		NetworkInterfaces: []types.InstanceNetworkInterface{
+			{NetworkInterfaceId: aws.String("eni-1")},
+			{NetworkInterfaceId: aws.String("eni-2")},
+		},
+		BlockDeviceMappings: []types.InstanceBlockDeviceMapping{
+			{DeviceName: aws.String("/dev/xvda"), Ebs: &types.EbsInstanceBlockDevice{VolumeId: aws.String("vol-root")}},
+			{DeviceName: aws.String("/dev/xvdb"), Ebs: &types.EbsInstanceBlockDevice{VolumeId: aws.String("vol-data")}},
+			{DeviceName: aws.String("/dev/xvdaa"), Ebs: &types.EbsInstanceBlockDevice{VolumeId: aws.String("vol-csi")}},
+		},
+	}
+	outpostInstance := instance
+	outpostInstance.OutpostArn = aws.String("arn:aws:outposts:us-west-2:111111111111:outpost/op-0aaa000a0aaaa00a0")
+
+	testCases := []struct {
+		name             string
+		node             *corev1.Node
+		region           string
+		instance         *types.Instance
+		describeErr      error
+		expectedRegion   string
+		expectedError    string
+		expectedMetadata *Metadata
+	}{
+		{
+			name:           "success",
+			node:           node,
+			instance:       &instance,
+			expectedRegion: "us-west-2",
+			expectedMetadata: &Metadata{
+				InstanceID:             "i-1234567890abcdef0",
+				InstanceType:           "c5.xlarge",
+				Region:                 "us-west-2",
+				AvailabilityZone:       "us-west-2a",
+				NumAttachedENIs:        2,
+				NumBlockDeviceMappings: 1,
+			},
+		},
+		{
+			name:           "success with region override and outpost",
+			node:           node,
+			region:         "us-east-1",
+			instance:       &outpostInstance,
+			expectedRegion: "us-east-1",
+			expectedMetadata: &Metadata{
+				InstanceID:             "i-1234567890abcdef0",
+				InstanceType:           "c5.xlarge",
+				Region:                 "us-east-1",
+				AvailabilityZone:       "us-west-2a",
+				NumAttachedENIs:        2,
+				NumBlockDeviceMappings: 1,
+				OutpostArn: arn.ARN{
+					Partition: "aws",
+					Service:   "outposts",
+					Region:    "us-west-2",
+					AccountID: "111111111111",
+					Resource:  "op-0aaa000a0aaaa00a0",
+				},
+			},
+		},
+		{
+			name:          "error getting node",
+			expectedError: "error getting Node test-node: nodes \"test-node\" not found",
+		},
+		{
+			name: "HyperPod node",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+				Spec: corev1.NodeSpec{
+					ProviderID: "aws:///usw2-az2/sagemaker/cluster/hyperpod-abcde3ghij4l-i-1234567890abcdef0",
+				},
+			},
+			expectedError: "node test-node is not an EC2 instance: hyperpod-abcde3ghij4l-i-1234567890abcdef0",
+		},
+		{
+			name: "missing region label",
+			node: &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+				Spec:       node.Spec,
+			},
+			expectedError: "could not retrieve region from topology label",
+		},
+		{
+			name:           "DescribeInstances error",
+			node:           node,
+			describeErr:    errors.New("UnauthorizedOperation"),
+			expectedRegion: "us-west-2",
+			expectedError:  "could not describe EC2 instance i-1234567890abcdef0: UnauthorizedOperation",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("CSI_NODE_NAME", "test-node")
+			ctrl := gomock.NewController(t)
+
+			clientset := fake.NewClientset()
+			if tc.node != nil {
+				clientset = fake.NewClientset(tc.node)
+			}
+
+			mockEC2 := cloud.NewMockEC2API(ctrl)
+			if tc.instance != nil || tc.describeErr != nil {
+				var output *ec2.DescribeInstancesOutput
+				if tc.instance != nil {
+					output = &ec2.DescribeInstancesOutput{Reservations: []types.Reservation{{Instances: []types.Instance{*tc.instance}}}}
+				}
+				mockEC2.EXPECT().DescribeInstances(gomock.Any(), &ec2.DescribeInstancesInput{InstanceIds: []string{"i-1234567890abcdef0"}}).Return(output, tc.describeErr)
+			}
+			ec2Client := func(region string) (util.EC2API, error) {
+				assert.Equal(t, tc.expectedRegion, region)
+				return mockEC2, nil
+			}
+
+			metadata, err := EC2InstanceInfo(clientset, ec2Client, tc.region)
+
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				require.Nil(t, metadata)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedMetadata.InstanceID, metadata.InstanceID)
+				assert.Equal(t, tc.expectedMetadata.InstanceType, metadata.InstanceType)
+				assert.Equal(t, tc.expectedMetadata.Region, metadata.Region)
+				assert.Equal(t, tc.expectedMetadata.AvailabilityZone, metadata.AvailabilityZone)
+				assert.Equal(t, tc.expectedMetadata.NumAttachedENIs, metadata.NumAttachedENIs)
+				assert.Equal(t, tc.expectedMetadata.NumBlockDeviceMappings, metadata.NumBlockDeviceMappings)
+				assert.Equal(t, tc.expectedMetadata.OutpostArn, metadata.OutpostArn)
+			}
+		})
+	}
+}
+
+func TestUpdateMetadataEC2Source(t *testing.T) {
+	t.Setenv("CSI_NODE_NAME", "test-node")
+	ctrl := gomock.NewController(t)
+	mockEC2 := cloud.NewMockEC2API(ctrl)
+	mockEC2.EXPECT().DescribeInstances(gomock.Any(), &ec2.DescribeInstancesInput{InstanceIds: []string{"i-1234567890abcdef0"}}).Return(&ec2.DescribeInstancesOutput{
+		Reservations: []types.Reservation{{Instances: []types.Instance{{
+			NetworkInterfaces: []types.InstanceNetworkInterface{{}, {}, {}},
+			BlockDeviceMappings: []types.InstanceBlockDeviceMapping{
+				{Ebs: &types.EbsInstanceBlockDevice{VolumeId: aws.String("vol-root")}},
+				{Ebs: &types.EbsInstanceBlockDevice{VolumeId: aws.String("vol-data")}},
+			},
+		}}}},
+	}, nil)
+
+	m := &Metadata{
+		InstanceID:   "i-1234567890abcdef0",
+		EC2Client:    mockEC2,
+		K8sAPIClient: fake.NewClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}}),
+	}
+	require.NoError(t, m.UpdateMetadata())
+	assert.Equal(t, 3, m.NumAttachedENIs)
+	assert.Equal(t, 1, m.NumBlockDeviceMappings)
+}
+
 func TestGetInstanceID(t *testing.T) {
 	metadata := &Metadata{
 		InstanceID: "i-1234567890abcdef0",
@@ -134,6 +134,20 @@ func (mr *MockMetadataServiceMockRecorder) GetRegion() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRegion", reflect.TypeOf((*MockMetadataService)(nil).GetRegion))
 }
 
+// IsCached mocks base method.
+func (m *MockMetadataService) IsCached() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsCached")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsCached indicates an expected call of IsCached.
+func (mr *MockMetadataServiceMockRecorder) IsCached() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsCached", reflect.TypeOf((*MockMetadataService)(nil).IsCached))
+}
+
 // UpdateMetadata mocks base method.
 func (m *MockMetadataService) UpdateMetadata() error {
 	m.ctrl.T.Helper()
@@ -169,6 +169,21 @@ func (mr *MockCloudMockRecorder) EnableFastSnapshotRestores(ctx, availabilityZon
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableFastSnapshotRestores", reflect.TypeOf((*MockCloud)(nil).EnableFastSnapshotRestores), ctx, availabilityZones, snapshotID)
 }
 
+// GetAttachedVolumesPatching mocks base method.
+func (m *MockCloud) GetAttachedVolumesPatching(ctx context.Context, nodeIDs []string) ([]*types.Volume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttachedVolumesPatching", ctx, nodeIDs)
+	ret0, _ := ret[0].([]*types.Volume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttachedVolumesPatching indicates an expected call of GetAttachedVolumesPatching.
+func (mr *MockCloudMockRecorder) GetAttachedVolumesPatching(ctx, nodeIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttachedVolumesPatching", reflect.TypeOf((*MockCloud)(nil).GetAttachedVolumesPatching), ctx, nodeIDs)
+}
+
 // GetDiskByID mocks base method.
 func (m *MockCloud) GetDiskByID(ctx context.Context, volumeID string) (*Disk, error) {
 	m.ctrl.T.Helper()
@@ -199,6 +214,21 @@ func (mr *MockCloudMockRecorder) GetDiskByName(ctx, name, capacityBytes interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDiskByName", reflect.TypeOf((*MockCloud)(nil).GetDiskByName), ctx, name, capacityBytes)
 }
 
+// GetInstanceTypesPatching mocks base method.
+func (m *MockCloud) GetInstanceTypesPatching(ctx context.Context, instanceTypes []string) ([]types.InstanceTypeInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInstanceTypesPatching", ctx, instanceTypes)
+	ret0, _ := ret[0].([]types.InstanceTypeInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInstanceTypesPatching indicates an expected call of GetInstanceTypesPatching.
+func (mr *MockCloudMockRecorder) GetInstanceTypesPatching(ctx, instanceTypes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInstanceTypesPatching", reflect.TypeOf((*MockCloud)(nil).GetInstanceTypesPatching), ctx, instanceTypes)
+}
+
 // GetInstancesPatching mocks base method.
 func (m *MockCloud) GetInstancesPatching(ctx context.Context, nodeIDs []string) ([]*types.Instance, error) {
 	m.ctrl.T.Helper()
@@ -274,6 +304,21 @@ func (mr *MockCloudMockRecorder) IsVolumeInitialized(ctx, volumeID interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsVolumeInitialized", reflect.TypeOf((*MockCloud)(nil).IsVolumeInitialized), ctx, volumeID)
 }
 
+// ListDisks mocks base method.
+func (m *MockCloud) ListDisks(ctx context.Context, tags map[string]string) ([]*Disk, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDisks", ctx, tags)
+	ret0, _ := ret[0].([]*Disk)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDisks indicates an expected call of ListDisks.
+func (mr *MockCloudMockRecorder) ListDisks(ctx, tags interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDisks", reflect.TypeOf((*MockCloud)(nil).ListDisks), ctx, tags)
+}
+
 // ListSnapshots mocks base method.
 func (m *MockCloud) ListSnapshots(ctx context.Context, volumeID string, maxResults int32, nextToken string) (*ListSnapshotsResponse, error) {
 	m.ctrl.T.Helper()
@@ -317,6 +362,20 @@ func (mr *MockCloudMockRecorder) ModifyTags(ctx, volumeID, tagOptions interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyTags", reflect.TypeOf((*MockCloud)(nil).ModifyTags), ctx, volumeID, tagOptions)
 }
 
+// ModifyResourceTags mocks base method.
+func (m *MockCloud) ModifyResourceTags(ctx context.Context, resourceIDs []string, tagOptions ModifyTagsOptions) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModifyResourceTags", ctx, resourceIDs, tagOptions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ModifyResourceTags indicates an expected call of ModifyResourceTags.
+func (mr *MockCloudMockRecorder) ModifyResourceTags(ctx, resourceIDs, tagOptions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModifyResourceTags", reflect.TypeOf((*MockCloud)(nil).ModifyResourceTags), ctx, resourceIDs, tagOptions)
+}
+
 // ResizeOrModifyDisk mocks base method.
 func (m *MockCloud) ResizeOrModifyDisk(ctx context.Context, volumeID string, newSizeBytes int64, options *ModifyDiskOptions) (int32, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/batcher"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+var tracer = otel.Tracer("github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud")
+
+// startSpan starts a child span of the span of ctx. Nothing is traced, and ctx is returned unchanged,
+// if the span of ctx is not recording, e.g. because tracing is disabled or the trace is not sampled.
+func startSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if !trace.SpanFromContext(ctx).IsRecording() {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+	return tracer.Start(ctx, name, opts...)
+}
+
+// TraceRequestsMiddleware traces each AWS API call, including its retries, in a child span of the
+// span of the context of the call.
+func TraceRequestsMiddleware() func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		// Added after the other Initialize middlewares so that the operation name is set
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("TraceRequestsMiddleware", func(ctx context.Context, input middleware.InitializeInput, next middleware.InitializeHandler) (output middleware.InitializeOutput, metadata middleware.Metadata, err error) {
+			serviceID := awsmiddleware.GetServiceID(ctx)
+			operationName := awsmiddleware.GetOperationName(ctx)
+			ctx, span := startSpan(ctx, serviceID+"."+operationName, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+				attribute.String("rpc.system", "aws-api"),
+				attribute.String("rpc.service", serviceID),
+				attribute.String("rpc.method", operationName),
+			))
+			defer span.End()
+
+			output, metadata, err = next.HandleInitialize(ctx, input)
+
+			if awsRequestID, ok := awsmiddleware.GetRequestIDMetadata(metadata); ok {
+				span.SetAttributes(attribute.String("aws.request_id", awsRequestID))
+			}
+			if requestID := util.RequestID(ctx); requestID != "" {
+				span.SetAttributes(attribute.String("csi.request_id", requestID))
+			}
+			if attempts, ok := retry.GetAttemptResults(metadata); ok {
+				throttles := 0
+				for _, attempt := range attempts.Results {
+					var apiErr smithy.APIError
+					if errors.As(attempt.Err, &apiErr) {
+						if _, isThrottleError := retry.DefaultThrottleErrorCodes[apiErr.ErrorCode()]; isThrottleError {
+							throttles++
+						}
+					}
+				}
+				span.SetAttributes(
+					attribute.Int("aws.retries", max(len(attempts.Results)-1, 0)),
+					attribute.Int("aws.throttles", throttles),
+				)
+			}
+			if err != nil {
+				recordSpanError(span, err)
+			}
+			return output, metadata, err
+		}), middleware.After)
+	}
+}
+
+// awaitBatchResult adds task to b and waits for its result. The wait is traced in a span linked
+// to the span of the batch execution.
+func awaitBatchResult[InputType comparable, ResultType any](ctx context.Context, name string, b *batcher.Batcher[InputType, ResultType], task InputType) batcher.BatchResult[ResultType] {
+	ctx, span := startSpan(ctx, name+" batch")
+	defer span.End()
+
+	ch := make(chan batcher.BatchResult[ResultType])
+	b.AddTask(ctx, task, ch)
+	r := <-ch
+
+	if r.SpanContext.IsValid() {
+		span.AddLink(trace.Link{SpanContext: r.SpanContext})
+	}
+	if r.Err != nil {
+		recordSpanError(span, r.Err)
+	}
+	return r
+}
+
+// tracePolls traces each call to condition in a child span named after the waiter.
+func tracePolls(waiter string, condition wait.ConditionWithContextFunc, attributes ...attribute.KeyValue) wait.ConditionWithContextFunc {
+	poll := 0
+	return func(ctx context.Context) (bool, error) {
+		poll++
+		ctx, span := startSpan(ctx, waiter+" poll", trace.WithAttributes(append(attributes, attribute.Int("poll", poll))...))
+		defer span.End()
+
+		done, err := condition(ctx)
+		span.SetAttributes(attribute.Bool("done", done))
+		if err != nil {
+			recordSpanError(span, err)
+		}
+		return done, err
+	}
+}
+
+func recordSpanError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -18,11 +18,18 @@ limitations under the License.
 package coalescer
 
 import (
+	"context"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/klog/v2"
 )
 
+var tracer = otel.Tracer("github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/coalescer")
+
 // Coalescer is an interface to combine multiple requests made over a period of time into a single request
 //
 // When a request is received that matches an existing in-flight request, the coalescer will attempt to
@@ -31,13 +38,16 @@ import (
 // When the delay on the request expires (determined by the time the first request comes in), the merged
 // input is passed to the execution function, and the result to all waiting callers (those that were
 // not rejected during the merge step).
+//
+// Each execution is traced in a span linked to the spans of the contexts of the coalesced requests,
+// and the span of each caller waiting for the result is linked to the span of the execution.
 type Coalescer[InputType any, ResultType any] interface {
 	// Coalesce is a function to coalesce a given input
 	// key = only requests with this same key will be coalesced (such as volume ID)
 	// input = input to merge with other inputs
 	// It is NOT guaranteed all callers receive the same result (for example, if
 	// an input fails to merge, only that caller will receive an error)
-	Coalesce(key string, input InputType) (ResultType, error)
+	Coalesce(ctx context.Context, key string, input InputType) (ResultType, error)
 }
 
 // New is a function to creates a new coalescer and immediately begin processing requests
@@ -45,10 +55,10 @@ type Coalescer[InputType any, ResultType any] interface {
 // mergeFunction = a function to merge a new input with the existing inputs
 // (should return an error if the new input cannot be combined with the existing inputs,
 // otherwise return the new merged input)
-// executeFunction = the function to call when the delay expires.
+// executeFunction = the function to call when the delay expires, with the context of the span of the execution.
 func New[InputType any, ResultType any](delay time.Duration,
 	mergeFunction func(input InputType, existing InputType) (InputType, error),
-	executeFunction func(key string, input InputType) (ResultType, error),
+	executeFunction func(ctx context.Context, key string, input InputType) (ResultType, error),
 ) Coalescer[InputType, ResultType] {
 	c := coalescer[InputType, ResultType]{
 		delay:           delay,
@@ -65,8 +75,9 @@ func New[InputType any, ResultType any](delay time.Duration,
 
 // Type to store a result or error in channels.
 type result[ResultType any] struct {
-	result ResultType
-	err    error
+	result      ResultType
+	err         error
+	spanContext trace.SpanContext
 }
 
 // Type to send inputs from Coalesce() to coalescerThread() via channel
@@ -75,18 +86,20 @@ type newInput[InputType any, ResultType any] struct {
 	key           string
 	input         InputType
 	resultChannel chan result[ResultType]
+	link          trace.Link
 }
 
 // Type to store pending inputs in the input map.
 type pendingInput[InputType any, ResultType any] struct {
 	input          InputType
 	resultChannels []chan result[ResultType]
+	links          []trace.Link
 }
 
 type coalescer[InputType any, ResultType any] struct {
 	delay           time.Duration
 	mergeFunction   func(input InputType, existing InputType) (InputType, error)
-	executeFunction func(key string, input InputType) (ResultType, error)
+	executeFunction func(ctx context.Context, key string, input InputType) (ResultType, error)
 
 	inputChannel chan newInput[InputType, ResultType]
 	timerChannel chan string
@@ -94,17 +107,26 @@ type coalescer[InputType any, ResultType any] struct {
 	pendingInputs map[string]pendingInput[InputType, ResultType]
 }
 
-func (c *coalescer[InputType, ResultType]) Coalesce(key string, input InputType) (ResultType, error) {
+func (c *coalescer[InputType, ResultType]) Coalesce(ctx context.Context, key string, input InputType) (ResultType, error) {
+	ctx, span := tracer.Start(ctx, "Coalescer.Coalesce", trace.WithAttributes(attribute.String("coalescer.key", key)))
+	defer span.End()
+
 	resultChannel := make(chan result[ResultType])
 
 	c.inputChannel <- newInput[InputType, ResultType]{
 		key:           key,
 		input:         input,
 		resultChannel: resultChannel,
+		link:          trace.LinkFromContext(ctx),
 	}
 	result := <-resultChannel
 
+	if result.spanContext.IsValid() {
+		span.AddLink(trace.Link{SpanContext: result.spanContext})
+	}
 	if result.err != nil {
+		span.RecordError(result.err)
+		span.SetStatus(codes.Error, result.err.Error())
 		return *new(ResultType), result.err
 	} else {
 		return result.result, nil
@@ -124,6 +146,7 @@ func (c *coalescer[InputType, ResultType]) coalescerThread() {
 					klog.V(7).InfoS("coalescerThread: Merged input into existing inputs", "key", i.key)
 					pending.input = newInput
 					pending.resultChannels = append(pending.resultChannels, i.resultChannel)
+					pending.links = appendSampledLink(pending.links, i.link)
 					c.pendingInputs[i.key] = pending
 				} else {
 					klog.V(7).InfoS("coalescerThread: Failed to merge inputs into existing inputs", "key", i.key)
@@ -138,6 +161,7 @@ func (c *coalescer[InputType, ResultType]) coalescerThread() {
 					resultChannels: []chan result[ResultType]{
 						i.resultChannel,
 					},
+					links: appendSampledLink(nil, i.link),
 				}
 				time.AfterFunc(c.delay, func() {
 					c.timerChannel <- i.key
@@ -150,11 +174,25 @@ func (c *coalescer[InputType, ResultType]) coalescerThread() {
 			delete(c.pendingInputs, k)
 
 			go func() {
-				r, err := c.executeFunction(k, pending.input)
+				// The execution is only traced if one of the coalesced requests is traced
+				ctx, span := context.Background(), trace.SpanFromContext(context.Background())
+				if len(pending.links) > 0 {
+					ctx, span = tracer.Start(ctx, "Coalescer.execute", trace.WithLinks(pending.links...), trace.WithAttributes(
+						attribute.String("coalescer.key", k),
+						attribute.Int("coalescer.requests", len(pending.resultChannels)),
+					))
+				}
+				r, err := c.executeFunction(ctx, k, pending.input)
+				if err != nil {
+					span.RecordError(err)
+					span.SetStatus(codes.Error, err.Error())
+				}
+				span.End()
 				klog.V(7).InfoS("coalescerThread: Finished executing", "key", k, "result", r, "error", err)
 				result := result[ResultType]{
-					result: r,
-					err:    err,
+					result:      r,
+					err:         err,
+					spanContext: span.SpanContext(),
 				}
 				for _, c := range pending.resultChannels {
 					c <- result
@@ -163,3 +201,11 @@ func (c *coalescer[InputType, ResultType]) coalescerThread() {
 		}
 	}
 }
+
+// appendSampledLink appends link to links if the linked span is sampled.
+func appendSampledLink(links []trace.Link, link trace.Link) []trace.Link {
+	if link.SpanContext.IsSampled() {
+		return append(links, link)
+	}
+	return links
+}
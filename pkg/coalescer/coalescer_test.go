@@ -17,6 +17,7 @@ limitations under the License.
 package coalescer
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -40,7 +41,7 @@ func mockMerge(input int, existing int) (int, error) {
 // Execute function used to test the coalescer
 // For testing purposes, small numbers (numbers less than 100) successfully execute,
 // and large numbers (numbers 100 or greater) fail to execute.
-func mockExecute(_ string, input int) (string, error) {
+func mockExecute(_ context.Context, _ string, input int) (string, error) {
 	if input < 100 {
 		return "success", nil
 	}
@@ -83,7 +84,7 @@ func TestCoalescer(t *testing.T) {
 
 			for _, i := range tc.inputs {
 				go func() {
-					_, err := c.Coalesce("testKey", i)
+					_, err := c.Coalesce(context.Background(), "testKey", i)
 					testChannel <- err
 				}()
 			}
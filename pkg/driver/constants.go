@@ -37,6 +37,26 @@ const (
 	VolumeAttributePartition = "partition"
 )
 
+// constants of keys in the VolumeContext of CSI ephemeral inline volumes.
+const (
+	// EphemeralKey is set to "true" by kubelet in the VolumeContext of CSI ephemeral inline volumes.
+	// Kubelet only passes it, and the pod keys below, if podInfoOnMount is enabled in the CSIDriver.
+	EphemeralKey = "csi.storage.k8s.io/ephemeral"
+	// PodNameKey contains the name of the pod an ephemeral inline volume belongs to.
+	PodNameKey = "csi.storage.k8s.io/pod.name"
+	// PodNamespaceKey contains the namespace of the pod an ephemeral inline volume belongs to.
+	PodNamespaceKey = "csi.storage.k8s.io/pod.namespace"
+	// PodUIDKey contains the UID of the pod an ephemeral inline volume belongs to.
+	PodUIDKey = "csi.storage.k8s.io/pod.uid"
+
+	// EphemeralSizeKey configures the size of an ephemeral inline volume, as a resource quantity (e.g. 100Gi).
+	// The volume type, IOPS, throughput and encryption are configured with the keys of volume parameters.
+	EphemeralSizeKey = "size"
+
+	// EphemeralVolumeHandlePrefix is the prefix of the volume handles kubelet generates for ephemeral inline volumes.
+	EphemeralVolumeHandlePrefix = "csi-"
+)
+
 // constants of keys in volume parameters.
 const (
 	// VolumeTypeKey represents key for volume type.
@@ -123,6 +143,45 @@ const (
 
 	// BlockAttachUntilInitializedKey will prevent restored volume from being attached until it is fully initialized.
 	BlockAttachUntilInitializedKey = "blockattachuntilinitialized"
+
+	// PrewarmOnStageKey makes the node plugin read every block of a volume restored from a snapshot
+	// in the background after staging it.
+	PrewarmOnStageKey = "prewarmonstage"
+
+	// ReadIOPSLimitKey configures the read IOPS limit applied on the node to each pod using the volume.
+	ReadIOPSLimitKey = "readiopslimit"
+
+	// WriteIOPSLimitKey configures the write IOPS limit applied on the node to each pod using the volume.
+	WriteIOPSLimitKey = "writeiopslimit"
+
+	// ReadBandwidthLimitKey configures the read bandwidth limit, in bytes per second, applied on the node to each pod using the volume.
+	ReadBandwidthLimitKey = "readbandwidthlimit"
+
+	// WriteBandwidthLimitKey configures the write bandwidth limit, in bytes per second, applied on the node to each pod using the volume.
+	WriteBandwidthLimitKey = "writebandwidthlimit"
+
+	// FinalSnapshotKey takes a snapshot of the volume before it is deleted.
+	FinalSnapshotKey = "finalsnapshot"
+
+	// FinalSnapshotLockModeKey locks the final snapshot of the volume in governance or compliance mode.
+	FinalSnapshotLockModeKey = "finalsnapshotlockmode"
+
+	// FinalSnapshotLockDurationKey is the duration for which the final snapshot of the volume is locked, in days.
+	FinalSnapshotLockDurationKey = "finalsnapshotlockduration"
+
+	// DeletionProtectionKey protects the volume from deletion until it is disabled.
+	DeletionProtectionKey = "deletionprotection"
+
+	// CopyTagsFromSnapshotKey copies the tags of the source snapshot of a volume to the volume.
+	CopyTagsFromSnapshotKey = "copytagsfromsnapshot"
+
+	// CopyTagsIncludeKey is a comma separated list of the keys, or key prefixes ending with "*", of the tags copied
+	// from the source snapshot of a volume or the source volume of a snapshot. All the tags are copied by default.
+	CopyTagsIncludeKey = "copytagsinclude"
+
+	// CopyTagsExcludeKey is a comma separated list of the keys, or key prefixes ending with "*", of the tags not copied
+	// from the source snapshot of a volume or the source volume of a snapshot.
+	CopyTagsExcludeKey = "copytagsexclude"
 )
 
 // constants of keys in snapshot parameters.
@@ -141,6 +200,9 @@ const (
 
 	// LockCoolOffPeriod is a key specifying the cooling-off period for compliance mode, specified in hours.
 	LockCoolOffPeriod = "lockcooloffperiod"
+
+	// CopyTagsFromVolumeKey copies the tags of the source volume of a snapshot to the snapshot.
+	CopyTagsFromVolumeKey = "copytagsfromvolume"
 )
 
 // constants for volume tags and their values.
@@ -190,12 +252,17 @@ const (
 const (
 	DefaultCSIEndpoint                       = "unix://tmp/csi.sock"
 	DefaultModifyVolumeRequestHandlerTimeout = 2 * time.Second
+	DefaultVolumeMigrationRetention          = 24 * time.Hour
+	DefaultPVCTagSyncInterval                = time.Hour
 )
 
 // constants for node-local volumes.
 const (
 	// NodeLocalVolumeHandlePrefix is the prefix for node-local volume handles.
 	NodeLocalVolumeHandlePrefix = "local-ebs://"
+	// InstanceStoreVolumeHandlePrefix is the prefix for node-local volume handles backed by
+	// instance store disks, followed by the index of a disk or "raid0" for all of them.
+	InstanceStoreVolumeHandlePrefix = NodeLocalVolumeHandlePrefix + "instance-store/"
 )
 
 // constants for fstypes.
@@ -33,12 +33,17 @@ import (
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud"
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/coalescer"
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/driver/internal"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/mounter"
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/plugin"
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util/template"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 )
 
@@ -62,6 +67,9 @@ var (
 )
 
 const trueStr = "true"
+
+// controllerInformerResync is the default resync period of the informers of the controller.
+const controllerInformerResync = time.Hour
 const isManagedByDriver = trueStr
 
 // ControllerService represents the controller service of CSI driver.
@@ -70,22 +78,43 @@ type ControllerService struct {
 	inFlight              *internal.InFlight
 	options               *Options
 	modifyVolumeCoalescer coalescer.Coalescer[modifyVolumeRequest, int32]
+	k8sClient             kubernetes.Interface
+	// recorder emits events about volumes on their PersistentVolumeClaims, nil if there is no Kubernetes client.
+	recorder record.EventRecorder
+	// migrations holds the running volume migrations.
+	migrations *volumeMigrations
+	// informerFactory is shared by the informers of the controller, nil if there is no Kubernetes client.
+	// Informers are only started by the features using them.
+	informerFactory informers.SharedInformerFactory
+	// eventObjects resolves the PersistentVolumeClaims of the events, nil if there is no Kubernetes client.
+	eventObjects *volumeEventObjects
+	// tagMetadata fills the Kubernetes metadata of the tag templates of volumes.
+	tagMetadata *tagTemplateMetadata
 	rpc.UnimplementedModifyServer
 	csi.UnimplementedControllerServer
 }
 
 // NewControllerService creates a new controller service.
-func NewControllerService(c cloud.Cloud, o *Options) *ControllerService {
-	return &ControllerService{
+func NewControllerService(c cloud.Cloud, o *Options, k kubernetes.Interface) *ControllerService {
+	d := &ControllerService{
 		cloud:                 c,
 		options:               o,
 		inFlight:              internal.NewInFlight(),
 		modifyVolumeCoalescer: newModifyVolumeCoalescer(c, o),
+		k8sClient:             k,
+		migrations:            newVolumeMigrations(),
+	}
+	if k != nil {
+		d.recorder = newControllerEventRecorder(k)
+		d.informerFactory = informers.NewSharedInformerFactory(k, controllerInformerResync)
+		d.eventObjects = newVolumeEventObjects(d.informerFactory)
 	}
+	d.tagMetadata = newTagTemplateMetadata(k, d.informerFactory, o.KubernetesClusterID)
+	return d
 }
 
 func (d *ControllerService) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest) (*csi.CreateVolumeResponse, error) {
-	klog.V(4).InfoS("CreateVolume: called", "args", util.SanitizeRequest(req))
+	klog.FromContext(ctx).V(4).Info("CreateVolume: called", "args", util.SanitizeRequest(req))
 	if err := validateCreateVolumeRequest(req); err != nil {
 		return nil, err
 	}
@@ -99,7 +128,7 @@ func (d *ControllerService) CreateVolume(ctx context.Context, req *csi.CreateVol
 	multiAttach := false
 	for _, c := range volCap {
 		if c.GetAccessMode().GetMode() == MultiNodeMultiWriter && isBlock(c) {
-			klog.V(4).InfoS("CreateVolume: multi-attach is enabled", "volumeID", volName)
+			klog.FromContext(ctx).V(4).Info("CreateVolume: multi-attach is enabled", "volumeID", volName)
 			multiAttach = true
 		}
 	}
@@ -134,6 +163,9 @@ func (d *ControllerService) CreateVolume(ctx context.Context, req *csi.CreateVol
 		ext4ClusterSize             string
 		ext4EncryptionSupport       bool
 		blockAttachUntilInitialized bool
+		prewarmOnStage              bool
+		ioLimits                    = map[string]string{}
+		copyTags                    tagCopyFilter
 	)
 
 	tProps := new(template.PVProps)
@@ -141,7 +173,7 @@ func (d *ControllerService) CreateVolume(ctx context.Context, req *csi.CreateVol
 	for key, value := range req.GetParameters() {
 		switch strings.ToLower(key) {
 		case "fstype":
-			klog.InfoS("\"fstype\" is deprecated, please use \"csi.storage.k8s.io/fstype\" instead")
+			klog.FromContext(ctx).Info("\"fstype\" is deprecated, please use \"csi.storage.k8s.io/fstype\" instead")
 		case VolumeTypeKey:
 			volumeType = value
 		case IopsPerGBKey:
@@ -188,7 +220,7 @@ func (d *ControllerService) CreateVolume(ctx context.Context, req *csi.CreateVol
 			volumeTags[PVNameTag] = value
 			tProps.PVName = value
 		case DeprecatedBlockExpressKey:
-			klog.V(2).InfoS("blockExpress key is deprecated and has no effect, all io2 volumes are now Block Express and share the same IOPS cap")
+			klog.FromContext(ctx).V(2).Info("blockExpress key is deprecated and has no effect, all io2 volumes are now Block Express and share the same IOPS cap")
 		case BlockSizeKey:
 			if isAlphanumeric := util.StringIsAlphanumeric(value); !isAlphanumeric {
 				return nil, status.Errorf(codes.InvalidArgument, "Could not parse blockSize (%s): %v", value, err)
@@ -220,6 +252,28 @@ func (d *ControllerService) CreateVolume(ctx context.Context, req *csi.CreateVol
 			ext4EncryptionSupport = isTrue(value)
 		case BlockAttachUntilInitializedKey:
 			blockAttachUntilInitialized = isTrue(value)
+		case PrewarmOnStageKey:
+			prewarmOnStage = isTrue(value)
+		case ReadIOPSLimitKey, WriteIOPSLimitKey, ReadBandwidthLimitKey, WriteBandwidthLimitKey:
+			ioLimits[strings.ToLower(key)] = value
+		case FinalSnapshotKey:
+			if isTrue(value) {
+				volumeTags[FinalSnapshotTagKey] = trueStr
+			}
+		case FinalSnapshotLockModeKey:
+			volumeTags[FinalSnapshotLockModeTagKey] = value
+		case FinalSnapshotLockDurationKey:
+			volumeTags[FinalSnapshotLockDurationTagKey] = value
+		case DeletionProtectionKey:
+			if isTrue(value) {
+				volumeTags[DeletionProtectionTagKey] = trueStr
+			}
+		case CopyTagsFromSnapshotKey:
+			copyTags.enabled = isTrue(value)
+		case CopyTagsIncludeKey:
+			copyTags.include = parseTagKeyPatterns(value)
+		case CopyTagsExcludeKey:
+			copyTags.exclude = parseTagKeyPatterns(value)
 		default:
 			if strings.HasPrefix(key, TagKeyPrefix) {
 				tagsToEvaluate = append(tagsToEvaluate, value)
@@ -249,10 +303,10 @@ func (d *ControllerService) CreateVolume(ctx context.Context, req *csi.CreateVol
 			throughput = int32(vacThroughput)
 		case DeprecatedModificationKeyVolumeType:
 			if _, ok := mutableParameters[ModificationKeyVolumeType]; ok {
-				klog.Infof("Ignoring deprecated key `volumeType` because preferred key `type` is present")
+				klog.FromContext(ctx).Info("Ignoring deprecated key `volumeType` because preferred key `type` is present")
 				continue
 			}
-			klog.InfoS("Key `volumeType` is deprecated, please use `type` instead")
+			klog.FromContext(ctx).Info("Key `volumeType` is deprecated, please use `type` instead")
 			volumeType = value
 		case VolumeTypeKey:
 			volumeType = value
@@ -260,16 +314,25 @@ func (d *ControllerService) CreateVolume(ctx context.Context, req *csi.CreateVol
 			switch {
 			case strings.HasPrefix(key, ModificationAddTag):
 				tagsToEvaluate = append(tagsToEvaluate, value)
+			case isIOLimitKey(key):
+				ioLimits[strings.ToLower(key)] = value
 			default:
 				return nil, status.Errorf(codes.InvalidArgument, "Invalid mutable parameter key: %s", key)
 			}
 		}
 	}
 
+	if _, err = parseFinalSnapshotLock(volumeTags); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid final snapshot: %v", err)
+	}
+
 	for key, value := range d.options.ExtraTags {
 		tagsToEvaluate = append(tagsToEvaluate, key+"="+value)
 	}
 
+	if err := d.tagMetadata.fill(ctx, tagsToEvaluate, tProps); err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not get the metadata of tag templates: %v", err)
+	}
 	addTags, err := template.Evaluate(tagsToEvaluate, tProps, d.options.WarnOnInvalidTag)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "Error interpolating tag value: %v", err)
@@ -337,6 +400,10 @@ func (d *ControllerService) CreateVolume(ctx context.Context, req *csi.CreateVol
 	if blockAttachUntilInitialized {
 		responseCtx[BlockAttachUntilInitializedKey] = trueStr
 	}
+	if _, err = parseIOLimits(ioLimits); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid I/O limits: %v", err)
+	}
+	maps.Copy(responseCtx, ioLimits)
 
 	if !ext4BigAlloc && len(ext4ClusterSize) > 0 {
 		return nil, status.Errorf(codes.InvalidArgument, "Cannot set ext4BigAllocClusterSize when ext4BigAlloc is false")
@@ -359,6 +426,24 @@ func (d *ControllerService) CreateVolume(ctx context.Context, req *csi.CreateVol
 
 		if sourceSnapshot != nil {
 			snapshotID = sourceSnapshot.GetSnapshotId()
+			if prewarmOnStage {
+				responseCtx[PrewarmOnStageKey] = trueStr
+			}
+			if copyTags.enabled {
+				snapshot, err := d.cloud.GetSnapshotByID(ctx, snapshotID)
+				if err != nil {
+					if errors.Is(err, cloud.ErrNotFound) {
+						return nil, status.Errorf(codes.NotFound, "Source snapshot %s not found", snapshotID)
+					}
+					return nil, status.Errorf(codes.Internal, "Could not get source snapshot %s: %v", snapshotID, err)
+				}
+				// The tags of the StorageClass and of the driver take precedence over the copied tags
+				for key, value := range copyTags.copyTags(snapshot.Tags) {
+					if _, ok := volumeTags[key]; !ok {
+						volumeTags[key] = value
+					}
+				}
+			}
 		}
 
 		if sourceVolume != nil {
@@ -428,8 +513,21 @@ func (d *ControllerService) CreateVolume(ctx context.Context, req *csi.CreateVol
 		default:
 			errCode = codes.Aborted
 		}
+		if errors.Is(err, cloud.ErrLimitExceeded) {
+			d.recordPVCEvent(tProps.PVCNamespace, tProps.PVCName, corev1.EventTypeWarning, eventReasonVolumeLimitExceeded,
+				"Could not create volume %s: %v", volName, err)
+		}
 		return nil, status.Errorf(errCode, "Could not create volume %q: %v", volName, err)
 	}
+
+	requestedIOPS := iops
+	if requestedIOPS == 0 {
+		requestedIOPS = iopsPerGB * disk.CapacityGiB
+	}
+	if disk.IOPS != 0 && disk.IOPS != requestedIOPS {
+		d.recordPVCEvent(tProps.PVCNamespace, tProps.PVCName, corev1.EventTypeNormal, eventReasonVolumeIOPSAdjusted,
+			"Volume %s was created with %d IOPS instead of the requested %d, to fit the limits of its type and size", disk.VolumeID, disk.IOPS, requestedIOPS)
+	}
 	return newCreateVolumeResponse(disk, responseCtx), nil
 }
 
@@ -451,7 +549,7 @@ func validateCreateVolumeRequest(req *csi.CreateVolumeRequest) error {
 }
 
 func (d *ControllerService) DeleteVolume(ctx context.Context, req *csi.DeleteVolumeRequest) (*csi.DeleteVolumeResponse, error) {
-	klog.V(4).InfoS("DeleteVolume: called", "args", util.SanitizeRequest(req))
+	klog.FromContext(ctx).V(4).Info("DeleteVolume: called", "args", util.SanitizeRequest(req))
 	if err := validateDeleteVolumeRequest(req); err != nil {
 		return nil, err
 	}
@@ -464,9 +562,26 @@ func (d *ControllerService) DeleteVolume(ctx context.Context, req *csi.DeleteVol
 	}
 	defer d.inFlight.Delete(volumeID)
 
+	disk, err := d.cloud.GetDiskByID(ctx, volumeID)
+	if err != nil {
+		if errors.Is(err, cloud.ErrNotFound) {
+			klog.FromContext(ctx).V(4).Info("DeleteVolume: volume not found, returning with success")
+			return &csi.DeleteVolumeResponse{}, nil
+		}
+		return nil, status.Errorf(codes.Internal, "Could not get volume ID %q: %v", volumeID, err)
+	}
+	if isTrue(disk.Tags[DeletionProtectionTagKey]) {
+		return nil, status.Errorf(codes.FailedPrecondition, "Volume ID %q is protected from deletion by tag %s", volumeID, DeletionProtectionTagKey)
+	}
+	if isTrue(disk.Tags[FinalSnapshotTagKey]) {
+		if err := d.createFinalSnapshot(ctx, disk); err != nil {
+			return nil, status.Errorf(codes.Internal, "Could not take final snapshot of volume ID %q: %v", volumeID, err)
+		}
+	}
+
 	if _, err := d.cloud.DeleteDisk(ctx, volumeID); err != nil {
 		if errors.Is(err, cloud.ErrNotFound) {
-			klog.V(4).InfoS("DeleteVolume: volume not found, returning with success")
+			klog.FromContext(ctx).V(4).Info("DeleteVolume: volume not found, returning with success")
 			return &csi.DeleteVolumeResponse{}, nil
 		}
 		return nil, status.Errorf(codes.Internal, "Could not delete volume ID %q: %v", volumeID, err)
@@ -483,7 +598,7 @@ func validateDeleteVolumeRequest(req *csi.DeleteVolumeRequest) error {
 }
 
 func (d *ControllerService) ControllerPublishVolume(ctx context.Context, req *csi.ControllerPublishVolumeRequest) (*csi.ControllerPublishVolumeResponse, error) {
-	klog.V(4).InfoS("ControllerPublishVolume: called", "args", util.SanitizeRequest(req))
+	klog.FromContext(ctx).V(4).Info("ControllerPublishVolume: called", "args", util.SanitizeRequest(req))
 
 	volumeID := req.GetVolumeId()
 	nodeID := req.GetNodeId()
@@ -504,24 +619,39 @@ func (d *ControllerService) ControllerPublishVolume(ctx context.Context, req *cs
 	}
 	defer d.inFlight.Delete(volumeID + nodeID)
 
-	klog.V(2).InfoS("ControllerPublishVolume: attaching", "volumeID", volumeID, "nodeID", nodeID)
+	if d.migrations.running(volumeID) {
+		return nil, status.Errorf(codes.Unavailable, "Volume %q is being migrated", volumeID)
+	}
+
+	klog.FromContext(ctx).V(2).Info("ControllerPublishVolume: attaching", "volumeID", volumeID, "nodeID", nodeID)
+	start := time.Now()
 	devicePath, err := d.cloud.AttachDisk(ctx, volumeID, nodeID)
 	if err != nil {
 		if errors.Is(err, cloud.ErrNotFound) {
 			return nil, status.Errorf(codes.NotFound, "Volume %q not found", volumeID)
 		}
 		if errors.Is(err, cloud.ErrLimitExceeded) {
+			d.recordVolumeEvent(volumeID, corev1.EventTypeWarning, eventReasonAttachmentLimitExceeded,
+				"Could not attach volume %s to node %s, the attachment limit of the node is exceeded: %v", volumeID, nodeID, err)
 			return nil, status.Errorf(codes.ResourceExhausted, "Attachment limit exceeded for volume %q on node %q: %v", volumeID, nodeID, err)
 		}
+		if errors.Is(err, cloud.ErrAttachmentStuck) {
+			d.recordVolumeEvent(volumeID, corev1.EventTypeWarning, eventReasonAttachmentStuck,
+				"Volume %s was stuck attaching to node %s and was detached, the attachment will be retried: %v", volumeID, nodeID, err)
+		}
 		return nil, status.Errorf(codes.Internal, "Could not attach volume %q to node %q: %v", volumeID, nodeID, err)
 	}
-	klog.InfoS("ControllerPublishVolume: attached", "volumeID", volumeID, "nodeID", nodeID, "devicePath", devicePath)
+	klog.FromContext(ctx).Info("ControllerPublishVolume: attached", "volumeID", volumeID, "nodeID", nodeID, "devicePath", devicePath)
+	if duration := time.Since(start); duration > slowAttachThreshold {
+		d.recordVolumeEvent(volumeID, corev1.EventTypeNormal, eventReasonAttachmentSlow,
+			"Volume %s took %s to attach to node %s", volumeID, duration.Round(time.Second), nodeID)
+	}
 
 	if val, ok := req.GetVolumeContext()[BlockAttachUntilInitializedKey]; ok && val == trueStr {
 		isInitialized := false
 		var err error
 
-		klog.V(4).InfoS("Ensuring volume is initialized because volume context "+BlockAttachUntilInitializedKey+"=true", "volumeID", volumeID)
+		klog.FromContext(ctx).V(4).Info("Ensuring volume is initialized because volume context "+BlockAttachUntilInitializedKey+"=true", "volumeID", volumeID)
 
 		for !isInitialized {
 			isInitialized, err = d.cloud.IsVolumeInitialized(ctx, volumeID)
@@ -559,6 +689,17 @@ func (d *ControllerService) controllerPublishVolumeNodeLocal(ctx context.Context
 	volumeID := req.GetVolumeId()
 	nodeID := req.GetNodeId()
 
+	// Instance store disks are not EBS volumes, they are found by the node without EC2 calls
+	if isInstanceStoreVolume(volumeID) {
+		if _, err := instanceStoreSelector(volumeID); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		klog.FromContext(ctx).Info("ControllerPublishVolume: instance store node-local volume", "volumeID", volumeID, "nodeID", nodeID)
+		return &csi.ControllerPublishVolumeResponse{PublishContext: map[string]string{
+			DevicePathKey: strings.TrimPrefix(volumeID, NodeLocalVolumeHandlePrefix),
+		}}, nil
+	}
+
 	deviceName := strings.TrimPrefix(volumeID, NodeLocalVolumeHandlePrefix)
 	if deviceName == "" || deviceName == volumeID {
 		return nil, status.Error(codes.InvalidArgument, "invalid node-local volume handle format")
@@ -572,7 +713,7 @@ func (d *ControllerService) controllerPublishVolumeNodeLocal(ctx context.Context
 		return nil, status.Errorf(codes.Internal, "Failed to get volume at device %s on node %s: %v", deviceName, nodeID, err)
 	}
 
-	klog.InfoS("ControllerPublishVolume: resolved node-local volume", "volumeID", volumeID, "realVolumeID", realVolumeID, "nodeID", nodeID, "deviceName", deviceName)
+	klog.FromContext(ctx).Info("ControllerPublishVolume: resolved node-local volume", "volumeID", volumeID, "realVolumeID", realVolumeID, "nodeID", nodeID, "deviceName", deviceName)
 
 	pvInfo := map[string]string{
 		DevicePathKey: deviceName,
@@ -582,7 +723,7 @@ func (d *ControllerService) controllerPublishVolumeNodeLocal(ctx context.Context
 }
 
 func (d *ControllerService) ControllerUnpublishVolume(ctx context.Context, req *csi.ControllerUnpublishVolumeRequest) (*csi.ControllerUnpublishVolumeResponse, error) {
-	klog.V(4).InfoS("ControllerUnpublishVolume: called", "args", util.SanitizeRequest(req))
+	klog.FromContext(ctx).V(4).Info("ControllerUnpublishVolume: called", "args", util.SanitizeRequest(req))
 
 	if err := validateControllerUnpublishVolumeRequest(req); err != nil {
 		return nil, err
@@ -592,7 +733,7 @@ func (d *ControllerService) ControllerUnpublishVolume(ctx context.Context, req *
 	nodeID := req.GetNodeId()
 
 	if isNodeLocalVolume(volumeID) {
-		klog.V(2).InfoS("ControllerUnpublishVolume: node-local mode, skipping detach", "volumeID", volumeID, "nodeID", nodeID)
+		klog.FromContext(ctx).V(2).Info("ControllerUnpublishVolume: node-local mode, skipping detach", "volumeID", volumeID, "nodeID", nodeID)
 		return &csi.ControllerUnpublishVolumeResponse{}, nil
 	}
 
@@ -601,15 +742,15 @@ func (d *ControllerService) ControllerUnpublishVolume(ctx context.Context, req *
 	}
 	defer d.inFlight.Delete(volumeID + nodeID)
 
-	klog.V(2).InfoS("ControllerUnpublishVolume: detaching", "volumeID", volumeID, "nodeID", nodeID)
+	klog.FromContext(ctx).V(2).Info("ControllerUnpublishVolume: detaching", "volumeID", volumeID, "nodeID", nodeID)
 	if err := d.cloud.DetachDisk(ctx, volumeID, nodeID); err != nil {
 		if errors.Is(err, cloud.ErrNotFound) {
-			klog.InfoS("ControllerUnpublishVolume: attachment not found", "volumeID", volumeID, "nodeID", nodeID)
+			klog.FromContext(ctx).Info("ControllerUnpublishVolume: attachment not found", "volumeID", volumeID, "nodeID", nodeID)
 			return &csi.ControllerUnpublishVolumeResponse{}, nil
 		}
 		return nil, status.Errorf(codes.Internal, "Could not detach volume %q from node %q: %v", volumeID, nodeID, err)
 	}
-	klog.InfoS("ControllerUnpublishVolume: detached", "volumeID", volumeID, "nodeID", nodeID)
+	klog.FromContext(ctx).Info("ControllerUnpublishVolume: detached", "volumeID", volumeID, "nodeID", nodeID)
 
 	return &csi.ControllerUnpublishVolumeResponse{}, nil
 }
@@ -627,7 +768,7 @@ func validateControllerUnpublishVolumeRequest(req *csi.ControllerUnpublishVolume
 }
 
 func (d *ControllerService) ControllerGetCapabilities(ctx context.Context, req *csi.ControllerGetCapabilitiesRequest) (*csi.ControllerGetCapabilitiesResponse, error) {
-	klog.V(4).InfoS("ControllerGetCapabilities: called", "args", req)
+	klog.FromContext(ctx).V(4).Info("ControllerGetCapabilities: called", "args", req)
 
 	caps := make([]*csi.ControllerServiceCapability, 0, len(controllerCaps))
 	for _, capability := range controllerCaps {
@@ -644,17 +785,17 @@ func (d *ControllerService) ControllerGetCapabilities(ctx context.Context, req *
 }
 
 func (d *ControllerService) GetCapacity(ctx context.Context, req *csi.GetCapacityRequest) (*csi.GetCapacityResponse, error) {
-	klog.V(4).InfoS("GetCapacity: called", "args", req)
+	klog.FromContext(ctx).V(4).Info("GetCapacity: called", "args", req)
 	return nil, status.Error(codes.Unimplemented, "")
 }
 
 func (d *ControllerService) ListVolumes(ctx context.Context, req *csi.ListVolumesRequest) (*csi.ListVolumesResponse, error) {
-	klog.V(4).InfoS("ListVolumes: called", "args", req)
+	klog.FromContext(ctx).V(4).Info("ListVolumes: called", "args", req)
 	return nil, status.Error(codes.Unimplemented, "")
 }
 
 func (d *ControllerService) ValidateVolumeCapabilities(ctx context.Context, req *csi.ValidateVolumeCapabilitiesRequest) (*csi.ValidateVolumeCapabilitiesResponse, error) {
-	klog.V(4).InfoS("ValidateVolumeCapabilities: called", "args", req)
+	klog.FromContext(ctx).V(4).Info("ValidateVolumeCapabilities: called", "args", req)
 	volumeID := req.GetVolumeId()
 	if len(volumeID) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "Volume ID not provided")
@@ -697,7 +838,7 @@ func (d *ControllerService) ValidateVolumeCapabilities(ctx context.Context, req
 }
 
 func (d *ControllerService) ControllerExpandVolume(ctx context.Context, req *csi.ControllerExpandVolumeRequest) (*csi.ControllerExpandVolumeResponse, error) {
-	klog.V(4).InfoS("ControllerExpandVolume: called", "args", util.SanitizeRequest(req))
+	klog.FromContext(ctx).V(4).Info("ControllerExpandVolume: called", "args", util.SanitizeRequest(req))
 	volumeID := req.GetVolumeId()
 	if len(volumeID) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "Volume ID not provided")
@@ -718,10 +859,14 @@ func (d *ControllerService) ControllerExpandVolume(ctx context.Context, req *csi
 		return nil, status.Error(codes.InvalidArgument, "After round-up, volume size exceeds the limit specified")
 	}
 
-	actualSizeGiB, err := d.modifyVolumeCoalescer.Coalesce(volumeID, modifyVolumeRequest{
+	actualSizeGiB, err := d.modifyVolumeCoalescer.Coalesce(ctx, volumeID, modifyVolumeRequest{
 		newSize: newSize,
 	})
 	if err != nil {
+		if status.Code(err) == codes.Unavailable {
+			// The resize was deferred
+			return nil, err
+		}
 		return nil, status.Errorf(codes.Internal, "Could not resize volume %q: %v", volumeID, err)
 	}
 
@@ -739,7 +884,7 @@ func (d *ControllerService) ControllerExpandVolume(ctx context.Context, req *csi
 }
 
 func (d *ControllerService) ControllerModifyVolume(ctx context.Context, req *csi.ControllerModifyVolumeRequest) (*csi.ControllerModifyVolumeResponse, error) {
-	klog.V(4).InfoS("ControllerModifyVolume: called", "args", util.SanitizeRequest(req))
+	klog.FromContext(ctx).V(4).Info("ControllerModifyVolume: called", "args", util.SanitizeRequest(req))
 
 	volumeID := req.GetVolumeId()
 	if len(volumeID) == 0 {
@@ -750,16 +895,33 @@ func (d *ControllerService) ControllerModifyVolume(ctx context.Context, req *csi
 		return nil, status.Error(codes.InvalidArgument, "node-local volumes cannot be modified")
 	}
 
-	options, err := parseModifyVolumeParameters(req.GetMutableParameters())
+	options, err := parseModifyVolumeParameters(ctx, req.GetMutableParameters(), d.tagMetadata)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = d.modifyVolumeCoalescer.Coalesce(volumeID, modifyVolumeRequest{
+	if options.migration != nil {
+		// The modification is applied to the new volume of a migrated volume
+		volumeID, err = d.migrateVolume(ctx, volumeID, req.GetMutableParameters(), options.migration)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = d.modifyVolumeCoalescer.Coalesce(ctx, volumeID, modifyVolumeRequest{
 		modifyDiskOptions: options.modifyDiskOptions,
 		modifyTagsOptions: options.modifyTagsOptions,
 	})
 	if err != nil {
+		pvcNamespace, pvcName := pvcFromParameters(req.GetMutableParameters())
+		switch status.Code(err) {
+		case codes.ResourceExhausted:
+			d.recordPVCEvent(pvcNamespace, pvcName, corev1.EventTypeWarning, eventReasonVolumeModificationLimitExceeded,
+				"Could not modify volume %s: %v", volumeID, status.Convert(err).Message())
+		case codes.Unavailable:
+			d.recordPVCEvent(pvcNamespace, pvcName, corev1.EventTypeNormal, eventReasonVolumeModificationDeferred,
+				"The modification of volume %s is deferred: %v", volumeID, status.Convert(err).Message())
+		}
 		return nil, err
 	}
 
@@ -767,7 +929,7 @@ func (d *ControllerService) ControllerModifyVolume(ctx context.Context, req *csi
 }
 
 func (d *ControllerService) ControllerGetVolume(ctx context.Context, req *csi.ControllerGetVolumeRequest) (*csi.ControllerGetVolumeResponse, error) {
-	klog.V(4).InfoS("ControllerGetVolume: called", "args", req)
+	klog.FromContext(ctx).V(4).Info("ControllerGetVolume: called", "args", req)
 	return nil, status.Error(codes.Unimplemented, "")
 }
 
@@ -806,6 +968,23 @@ func isNodeLocalVolume(volumeID string) bool {
 	return strings.HasPrefix(volumeID, NodeLocalVolumeHandlePrefix)
 }
 
+func isInstanceStoreVolume(volumeID string) bool {
+	return strings.HasPrefix(volumeID, InstanceStoreVolumeHandlePrefix)
+}
+
+// instanceStoreSelector returns the selector of the instance store disks of volumeID for
+// FindInstanceStoreDevice: the index of a disk or mounter.InstanceStoreRAID0.
+func instanceStoreSelector(volumeID string) (string, error) {
+	selector := strings.TrimPrefix(volumeID, InstanceStoreVolumeHandlePrefix)
+	if selector == mounter.InstanceStoreRAID0 {
+		return selector, nil
+	}
+	if index, err := strconv.Atoi(selector); err != nil || index < 0 {
+		return "", fmt.Errorf("invalid instance store volume handle %q, expected %s<index> or %s%s", volumeID, InstanceStoreVolumeHandlePrefix, InstanceStoreVolumeHandlePrefix, mounter.InstanceStoreRAID0)
+	}
+	return selector, nil
+}
+
 func isValidCapabilityForNodeLocal(c *csi.VolumeCapability) bool {
 	accessMode := c.GetAccessMode().GetMode()
 	return accessMode == SingleNodeWriter || accessMode == MultiNodeMultiWriter
@@ -834,7 +1013,7 @@ func isValidVolumeContext(volContext map[string]string) bool {
 }
 
 func (d *ControllerService) CreateSnapshot(ctx context.Context, req *csi.CreateSnapshotRequest) (*csi.CreateSnapshotResponse, error) {
-	klog.V(4).InfoS("CreateSnapshot: called", "args", util.SanitizeRequest(req))
+	klog.FromContext(ctx).V(4).Info("CreateSnapshot: called", "args", util.SanitizeRequest(req))
 	if err := validateCreateSnapshotRequest(req); err != nil {
 		return nil, err
 	}
@@ -852,14 +1031,14 @@ func (d *ControllerService) CreateSnapshot(ctx context.Context, req *csi.CreateS
 
 	snapshot, err := d.cloud.GetSnapshotByName(ctx, snapshotName)
 	if err != nil && !errors.Is(err, cloud.ErrNotFound) {
-		klog.ErrorS(err, "Error looking for the snapshot", "snapshotName", snapshotName)
+		klog.FromContext(ctx).Error(err, "Error looking for the snapshot", "snapshotName", snapshotName)
 		return nil, err
 	}
 	if snapshot != nil {
 		if snapshot.SourceVolumeID != volumeID {
 			return nil, status.Errorf(codes.AlreadyExists, "Snapshot %s already exists for different volume (%s)", snapshotName, snapshot.SourceVolumeID)
 		}
-		klog.V(4).InfoS("Snapshot of volume already exists; nothing to do", "snapshotName", snapshotName, "volumeId", volumeID)
+		klog.FromContext(ctx).V(4).Info("Snapshot of volume already exists; nothing to do", "snapshotName", snapshotName, "volumeId", volumeID)
 		return newCreateSnapshotResponse(snapshot), nil
 	}
 
@@ -872,6 +1051,7 @@ func (d *ControllerService) CreateSnapshot(ctx context.Context, req *csi.CreateS
 	var fsrAvailabilityZones []string
 	vsProps := new(template.VolumeSnapshotProps)
 	vsLock := new(cloud.SnapshotLockOptions)
+	var copyTags tagCopyFilter
 	for key, value := range req.GetParameters() {
 		switch strings.ToLower(key) {
 		case VolumeSnapshotNameKey:
@@ -909,6 +1089,12 @@ func (d *ControllerService) CreateSnapshot(ctx context.Context, req *csi.CreateS
 				return nil, status.Errorf(codes.InvalidArgument, "Could not parse SnapshotLockCoolOffPeriod: %q", value)
 			}
 			vsLock.CoolOffPeriod = aws.Int32(int32(lockCoolOffPeriod))
+		case CopyTagsFromVolumeKey:
+			copyTags.enabled = isTrue(value)
+		case CopyTagsIncludeKey:
+			copyTags.include = parseTagKeyPatterns(value)
+		case CopyTagsExcludeKey:
+			copyTags.exclude = parseTagKeyPatterns(value)
 		default:
 			if strings.HasPrefix(key, TagKeyPrefix) {
 				vscTags = append(vscTags, value)
@@ -937,6 +1123,22 @@ func (d *ControllerService) CreateSnapshot(ctx context.Context, req *csi.CreateS
 
 	maps.Copy(snapshotTags, addTags)
 
+	if copyTags.enabled {
+		disk, err := d.cloud.GetDiskByID(ctx, volumeID)
+		if err != nil {
+			if errors.Is(err, cloud.ErrNotFound) {
+				return nil, status.Errorf(codes.NotFound, "Source volume %s not found", volumeID)
+			}
+			return nil, status.Errorf(codes.Internal, "Could not get source volume %s: %v", volumeID, err)
+		}
+		// The tags of the VolumeSnapshotClass and of the driver take precedence over the copied tags
+		for key, value := range copyTags.copyTags(disk.Tags) {
+			if _, ok := snapshotTags[key]; !ok {
+				snapshotTags[key] = value
+			}
+		}
+	}
+
 	opts := &cloud.SnapshotOptions{
 		Tags:       snapshotTags,
 		OutpostArn: outpostArn,
@@ -946,9 +1148,9 @@ func (d *ControllerService) CreateSnapshot(ctx context.Context, req *csi.CreateS
 	if len(fsrAvailabilityZones) > 0 {
 		zones, err := d.cloud.AvailabilityZones(ctx)
 		if err != nil {
-			klog.ErrorS(err, "failed to get availability zones")
+			klog.FromContext(ctx).Error(err, "failed to get availability zones")
 		} else {
-			klog.V(4).InfoS("Availability Zones", "zone", zones)
+			klog.FromContext(ctx).V(4).Info("Availability Zones", "zone", zones)
 			for _, az := range fsrAvailabilityZones {
 				if _, ok := zones[az]; !ok {
 					return nil, status.Errorf(codes.InvalidArgument, "Availability zone %s is not supported for fast snapshot restore", az)
@@ -1002,7 +1204,7 @@ func validateCreateSnapshotRequest(req *csi.CreateSnapshotRequest) error {
 }
 
 func (d *ControllerService) DeleteSnapshot(ctx context.Context, req *csi.DeleteSnapshotRequest) (*csi.DeleteSnapshotResponse, error) {
-	klog.V(4).InfoS("DeleteSnapshot: called", "args", util.SanitizeRequest(req))
+	klog.FromContext(ctx).V(4).Info("DeleteSnapshot: called", "args", util.SanitizeRequest(req))
 	if err := validateDeleteSnapshotRequest(req); err != nil {
 		return nil, err
 	}
@@ -1018,7 +1220,7 @@ func (d *ControllerService) DeleteSnapshot(ctx context.Context, req *csi.DeleteS
 
 	if _, err := d.cloud.DeleteSnapshot(ctx, snapshotID); err != nil {
 		if errors.Is(err, cloud.ErrNotFound) {
-			klog.V(4).InfoS("DeleteSnapshot: snapshot not found, returning with success")
+			klog.FromContext(ctx).V(4).Info("DeleteSnapshot: snapshot not found, returning with success")
 			return &csi.DeleteSnapshotResponse{}, nil
 		}
 		return nil, status.Errorf(codes.Internal, "Could not delete snapshot ID %q: %v", snapshotID, err)
@@ -1035,7 +1237,7 @@ func validateDeleteSnapshotRequest(req *csi.DeleteSnapshotRequest) error {
 }
 
 func (d *ControllerService) ListSnapshots(ctx context.Context, req *csi.ListSnapshotsRequest) (*csi.ListSnapshotsResponse, error) {
-	klog.V(4).InfoS("ListSnapshots: called", "args", util.SanitizeRequest(req))
+	klog.FromContext(ctx).V(4).Info("ListSnapshots: called", "args", util.SanitizeRequest(req))
 	var snapshots []*cloud.Snapshot
 
 	snapshotID := req.GetSnapshotId()
@@ -1043,7 +1245,7 @@ func (d *ControllerService) ListSnapshots(ctx context.Context, req *csi.ListSnap
 		snapshot, err := d.cloud.GetSnapshotByID(ctx, snapshotID)
 		if err != nil {
 			if errors.Is(err, cloud.ErrNotFound) {
-				klog.V(4).InfoS("ListSnapshots: snapshot not found, returning with success")
+				klog.FromContext(ctx).V(4).Info("ListSnapshots: snapshot not found, returning with success")
 				return &csi.ListSnapshotsResponse{}, nil
 			}
 			return nil, status.Errorf(codes.Internal, "Could not get snapshot ID %q: %v", snapshotID, err)
@@ -1062,7 +1264,7 @@ func (d *ControllerService) ListSnapshots(ctx context.Context, req *csi.ListSnap
 	cloudSnapshots, err := d.cloud.ListSnapshots(ctx, volumeID, maxEntries, nextToken)
 	if err != nil {
 		if errors.Is(err, cloud.ErrNotFound) {
-			klog.V(4).InfoS("ListSnapshots: snapshot not found, returning with success")
+			klog.FromContext(ctx).V(4).Info("ListSnapshots: snapshot not found, returning with success")
 			return &csi.ListSnapshotsResponse{}, nil
 		}
 		if errors.Is(err, cloud.ErrInvalidMaxResults) {
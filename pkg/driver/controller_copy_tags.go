@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"slices"
+	"strings"
+)
+
+// tagCopyFilter selects the tags copied from the source volume of a snapshot, or from the source
+// snapshot of a volume. The include and exclude lists are tag keys, or key prefixes ending with
+// "*". The excluded keys take precedence over the included keys, and all the keys are included
+// when the include list is empty.
+type tagCopyFilter struct {
+	enabled bool
+	include []string
+	exclude []string
+}
+
+// parseTagKeyPatterns parses a comma separated list of tag keys and key prefixes.
+func parseTagKeyPatterns(value string) []string {
+	var patterns []string
+	for pattern := range strings.SplitSeq(value, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+func matchesTagKeyPattern(key string, patterns []string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			return strings.HasPrefix(key, prefix)
+		}
+		return key == pattern
+	})
+}
+
+// copyTags returns the tags of source selected by f, without the tags reserved by AWS and by the driver.
+func (f tagCopyFilter) copyTags(source map[string]string) map[string]string {
+	tags := map[string]string{}
+	if !f.enabled {
+		return tags
+	}
+	for key, value := range source {
+		if strings.HasPrefix(key, "aws:") || matchesTagKeyPattern(key, f.exclude) {
+			continue
+		}
+		if len(f.include) > 0 && !matchesTagKeyPattern(key, f.include) {
+			continue
+		}
+		// The reserved tags of the source are skipped, the driver sets its own on the new resource
+		if err := validateExtraTags(map[string]string{key: value}, false); err != nil {
+			continue
+		}
+		tags[key] = value
+	}
+	return tags
+}
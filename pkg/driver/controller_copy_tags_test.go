@@ -0,0 +1,147 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/driver/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagCopyFilter(t *testing.T) {
+	source := map[string]string{
+		"team":                   "storage",
+		"cost-center":            "42",
+		"cost-owner":             "alice",
+		"aws:backup:source":      "plan",
+		cloud.VolumeNameTagKey:   "pvc-1",
+		cloud.AwsEbsDriverTagKey: isManagedByDriver,
+		PVCNameTag:               "claim",
+	}
+
+	testCases := []struct {
+		name     string
+		filter   tagCopyFilter
+		expected map[string]string
+	}{
+		{
+			name:     "disabled",
+			filter:   tagCopyFilter{include: []string{"team"}},
+			expected: map[string]string{},
+		},
+		{
+			name:     "all tags",
+			filter:   tagCopyFilter{enabled: true},
+			expected: map[string]string{"team": "storage", "cost-center": "42", "cost-owner": "alice"},
+		},
+		{
+			name:     "include",
+			filter:   tagCopyFilter{enabled: true, include: parseTagKeyPatterns("team, cost-*")},
+			expected: map[string]string{"team": "storage", "cost-center": "42", "cost-owner": "alice"},
+		},
+		{
+			name:     "include and exclude",
+			filter:   tagCopyFilter{enabled: true, include: parseTagKeyPatterns("cost-*"), exclude: parseTagKeyPatterns("cost-owner")},
+			expected: map[string]string{"cost-center": "42"},
+		},
+		{
+			name:     "include reserved",
+			filter:   tagCopyFilter{enabled: true, include: parseTagKeyPatterns("CSIVolumeName,kubernetes.io/*")},
+			expected: map[string]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.filter.copyTags(source))
+		})
+	}
+}
+
+func TestCreateSnapshotCopyTags(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	mockCloud := cloud.NewMockCloud(mockCtl)
+	mockCloud.EXPECT().GetSnapshotByName(gomock.Any(), "test-snapshot").Return(nil, cloud.ErrNotFound)
+	mockCloud.EXPECT().GetDiskByID(gomock.Any(), "vol-test").Return(&cloud.Disk{VolumeID: "vol-test", Tags: map[string]string{
+		"team":                   "storage",
+		"key1":                   "volume-value",
+		"cost-center":            "42",
+		cloud.VolumeNameTagKey:   "pvc-1",
+		cloud.AwsEbsDriverTagKey: isManagedByDriver,
+	}}, nil)
+	mockCloud.EXPECT().CreateSnapshot(gomock.Any(), "vol-test", &cloud.SnapshotOptions{Tags: map[string]string{
+		cloud.SnapshotNameTagKey: "test-snapshot",
+		cloud.AwsEbsDriverTagKey: isManagedByDriver,
+		"team":                   "storage",
+		"key1":                   "value1",
+	}}).Return(&cloud.Snapshot{SnapshotID: "snap-test", SourceVolumeID: "vol-test"}, nil)
+
+	d := ControllerService{cloud: mockCloud, inFlight: internal.NewInFlight(), options: &Options{}}
+	_, err := d.CreateSnapshot(t.Context(), &csi.CreateSnapshotRequest{
+		Name:           "test-snapshot",
+		SourceVolumeId: "vol-test",
+		Parameters: map[string]string{
+			"copyTagsFromVolume": "true",
+			"copyTagsExclude":    "cost-*",
+			"tagSpecification_1": "key1=value1",
+		},
+	})
+	require.NoError(t, err)
+}
+
+func TestCreateVolumeCopyTags(t *testing.T) {
+	mockCtl := gomock.NewController(t)
+	mockCloud := cloud.NewMockCloud(mockCtl)
+	mockCloud.EXPECT().GetSnapshotByID(gomock.Any(), "snap-test").Return(&cloud.Snapshot{SnapshotID: "snap-test", Tags: map[string]string{
+		"team":                   "storage",
+		"cost-center":            "42",
+		cloud.SnapshotNameTagKey: "snapcontent-1",
+		cloud.AwsEbsDriverTagKey: isManagedByDriver,
+	}}, nil)
+	mockCloud.EXPECT().CreateDisk(gomock.Any(), "vol-name", gomock.Any()).DoAndReturn(
+		func(_ any, _ string, opts *cloud.DiskOptions) (*cloud.Disk, error) {
+			assert.Equal(t, map[string]string{
+				cloud.VolumeNameTagKey:   "vol-name",
+				cloud.AwsEbsDriverTagKey: isManagedByDriver,
+				"cost-center":            "42",
+			}, opts.Tags)
+			return &cloud.Disk{VolumeID: "vol-test", CapacityGiB: 1, AvailabilityZone: "us-east-1a"}, nil
+		})
+
+	d := ControllerService{cloud: mockCloud, inFlight: internal.NewInFlight(), options: &Options{}}
+	_, err := d.CreateVolume(t.Context(), &csi.CreateVolumeRequest{
+		Name:          "vol-name",
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 1 << 30},
+		VolumeCapabilities: []*csi.VolumeCapability{{
+			AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+			AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+		}},
+		Parameters: map[string]string{
+			"copyTagsFromSnapshot": "true",
+			"copyTagsInclude":      "cost-center",
+		},
+		VolumeContentSource: &csi.VolumeContentSource{
+			Type: &csi.VolumeContentSource_Snapshot{Snapshot: &csi.VolumeContentSource_SnapshotSource{SnapshotId: "snap-test"}},
+		},
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/metrics"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// deferredModificationApplyDelay is added to the time after which a deferred modification can be
+// applied, so that it is not rejected again because of clock skew.
+const deferredModificationApplyDelay = time.Minute
+
+// deferredModifications applies the volume modifications deferred by ResizeOrModifyDisk once EBS
+// allows their volume to be modified again. Modifications deferred before a restart of the controller
+// are applied by the next ControllerExpandVolume or ControllerModifyVolume of their volume, which the
+// CSI sidecars retry.
+type deferredModifications struct {
+	mu     sync.Mutex
+	timers map[string]*time.Timer
+	// apply applies the deferred modification of a volume.
+	apply func(volumeID string) error
+}
+
+func newDeferredModifications() *deferredModifications {
+	return &deferredModifications{timers: make(map[string]*time.Timer)}
+}
+
+// schedule schedules the deferred modification of volumeID to be applied after until.
+func (m *deferredModifications) schedule(volumeID string, until time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if timer, ok := m.timers[volumeID]; ok {
+		timer.Stop()
+	}
+	m.timers[volumeID] = time.AfterFunc(time.Until(until)+deferredModificationApplyDelay, func() { m.run(volumeID) })
+	metrics.Recorder().SetGauge(metrics.DeferredModification, metrics.DeferredModificationHelpText, float64(until.Unix()),
+		map[string]string{"volume_id": volumeID})
+	klog.InfoS("Scheduled deferred volume modification", "volumeID", volumeID, "until", until)
+}
+
+// forget forgets the deferred modification of volumeID, after it was applied or could not be applied.
+func (m *deferredModifications) forget(volumeID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	timer, ok := m.timers[volumeID]
+	if !ok {
+		return
+	}
+	timer.Stop()
+	delete(m.timers, volumeID)
+	metrics.Recorder().DeleteGauge(metrics.DeferredModification, map[string]string{"volume_id": volumeID})
+}
+
+func (m *deferredModifications) run(volumeID string) {
+	klog.InfoS("Applying deferred volume modification", "volumeID", volumeID)
+	// An Unavailable error means that the modification was deferred again, and rescheduled
+	if err := m.apply(volumeID); err != nil && status.Code(err) != codes.Unavailable {
+		// The modification is applied by the next retry of the CSI sidecars
+		klog.ErrorS(err, "Could not apply deferred volume modification", "volumeID", volumeID)
+		m.forget(volumeID)
+	}
+}
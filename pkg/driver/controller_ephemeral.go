@@ -0,0 +1,416 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud/metadata"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/driver/internal"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ephemeralGCInterval is how often volumes of ephemeral inline volumes whose pod is gone are deleted.
+	ephemeralGCInterval = 5 * time.Minute
+
+	// Extra fields of the user info of service account tokens bound to a pod.
+	tokenReviewNodeNameKey = "authentication.kubernetes.io/node-name"
+	tokenReviewPodNameKey  = "authentication.kubernetes.io/pod-name"
+
+	maxEphemeralAPIRequestBytes = 1 << 16
+)
+
+// ephemeralVolumeServer serves the ephemeral volume API. Node plugins are only allowed to manage
+// the volumes of ephemeral inline volumes of pods scheduled to their own node.
+type ephemeralVolumeServer struct {
+	cloud    cloud.Cloud
+	k8s      kubernetes.Interface
+	options  *Options
+	inFlight *internal.InFlight
+	// nodeServiceAccountNamespace and nodeServiceAccountUsername identify the service account of the node plugin.
+	nodeServiceAccountNamespace string
+	nodeServiceAccountUsername  string
+}
+
+func newEphemeralVolumeServer(c cloud.Cloud, o *Options, k kubernetes.Interface) *ephemeralVolumeServer {
+	namespace, name, _ := strings.Cut(o.EphemeralVolumesNodeServiceAccount, "/")
+	return &ephemeralVolumeServer{
+		cloud:                       c,
+		k8s:                         k,
+		options:                     o,
+		inFlight:                    internal.NewInFlight(),
+		nodeServiceAccountNamespace: namespace,
+		nodeServiceAccountUsername:  "system:serviceaccount:" + namespace + ":" + name,
+	}
+}
+
+// Start serves the ephemeral volume API on --ephemeral-volumes-listen-address and starts the
+// garbage collection of orphaned volumes, until ctx is done.
+func (s *ephemeralVolumeServer) Start(ctx context.Context) error {
+	listenConfig := net.ListenConfig{}
+	listener, err := listenConfig.Listen(ctx, "tcp", s.options.EphemeralVolumesListenAddress)
+	if err != nil {
+		return fmt.Errorf("failed to listen for the ephemeral volume API: %w", err)
+	}
+
+	srv := &http.Server{
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		// The API carries service account tokens, Options.Validate requires a certificate
+		err := srv.ServeTLS(listener, s.options.EphemeralVolumesCertFile, s.options.EphemeralVolumesKeyFile)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			klog.ErrorS(err, "Ephemeral volume API server stopped")
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+	go wait.UntilWithContext(ctx, s.garbageCollect, ephemeralGCInterval)
+
+	klog.InfoS("Serving the ephemeral volume API", "address", listener.Addr())
+	return nil
+}
+
+func (s *ephemeralVolumeServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+ephemeralAPIPublishPath, s.handle(func(ctx context.Context, nodeName string, req *ephemeralVolumeRequest) (any, error) {
+		return s.publish(ctx, nodeName, req)
+	}))
+	mux.HandleFunc("POST "+ephemeralAPIUnpublishPath, s.handle(func(ctx context.Context, nodeName string, req *ephemeralVolumeRequest) (any, error) {
+		return struct{}{}, s.unpublish(ctx, nodeName, req)
+	}))
+	return mux
+}
+
+// handle authenticates the node plugin calling the API and decodes its request for fn.
+func (s *ephemeralVolumeServer) handle(fn func(ctx context.Context, nodeName string, req *ephemeralVolumeRequest) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		req := &ephemeralVolumeRequest{}
+		var resp any
+		nodeName, err := s.authenticate(ctx, r)
+		if err == nil {
+			if decodeErr := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxEphemeralAPIRequestBytes)).Decode(req); decodeErr != nil {
+				err = status.Errorf(codes.InvalidArgument, "Could not decode request: %v", decodeErr)
+			} else if !isEphemeralVolumeHandle(req.VolumeHandle) {
+				err = status.Errorf(codes.InvalidArgument, "Invalid volume handle %q", req.VolumeHandle)
+			} else {
+				resp, err = fn(ctx, nodeName, req)
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err != nil {
+			klog.FromContext(ctx).Error(err, "Ephemeral volume API request failed", "path", r.URL.Path, "nodeName", nodeName, "volumeHandle", req.VolumeHandle)
+			code, apiErr := ephemeralAPIStatusCode(err)
+			w.WriteHeader(code)
+			_ = json.NewEncoder(w).Encode(apiErr)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+}
+
+// authenticate reviews the service account token of the request and returns the name of the
+// node the calling node plugin runs on.
+func (s *ephemeralVolumeServer) authenticate(ctx context.Context, r *http.Request) (string, error) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return "", status.Error(codes.Unauthenticated, "Bearer token not provided")
+	}
+
+	review, err := s.k8s.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: []string{EphemeralAPIAudience},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "Could not review token: %v", err)
+	}
+	if !review.Status.Authenticated {
+		return "", status.Errorf(codes.Unauthenticated, "Invalid token: %s", review.Status.Error)
+	}
+	user := review.Status.User
+	if user.Username != s.nodeServiceAccountUsername {
+		return "", status.Errorf(codes.PermissionDenied, "%s is not allowed to manage ephemeral volumes", user.Username)
+	}
+
+	// Tokens bound to a pod carry the name of its node since Kubernetes 1.32, fall back to the pod otherwise
+	if nodeNames := user.Extra[tokenReviewNodeNameKey]; len(nodeNames) == 1 && nodeNames[0] != "" {
+		return nodeNames[0], nil
+	}
+	podNames := user.Extra[tokenReviewPodNameKey]
+	if len(podNames) != 1 {
+		return "", status.Error(codes.PermissionDenied, "Token is not bound to a pod")
+	}
+	pod, err := s.k8s.CoreV1().Pods(s.nodeServiceAccountNamespace).Get(ctx, podNames[0], metav1.GetOptions{})
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "Could not get node plugin pod %s: %v", podNames[0], err)
+	}
+	if pod.Spec.NodeName == "" {
+		return "", status.Errorf(codes.PermissionDenied, "Node plugin pod %s is not scheduled", podNames[0])
+	}
+	return pod.Spec.NodeName, nil
+}
+
+// publish creates the volume of an ephemeral inline volume, if it does not exist yet, and attaches it to nodeName.
+func (s *ephemeralVolumeServer) publish(ctx context.Context, nodeName string, req *ephemeralVolumeRequest) (*ephemeralPublishResponse, error) {
+	pod, err := s.k8s.CoreV1().Pods(req.PodNamespace).Get(ctx, req.PodName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, status.Errorf(codes.NotFound, "Pod %s/%s not found", req.PodNamespace, req.PodName)
+		}
+		return nil, status.Errorf(codes.Internal, "Could not get pod %s/%s: %v", req.PodNamespace, req.PodName, err)
+	}
+	if string(pod.UID) != req.PodUID || pod.Spec.NodeName != nodeName {
+		return nil, status.Errorf(codes.PermissionDenied, "Pod %s/%s with UID %s does not run on node %s", req.PodNamespace, req.PodName, req.PodUID, nodeName)
+	}
+	attributes, ok := ephemeralVolumeAttributes(pod, req.VolumeHandle)
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "Pod %s/%s has no ephemeral inline volume with handle %s", req.PodNamespace, req.PodName, req.VolumeHandle)
+	}
+	opts, err := parseEphemeralVolumeAttributes(attributes)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid ephemeral volume attributes: %v", err)
+	}
+
+	node, err := s.k8s.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not get node %s: %v", nodeName, err)
+	}
+	instanceID, err := metadata.ParseProviderID(node)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "Could not get instance ID of node %s: %v", nodeName, err)
+	}
+	zone := node.Labels[WellKnownZoneTopologyKey]
+	if zone == "" {
+		return nil, status.Errorf(codes.FailedPrecondition, "Node %s has no %s label", nodeName, WellKnownZoneTopologyKey)
+	}
+
+	if ok := s.inFlight.Insert(req.VolumeHandle); !ok {
+		return nil, status.Errorf(codes.Aborted, VolumeOperationAlreadyExists, req.VolumeHandle)
+	}
+	defer s.inFlight.Delete(req.VolumeHandle)
+
+	disk, err := s.findDisk(ctx, req.VolumeHandle)
+	if err != nil {
+		return nil, err
+	}
+	if disk == nil {
+		klog.FromContext(ctx).Info("Creating ephemeral volume", "volumeHandle", req.VolumeHandle, "pod", klog.KObj(pod), "nodeName", nodeName)
+		disk, err = s.cloud.CreateDisk(ctx, req.VolumeHandle, &cloud.DiskOptions{
+			CapacityBytes:    opts.CapacityBytes,
+			Tags:             s.volumeTags(req, nodeName),
+			VolumeType:       opts.VolumeType,
+			IOPS:             opts.IOPS,
+			Throughput:       opts.Throughput,
+			AvailabilityZone: zone,
+			Encrypted:        opts.Encrypted,
+			KmsKeyID:         opts.KmsKeyID,
+		})
+		if err != nil {
+			if errors.Is(err, cloud.ErrInvalidArgument) {
+				return nil, status.Errorf(codes.InvalidArgument, "Could not create ephemeral volume %q: %v", req.VolumeHandle, err)
+			}
+			return nil, status.Errorf(codes.Internal, "Could not create ephemeral volume %q: %v", req.VolumeHandle, err)
+		}
+	}
+
+	devicePath, err := s.cloud.AttachDisk(ctx, disk.VolumeID, instanceID)
+	if err != nil {
+		if errors.Is(err, cloud.ErrLimitExceeded) {
+			return nil, status.Errorf(codes.ResourceExhausted, "Attachment limit exceeded for volume %q on node %q: %v", disk.VolumeID, nodeName, err)
+		}
+		return nil, status.Errorf(codes.Internal, "Could not attach volume %q to node %q: %v", disk.VolumeID, nodeName, err)
+	}
+	klog.FromContext(ctx).Info("Attached ephemeral volume", "volumeHandle", req.VolumeHandle, "volumeID", disk.VolumeID, "nodeName", nodeName, "devicePath", devicePath)
+
+	return &ephemeralPublishResponse{VolumeID: disk.VolumeID, DevicePath: devicePath}, nil
+}
+
+// unpublish detaches and deletes the volume of an ephemeral inline volume published to nodeName.
+func (s *ephemeralVolumeServer) unpublish(ctx context.Context, nodeName string, req *ephemeralVolumeRequest) error {
+	if ok := s.inFlight.Insert(req.VolumeHandle); !ok {
+		return status.Errorf(codes.Aborted, VolumeOperationAlreadyExists, req.VolumeHandle)
+	}
+	defer s.inFlight.Delete(req.VolumeHandle)
+
+	disk, err := s.findDisk(ctx, req.VolumeHandle)
+	if err != nil {
+		return err
+	}
+	if disk == nil {
+		klog.FromContext(ctx).V(4).Info("Ephemeral volume not found, assuming it was deleted", "volumeHandle", req.VolumeHandle)
+		return nil
+	}
+	if disk.Tags[EphemeralNodeNameTagKey] != nodeName {
+		return status.Errorf(codes.PermissionDenied, "Ephemeral volume %q does not belong to node %s", req.VolumeHandle, nodeName)
+	}
+	return s.deleteDisk(ctx, disk)
+}
+
+// garbageCollect deletes the volumes of ephemeral inline volumes whose pod or node no longer
+// exists, which were not unpublished because the node plugin or its node failed.
+func (s *ephemeralVolumeServer) garbageCollect(ctx context.Context) {
+	disks, err := s.cloud.ListDisks(ctx, map[string]string{
+		ResourceLifecycleTagPrefix + s.options.KubernetesClusterID: ResourceLifecycleOwned,
+		EphemeralPodUIDTagKey: "",
+	})
+	if err != nil {
+		klog.ErrorS(err, "Failed to list ephemeral volumes")
+		return
+	}
+
+	for _, disk := range disks {
+		orphaned, err := s.isOrphaned(ctx, disk)
+		if err != nil {
+			klog.ErrorS(err, "Failed to check if ephemeral volume is orphaned", "volumeID", disk.VolumeID)
+			continue
+		}
+		if !orphaned {
+			continue
+		}
+
+		handle := disk.Tags[cloud.VolumeNameTagKey]
+		if ok := s.inFlight.Insert(handle); !ok {
+			continue
+		}
+		klog.InfoS("Deleting orphaned ephemeral volume", "volumeID", disk.VolumeID, "volumeHandle", handle,
+			"pod", klog.KRef(disk.Tags[EphemeralPodNamespaceTagKey], disk.Tags[EphemeralPodNameTagKey]), "nodeName", disk.Tags[EphemeralNodeNameTagKey])
+		if err := s.deleteDisk(ctx, disk); err != nil {
+			klog.ErrorS(err, "Failed to delete orphaned ephemeral volume", "volumeID", disk.VolumeID)
+		}
+		s.inFlight.Delete(handle)
+	}
+}
+
+// isOrphaned returns true if the pod or the node of the ephemeral volume disk no longer exists.
+func (s *ephemeralVolumeServer) isOrphaned(ctx context.Context, disk *cloud.Disk) (bool, error) {
+	pod, err := s.k8s.CoreV1().Pods(disk.Tags[EphemeralPodNamespaceTagKey]).Get(ctx, disk.Tags[EphemeralPodNameTagKey], metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	// A pod with the same name may have been created again
+	if string(pod.UID) != disk.Tags[EphemeralPodUIDTagKey] {
+		return true, nil
+	}
+
+	_, err = s.k8s.CoreV1().Nodes().Get(ctx, disk.Tags[EphemeralNodeNameTagKey], metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return true, nil
+	}
+	return false, err
+}
+
+// findDisk returns the volume of the ephemeral inline volume with handle, or nil if it does not exist.
+func (s *ephemeralVolumeServer) findDisk(ctx context.Context, handle string) (*cloud.Disk, error) {
+	disks, err := s.cloud.ListDisks(ctx, map[string]string{
+		cloud.VolumeNameTagKey: handle,
+		EphemeralPodUIDTagKey:  "",
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not get ephemeral volume %q: %v", handle, err)
+	}
+	switch len(disks) {
+	case 0:
+		return nil, nil
+	case 1:
+		return disks[0], nil
+	default:
+		return nil, status.Errorf(codes.Internal, "Found %d volumes for ephemeral volume %q", len(disks), handle)
+	}
+}
+
+func (s *ephemeralVolumeServer) deleteDisk(ctx context.Context, disk *cloud.Disk) error {
+	for _, instanceID := range disk.Attachments {
+		klog.FromContext(ctx).V(2).Info("Detaching ephemeral volume", "volumeID", disk.VolumeID, "instanceID", instanceID)
+		if err := s.cloud.DetachDisk(ctx, disk.VolumeID, instanceID); err != nil && !errors.Is(err, cloud.ErrNotFound) {
+			return status.Errorf(codes.Internal, "Could not detach volume %q from instance %q: %v", disk.VolumeID, instanceID, err)
+		}
+	}
+	klog.FromContext(ctx).Info("Deleting ephemeral volume", "volumeID", disk.VolumeID)
+	if _, err := s.cloud.DeleteDisk(ctx, disk.VolumeID); err != nil && !errors.Is(err, cloud.ErrNotFound) {
+		return status.Errorf(codes.Internal, "Could not delete volume %q: %v", disk.VolumeID, err)
+	}
+	return nil
+}
+
+// volumeTags returns the tags of the volume of an ephemeral inline volume, which identify its pod
+// and node for crash recovery.
+func (s *ephemeralVolumeServer) volumeTags(req *ephemeralVolumeRequest, nodeName string) map[string]string {
+	tags := map[string]string{}
+	maps.Copy(tags, s.options.ExtraTags)
+	maps.Copy(tags, map[string]string{
+		cloud.VolumeNameTagKey:      req.VolumeHandle,
+		cloud.AwsEbsDriverTagKey:    isManagedByDriver,
+		EphemeralPodUIDTagKey:       req.PodUID,
+		EphemeralPodNamespaceTagKey: req.PodNamespace,
+		EphemeralPodNameTagKey:      req.PodName,
+		EphemeralNodeNameTagKey:     nodeName,
+		ResourceLifecycleTagPrefix + s.options.KubernetesClusterID: ResourceLifecycleOwned,
+		NameTag:              s.options.KubernetesClusterID + "-ephemeral-" + req.VolumeHandle,
+		KubernetesClusterTag: s.options.KubernetesClusterID,
+		ClusterNameTagKey:    s.options.KubernetesClusterID,
+	})
+	return tags
+}
+
+// ephemeralVolumeAttributes returns the volumeAttributes of the ephemeral inline volume of pod
+// with volumeHandle, which kubelet computes from the pod UID and the name of the volume.
+func ephemeralVolumeAttributes(pod *corev1.Pod, volumeHandle string) (map[string]string, bool) {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.CSI == nil || volume.CSI.Driver != util.GetDriverName() {
+			continue
+		}
+		if makeEphemeralVolumeHandle(string(pod.UID), volume.Name) == volumeHandle {
+			return volume.CSI.VolumeAttributes, true
+		}
+	}
+	return nil, false
+}
+
+// makeEphemeralVolumeHandle returns the volume handle kubelet generates for an ephemeral inline volume.
+func makeEphemeralVolumeHandle(podUID, volumeName string) string {
+	return fmt.Sprintf("%s%x", EphemeralVolumeHandlePrefix, sha256.Sum256([]byte(podUID+volumeName)))
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const (
+	testEphemeralNode     = "node-1"
+	testEphemeralInstance = "i-0123456789abcdef0"
+	testEphemeralPodUID   = "9c5b8a2e-1f3d-4c6b-8e7a-0d1f2e3c4b5a"
+	testEphemeralToken    = "node-token"
+)
+
+func newEphemeralTestObjects() []runtime.Object {
+	return []runtime.Object{
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: testEphemeralNode, Labels: map[string]string{WellKnownZoneTopologyKey: "us-east-1a"}},
+			Spec:       corev1.NodeSpec{ProviderID: "aws:///us-east-1a/" + testEphemeralInstance},
+		},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default", UID: types.UID(testEphemeralPodUID)},
+			Spec: corev1.PodSpec{
+				NodeName: testEphemeralNode,
+				Volumes: []corev1.Volume{{
+					Name: "scratch",
+					VolumeSource: corev1.VolumeSource{CSI: &corev1.CSIVolumeSource{
+						Driver:           util.GetDriverName(),
+						VolumeAttributes: map[string]string{"size": "4Gi", "type": "gp3"},
+					}},
+				}},
+			},
+		},
+	}
+}
+
+// newEphemeralTestClient serves s and returns a client of it authenticating with testEphemeralToken.
+func newEphemeralTestClient(t *testing.T, s *ephemeralVolumeServer) *ephemeralVolumeClient {
+	t.Helper()
+	srv := httptest.NewServer(s.handler())
+	t.Cleanup(srv.Close)
+
+	client, err := newEphemeralVolumeClient(srv.URL, "")
+	require.NoError(t, err)
+	client.tokenPath = filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(client.tokenPath, []byte(testEphemeralToken+"\n"), 0o600))
+	return client
+}
+
+// newEphemeralTestClientset returns a clientset reviewing testEphemeralToken as the token of
+// the node plugin pod running on testEphemeralNode.
+func newEphemeralTestClientset(objects ...runtime.Object) *fake.Clientset {
+	clientset := fake.NewClientset(objects...)
+	clientset.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		if review.Spec.Token == testEphemeralToken && len(review.Spec.Audiences) == 1 && review.Spec.Audiences[0] == EphemeralAPIAudience {
+			review.Status = authenticationv1.TokenReviewStatus{
+				Authenticated: true,
+				User: authenticationv1.UserInfo{
+					Username: "system:serviceaccount:kube-system:ebs-csi-node-sa",
+					Extra:    map[string]authenticationv1.ExtraValue{tokenReviewNodeNameKey: {testEphemeralNode}},
+				},
+			}
+		}
+		return true, review, nil
+	})
+	return clientset
+}
+
+func TestEphemeralVolumeServer(t *testing.T) {
+	initVariables()
+	handle := makeEphemeralVolumeHandle(testEphemeralPodUID, "scratch")
+	publishReq := &ephemeralVolumeRequest{VolumeHandle: handle, PodNamespace: "default", PodName: "app", PodUID: testEphemeralPodUID}
+	findTags := map[string]string{cloud.VolumeNameTagKey: handle, EphemeralPodUIDTagKey: ""}
+
+	testCases := []struct {
+		name           string
+		unpublish      bool
+		req            *ephemeralVolumeRequest
+		token          string
+		serviceAccount string
+		mockCloud      func(c *cloud.MockCloud)
+		expectedResp   *ephemeralPublishResponse
+		expectedCode   codes.Code
+	}{
+		{
+			name: "publish creates and attaches the volume",
+			req:  publishReq,
+			mockCloud: func(c *cloud.MockCloud) {
+				c.EXPECT().ListDisks(gomock.Any(), findTags).Return(nil, nil)
+				c.EXPECT().CreateDisk(gomock.Any(), handle, gomock.Any()).DoAndReturn(func(_ context.Context, _ string, opts *cloud.DiskOptions) (*cloud.Disk, error) {
+					assert.Equal(t, int64(4*1024*1024*1024), opts.CapacityBytes)
+					assert.Equal(t, "gp3", opts.VolumeType)
+					assert.Equal(t, "us-east-1a", opts.AvailabilityZone)
+					assert.Equal(t, testEphemeralPodUID, opts.Tags[EphemeralPodUIDTagKey])
+					assert.Equal(t, testEphemeralNode, opts.Tags[EphemeralNodeNameTagKey])
+					assert.Equal(t, ResourceLifecycleOwned, opts.Tags[ResourceLifecycleTagPrefix+"cluster-123"])
+					assert.Equal(t, "bar", opts.Tags["foo"])
+					return &cloud.Disk{VolumeID: "vol-test"}, nil
+				})
+				c.EXPECT().AttachDisk(gomock.Any(), "vol-test", testEphemeralInstance).Return("/dev/xvdba", nil)
+			},
+			expectedResp: &ephemeralPublishResponse{VolumeID: "vol-test", DevicePath: "/dev/xvdba"},
+		},
+		{
+			name: "publish attaches an existing volume",
+			req:  publishReq,
+			mockCloud: func(c *cloud.MockCloud) {
+				c.EXPECT().ListDisks(gomock.Any(), findTags).Return([]*cloud.Disk{{VolumeID: "vol-test"}}, nil)
+				c.EXPECT().AttachDisk(gomock.Any(), "vol-test", testEphemeralInstance).Return("/dev/xvdba", nil)
+			},
+			expectedResp: &ephemeralPublishResponse{VolumeID: "vol-test", DevicePath: "/dev/xvdba"},
+		},
+		{
+			name: "publish attachment limit exceeded",
+			req:  publishReq,
+			mockCloud: func(c *cloud.MockCloud) {
+				c.EXPECT().ListDisks(gomock.Any(), findTags).Return([]*cloud.Disk{{VolumeID: "vol-test"}}, nil)
+				c.EXPECT().AttachDisk(gomock.Any(), "vol-test", testEphemeralInstance).Return("", cloud.ErrLimitExceeded)
+			},
+			expectedCode: codes.ResourceExhausted,
+		},
+		{
+			name:         "publish volume of another pod",
+			req:          &ephemeralVolumeRequest{VolumeHandle: makeEphemeralVolumeHandle("other-uid", "scratch"), PodNamespace: "default", PodName: "app", PodUID: "other-uid"},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "publish volume not in pod spec",
+			req:          &ephemeralVolumeRequest{VolumeHandle: makeEphemeralVolumeHandle(testEphemeralPodUID, "other"), PodNamespace: "default", PodName: "app", PodUID: testEphemeralPodUID},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "publish pod not found",
+			req:          &ephemeralVolumeRequest{VolumeHandle: handle, PodNamespace: "default", PodName: "missing", PodUID: testEphemeralPodUID},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:         "invalid token",
+			req:          publishReq,
+			token:        "other-token",
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:           "token of another service account",
+			req:            publishReq,
+			serviceAccount: "kube-system/other-sa",
+			expectedCode:   codes.PermissionDenied,
+		},
+		{
+			name:         "invalid volume handle",
+			req:          &ephemeralVolumeRequest{VolumeHandle: "vol-test"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:      "unpublish detaches and deletes the volume",
+			unpublish: true,
+			req:       &ephemeralVolumeRequest{VolumeHandle: handle},
+			mockCloud: func(c *cloud.MockCloud) {
+				c.EXPECT().ListDisks(gomock.Any(), findTags).Return([]*cloud.Disk{{
+					VolumeID:    "vol-test",
+					Attachments: []string{testEphemeralInstance},
+					Tags:        map[string]string{EphemeralNodeNameTagKey: testEphemeralNode},
+				}}, nil)
+				c.EXPECT().DetachDisk(gomock.Any(), "vol-test", testEphemeralInstance).Return(nil)
+				c.EXPECT().DeleteDisk(gomock.Any(), "vol-test").Return(true, nil)
+			},
+		},
+		{
+			name:      "unpublish volume already deleted",
+			unpublish: true,
+			req:       &ephemeralVolumeRequest{VolumeHandle: handle},
+			mockCloud: func(c *cloud.MockCloud) {
+				c.EXPECT().ListDisks(gomock.Any(), findTags).Return(nil, nil)
+			},
+		},
+		{
+			name:      "unpublish volume of another node",
+			unpublish: true,
+			req:       &ephemeralVolumeRequest{VolumeHandle: handle},
+			mockCloud: func(c *cloud.MockCloud) {
+				c.EXPECT().ListDisks(gomock.Any(), findTags).Return([]*cloud.Disk{{
+					VolumeID: "vol-test",
+					Tags:     map[string]string{EphemeralNodeNameTagKey: "node-2"},
+				}}, nil)
+			},
+			expectedCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockCloud := cloud.NewMockCloud(ctrl)
+			if tc.mockCloud != nil {
+				tc.mockCloud(mockCloud)
+			}
+			serviceAccount := "kube-system/ebs-csi-node-sa"
+			if tc.serviceAccount != "" {
+				serviceAccount = tc.serviceAccount
+			}
+			s := newEphemeralVolumeServer(mockCloud, &Options{
+				KubernetesClusterID:                "cluster-123",
+				ExtraTags:                          map[string]string{"foo": "bar"},
+				EphemeralVolumesNodeServiceAccount: serviceAccount,
+			}, newEphemeralTestClientset(newEphemeralTestObjects()...))
+			client := newEphemeralTestClient(t, s)
+			if tc.token != "" {
+				require.NoError(t, os.WriteFile(client.tokenPath, []byte(tc.token), 0o600))
+			}
+
+			var resp *ephemeralPublishResponse
+			var err error
+			if tc.unpublish {
+				err = client.Unpublish(t.Context(), tc.req)
+			} else {
+				resp, err = client.Publish(t.Context(), tc.req)
+			}
+
+			if tc.expectedCode != codes.OK {
+				require.Error(t, err)
+				assert.Equal(t, tc.expectedCode, status.Code(err), err)
+				return
+			}
+			require.NoError(t, err)
+			if !tc.unpublish {
+				assert.Equal(t, tc.expectedResp, resp)
+			}
+		})
+	}
+}
+
+func TestEphemeralVolumeServerGarbageCollect(t *testing.T) {
+	initVariables()
+	ctrl := gomock.NewController(t)
+	mockCloud := cloud.NewMockCloud(ctrl)
+
+	disk := func(volumeID, podName, podUID, nodeName string) *cloud.Disk {
+		return &cloud.Disk{
+			VolumeID:    volumeID,
+			Attachments: []string{testEphemeralInstance},
+			Tags: map[string]string{
+				cloud.VolumeNameTagKey:      makeEphemeralVolumeHandle(podUID, "scratch"),
+				EphemeralPodNamespaceTagKey: "default",
+				EphemeralPodNameTagKey:      podName,
+				EphemeralPodUIDTagKey:       podUID,
+				EphemeralNodeNameTagKey:     nodeName,
+			},
+		}
+	}
+	mockCloud.EXPECT().ListDisks(gomock.Any(), map[string]string{
+		ResourceLifecycleTagPrefix + "cluster-123": ResourceLifecycleOwned,
+		EphemeralPodUIDTagKey:                      "",
+	}).Return([]*cloud.Disk{
+		disk("vol-running", "app", testEphemeralPodUID, testEphemeralNode),
+		disk("vol-pod-deleted", "deleted", "deleted-uid", testEphemeralNode),
+		disk("vol-pod-recreated", "app", "old-uid", testEphemeralNode),
+		disk("vol-node-deleted", "app", testEphemeralPodUID, "node-deleted"),
+	}, nil)
+	for _, volumeID := range []string{"vol-pod-deleted", "vol-pod-recreated", "vol-node-deleted"} {
+		mockCloud.EXPECT().DetachDisk(gomock.Any(), volumeID, testEphemeralInstance).Return(cloud.ErrNotFound)
+		mockCloud.EXPECT().DeleteDisk(gomock.Any(), volumeID).Return(true, nil)
+	}
+
+	s := newEphemeralVolumeServer(mockCloud, &Options{
+		KubernetesClusterID:                "cluster-123",
+		EphemeralVolumesNodeServiceAccount: "kube-system/ebs-csi-node-sa",
+	}, fake.NewClientset(newEphemeralTestObjects()...))
+	s.garbageCollect(t.Context())
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"time"
+
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+const (
+	eventReasonVolumeLimitExceeded             = "VolumeLimitExceeded"
+	eventReasonVolumeIOPSAdjusted              = "VolumeIOPSAdjusted"
+	eventReasonAttachmentLimitExceeded         = "VolumeAttachmentLimitExceeded"
+	eventReasonAttachmentStuck                 = "VolumeAttachmentStuck"
+	eventReasonAttachmentSlow                  = "VolumeAttachmentSlow"
+	eventReasonVolumeModificationLimitExceeded = "VolumeModificationLimitExceeded"
+	eventReasonVolumeModificationDeferred      = "VolumeModificationDeferred"
+	eventReasonVolumeMigrationPending          = "VolumeMigrationPending"
+	eventReasonVolumeMigrationStarted          = "VolumeMigrationStarted"
+	eventReasonVolumeMigrated                  = "VolumeMigrated"
+	eventReasonVolumeMigrationFailed           = "VolumeMigrationFailed"
+
+	// slowAttachThreshold is the duration of ControllerPublishVolume after which an event is
+	// emitted about the slow attachment.
+	slowAttachThreshold = time.Minute
+
+	volumeHandleIndex = "volumeHandle"
+)
+
+// newControllerEventRecorder returns an EventRecorder that emits events on behalf of the controller.
+// The events are deduplicated and rate limited per object by the event correlator of client-go.
+func newControllerEventRecorder(k kubernetes.Interface) record.EventRecorder {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: k.CoreV1().Events("")})
+	return broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: util.GetDriverName() + "-controller"})
+}
+
+// pvcFromParameters returns the namespace and name of the PersistentVolumeClaim in the parameters
+// the external-provisioner and external-resizer pass with --extra-create-metadata and --extra-modify-metadata.
+func pvcFromParameters(params map[string]string) (namespace, name string) {
+	return params[PVCNamespaceKey], params[PVCNameKey]
+}
+
+// volumeEventObjects resolves the PersistentVolumeClaims on which the events of volumes are emitted
+// from informer caches, so that emitting an event does not call the API server. Until the caches are
+// synced, events are emitted on a reference without UID or not at all.
+type volumeEventObjects struct {
+	factory    informers.SharedInformerFactory
+	driverName string
+
+	pvIndexer cache.Indexer
+	pvcLister corelisters.PersistentVolumeClaimLister
+	synced    []cache.InformerSynced
+}
+
+func newVolumeEventObjects(factory informers.SharedInformerFactory) *volumeEventObjects {
+	return &volumeEventObjects{factory: factory, driverName: util.GetDriverName()}
+}
+
+// start starts the informers of PersistentVolumes and PersistentVolumeClaims, which run for the
+// lifetime of the controller.
+func (o *volumeEventObjects) start() {
+	pvInformer := o.factory.Core().V1().PersistentVolumes().Informer()
+	if err := pvInformer.AddIndexers(cache.Indexers{volumeHandleIndex: o.volumeHandleIndexFunc}); err != nil {
+		klog.ErrorS(err, "Could not index PersistentVolumes by volume handle, the events of attachments are not emitted")
+	} else {
+		o.pvIndexer = pvInformer.GetIndexer()
+	}
+	pvcInformer := o.factory.Core().V1().PersistentVolumeClaims()
+	o.pvcLister = pvcInformer.Lister()
+	o.synced = []cache.InformerSynced{pvInformer.HasSynced, pvcInformer.Informer().HasSynced}
+	o.factory.Start(wait.NeverStop)
+}
+
+// hasSynced returns true once the informers are started and their caches are synced.
+func (o *volumeEventObjects) hasSynced() bool {
+	if o == nil || len(o.synced) == 0 {
+		return false
+	}
+	for _, synced := range o.synced {
+		if !synced() {
+			return false
+		}
+	}
+	return true
+}
+
+// byName returns the PersistentVolumeClaim namespace/name. A reference without UID, which
+// kubectl describe does not list, is returned if the PersistentVolumeClaim is not cached.
+func (o *volumeEventObjects) byName(namespace, name string) runtime.Object {
+	if o.hasSynced() {
+		if pvc, err := o.pvcLister.PersistentVolumeClaims(namespace).Get(name); err == nil {
+			return pvc
+		}
+	}
+	return &corev1.ObjectReference{Kind: "PersistentVolumeClaim", APIVersion: "v1", Namespace: namespace, Name: name}
+}
+
+// byVolumeID returns the PersistentVolumeClaim bound to the PersistentVolume of volumeID, or nil
+// if there is none or the caches are not synced yet.
+func (o *volumeEventObjects) byVolumeID(volumeID string) runtime.Object {
+	if !o.hasSynced() || o.pvIndexer == nil {
+		return nil
+	}
+	objs, err := o.pvIndexer.ByIndex(volumeHandleIndex, volumeID)
+	if err != nil || len(objs) == 0 {
+		return nil
+	}
+	pv, ok := objs[0].(*corev1.PersistentVolume)
+	if !ok {
+		return nil
+	}
+	return claimReference(pv)
+}
+
+// volumeHandleIndexFunc indexes the PersistentVolumes of the driver by their volume handle.
+func (o *volumeEventObjects) volumeHandleIndexFunc(obj any) ([]string, error) {
+	pv, ok := obj.(*corev1.PersistentVolume)
+	if !ok || pv.Spec.CSI == nil || pv.Spec.CSI.Driver != o.driverName {
+		return nil, nil
+	}
+	return []string{pv.Spec.CSI.VolumeHandle}, nil
+}
+
+// claimReference returns a reference to the PersistentVolumeClaim bound to pv, or nil if pv is not bound.
+func claimReference(pv *corev1.PersistentVolume) runtime.Object {
+	ref := pv.Spec.ClaimRef
+	if ref == nil || ref.Namespace == "" || ref.Name == "" {
+		return nil
+	}
+	return &corev1.ObjectReference{Kind: "PersistentVolumeClaim", APIVersion: "v1", Namespace: ref.Namespace, Name: ref.Name, UID: ref.UID}
+}
+
+// recordPVCEvent emits an event on the PersistentVolumeClaim namespace/name. Nothing is emitted if
+// there is no Kubernetes client or the PersistentVolumeClaim is not known.
+func (d *ControllerService) recordPVCEvent(namespace, name, eventType, reason, messageFmt string, args ...any) {
+	if d.recorder == nil || namespace == "" || name == "" {
+		return
+	}
+	d.recordEvent(d.eventObjects.byName(namespace, name), eventType, reason, messageFmt, args...)
+}
+
+// recordVolumeEvent emits an event on the PersistentVolumeClaim of volumeID, found from the claimRef
+// of its PersistentVolume. Nothing is emitted if the volume has no bound PersistentVolume.
+func (d *ControllerService) recordVolumeEvent(volumeID, eventType, reason, messageFmt string, args ...any) {
+	if d.recorder == nil {
+		return
+	}
+	d.recordEvent(d.eventObjects.byVolumeID(volumeID), eventType, reason, messageFmt, args...)
+}
+
+// recordEvent emits an event on obj. Nothing is emitted if there is no Kubernetes client or obj is nil.
+func (d *ControllerService) recordEvent(obj runtime.Object, eventType, reason, messageFmt string, args ...any) {
+	if d.recorder == nil || obj == nil {
+		return
+	}
+	d.recorder.Eventf(obj, eventType, reason, messageFmt, args...)
+}
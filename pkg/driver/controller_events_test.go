@@ -0,0 +1,195 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"testing"
+	"time"
+
+	csi "github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/driver/internal"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestControllerEvents(t *testing.T) {
+	volCaps := []*csi.VolumeCapability{{
+		AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+		AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+	}}
+	createVolumeRequest := func(params map[string]string) *csi.CreateVolumeRequest {
+		return &csi.CreateVolumeRequest{
+			Name:               "pvc-test",
+			CapacityRange:      &csi.CapacityRange{RequiredBytes: util.GiBToBytes(4)},
+			VolumeCapabilities: volCaps,
+			Parameters:         params,
+		}
+	}
+	pvcParams := map[string]string{PVCNameKey: "claim", PVCNamespaceKey: "default"}
+
+	testCases := []struct {
+		name   string
+		call   func(d *ControllerService, c *cloud.MockCloud)
+		events []string
+	}{
+		{
+			name: "volume quota exceeded",
+			call: func(d *ControllerService, c *cloud.MockCloud) {
+				c.EXPECT().CreateDisk(gomock.Any(), "pvc-test", gomock.Any()).Return(nil, fmt.Errorf("%w: quota", cloud.ErrLimitExceeded))
+				_, err := d.CreateVolume(context.Background(), createVolumeRequest(pvcParams))
+				assert.Error(t, err)
+			},
+			events: []string{"Warning VolumeLimitExceeded Could not create volume pvc-test: limit exceeded: quota"},
+		},
+		{
+			name: "volume quota exceeded without PVC parameters",
+			call: func(d *ControllerService, c *cloud.MockCloud) {
+				c.EXPECT().CreateDisk(gomock.Any(), "pvc-test", gomock.Any()).Return(nil, cloud.ErrLimitExceeded)
+				_, err := d.CreateVolume(context.Background(), createVolumeRequest(nil))
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "IOPS capped",
+			call: func(d *ControllerService, c *cloud.MockCloud) {
+				c.EXPECT().CreateDisk(gomock.Any(), "pvc-test", gomock.Any()).Return(&cloud.Disk{VolumeID: "vol-test", CapacityGiB: 4, IOPS: 200}, nil)
+				params := map[string]string{VolumeTypeKey: cloud.VolumeTypeIO1, IopsPerGBKey: "10000"}
+				maps.Copy(params, pvcParams)
+				resp, err := d.CreateVolume(context.Background(), createVolumeRequest(params))
+				assert.NoError(t, err)
+				assert.Empty(t, resp.GetVolume().GetVolumeContext())
+			},
+			events: []string{"Normal VolumeIOPSAdjusted Volume vol-test was created with 200 IOPS instead of the requested 40000, to fit the limits of its type and size"},
+		},
+		{
+			name: "attachment limit exceeded",
+			call: func(d *ControllerService, c *cloud.MockCloud) {
+				c.EXPECT().AttachDisk(gomock.Any(), "vol-test", expInstanceID).Return("", cloud.ErrLimitExceeded)
+				_, err := d.ControllerPublishVolume(context.Background(), &csi.ControllerPublishVolumeRequest{
+					VolumeId: "vol-test", NodeId: expInstanceID, VolumeCapability: volCaps[0],
+				})
+				assert.Error(t, err)
+			},
+			events: []string{"Warning VolumeAttachmentLimitExceeded Could not attach volume vol-test to node " + expInstanceID + ", the attachment limit of the node is exceeded: limit exceeded"},
+		},
+		{
+			name: "attachment limit exceeded without PersistentVolume",
+			call: func(d *ControllerService, c *cloud.MockCloud) {
+				c.EXPECT().AttachDisk(gomock.Any(), "vol-other", expInstanceID).Return("", cloud.ErrLimitExceeded)
+				_, err := d.ControllerPublishVolume(context.Background(), &csi.ControllerPublishVolumeRequest{
+					VolumeId: "vol-other", NodeId: expInstanceID, VolumeCapability: volCaps[0],
+				})
+				assert.Error(t, err)
+			},
+		},
+		{
+			name: "attachment stuck",
+			call: func(d *ControllerService, c *cloud.MockCloud) {
+				c.EXPECT().AttachDisk(gomock.Any(), "vol-test", expInstanceID).Return("", cloud.ErrAttachmentStuck)
+				_, err := d.ControllerPublishVolume(context.Background(), &csi.ControllerPublishVolumeRequest{
+					VolumeId: "vol-test", NodeId: expInstanceID, VolumeCapability: volCaps[0],
+				})
+				assert.Error(t, err)
+			},
+			events: []string{"Warning VolumeAttachmentStuck Volume vol-test was stuck attaching to node " + expInstanceID + " and was detached, the attachment will be retried: attachment stuck"},
+		},
+		{
+			name: "modification deferred",
+			call: func(d *ControllerService, c *cloud.MockCloud) {
+				until := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+				c.EXPECT().ResizeOrModifyDisk(gomock.Any(), "vol-test", int64(0), gomock.Any()).Return(int32(0), &cloud.ModificationDeferredError{VolumeID: "vol-test", Until: until})
+				params := map[string]string{ModificationKeyIOPS: "4000"}
+				maps.Copy(params, pvcParams)
+				_, err := d.ControllerModifyVolume(context.Background(), &csi.ControllerModifyVolumeRequest{VolumeId: "vol-test", MutableParameters: params})
+				assert.Equal(t, codes.Unavailable, status.Code(err))
+			},
+			events: []string{"Normal VolumeModificationDeferred The modification of volume vol-test is deferred: modification of volume \"vol-test\" deferred until 2099-01-01T00:00:00Z, because EBS does not allow the volume to be modified again yet"},
+		},
+		{
+			name: "resize deferred",
+			call: func(d *ControllerService, c *cloud.MockCloud) {
+				until := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+				c.EXPECT().ResizeOrModifyDisk(gomock.Any(), "vol-test", util.GiBToBytes(8), gomock.Any()).Return(int32(0), &cloud.ModificationDeferredError{VolumeID: "vol-test", Until: until})
+				_, err := d.ControllerExpandVolume(context.Background(), &csi.ControllerExpandVolumeRequest{
+					VolumeId: "vol-test", CapacityRange: &csi.CapacityRange{RequiredBytes: util.GiBToBytes(8)},
+				})
+				assert.Equal(t, codes.Unavailable, status.Code(err))
+				assert.Contains(t, status.Convert(err).Message(), "deferred until 2099-01-01T00:00:00Z")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			c := cloud.NewMockCloud(ctrl)
+			recorder := record.NewFakeRecorder(10)
+			k := fake.NewClientset(
+				&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "default", UID: "pvc-uid"}},
+				&corev1.PersistentVolume{
+					ObjectMeta: metav1.ObjectMeta{Name: "pv-test"},
+					Spec: corev1.PersistentVolumeSpec{
+						PersistentVolumeSource: corev1.PersistentVolumeSource{
+							CSI: &corev1.CSIPersistentVolumeSource{Driver: util.GetDriverName(), VolumeHandle: "vol-test"},
+						},
+						ClaimRef: &corev1.ObjectReference{Namespace: "default", Name: "claim", UID: "pvc-uid"},
+					},
+				},
+			)
+			d := &ControllerService{
+				cloud:                 c,
+				inFlight:              internal.NewInFlight(),
+				options:               &Options{},
+				modifyVolumeCoalescer: newModifyVolumeCoalescer(c, &Options{}),
+				k8sClient:             k,
+				recorder:              recorder,
+				eventObjects:          newVolumeEventObjects(informers.NewSharedInformerFactory(k, 0)),
+			}
+			d.eventObjects.start()
+			require.Eventually(t, d.eventObjects.hasSynced, 5*time.Second, 10*time.Millisecond)
+			tc.call(d, c)
+
+			close(recorder.Events)
+			var events []string
+			for e := range recorder.Events {
+				events = append(events, e)
+			}
+			assert.Equal(t, tc.events, events)
+		})
+	}
+}
+
+func TestVolumeEventObjectsNotSynced(t *testing.T) {
+	o := newVolumeEventObjects(informers.NewSharedInformerFactory(fake.NewClientset(), 0))
+
+	// Events are not delayed until the caches are synced
+	assert.Nil(t, o.byVolumeID("vol-test"))
+	assert.Equal(t, &corev1.ObjectReference{Kind: "PersistentVolumeClaim", APIVersion: "v1", Namespace: "default", Name: "claim"}, o.byName("default", "claim"))
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud"
+	"k8s.io/klog/v2"
+)
+
+// finalSnapshotNamePrefix is the prefix of the CSIVolumeSnapshotName tag of the final snapshots of volumes.
+const finalSnapshotNamePrefix = "final-snapshot-"
+
+// finalSnapshotName returns the CSIVolumeSnapshotName tag of the final snapshot of volumeID, which
+// identifies it when DeleteVolume is retried.
+func finalSnapshotName(volumeID string) string {
+	return finalSnapshotNamePrefix + volumeID
+}
+
+// parseFinalSnapshotLock returns the lock of the final snapshot of a volume with tags, or nil if the
+// final snapshot is not locked.
+func parseFinalSnapshotLock(tags map[string]string) (*cloud.SnapshotLockOptions, error) {
+	mode, hasMode := tags[FinalSnapshotLockModeTagKey]
+	duration, hasDuration := tags[FinalSnapshotLockDurationTagKey]
+	if !hasMode && !hasDuration {
+		return nil, nil
+	}
+	if !isTrue(tags[FinalSnapshotTagKey]) {
+		return nil, fmt.Errorf("the lock of the final snapshot requires %s to be true", FinalSnapshotKey)
+	}
+	if !slices.Contains(types.LockMode("").Values(), types.LockMode(mode)) {
+		return nil, fmt.Errorf("invalid lock mode of the final snapshot %q", mode)
+	}
+	days, err := strconv.ParseInt(duration, 10, 32)
+	if err != nil || days <= 0 {
+		return nil, fmt.Errorf("invalid lock duration of the final snapshot %q", duration)
+	}
+	return &cloud.SnapshotLockOptions{
+		LockMode:     types.LockMode(mode),
+		LockDuration: aws.Int32(int32(days)),
+	}, nil
+}
+
+// createFinalSnapshot takes the final snapshot of disk before it is deleted, and locks it if requested.
+// The data of a snapshot is captured when it is started, so the volume can be deleted while the
+// snapshot is pending, but not once it failed.
+func (d *ControllerService) createFinalSnapshot(ctx context.Context, disk *cloud.Disk) error {
+	lock, err := parseFinalSnapshotLock(disk.Tags)
+	if err != nil {
+		return err
+	}
+	name := finalSnapshotName(disk.VolumeID)
+
+	created := false
+	snapshot, err := d.cloud.GetSnapshotByName(ctx, name)
+	switch {
+	case errors.Is(err, cloud.ErrNotFound):
+		created = true
+		snapshot, err = d.cloud.CreateSnapshot(ctx, disk.VolumeID, &cloud.SnapshotOptions{Tags: d.finalSnapshotTags(disk, name)})
+		if err != nil {
+			return fmt.Errorf("could not create final snapshot: %w", err)
+		}
+		klog.FromContext(ctx).Info("DeleteVolume: created final snapshot", "volumeID", disk.VolumeID, "snapshotID", snapshot.SnapshotID)
+	case err != nil:
+		return fmt.Errorf("could not get final snapshot: %w", err)
+	case snapshot.SourceVolumeID != disk.VolumeID:
+		return fmt.Errorf("final snapshot %s is a snapshot of volume %s", snapshot.SnapshotID, snapshot.SourceVolumeID)
+	}
+
+	if snapshot.Failed {
+		// A failed snapshot is deleted, for a new final snapshot to be taken when DeleteVolume is retried
+		if _, err := d.cloud.DeleteSnapshot(ctx, snapshot.SnapshotID); err != nil && !errors.Is(err, cloud.ErrNotFound) {
+			return fmt.Errorf("final snapshot %s failed and could not be deleted: %w", snapshot.SnapshotID, err)
+		}
+		return fmt.Errorf("final snapshot %s failed", snapshot.SnapshotID)
+	}
+
+	// The snapshot is only locked when it is created, like the snapshots of CreateSnapshot, as locking
+	// it again when DeleteVolume is retried would extend the lock from now
+	if lock != nil && created {
+		lock.SnapshotId = aws.String(snapshot.SnapshotID)
+		if err := d.cloud.LockSnapshot(ctx, lock); err != nil {
+			// The snapshot is deleted for a locked snapshot to be taken when DeleteVolume is retried
+			if _, deleteErr := d.cloud.DeleteSnapshot(ctx, snapshot.SnapshotID); deleteErr != nil {
+				klog.FromContext(ctx).Error(deleteErr, "DeleteVolume: could not delete final snapshot that could not be locked", "snapshotID", snapshot.SnapshotID)
+			}
+			return fmt.Errorf("could not lock final snapshot %s: %w", snapshot.SnapshotID, err)
+		}
+	}
+	return nil
+}
+
+// finalSnapshotTags returns the tags of the final snapshot name of disk. The snapshot keeps the tags of
+// the volume identifying its PVC, but is not owned by the cluster so that it outlives it.
+func (d *ControllerService) finalSnapshotTags(disk *cloud.Disk, name string) map[string]string {
+	tags := tagCopyFilter{enabled: true}.copyTags(disk.Tags)
+	for _, key := range []string{PVCNameTag, PVCNamespaceTag, PVNameTag} {
+		if value, ok := disk.Tags[key]; ok {
+			tags[key] = value
+		}
+	}
+	if d.options.KubernetesClusterID != "" {
+		tags[NameTag] = d.options.KubernetesClusterID + "-" + name
+		tags[ClusterNameTagKey] = d.options.KubernetesClusterID
+	}
+	maps.Copy(tags, d.options.ExtraTags)
+	tags[cloud.SnapshotNameTagKey] = name
+	tags[cloud.AwsEbsDriverTagKey] = isManagedByDriver
+	return tags
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFinalSnapshotLock(t *testing.T) {
+	testCases := []struct {
+		name        string
+		tags        map[string]string
+		expected    *cloud.SnapshotLockOptions
+		expectError bool
+	}{
+		{
+			name: "no lock",
+			tags: map[string]string{FinalSnapshotTagKey: "true"},
+		},
+		{
+			name: "lock",
+			tags: map[string]string{FinalSnapshotTagKey: "true", FinalSnapshotLockModeTagKey: "compliance", FinalSnapshotLockDurationTagKey: "30"},
+			expected: &cloud.SnapshotLockOptions{
+				LockMode:     types.LockModeCompliance,
+				LockDuration: aws.Int32(30),
+			},
+		},
+		{
+			name:        "lock without final snapshot",
+			tags:        map[string]string{FinalSnapshotLockModeTagKey: "governance", FinalSnapshotLockDurationTagKey: "30"},
+			expectError: true,
+		},
+		{
+			name:        "invalid lock mode",
+			tags:        map[string]string{FinalSnapshotTagKey: "true", FinalSnapshotLockModeTagKey: "forever", FinalSnapshotLockDurationTagKey: "30"},
+			expectError: true,
+		},
+		{
+			name:        "missing lock duration",
+			tags:        map[string]string{FinalSnapshotTagKey: "true", FinalSnapshotLockModeTagKey: "governance"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			lock, err := parseFinalSnapshotLock(tc.tags)
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, lock)
+		})
+	}
+}
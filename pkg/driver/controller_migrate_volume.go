@@ -0,0 +1,526 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// volumeMigrationTimeout is the maximum duration of a volume migration, most of which is spent
+	// taking the snapshot of the volume.
+	volumeMigrationTimeout = 12 * time.Hour
+	// volumeMigrationPollInterval is how often the snapshot of a migrated volume is checked.
+	volumeMigrationPollInterval = 15 * time.Second
+	// volumeMigrationGCInterval is how often the original volumes of migrated volumes are deleted
+	// once their retention is over.
+	volumeMigrationGCInterval = 10 * time.Minute
+)
+
+const (
+	// pvProtectionFinalizer is the finalizer Kubernetes adds to PersistentVolumes, and only removes once
+	// they are not bound to a PersistentVolumeClaim anymore.
+	pvProtectionFinalizer = "kubernetes.io/pv-protection"
+	// provisionerFinalizer is the finalizer the external-provisioner adds to PersistentVolumes with
+	// the Delete reclaim policy, and removes once their reclaim policy is Retain.
+	provisionerFinalizer = "external-provisioner.volume.kubernetes.io/finalizer"
+)
+
+// persistentVolumeRecreateBackoff is the backoff of the creation of the PersistentVolume of a
+// migrated volume, which fails until its previous version is gone.
+var persistentVolumeRecreateBackoff = wait.Backoff{Duration: 100 * time.Millisecond, Factor: 2, Steps: 10, Cap: 10 * time.Second}
+
+// errPersistentVolumeLost is returned when the PersistentVolume of a migrated volume was deleted, and
+// could be created again neither for the new volume nor for the original volume.
+var errPersistentVolumeLost = errors.New("the PersistentVolume could not be created again")
+
+// volumeMigrationTarget is the encryption requested by a VolumeAttributesClass, which cannot be
+// changed in place and requires the migration of the volume to a new volume.
+type volumeMigrationTarget struct {
+	encrypted bool
+	// kmsKeyID is the ARN or the ID of the KMS key of the volume, empty for the default key.
+	kmsKeyID string
+}
+
+// needsMigration returns true if disk must be migrated to have the encryption of t.
+func (t *volumeMigrationTarget) needsMigration(disk *cloud.Disk) (bool, error) {
+	if !t.encrypted {
+		if disk.Encrypted {
+			return false, status.Errorf(codes.InvalidArgument, "Volume %q is encrypted and cannot be decrypted", disk.VolumeID)
+		}
+		return false, nil
+	}
+	if !disk.Encrypted {
+		return true, nil
+	}
+	// EC2 returns the ARN of the key of the volume
+	return t.kmsKeyID != "" && disk.KmsKeyID != t.kmsKeyID && !strings.HasSuffix(disk.KmsKeyID, ":key/"+t.kmsKeyID), nil
+}
+
+// volumeMigration is a running volume migration.
+type volumeMigration struct {
+	done chan struct{}
+	// newVolumeID and err are set before done is closed.
+	newVolumeID string
+	err         error
+}
+
+// volumeMigrations holds the volume migrations of the controller by the ID of the migrated volume.
+// A migration is kept until ControllerModifyVolume reports its result.
+type volumeMigrations struct {
+	mu   sync.Mutex
+	jobs map[string]*volumeMigration
+}
+
+func newVolumeMigrations() *volumeMigrations {
+	return &volumeMigrations{jobs: make(map[string]*volumeMigration)}
+}
+
+// running returns true if volumeID is being migrated.
+func (m *volumeMigrations) running(volumeID string) bool {
+	if m == nil {
+		return false
+	}
+	m.mu.Lock()
+	job, ok := m.jobs[volumeID]
+	m.mu.Unlock()
+	if !ok {
+		return false
+	}
+	select {
+	case <-job.done:
+		return false
+	default:
+		return true
+	}
+}
+
+// migrateVolume migrates volumeID to a new volume with the encryption of target, unless it already has it.
+// The migration runs in the background and ControllerModifyVolume returns Unavailable until it is done.
+// It returns the ID of the volume of the PersistentVolume once the migration is done.
+func (d *ControllerService) migrateVolume(ctx context.Context, volumeID string, params map[string]string, target *volumeMigrationTarget) (string, error) {
+	if !d.options.EnableVolumeMigration || d.k8sClient == nil {
+		return "", status.Error(codes.InvalidArgument, "Changing the encryption of a volume requires its migration, which is not enabled")
+	}
+	pvName := params[PVNameKey]
+	if pvName == "" {
+		return "", status.Error(codes.InvalidArgument, "Changing the encryption of a volume requires its migration, which requires --extra-modify-metadata on the external-resizer")
+	}
+
+	d.migrations.mu.Lock()
+	job, ok := d.migrations.jobs[volumeID]
+	d.migrations.mu.Unlock()
+	if ok {
+		select {
+		case <-job.done:
+			d.migrations.mu.Lock()
+			delete(d.migrations.jobs, volumeID)
+			d.migrations.mu.Unlock()
+			if job.err != nil {
+				return "", status.Errorf(codes.Internal, "Could not migrate volume %q: %v", volumeID, job.err)
+			}
+			return job.newVolumeID, nil
+		default:
+			return "", status.Errorf(codes.Unavailable, "Volume %q is being migrated", volumeID)
+		}
+	}
+
+	disk, err := d.cloud.GetDiskByID(ctx, volumeID)
+	if err != nil {
+		if errors.Is(err, cloud.ErrNotFound) {
+			return "", status.Errorf(codes.NotFound, "Could not modify volume (not found) %q: %v", volumeID, err)
+		}
+		return "", status.Errorf(codes.Internal, "Could not get volume %q: %v", volumeID, err)
+	}
+	needsMigration, err := target.needsMigration(disk)
+	if err != nil || !needsMigration {
+		return volumeID, err
+	}
+
+	pv, err := d.k8sClient.CoreV1().PersistentVolumes().Get(ctx, pvName, metav1.GetOptions{})
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "Could not get PersistentVolume %q: %v", pvName, err)
+	}
+	if pv.Spec.CSI == nil || pv.Spec.CSI.VolumeHandle != volumeID {
+		// The PersistentVolume was already migrated
+		return "", status.Errorf(codes.Aborted, "PersistentVolume %q does not reference volume %q", pvName, volumeID)
+	}
+	// The PersistentVolume references the new volume once migrated, so the claim is resolved now
+	claim := claimReference(pv)
+	attached, err := d.isAttached(ctx, disk, pvName)
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "Could not check the attachments of volume %q: %v", volumeID, err)
+	}
+	if attached {
+		d.recordEvent(claim, corev1.EventTypeNormal, eventReasonVolumeMigrationPending,
+			"Volume %s will be migrated to a new volume once it is detached, to change its encryption", volumeID)
+		return "", status.Errorf(codes.Unavailable, "Volume %q must be detached to be migrated", volumeID)
+	}
+
+	job = &volumeMigration{done: make(chan struct{})}
+	d.migrations.mu.Lock()
+	d.migrations.jobs[volumeID] = job
+	d.migrations.mu.Unlock()
+	d.recordEvent(claim, corev1.EventTypeNormal, eventReasonVolumeMigrationStarted,
+		"Migrating volume %s to a new volume restored from its snapshot, to change its encryption", volumeID)
+	go func() {
+		defer close(job.done)
+		ctx, cancel := context.WithTimeout(context.Background(), volumeMigrationTimeout)
+		defer cancel()
+		job.newVolumeID, job.err = d.runVolumeMigration(ctx, disk, pvName, target)
+		if job.err != nil {
+			klog.ErrorS(job.err, "Volume migration failed", "volumeID", volumeID, "persistentVolume", pvName)
+			d.recordEvent(claim, corev1.EventTypeWarning, eventReasonVolumeMigrationFailed,
+				"Could not migrate volume %s: %v", volumeID, job.err)
+			return
+		}
+		klog.InfoS("Volume migrated", "volumeID", volumeID, "newVolumeID", job.newVolumeID, "persistentVolume", pvName)
+		d.recordEvent(claim, corev1.EventTypeNormal, eventReasonVolumeMigrated,
+			"Volume %s was migrated to volume %s, which is now used by PersistentVolume %s. Volume %s is deleted in %v",
+			volumeID, job.newVolumeID, pvName, volumeID, d.options.VolumeMigrationRetention)
+	}()
+	return "", status.Errorf(codes.Unavailable, "Volume %q is being migrated", volumeID)
+}
+
+// runVolumeMigration restores a snapshot of disk to a new volume with the encryption of target, and
+// replaces the PersistentVolume pvName by one of the new volume. It returns the ID of the new volume.
+func (d *ControllerService) runVolumeMigration(ctx context.Context, disk *cloud.Disk, pvName string, target *volumeMigrationTarget) (string, error) {
+	volumeID := disk.VolumeID
+	snapshotName := "migration-" + volumeID
+	// The leftovers of a migration interrupted by a restart of the controller are discarded, as the
+	// volume may have been written since
+	if err := d.deleteVolumeMigrationLeftovers(ctx, volumeID, snapshotName); err != nil {
+		return "", err
+	}
+
+	klog.InfoS("Migrating volume", "volumeID", volumeID, "persistentVolume", pvName)
+	snapshot, err := d.cloud.CreateSnapshot(ctx, volumeID, &cloud.SnapshotOptions{
+		Tags: map[string]string{
+			cloud.SnapshotNameTagKey: snapshotName,
+			cloud.AwsEbsDriverTagKey: isManagedByDriver,
+		},
+		OutpostArn: disk.OutpostArn,
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not create snapshot: %w", err)
+	}
+	defer func() {
+		if _, err := d.cloud.DeleteSnapshot(context.Background(), snapshot.SnapshotID); err != nil {
+			klog.ErrorS(err, "Could not delete the snapshot of migrated volume", "volumeID", volumeID, "snapshotID", snapshot.SnapshotID)
+		}
+	}()
+	err = wait.PollUntilContextCancel(ctx, volumeMigrationPollInterval, true, func(ctx context.Context) (bool, error) {
+		s, err := d.cloud.GetSnapshotByID(ctx, snapshot.SnapshotID)
+		if err != nil {
+			return false, err
+		}
+		if s.Failed {
+			return false, errors.New("snapshot is in the error state")
+		}
+		return s.ReadyToUse, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not wait for snapshot %q: %w", snapshot.SnapshotID, err)
+	}
+
+	newDisk, err := d.cloud.CreateDisk(ctx, fmt.Sprintf("%s-%d", snapshotName, time.Now().Unix()), migratedDiskOptions(disk, pvName, target, snapshot.SnapshotID))
+	if err != nil {
+		return "", fmt.Errorf("could not create volume from snapshot %q: %w", snapshot.SnapshotID, err)
+	}
+	if err := d.replacePersistentVolume(ctx, pvName, volumeID, newDisk.VolumeID); err != nil {
+		// Both volumes are kept until the PersistentVolume is created manually
+		if errors.Is(err, errPersistentVolumeLost) {
+			return "", err
+		}
+		if _, deleteErr := d.cloud.DeleteDisk(context.Background(), newDisk.VolumeID); deleteErr != nil {
+			klog.ErrorS(deleteErr, "Could not delete the new volume of failed migration", "volumeID", volumeID, "newVolumeID", newDisk.VolumeID)
+		}
+		return "", err
+	}
+	return newDisk.VolumeID, nil
+}
+
+// migratedDiskOptions returns the options of the volume restored from snapshotID, which keeps the
+// attributes and the tags of disk.
+func migratedDiskOptions(disk *cloud.Disk, pvName string, target *volumeMigrationTarget, snapshotID string) *cloud.DiskOptions {
+	tags := make(map[string]string, len(disk.Tags)+2)
+	for key, value := range disk.Tags {
+		if strings.HasPrefix(key, "aws:") || key == cloud.DeferredModificationKey {
+			continue
+		}
+		tags[key] = value
+	}
+	tags[MigratedFromVolumeTagKey] = disk.VolumeID
+	tags[MigratedPersistentVolumeTagKey] = pvName
+
+	options := &cloud.DiskOptions{
+		CapacityBytes:    util.GiBToBytes(disk.CapacityGiB),
+		Tags:             tags,
+		VolumeType:       disk.VolumeType,
+		AvailabilityZone: disk.AvailabilityZone,
+		OutpostArn:       disk.OutpostArn,
+		Encrypted:        true,
+		KmsKeyID:         target.kmsKeyID,
+		SnapshotID:       snapshotID,
+	}
+	switch disk.VolumeType {
+	case cloud.VolumeTypeGP3:
+		options.IOPS = disk.IOPS
+		options.Throughput = disk.Throughput
+	case cloud.VolumeTypeIO1, cloud.VolumeTypeIO2:
+		options.IOPS = disk.IOPS
+	}
+	return options
+}
+
+// deleteVolumeMigrationLeftovers deletes the snapshot and the new volumes of a previous migration of volumeID.
+func (d *ControllerService) deleteVolumeMigrationLeftovers(ctx context.Context, volumeID, snapshotName string) error {
+	snapshot, err := d.cloud.GetSnapshotByName(ctx, snapshotName)
+	switch {
+	case err == nil:
+		klog.InfoS("Deleting the snapshot of a previous migration of volume", "volumeID", volumeID, "snapshotID", snapshot.SnapshotID)
+		if _, err := d.cloud.DeleteSnapshot(ctx, snapshot.SnapshotID); err != nil && !errors.Is(err, cloud.ErrNotFound) {
+			return fmt.Errorf("could not delete snapshot %q: %w", snapshot.SnapshotID, err)
+		}
+	case !errors.Is(err, cloud.ErrNotFound):
+		return fmt.Errorf("could not get snapshot %q: %w", snapshotName, err)
+	}
+
+	disks, err := d.cloud.ListDisks(ctx, map[string]string{MigratedFromVolumeTagKey: volumeID})
+	if err != nil {
+		return fmt.Errorf("could not list the volumes of previous migrations: %w", err)
+	}
+	for _, disk := range disks {
+		// A completed migration replaced the PersistentVolume, its new volume is the one in use
+		if _, ok := disk.Tags[MigrationCompletedTagKey]; ok {
+			continue
+		}
+		klog.InfoS("Deleting the new volume of a previous migration of volume", "volumeID", volumeID, "newVolumeID", disk.VolumeID)
+		if _, err := d.cloud.DeleteDisk(ctx, disk.VolumeID); err != nil && !errors.Is(err, cloud.ErrNotFound) {
+			return fmt.Errorf("could not delete volume %q: %w", disk.VolumeID, err)
+		}
+	}
+	return nil
+}
+
+// isAttached returns true if disk is attached to an instance, or if a VolumeAttachment of the
+// PersistentVolume pvName exists.
+func (d *ControllerService) isAttached(ctx context.Context, disk *cloud.Disk, pvName string) (bool, error) {
+	if len(disk.Attachments) > 0 {
+		return true, nil
+	}
+	attachments, err := d.k8sClient.StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+	for _, attachment := range attachments.Items {
+		if pv := attachment.Spec.Source.PersistentVolumeName; pv != nil && *pv == pvName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// replacePersistentVolume replaces the PersistentVolume pvName of oldVolumeID by a copy of it with
+// newVolumeID, because the volume of a PersistentVolume cannot be changed. Its PersistentVolumeClaim
+// is Lost until the new PersistentVolume is created, and bound to it again by Kubernetes.
+// If the new PersistentVolume cannot be created, the PersistentVolume of oldVolumeID is created again.
+// errPersistentVolumeLost is returned if that fails too.
+func (d *ControllerService) replacePersistentVolume(ctx context.Context, pvName, oldVolumeID, newVolumeID string) error {
+	pvs := d.k8sClient.CoreV1().PersistentVolumes()
+	pv, err := pvs.Get(ctx, pvName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("could not get PersistentVolume %q: %w", pvName, err)
+	}
+	if pv.Spec.CSI == nil || pv.Spec.CSI.VolumeHandle != oldVolumeID {
+		return fmt.Errorf("PersistentVolume %q does not reference volume %q anymore", pvName, oldVolumeID)
+	}
+	// The finalizers of other controllers would keep the PersistentVolume from being deleted
+	for _, finalizer := range pv.Finalizers {
+		if finalizer != pvProtectionFinalizer && finalizer != provisionerFinalizer {
+			return fmt.Errorf("PersistentVolume %q has the finalizer %q of another controller", pvName, finalizer)
+		}
+	}
+	attached, err := d.isAttached(ctx, &cloud.Disk{VolumeID: oldVolumeID}, pvName)
+	if err != nil {
+		return fmt.Errorf("could not check the attachments of PersistentVolume %q: %w", pvName, err)
+	}
+	if attached {
+		return fmt.Errorf("PersistentVolume %q was attached during its migration", pvName)
+	}
+
+	originalPV := recreatedPersistentVolume(pv)
+	newPV := recreatedPersistentVolume(pv)
+	newPV.Spec.CSI.VolumeHandle = newVolumeID
+
+	// The original volume is retained, and deleted once the retention of migrated volumes is over
+	reclaimPolicy := pv.Spec.PersistentVolumeReclaimPolicy
+	pv.Spec.PersistentVolumeReclaimPolicy = corev1.PersistentVolumeReclaimRetain
+	if pv, err = pvs.Update(ctx, pv, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("could not retain the volume of PersistentVolume %q: %w", pvName, err)
+	}
+	if err := pvs.Delete(ctx, pvName, metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &pv.UID}}); err != nil {
+		pv.Spec.PersistentVolumeReclaimPolicy = reclaimPolicy
+		if _, updateErr := pvs.Update(ctx, pv, metav1.UpdateOptions{}); updateErr != nil {
+			klog.ErrorS(updateErr, "Could not restore the reclaim policy of PersistentVolume", "persistentVolume", pvName, "reclaimPolicy", reclaimPolicy)
+		}
+		return fmt.Errorf("could not delete PersistentVolume %q: %w", pvName, err)
+	}
+	// The PersistentVolume is still bound, Kubernetes would keep it until its PersistentVolumeClaim is deleted
+	if err := removePVProtectionFinalizer(ctx, d.k8sClient.CoreV1().PersistentVolumes(), pvName, pv.UID); err != nil {
+		klog.ErrorS(err, "Could not remove the protection finalizer of replaced PersistentVolume", "persistentVolume", pvName)
+	}
+
+	// The new volume is the one in use from now on, the original volume is deleted once the retention is over
+	err = d.cloud.ModifyTags(ctx, newVolumeID, cloud.ModifyTagsOptions{TagsToAdd: map[string]string{MigrationCompletedTagKey: time.Now().UTC().Format(time.RFC3339)}})
+	if err == nil {
+		if err = createPersistentVolume(ctx, d.k8sClient.CoreV1().PersistentVolumes(), newPV); err == nil {
+			return nil
+		}
+		if tagErr := d.cloud.ModifyTags(context.Background(), newVolumeID, cloud.ModifyTagsOptions{TagsToDelete: []string{MigrationCompletedTagKey}}); tagErr != nil {
+			klog.ErrorS(tagErr, "Could not remove the completion tag of the new volume of failed migration, its PersistentVolume must be created manually", "newVolumeID", newVolumeID, "persistentVolume", newPV)
+			return fmt.Errorf("%w for volume %q (%w), and the completion of its migration could not be reverted", errPersistentVolumeLost, newVolumeID, err)
+		}
+	}
+
+	klog.ErrorS(err, "Could not create the PersistentVolume of migrated volume, creating it again for the original volume", "persistentVolume", pvName, "volumeID", oldVolumeID)
+	if restoreErr := createPersistentVolume(context.Background(), d.k8sClient.CoreV1().PersistentVolumes(), originalPV); restoreErr != nil {
+		klog.ErrorS(restoreErr, "Could not create the PersistentVolume of the original volume either, the PersistentVolume of the new volume must be created manually", "persistentVolume", newPV)
+		return fmt.Errorf("%w for volume %q (%w) nor for volume %q (%w)", errPersistentVolumeLost, newVolumeID, err, oldVolumeID, restoreErr)
+	}
+	return fmt.Errorf("could not create PersistentVolume %q for volume %q, it references volume %q again: %w", pvName, newVolumeID, oldVolumeID, err)
+}
+
+// recreatedPersistentVolume returns a copy of pv to create it again, with the reclaim policy and the
+// finalizers of pv.
+func recreatedPersistentVolume(pv *corev1.PersistentVolume) *corev1.PersistentVolume {
+	recreated := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        pv.Name,
+			Labels:      pv.Labels,
+			Annotations: pv.Annotations,
+			Finalizers:  pv.Finalizers,
+		},
+		Spec: *pv.Spec.DeepCopy(),
+	}
+	if recreated.Spec.ClaimRef != nil {
+		recreated.Spec.ClaimRef.ResourceVersion = ""
+	}
+	return recreated
+}
+
+// removePVProtectionFinalizer removes the pv-protection finalizer of the deleted PersistentVolume
+// pvName with the UID uid, and keeps its other finalizers.
+func removePVProtectionFinalizer(ctx context.Context, pvs corev1client.PersistentVolumeInterface, pvName string, uid types.UID) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pv, err := pvs.Get(ctx, pvName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) || (err == nil && pv.UID != uid) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(pv.Finalizers, pvProtectionFinalizer) {
+			return nil
+		}
+		pv.Finalizers = slices.DeleteFunc(pv.Finalizers, func(f string) bool { return f == pvProtectionFinalizer })
+		_, err = pvs.Update(ctx, pv, metav1.UpdateOptions{})
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	})
+}
+
+// createPersistentVolume creates pv, retrying until its previous version is gone.
+func createPersistentVolume(ctx context.Context, pvs corev1client.PersistentVolumeInterface, pv *corev1.PersistentVolume) error {
+	var lastErr error
+	err := wait.ExponentialBackoffWithContext(ctx, persistentVolumeRecreateBackoff, func(ctx context.Context) (bool, error) {
+		if _, lastErr = pvs.Create(ctx, pv, metav1.CreateOptions{}); lastErr != nil {
+			klog.V(4).InfoS("Could not create PersistentVolume yet", "persistentVolume", pv.Name, "volumeID", pv.Spec.CSI.VolumeHandle, "err", lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil && lastErr != nil {
+		return fmt.Errorf("could not create PersistentVolume %q for volume %q: %w", pv.Name, pv.Spec.CSI.VolumeHandle, lastErr)
+	}
+	return err
+}
+
+// deleteMigratedVolumes deletes the original volumes of migrated volumes once their retention is
+// over, and the new volumes of failed migrations. Migrated volumes are found by their tags, as the
+// PersistentVolume of a migrated volume may be gone.
+func (d *ControllerService) deleteMigratedVolumes(ctx context.Context) {
+	disks, err := d.cloud.ListDisks(ctx, map[string]string{MigratedFromVolumeTagKey: ""})
+	if err != nil {
+		klog.ErrorS(err, "Failed to list migrated volumes")
+		return
+	}
+
+	for _, disk := range disks {
+		oldVolumeID, pvName := disk.Tags[MigratedFromVolumeTagKey], disk.Tags[MigratedPersistentVolumeTagKey]
+		if completed, ok := disk.Tags[MigrationCompletedTagKey]; ok {
+			completedAt, err := time.Parse(time.RFC3339, completed)
+			if err != nil {
+				completedAt = disk.CreateTime
+			}
+			if time.Since(completedAt) < d.options.VolumeMigrationRetention {
+				continue
+			}
+			klog.InfoS("Deleting the original volume of migrated volume", "volumeID", oldVolumeID, "newVolumeID", disk.VolumeID, "persistentVolume", pvName)
+			if _, err := d.cloud.DeleteDisk(ctx, oldVolumeID); err != nil && !errors.Is(err, cloud.ErrNotFound) {
+				klog.ErrorS(err, "Failed to delete the original volume of migrated volume", "volumeID", oldVolumeID)
+				continue
+			}
+			err = d.cloud.ModifyTags(ctx, disk.VolumeID, cloud.ModifyTagsOptions{TagsToDelete: []string{MigratedFromVolumeTagKey, MigratedPersistentVolumeTagKey, MigrationCompletedTagKey}})
+			if err != nil {
+				klog.ErrorS(err, "Failed to remove the migration tags of migrated volume", "volumeID", disk.VolumeID)
+			}
+			continue
+		}
+
+		// The migration may run in another replica of the controller until it times out
+		if time.Since(disk.CreateTime) < max(d.options.VolumeMigrationRetention, volumeMigrationTimeout) ||
+			d.migrations.running(oldVolumeID) || len(disk.Attachments) > 0 {
+			continue
+		}
+		klog.InfoS("Deleting the new volume of failed migration", "volumeID", oldVolumeID, "newVolumeID", disk.VolumeID, "persistentVolume", pvName)
+		if _, err := d.cloud.DeleteDisk(ctx, disk.VolumeID); err != nil && !errors.Is(err, cloud.ErrNotFound) {
+			klog.ErrorS(err, "Failed to delete the new volume of failed migration", "volumeID", disk.VolumeID)
+		}
+	}
+}
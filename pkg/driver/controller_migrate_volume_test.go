@@ -0,0 +1,433 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	csi "github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/driver/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+)
+
+func TestNeedsMigration(t *testing.T) {
+	keyARN := "arn:aws:kms:us-east-1:123456789012:key/key-id"
+	testCases := []struct {
+		name      string
+		target    volumeMigrationTarget
+		disk      cloud.Disk
+		expected  bool
+		expectErr bool
+	}{
+		{
+			name:     "unencrypted volume",
+			target:   volumeMigrationTarget{encrypted: true},
+			disk:     cloud.Disk{},
+			expected: true,
+		},
+		{
+			name:   "encrypted volume with the default key",
+			target: volumeMigrationTarget{encrypted: true},
+			disk:   cloud.Disk{Encrypted: true, KmsKeyID: keyARN},
+		},
+		{
+			name:   "encrypted volume with the key ID",
+			target: volumeMigrationTarget{encrypted: true, kmsKeyID: "key-id"},
+			disk:   cloud.Disk{Encrypted: true, KmsKeyID: keyARN},
+		},
+		{
+			name:   "encrypted volume with the key ARN",
+			target: volumeMigrationTarget{encrypted: true, kmsKeyID: keyARN},
+			disk:   cloud.Disk{Encrypted: true, KmsKeyID: keyARN},
+		},
+		{
+			name:     "encrypted volume with another key",
+			target:   volumeMigrationTarget{encrypted: true, kmsKeyID: "other-key-id"},
+			disk:     cloud.Disk{Encrypted: true, KmsKeyID: keyARN},
+			expected: true,
+		},
+		{
+			name:   "unencrypted volume not to be encrypted",
+			target: volumeMigrationTarget{},
+			disk:   cloud.Disk{},
+		},
+		{
+			name:      "encrypted volume to be decrypted",
+			target:    volumeMigrationTarget{},
+			disk:      cloud.Disk{Encrypted: true, KmsKeyID: keyARN},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			needsMigration, err := tc.target.needsMigration(&tc.disk)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, needsMigration)
+		})
+	}
+}
+
+func newMigrationTestPV(volumeID string) *corev1.PersistentVolume {
+	return &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-test", UID: "pv-uid", Finalizers: []string{"kubernetes.io/pv-protection"}},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeReclaimPolicy: corev1.PersistentVolumeReclaimDelete,
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com", VolumeHandle: volumeID},
+			},
+			ClaimRef: &corev1.ObjectReference{Namespace: "default", Name: "claim", UID: "pvc-uid", ResourceVersion: "1"},
+		},
+	}
+}
+
+func TestControllerModifyVolumeMigration(t *testing.T) {
+	params := map[string]string{
+		"encrypted":         "true",
+		ModificationKeyIOPS: "4000",
+		PVNameKey:           "pv-test",
+		PVCNameKey:          "claim",
+		PVCNamespaceKey:     "default",
+	}
+	disk := &cloud.Disk{
+		VolumeID:         "vol-old",
+		CapacityGiB:      10,
+		AvailabilityZone: "us-east-1a",
+		VolumeType:       cloud.VolumeTypeGP3,
+		IOPS:             3000,
+		Throughput:       125,
+		Tags:             map[string]string{"team": "storage", "aws:cloudformation:stack-name": "stack"},
+	}
+
+	testCases := []struct {
+		name          string
+		options       *Options
+		disk          *cloud.Disk
+		attachments   []storagev1.VolumeAttachment
+		expectMigrate bool
+		expectedCode  codes.Code
+		expectedEvent string
+	}{
+		{
+			name:         "migration disabled",
+			options:      &Options{},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:    "volume attached",
+			options: &Options{EnableVolumeMigration: true},
+			disk:    disk,
+			attachments: []storagev1.VolumeAttachment{{
+				ObjectMeta: metav1.ObjectMeta{Name: "attachment"},
+				Spec:       storagev1.VolumeAttachmentSpec{Source: storagev1.VolumeAttachmentSource{PersistentVolumeName: ptr.To("pv-test")}},
+			}},
+			expectedCode:  codes.Unavailable,
+			expectedEvent: "Normal VolumeMigrationPending Volume vol-old will be migrated to a new volume once it is detached, to change its encryption",
+		},
+		{
+			name:          "volume migrated",
+			options:       &Options{EnableVolumeMigration: true},
+			disk:          disk,
+			expectMigrate: true,
+			expectedCode:  codes.Unavailable,
+			expectedEvent: "Normal VolumeMigrationStarted Migrating volume vol-old to a new volume restored from its snapshot, to change its encryption",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			c := cloud.NewMockCloud(ctrl)
+			recorder := record.NewFakeRecorder(10)
+			objects := []runtime.Object{newMigrationTestPV("vol-old"), &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "default", UID: "pvc-uid"}}}
+			for i := range tc.attachments {
+				objects = append(objects, &tc.attachments[i])
+			}
+			k := fake.NewClientset(objects...)
+			d := &ControllerService{
+				cloud:                 c,
+				inFlight:              internal.NewInFlight(),
+				options:               tc.options,
+				modifyVolumeCoalescer: newModifyVolumeCoalescer(c, tc.options),
+				k8sClient:             k,
+				recorder:              recorder,
+				migrations:            newVolumeMigrations(),
+			}
+
+			if tc.disk != nil {
+				c.EXPECT().GetDiskByID(gomock.Any(), "vol-old").Return(tc.disk, nil)
+			}
+			if tc.expectMigrate {
+				c.EXPECT().GetSnapshotByName(gomock.Any(), "migration-vol-old").Return(nil, cloud.ErrNotFound)
+				c.EXPECT().ListDisks(gomock.Any(), map[string]string{MigratedFromVolumeTagKey: "vol-old"}).Return(nil, nil)
+				c.EXPECT().CreateSnapshot(gomock.Any(), "vol-old", gomock.Any()).Return(&cloud.Snapshot{SnapshotID: "snap-test"}, nil)
+				c.EXPECT().GetSnapshotByID(gomock.Any(), "snap-test").Return(&cloud.Snapshot{SnapshotID: "snap-test", ReadyToUse: true}, nil)
+				c.EXPECT().CreateDisk(gomock.Any(), gomock.Any(), &cloud.DiskOptions{
+					CapacityBytes:    10 * 1024 * 1024 * 1024,
+					Tags:             map[string]string{"team": "storage", MigratedFromVolumeTagKey: "vol-old", MigratedPersistentVolumeTagKey: "pv-test"},
+					VolumeType:       cloud.VolumeTypeGP3,
+					IOPS:             3000,
+					Throughput:       125,
+					AvailabilityZone: "us-east-1a",
+					Encrypted:        true,
+					SnapshotID:       "snap-test",
+				}).Return(&cloud.Disk{VolumeID: "vol-new"}, nil)
+				c.EXPECT().ModifyTags(gomock.Any(), "vol-new", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, options cloud.ModifyTagsOptions) error {
+					assert.Contains(t, options.TagsToAdd, MigrationCompletedTagKey)
+					return nil
+				})
+				c.EXPECT().DeleteSnapshot(gomock.Any(), "snap-test").Return(true, nil)
+				c.EXPECT().ResizeOrModifyDisk(gomock.Any(), "vol-new", int64(0), gomock.Any()).Return(int32(10), nil)
+			}
+
+			_, err := d.ControllerModifyVolume(context.Background(), &csi.ControllerModifyVolumeRequest{VolumeId: "vol-old", MutableParameters: params})
+			assert.Equal(t, tc.expectedCode, status.Code(err))
+			if tc.expectedEvent != "" {
+				assert.Equal(t, tc.expectedEvent, <-recorder.Events)
+			}
+			if !tc.expectMigrate {
+				return
+			}
+
+			d.migrations.mu.Lock()
+			job := d.migrations.jobs["vol-old"]
+			d.migrations.mu.Unlock()
+			select {
+			case <-job.done:
+			case <-time.After(10 * time.Second):
+				t.Fatal("timed out waiting for the migration")
+			}
+			require.NoError(t, job.err)
+			assert.Equal(t, "Normal VolumeMigrated Volume vol-old was migrated to volume vol-new, which is now used by PersistentVolume pv-test. Volume vol-old is deleted in 0s", <-recorder.Events)
+
+			pv, err := k.CoreV1().PersistentVolumes().Get(context.Background(), "pv-test", metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, "vol-new", pv.Spec.CSI.VolumeHandle)
+			assert.Equal(t, corev1.PersistentVolumeReclaimDelete, pv.Spec.PersistentVolumeReclaimPolicy)
+			assert.Empty(t, pv.Spec.ClaimRef.ResourceVersion)
+
+			// The retry of the external-resizer applies the rest of the modification to the new volume
+			_, err = d.ControllerModifyVolume(context.Background(), &csi.ControllerModifyVolumeRequest{VolumeId: "vol-old", MutableParameters: params})
+			require.NoError(t, err)
+			assert.False(t, d.migrations.running("vol-old"))
+		})
+	}
+}
+
+func TestRunVolumeMigrationFailures(t *testing.T) {
+	backoff := persistentVolumeRecreateBackoff
+	persistentVolumeRecreateBackoff = wait.Backoff{Duration: time.Millisecond, Steps: 2}
+	t.Cleanup(func() { persistentVolumeRecreateBackoff = backoff })
+
+	disk := &cloud.Disk{VolumeID: "vol-old", CapacityGiB: 10, VolumeType: cloud.VolumeTypeGP3}
+	expectSnapshot := func(c *cloud.MockCloud, snapshot *cloud.Snapshot) {
+		c.EXPECT().GetSnapshotByName(gomock.Any(), "migration-vol-old").Return(nil, cloud.ErrNotFound)
+		c.EXPECT().ListDisks(gomock.Any(), map[string]string{MigratedFromVolumeTagKey: "vol-old"}).Return(nil, nil)
+		c.EXPECT().CreateSnapshot(gomock.Any(), "vol-old", gomock.Any()).Return(&cloud.Snapshot{SnapshotID: "snap-test"}, nil)
+		c.EXPECT().GetSnapshotByID(gomock.Any(), "snap-test").Return(snapshot, nil)
+		c.EXPECT().DeleteSnapshot(gomock.Any(), "snap-test").Return(true, nil)
+	}
+	completionTag := cloud.ModifyTagsOptions{TagsToDelete: []string{MigrationCompletedTagKey}}
+
+	testCases := []struct {
+		name string
+		pv   *corev1.PersistentVolume
+		// failCreate fails the creation of the PersistentVolumes of these volumes
+		failCreate     []string
+		expect         func(c *cloud.MockCloud)
+		expectedVolume string
+		expectLost     bool
+	}{
+		{
+			name: "snapshot failed",
+			pv:   newMigrationTestPV("vol-old"),
+			expect: func(c *cloud.MockCloud) {
+				expectSnapshot(c, &cloud.Snapshot{SnapshotID: "snap-test", Failed: true})
+			},
+			expectedVolume: "vol-old",
+		},
+		{
+			name: "finalizer of another controller",
+			pv: func() *corev1.PersistentVolume {
+				pv := newMigrationTestPV("vol-old")
+				pv.Finalizers = append(pv.Finalizers, "example.com/backup")
+				return pv
+			}(),
+			expect: func(c *cloud.MockCloud) {
+				expectSnapshot(c, &cloud.Snapshot{SnapshotID: "snap-test", ReadyToUse: true})
+				c.EXPECT().CreateDisk(gomock.Any(), gomock.Any(), gomock.Any()).Return(&cloud.Disk{VolumeID: "vol-new"}, nil)
+				c.EXPECT().DeleteDisk(gomock.Any(), "vol-new").Return(true, nil)
+			},
+			expectedVolume: "vol-old",
+		},
+		{
+			name:       "PersistentVolume of the original volume restored",
+			pv:         newMigrationTestPV("vol-old"),
+			failCreate: []string{"vol-new"},
+			expect: func(c *cloud.MockCloud) {
+				expectSnapshot(c, &cloud.Snapshot{SnapshotID: "snap-test", ReadyToUse: true})
+				c.EXPECT().CreateDisk(gomock.Any(), gomock.Any(), gomock.Any()).Return(&cloud.Disk{VolumeID: "vol-new"}, nil)
+				c.EXPECT().ModifyTags(gomock.Any(), "vol-new", gomock.Any()).Return(nil)
+				c.EXPECT().ModifyTags(gomock.Any(), "vol-new", completionTag).Return(nil)
+				c.EXPECT().DeleteDisk(gomock.Any(), "vol-new").Return(true, nil)
+			},
+			expectedVolume: "vol-old",
+		},
+		{
+			name:       "PersistentVolume lost",
+			pv:         newMigrationTestPV("vol-old"),
+			failCreate: []string{"vol-new", "vol-old"},
+			expect: func(c *cloud.MockCloud) {
+				expectSnapshot(c, &cloud.Snapshot{SnapshotID: "snap-test", ReadyToUse: true})
+				c.EXPECT().CreateDisk(gomock.Any(), gomock.Any(), gomock.Any()).Return(&cloud.Disk{VolumeID: "vol-new"}, nil)
+				c.EXPECT().ModifyTags(gomock.Any(), "vol-new", gomock.Any()).Return(nil)
+				c.EXPECT().ModifyTags(gomock.Any(), "vol-new", completionTag).Return(nil)
+			},
+			expectLost: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			c := cloud.NewMockCloud(ctrl)
+			tc.expect(c)
+			k := fake.NewClientset(tc.pv)
+			k.PrependReactor("create", "persistentvolumes", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				pv := action.(k8stesting.CreateAction).GetObject().(*corev1.PersistentVolume)
+				if slices.Contains(tc.failCreate, pv.Spec.CSI.VolumeHandle) {
+					return true, nil, errors.New("admission webhook denied the request")
+				}
+				return false, nil, nil
+			})
+			d := &ControllerService{
+				cloud:      c,
+				options:    &Options{EnableVolumeMigration: true},
+				k8sClient:  k,
+				migrations: newVolumeMigrations(),
+			}
+
+			_, err := d.runVolumeMigration(context.Background(), disk, "pv-test", &volumeMigrationTarget{encrypted: true})
+			require.Error(t, err)
+			assert.Equal(t, tc.expectLost, errors.Is(err, errPersistentVolumeLost))
+
+			pv, err := k.CoreV1().PersistentVolumes().Get(context.Background(), "pv-test", metav1.GetOptions{})
+			if tc.expectLost {
+				assert.True(t, apierrors.IsNotFound(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedVolume, pv.Spec.CSI.VolumeHandle)
+			assert.Equal(t, corev1.PersistentVolumeReclaimDelete, pv.Spec.PersistentVolumeReclaimPolicy)
+		})
+	}
+}
+
+func TestDeleteMigratedVolumes(t *testing.T) {
+	migrated := func(volumeID string, age time.Duration, completed string) *cloud.Disk {
+		disk := &cloud.Disk{
+			VolumeID:   volumeID,
+			CreateTime: time.Now().Add(-age),
+			Tags:       map[string]string{MigratedFromVolumeTagKey: "vol-old", MigratedPersistentVolumeTagKey: "pv-test"},
+		}
+		if completed != "" {
+			disk.Tags[MigrationCompletedTagKey] = completed
+		}
+		return disk
+	}
+	completedAt := func(age time.Duration) string {
+		return time.Now().Add(-age).UTC().Format(time.RFC3339)
+	}
+
+	testCases := []struct {
+		name   string
+		disk   *cloud.Disk
+		expect func(c *cloud.MockCloud)
+	}{
+		{
+			name: "retention not over",
+			disk: migrated("vol-new", 48*time.Hour, completedAt(time.Hour)),
+		},
+		{
+			name: "original volume deleted",
+			disk: migrated("vol-new", 48*time.Hour, completedAt(25*time.Hour)),
+			expect: func(c *cloud.MockCloud) {
+				c.EXPECT().DeleteDisk(gomock.Any(), "vol-old").Return(true, nil)
+				c.EXPECT().ModifyTags(gomock.Any(), "vol-new", cloud.ModifyTagsOptions{TagsToDelete: []string{MigratedFromVolumeTagKey, MigratedPersistentVolumeTagKey, MigrationCompletedTagKey}}).Return(nil)
+			},
+		},
+		{
+			name: "migration may still run in another replica",
+			disk: migrated("vol-new", 2*time.Hour, ""),
+		},
+		{
+			name: "new volume of failed migration deleted",
+			disk: migrated("vol-new", 48*time.Hour, ""),
+			expect: func(c *cloud.MockCloud) {
+				c.EXPECT().DeleteDisk(gomock.Any(), "vol-new").Return(true, nil)
+			},
+		},
+		{
+			name: "new volume of failed migration attached",
+			disk: func() *cloud.Disk {
+				disk := migrated("vol-new", 48*time.Hour, "")
+				disk.Attachments = []string{"i-1234"}
+				return disk
+			}(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			c := cloud.NewMockCloud(ctrl)
+			c.EXPECT().ListDisks(gomock.Any(), map[string]string{MigratedFromVolumeTagKey: ""}).Return([]*cloud.Disk{tc.disk}, nil)
+			if tc.expect != nil {
+				tc.expect(c)
+			}
+			d := &ControllerService{
+				cloud:      c,
+				options:    &Options{VolumeMigrationRetention: 24 * time.Hour},
+				migrations: newVolumeMigrations(),
+			}
+			d.deleteMigratedVolumes(context.Background())
+		})
+	}
+}
@@ -53,6 +53,10 @@ type modifyVolumeRequest struct {
 	newSize           int64
 	modifyDiskOptions cloud.ModifyDiskOptions
 	modifyTagsOptions cloud.ModifyTagsOptions
+	// applyDeferred applies the deferred modification of the volume, if any.
+	applyDeferred bool
+	// migration is the encryption requested for the volume, nil if none was requested.
+	migration *volumeMigrationTarget
 }
 
 func (d *ControllerService) GetCSIDriverModificationCapability(
@@ -66,18 +70,21 @@ func (d *ControllerService) ModifyVolumeProperties(
 	ctx context.Context,
 	req *rpc.ModifyVolumePropertiesRequest,
 ) (*rpc.ModifyVolumePropertiesResponse, error) {
-	klog.V(4).InfoS("ModifyVolumeProperties called", "req", req)
+	klog.FromContext(ctx).V(4).Info("ModifyVolumeProperties called", "req", req)
 	name := req.GetName()
 	if name == "" {
 		return nil, status.Error(codes.InvalidArgument, "Volume name not provided")
 	}
 
-	options, err := parseModifyVolumeParameters(req.GetParameters())
+	options, err := parseModifyVolumeParameters(ctx, req.GetParameters(), d.tagMetadata)
 	if err != nil {
 		return nil, err
 	}
+	if options.migration != nil {
+		return nil, status.Error(codes.InvalidArgument, "The encryption of a volume can only be changed by a VolumeAttributesClass")
+	}
 
-	_, err = d.modifyVolumeCoalescer.Coalesce(name, *options)
+	_, err = d.modifyVolumeCoalescer.Coalesce(ctx, name, *options)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +93,13 @@ func (d *ControllerService) ModifyVolumeProperties(
 }
 
 func newModifyVolumeCoalescer(c cloud.Cloud, o *Options) coalescer.Coalescer[modifyVolumeRequest, int32] {
-	return coalescer.New[modifyVolumeRequest, int32](o.ModifyVolumeRequestHandlerTimeout, mergeModifyVolumeRequest, executeModifyVolumeRequest(c))
+	deferred := newDeferredModifications()
+	modifyVolumeCoalescer := coalescer.New[modifyVolumeRequest, int32](o.ModifyVolumeRequestHandlerTimeout, mergeModifyVolumeRequest, executeModifyVolumeRequest(c, deferred))
+	deferred.apply = func(volumeID string) error {
+		_, err := modifyVolumeCoalescer.Coalesce(context.Background(), volumeID, modifyVolumeRequest{applyDeferred: true})
+		return err
+	}
+	return modifyVolumeCoalescer
 }
 
 func mergeModifyVolumeRequest(input modifyVolumeRequest, existing modifyVolumeRequest) (modifyVolumeRequest, error) {
@@ -114,6 +127,7 @@ func mergeModifyVolumeRequest(input modifyVolumeRequest, existing modifyVolumeRe
 		}
 		existing.modifyDiskOptions.VolumeType = input.modifyDiskOptions.VolumeType
 	}
+	existing.applyDeferred = existing.applyDeferred || input.applyDeferred
 	if len(input.modifyTagsOptions.TagsToAdd) > 0 || len(input.modifyTagsOptions.TagsToDelete) > 0 {
 		if (len(existing.modifyTagsOptions.TagsToAdd) > 0 || len(existing.modifyTagsOptions.TagsToDelete) > 0) && !(reflect.DeepEqual(input.modifyTagsOptions, existing.modifyTagsOptions)) {
 			return existing, fmt.Errorf("different tags were requested by a previous request. Current: %v, Requested: %v", existing.modifyTagsOptions, input.modifyTagsOptions)
@@ -139,19 +153,23 @@ func executeModifyTagsRequest(volumeID string, options modifyVolumeRequest, c cl
 	return nil
 }
 
-func executeModifyVolumeRequest(c cloud.Cloud) func(string, modifyVolumeRequest) (int32, error) {
-	return func(volumeID string, req modifyVolumeRequest) (int32, error) {
-		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+func executeModifyVolumeRequest(c cloud.Cloud, deferred *deferredModifications) func(context.Context, string, modifyVolumeRequest) (int32, error) {
+	return func(ctx context.Context, volumeID string, req modifyVolumeRequest) (int32, error) {
+		ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 		defer cancel()
 		err := executeModifyTagsRequest(volumeID, req, c, ctx)
 		if err != nil {
 			return 0, err
 		}
 
-		if (req.modifyDiskOptions.IOPS != 0) || (req.modifyDiskOptions.Throughput != 0) || (req.modifyDiskOptions.VolumeType != "") || (req.newSize != 0) || (req.modifyDiskOptions.IOPSPerGB != 0) {
+		if (req.modifyDiskOptions.IOPS != 0) || (req.modifyDiskOptions.Throughput != 0) || (req.modifyDiskOptions.VolumeType != "") || (req.newSize != 0) || (req.modifyDiskOptions.IOPSPerGB != 0) || req.applyDeferred {
 			actualSizeGiB, err := c.ResizeOrModifyDisk(ctx, volumeID, req.newSize, &req.modifyDiskOptions)
+			var deferredErr *cloud.ModificationDeferredError
 			if err != nil {
 				switch {
+				case errors.As(err, &deferredErr):
+					deferred.schedule(volumeID, deferredErr.Until)
+					return 0, status.Error(codes.Unavailable, err.Error())
 				case errors.Is(err, cloud.ErrInvalidArgument):
 					// Returning Internal error instead of InvaliArgument because at this point any tag modifications have succeeded.
 					// It would not be correct to return an error that is considered infeasible by the resizer if the volume was already modified in any way.
@@ -167,6 +185,7 @@ func executeModifyVolumeRequest(c cloud.Cloud) func(string, modifyVolumeRequest)
 					return 0, status.Errorf(codes.Internal, "Could not modify volume %q: %v", volumeID, err)
 				}
 			} else {
+				deferred.forget(volumeID)
 				return actualSizeGiB, nil
 			}
 		}
@@ -175,7 +194,7 @@ func executeModifyVolumeRequest(c cloud.Cloud) func(string, modifyVolumeRequest)
 	}
 }
 
-func parseModifyVolumeParameters(params map[string]string) (*modifyVolumeRequest, error) {
+func parseModifyVolumeParameters(ctx context.Context, params map[string]string, metadata *tagTemplateMetadata) (*modifyVolumeRequest, error) {
 	options := modifyVolumeRequest{
 		modifyTagsOptions: cloud.ModifyTagsOptions{
 			TagsToAdd:    make(map[string]string),
@@ -201,10 +220,10 @@ func parseModifyVolumeParameters(params map[string]string) (*modifyVolumeRequest
 			options.modifyDiskOptions.Throughput = int32(throughput)
 		case DeprecatedModificationKeyVolumeType:
 			if _, ok := params[ModificationKeyVolumeType]; ok {
-				klog.Infof("Ignoring deprecated key `volumeType` because preferred key `type` is present")
+				klog.FromContext(ctx).Info("Ignoring deprecated key `volumeType` because preferred key `type` is present")
 				continue
 			}
-			klog.InfoS("Key `volumeType` is deprecated, please use `type` instead")
+			klog.FromContext(ctx).Info("Key `volumeType` is deprecated, please use `type` instead")
 			options.modifyDiskOptions.VolumeType = value
 		case ModificationKeyVolumeType:
 			options.modifyDiskOptions.VolumeType = value
@@ -226,8 +245,27 @@ func parseModifyVolumeParameters(params map[string]string) (*modifyVolumeRequest
 			tProps.PVName = value
 		default:
 			switch {
+			case strings.EqualFold(key, EncryptedKey):
+				if options.migration == nil {
+					options.migration = &volumeMigrationTarget{}
+				}
+				options.migration.encrypted = isTrue(value)
+			case strings.EqualFold(key, KmsKeyIDKey):
+				if options.migration == nil {
+					options.migration = &volumeMigrationTarget{}
+				}
+				options.migration.kmsKeyID = value
+			case strings.EqualFold(key, DeletionProtectionKey):
+				if isTrue(value) {
+					noValidationTags[DeletionProtectionTagKey] = trueStr
+				} else {
+					options.modifyTagsOptions.TagsToDelete = append(options.modifyTagsOptions.TagsToDelete, DeletionProtectionTagKey)
+				}
 			case strings.HasPrefix(key, ModificationAddTag):
 				rawTagsToAdd = append(rawTagsToAdd, value)
+			case isIOLimitKey(key):
+				// I/O limits are passed to the node in the volume context, which cannot be modified
+				return nil, status.Errorf(codes.InvalidArgument, "I/O limit %s cannot be modified, it only takes effect for volumes created with it", key)
 			case strings.HasPrefix(key, ModificationDeleteTag):
 				if err := validateExtraTags(map[string]string{value: ""}, false); err != nil {
 					return nil, status.Errorf(codes.InvalidArgument, "Cannot delete reserved tag: %v", err)
@@ -238,6 +276,9 @@ func parseModifyVolumeParameters(params map[string]string) (*modifyVolumeRequest
 			}
 		}
 	}
+	if err := metadata.fill(ctx, rawTagsToAdd, tProps); err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not get the metadata of tag templates: %v", err)
+	}
 	addTags, err := template.Evaluate(rawTagsToAdd, tProps, false)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "Error interpolating tag value: %v", err)
@@ -247,5 +288,8 @@ func parseModifyVolumeParameters(params map[string]string) (*modifyVolumeRequest
 	}
 	maps.Copy(addTags, noValidationTags)
 	options.modifyTagsOptions.TagsToAdd = addTags
+	if options.migration != nil && options.migration.kmsKeyID != "" && !options.migration.encrypted {
+		return nil, status.Errorf(codes.InvalidArgument, "Parameter %s requires %s to be true", KmsKeyIDKey, EncryptedKey)
+	}
 	return &options, nil
 }
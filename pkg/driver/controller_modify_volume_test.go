@@ -252,6 +252,13 @@ func TestParseModifyVolumeParameters(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "I/O limit",
+			params: map[string]string{
+				"readIOPSLimit": "1000",
+			},
+			expectError: true,
+		},
 		{
 			name: "delete reserved tag CSIVolumeName",
 			params: map[string]string{
@@ -285,11 +292,56 @@ func TestParseModifyVolumeParameters(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "encryption",
+			params: map[string]string{
+				"encrypted": "true",
+				"kmsKeyId":  "key-id",
+			},
+			expectedOptions: &modifyVolumeRequest{
+				modifyTagsOptions: cloud.ModifyTagsOptions{
+					TagsToAdd:    map[string]string{},
+					TagsToDelete: []string{},
+				},
+				migration: &volumeMigrationTarget{encrypted: true, kmsKeyID: "key-id"},
+			},
+		},
+		{
+			name: "enable deletion protection",
+			params: map[string]string{
+				"deletionProtection": "true",
+			},
+			expectedOptions: &modifyVolumeRequest{
+				modifyTagsOptions: cloud.ModifyTagsOptions{
+					TagsToAdd:    map[string]string{DeletionProtectionTagKey: "true"},
+					TagsToDelete: []string{},
+				},
+			},
+		},
+		{
+			name: "disable deletion protection",
+			params: map[string]string{
+				"deletionProtection": "false",
+			},
+			expectedOptions: &modifyVolumeRequest{
+				modifyTagsOptions: cloud.ModifyTagsOptions{
+					TagsToAdd:    map[string]string{},
+					TagsToDelete: []string{DeletionProtectionTagKey},
+				},
+			},
+		},
+		{
+			name: "KMS key without encryption",
+			params: map[string]string{
+				"kmsKeyId": "key-id",
+			},
+			expectError: true,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result, err := parseModifyVolumeParameters(tc.params)
+			result, err := parseModifyVolumeParameters(t.Context(), tc.params, nil)
 			assert.Equal(t, tc.expectedOptions, result)
 			if tc.expectError {
 				require.Error(t, err)
@@ -0,0 +1,278 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// pvcTagSyncWorkers is the number of PVCs whose tags are synced concurrently.
+	pvcTagSyncWorkers = 2
+	// maxTagValueLength is the maximum length of the value of an EC2 tag.
+	maxTagValueLength = 256
+)
+
+// pvcTagSyncer mirrors the labels and annotations of PVCs selected by --pvc-tag-sync-labels and
+// --pvc-tag-sync-annotations to the tags of their volume and of the snapshots the driver took of it.
+// The tags with these keys are owned by the syncer, which removes them when the label or annotation
+// is removed.
+type pvcTagSyncer struct {
+	cloud       cloud.Cloud
+	labels      []string
+	annotations []string
+	interval    time.Duration
+
+	// factory is shared with the other informers of the controller.
+	factory     informers.SharedInformerFactory
+	pvcInformer cache.SharedIndexInformer
+	pvcLister   corelisters.PersistentVolumeClaimLister
+	pvLister    corelisters.PersistentVolumeLister
+	synced      []cache.InformerSynced
+	queue       workqueue.TypedRateLimitingInterface[string]
+}
+
+func newPVCTagSyncer(c cloud.Cloud, o *Options, factory informers.SharedInformerFactory) *pvcTagSyncer {
+	pvcInformer := factory.Core().V1().PersistentVolumeClaims()
+	pvInformer := factory.Core().V1().PersistentVolumes()
+	return &pvcTagSyncer{
+		cloud:       c,
+		labels:      o.PVCTagSyncLabels,
+		annotations: o.PVCTagSyncAnnotations,
+		interval:    o.PVCTagSyncInterval,
+		factory:     factory,
+		pvcInformer: pvcInformer.Informer(),
+		pvcLister:   pvcInformer.Lister(),
+		pvLister:    pvInformer.Lister(),
+		synced:      []cache.InformerSynced{pvcInformer.Informer().HasSynced, pvInformer.Informer().HasSynced},
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[string](),
+			workqueue.TypedRateLimitingQueueConfig[string]{Name: "pvc-tag-sync"}),
+	}
+}
+
+// Start starts syncing the tags of the volumes of PVCs, until ctx is done. It only runs in the
+// controller replica holding the leader election, the PVCs are only queued from then on.
+func (s *pvcTagSyncer) Start(ctx context.Context) error {
+	// The resyncs of the handler, with the same resource version, sync all the PVCs periodically
+	_, err := s.pvcInformer.AddEventHandlerWithResyncPeriod(cache.ResourceEventHandlerFuncs{
+		AddFunc: s.enqueue,
+		UpdateFunc: func(oldObj, newObj any) {
+			oldPVC, oldOK := oldObj.(*corev1.PersistentVolumeClaim)
+			newPVC, newOK := newObj.(*corev1.PersistentVolumeClaim)
+			if !oldOK || !newOK {
+				return
+			}
+			if oldPVC.ResourceVersion == newPVC.ResourceVersion || oldPVC.Spec.VolumeName != newPVC.Spec.VolumeName ||
+				!maps.Equal(s.desiredTags(oldPVC), s.desiredTags(newPVC)) {
+				s.enqueue(newObj)
+			}
+		},
+	}, s.interval)
+	if err != nil {
+		return fmt.Errorf("failed to add PersistentVolumeClaim event handler: %w", err)
+	}
+
+	s.factory.Start(ctx.Done())
+	go func() {
+		defer s.queue.ShutDown()
+		if !cache.WaitForCacheSync(ctx.Done(), s.synced...) {
+			klog.ErrorS(nil, "PVC tag sync: cache sync failed")
+			return
+		}
+		klog.V(4).InfoS("PVC tag sync: caches synced")
+		for range pvcTagSyncWorkers {
+			go wait.UntilWithContext(ctx, s.runWorker, 0)
+		}
+		<-ctx.Done()
+	}()
+	return nil
+}
+
+func (s *pvcTagSyncer) enqueue(obj any) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		klog.ErrorS(err, "PVC tag sync: could not get key of PersistentVolumeClaim")
+		return
+	}
+	s.queue.Add(key)
+}
+
+func (s *pvcTagSyncer) runWorker(ctx context.Context) {
+	for {
+		key, shutdown := s.queue.Get()
+		if shutdown {
+			return
+		}
+		if err := s.sync(ctx, key); err != nil {
+			klog.ErrorS(err, "PVC tag sync: could not sync tags", "persistentVolumeClaim", key)
+			s.queue.AddRateLimited(key)
+		} else {
+			s.queue.Forget(key)
+		}
+		s.queue.Done(key)
+	}
+}
+
+// sync syncs the tags of the volume of the PVC key, and of the snapshots the driver took of it.
+func (s *pvcTagSyncer) sync(ctx context.Context, key string) error {
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	pvc, err := s.pvcLister.PersistentVolumeClaims(namespace).Get(name)
+	if apierrors.IsNotFound(err) || (err == nil && pvc.Spec.VolumeName == "") {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	pv, err := s.pvLister.Get(pvc.Spec.VolumeName)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if pv.Spec.CSI == nil || pv.Spec.CSI.Driver != util.GetDriverName() || isNodeLocalVolume(pv.Spec.CSI.VolumeHandle) {
+		return nil
+	}
+	volumeID := pv.Spec.CSI.VolumeHandle
+	desired := s.desiredTags(pvc)
+
+	disk, err := s.cloud.GetDiskByID(ctx, volumeID)
+	if errors.Is(err, cloud.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not get volume %q: %w", volumeID, err)
+	}
+	// Resources with the same changes are tagged together, in as few requests as possible
+	changes := map[string]cloud.ModifyTagsOptions{}
+	resources := map[string][]string{}
+	addResource := func(resourceID string, tags map[string]string) {
+		c := s.tagChanges(tags, desired)
+		if len(c.TagsToAdd) == 0 && len(c.TagsToDelete) == 0 {
+			return
+		}
+		group := fmt.Sprint(c.TagsToDelete, c.TagsToAdd)
+		changes[group] = c
+		resources[group] = append(resources[group], resourceID)
+	}
+	addResource(volumeID, disk.Tags)
+
+	nextToken := ""
+	for {
+		resp, err := s.cloud.ListSnapshots(ctx, volumeID, 0, nextToken)
+		if errors.Is(err, cloud.ErrNotFound) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("could not list the snapshots of volume %q: %w", volumeID, err)
+		}
+		for _, snapshot := range resp.Snapshots {
+			if snapshot.Tags[cloud.AwsEbsDriverTagKey] == isManagedByDriver {
+				addResource(snapshot.SnapshotID, snapshot.Tags)
+			}
+		}
+		if resp.NextToken == "" {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+
+	for _, group := range slices.Sorted(maps.Keys(changes)) {
+		klog.V(4).InfoS("PVC tag sync: modifying tags", "persistentVolumeClaim", klog.KObj(pvc), "volumeID", volumeID,
+			"resourceIDs", resources[group], "tagsToAdd", changes[group].TagsToAdd, "tagsToDelete", changes[group].TagsToDelete)
+		if err := s.cloud.ModifyResourceTags(ctx, resources[group], changes[group]); err != nil {
+			return fmt.Errorf("could not modify the tags of volume %q: %w", volumeID, err)
+		}
+	}
+	return nil
+}
+
+// desiredTags returns the tags mirrored from the labels and annotations of pvc. The labels take
+// precedence over the annotations with the same key.
+func (s *pvcTagSyncer) desiredTags(pvc *corev1.PersistentVolumeClaim) map[string]string {
+	tags := map[string]string{}
+	for _, key := range s.annotations {
+		if value, ok := pvc.Annotations[key]; ok {
+			tags[key] = value
+		}
+	}
+	for _, key := range s.labels {
+		if value, ok := pvc.Labels[key]; ok {
+			tags[key] = value
+		}
+	}
+	return tags
+}
+
+// tagChanges returns the changes of the tags current of a resource for it to have the synced tags desired.
+// Values longer than the maximum length of a tag value cannot be synced, the tags of their keys are
+// deleted rather than left with a stale value.
+func (s *pvcTagSyncer) tagChanges(current, desired map[string]string) cloud.ModifyTagsOptions {
+	changes := cloud.ModifyTagsOptions{TagsToAdd: map[string]string{}}
+	for key, value := range desired {
+		if len(value) > maxTagValueLength {
+			klog.V(4).InfoS("PVC tag sync: not syncing value longer than the maximum length of a tag value", "key", key)
+			continue
+		}
+		if currentValue, ok := current[key]; !ok || currentValue != value {
+			changes.TagsToAdd[key] = value
+		}
+	}
+	for _, key := range slices.Concat(s.labels, s.annotations) {
+		if value, ok := desired[key]; ok && len(value) <= maxTagValueLength {
+			continue
+		}
+		if _, ok := current[key]; ok && !slices.Contains(changes.TagsToDelete, key) {
+			changes.TagsToDelete = append(changes.TagsToDelete, key)
+		}
+	}
+	slices.Sort(changes.TagsToDelete)
+	return changes
+}
+
+// validatePVCTagSyncKeys validates that the keys of the labels and annotations synced to tags are
+// valid tag keys that are not reserved.
+func validatePVCTagSyncKeys(keys []string) error {
+	tags := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if len(key) > 128 || strings.HasPrefix(key, "aws:") {
+			return fmt.Errorf("%q is not a valid tag key", key)
+		}
+		tags[key] = ""
+	}
+	return validateExtraTags(tags, false)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"maps"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestPVCTagSync(t *testing.T) {
+	managed := map[string]string{cloud.AwsEbsDriverTagKey: isManagedByDriver}
+	withManaged := func(tags map[string]string) map[string]string {
+		maps.Copy(tags, managed)
+		return tags
+	}
+
+	testCases := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		volumeName  string
+		expect      func(c *cloud.MockCloud)
+	}{
+		{
+			name:       "label added to volume and snapshots",
+			labels:     map[string]string{"team": "storage", "unsynced": "value"},
+			volumeName: "pv-test",
+			expect: func(c *cloud.MockCloud) {
+				c.EXPECT().GetDiskByID(gomock.Any(), "vol-test").Return(&cloud.Disk{VolumeID: "vol-test", Tags: map[string]string{}}, nil)
+				c.EXPECT().ListSnapshots(gomock.Any(), "vol-test", int32(0), "").Return(&cloud.ListSnapshotsResponse{
+					Snapshots: []*cloud.Snapshot{
+						{SnapshotID: "snap-driver", Tags: withManaged(map[string]string{})},
+						{SnapshotID: "snap-backup", Tags: map[string]string{}},
+					},
+					NextToken: "token",
+				}, nil)
+				c.EXPECT().ListSnapshots(gomock.Any(), "vol-test", int32(0), "token").Return(&cloud.ListSnapshotsResponse{
+					Snapshots: []*cloud.Snapshot{{SnapshotID: "snap-driver-2", Tags: withManaged(map[string]string{"team": "storage"})}},
+				}, nil)
+				c.EXPECT().ModifyResourceTags(gomock.Any(), []string{"vol-test", "snap-driver"}, cloud.ModifyTagsOptions{
+					TagsToAdd: map[string]string{"team": "storage"},
+				}).Return(nil)
+			},
+		},
+		{
+			name:        "label changed, label removed and annotation too long",
+			labels:      map[string]string{"team": "compute"},
+			annotations: map[string]string{"example.com/owner": strings.Repeat("a", maxTagValueLength+1)},
+			volumeName:  "pv-test",
+			expect: func(c *cloud.MockCloud) {
+				c.EXPECT().GetDiskByID(gomock.Any(), "vol-test").Return(&cloud.Disk{VolumeID: "vol-test", Tags: map[string]string{
+					"team": "storage", "cost-center": "42", "example.com/owner": "alice",
+				}}, nil)
+				c.EXPECT().ListSnapshots(gomock.Any(), "vol-test", int32(0), "").Return(nil, cloud.ErrNotFound)
+				c.EXPECT().ModifyResourceTags(gomock.Any(), []string{"vol-test"}, cloud.ModifyTagsOptions{
+					TagsToAdd:    map[string]string{"team": "compute"},
+					TagsToDelete: []string{"cost-center", "example.com/owner"},
+				}).Return(nil)
+			},
+		},
+		{
+			name:       "tags in sync",
+			labels:     map[string]string{"team": "storage"},
+			volumeName: "pv-test",
+			expect: func(c *cloud.MockCloud) {
+				c.EXPECT().GetDiskByID(gomock.Any(), "vol-test").Return(&cloud.Disk{VolumeID: "vol-test", Tags: map[string]string{"team": "storage"}}, nil)
+				c.EXPECT().ListSnapshots(gomock.Any(), "vol-test", int32(0), "").Return(nil, cloud.ErrNotFound)
+			},
+		},
+		{
+			name:   "unbound PVC",
+			labels: map[string]string{"team": "storage"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			c := cloud.NewMockCloud(ctrl)
+			if tc.expect != nil {
+				tc.expect(c)
+			}
+			k := fake.NewClientset(
+				&corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: "default", Labels: tc.labels, Annotations: tc.annotations},
+					Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: tc.volumeName},
+				},
+				&corev1.PersistentVolume{
+					ObjectMeta: metav1.ObjectMeta{Name: "pv-test"},
+					Spec: corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{
+						CSI: &corev1.CSIPersistentVolumeSource{Driver: util.GetDriverName(), VolumeHandle: "vol-test"},
+					}},
+				},
+			)
+			s := newPVCTagSyncer(c, &Options{
+				PVCTagSyncLabels:      []string{"team", "cost-center"},
+				PVCTagSyncAnnotations: []string{"example.com/owner"},
+				PVCTagSyncInterval:    time.Hour,
+			}, informers.NewSharedInformerFactory(k, 0))
+			s.factory.Start(t.Context().Done())
+			require.True(t, cache.WaitForCacheSync(t.Context().Done(), s.synced...))
+
+			require.NoError(t, s.sync(t.Context(), "default/claim"))
+		})
+	}
+}
+
+func TestValidatePVCTagSyncKeys(t *testing.T) {
+	require.NoError(t, validatePVCTagSyncKeys([]string{"team", "example.com/owner"}))
+	require.Error(t, validatePVCTagSyncKeys([]string{"kubernetes.io/team"}))
+	require.Error(t, validatePVCTagSyncKeys([]string{util.GetDriverName() + "/team"}))
+	require.Error(t, validatePVCTagSyncKeys([]string{"aws:team"}))
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util/template"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	corelisters "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// tagTemplateMetadataFields are the fields of template.PVProps that are fetched from Kubernetes.
+var tagTemplateMetadataFields = []string{".PVCLabels", ".PVCAnnotations", ".StorageClassName", ".NamespaceLabels"}
+
+// tagTemplateMetadata fills the properties of the tag templates of volumes with the metadata of their
+// PVC and namespace. The PVCs and namespaces are cached by informers, which are only started once a
+// tag template references them, so that the permission to watch them is only needed to use them.
+type tagTemplateMetadata struct {
+	k8s       kubernetes.Interface
+	factory   informers.SharedInformerFactory
+	clusterID string
+
+	once      sync.Once
+	pvcLister corelisters.PersistentVolumeClaimLister
+	nsLister  corelisters.NamespaceLister
+	synced    []cache.InformerSynced
+}
+
+func newTagTemplateMetadata(k kubernetes.Interface, factory informers.SharedInformerFactory, clusterID string) *tagTemplateMetadata {
+	return &tagTemplateMetadata{k8s: k, factory: factory, clusterID: clusterID}
+}
+
+// fill fills props with the ID of the cluster, and with the metadata of the PVC of props if one of
+// the templates tm references it.
+func (m *tagTemplateMetadata) fill(ctx context.Context, tm []string, props *template.PVProps) error {
+	if m == nil {
+		return nil
+	}
+	props.ClusterID = m.clusterID
+	if m.k8s == nil || m.factory == nil || props.PVCName == "" || props.PVCNamespace == "" || !referencesMetadata(tm) {
+		return nil
+	}
+	m.once.Do(m.start)
+	// The API server is queried until the caches are synced, rather than waiting for them
+	synced := m.hasSynced()
+
+	var pvc *corev1.PersistentVolumeClaim
+	var err error
+	if synced {
+		pvc, err = m.pvcLister.PersistentVolumeClaims(props.PVCNamespace).Get(props.PVCName)
+	}
+	if !synced || apierrors.IsNotFound(err) {
+		// The PVC of a new volume may not be in the cache yet
+		pvc, err = m.k8s.CoreV1().PersistentVolumeClaims(props.PVCNamespace).Get(ctx, props.PVCName, metav1.GetOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("could not get PVC %s/%s: %w", props.PVCNamespace, props.PVCName, err)
+	}
+	props.PVCLabels = pvc.Labels
+	props.PVCAnnotations = pvc.Annotations
+	if pvc.Spec.StorageClassName != nil {
+		props.StorageClassName = *pvc.Spec.StorageClassName
+	}
+
+	var ns *corev1.Namespace
+	if synced {
+		ns, err = m.nsLister.Get(props.PVCNamespace)
+	} else {
+		ns, err = m.k8s.CoreV1().Namespaces().Get(ctx, props.PVCNamespace, metav1.GetOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("could not get namespace %s: %w", props.PVCNamespace, err)
+	}
+	props.NamespaceLabels = ns.Labels
+	return nil
+}
+
+// start starts the informers of PVCs and namespaces, which run for the lifetime of the controller.
+func (m *tagTemplateMetadata) start() {
+	klog.InfoS("Watching PVCs and namespaces for the metadata of tag templates")
+	pvcInformer := m.factory.Core().V1().PersistentVolumeClaims()
+	nsInformer := m.factory.Core().V1().Namespaces()
+	m.pvcLister = pvcInformer.Lister()
+	m.nsLister = nsInformer.Lister()
+	m.synced = []cache.InformerSynced{pvcInformer.Informer().HasSynced, nsInformer.Informer().HasSynced}
+	m.factory.Start(wait.NeverStop)
+}
+
+// hasSynced returns true once the caches of PVCs and namespaces are synced.
+func (m *tagTemplateMetadata) hasSynced() bool {
+	for _, synced := range m.synced {
+		if !synced() {
+			return false
+		}
+	}
+	return true
+}
+
+// referencesMetadata returns true if one of the templates tm references the Kubernetes metadata of the PVC.
+func referencesMetadata(tm []string) bool {
+	for _, t := range tm {
+		for _, field := range tagTemplateMetadataFields {
+			if strings.Contains(t, field) {
+				return true
+			}
+		}
+	}
+	return false
+}
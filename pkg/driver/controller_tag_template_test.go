@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util/template"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTagTemplateMetadata(t *testing.T) {
+	storageClassName := "ebs-sc"
+	k := fake.NewClientset(
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "claim", Namespace: "default",
+				Labels:      map[string]string{"team": "storage"},
+				Annotations: map[string]string{"example.com/owner": "alice"},
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: &storageClassName},
+		},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"env": "prod"}}},
+	)
+
+	testCases := []struct {
+		name      string
+		tm        []string
+		props     template.PVProps
+		expected  template.PVProps
+		expectErr bool
+	}{
+		{
+			name:     "metadata not referenced",
+			tm:       []string{"pvc={{ .PVCName }}", "cluster={{ .ClusterID }}"},
+			props:    template.PVProps{PVCName: "claim", PVCNamespace: "default"},
+			expected: template.PVProps{PVCName: "claim", PVCNamespace: "default", ClusterID: "cluster-1"},
+		},
+		{
+			name:  "metadata referenced",
+			tm:    []string{"team={{ get \"team\" .PVCLabels }}"},
+			props: template.PVProps{PVCName: "claim", PVCNamespace: "default"},
+			expected: template.PVProps{
+				PVCName: "claim", PVCNamespace: "default", ClusterID: "cluster-1",
+				PVCLabels:        map[string]string{"team": "storage"},
+				PVCAnnotations:   map[string]string{"example.com/owner": "alice"},
+				StorageClassName: storageClassName,
+				NamespaceLabels:  map[string]string{"env": "prod"},
+			},
+		},
+		{
+			name:     "no PVC",
+			tm:       []string{"class={{ .StorageClassName }}"},
+			props:    template.PVProps{},
+			expected: template.PVProps{ClusterID: "cluster-1"},
+		},
+		{
+			name:      "PVC not found",
+			tm:        []string{"class={{ .StorageClassName }}"},
+			props:     template.PVProps{PVCName: "missing", PVCNamespace: "default"},
+			expectErr: true,
+		},
+	}
+
+	m := newTagTemplateMetadata(k, informers.NewSharedInformerFactory(k, 0), "cluster-1")
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := m.fill(t.Context(), tc.tm, &tc.props)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, tc.props)
+		})
+	}
+}
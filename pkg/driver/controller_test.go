@@ -2024,6 +2024,151 @@ func TestCreateVolume(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "success with I/O limits passed to volume context",
+			testFunc: func(t *testing.T) {
+				t.Helper()
+				req := &csi.CreateVolumeRequest{
+					Name:               "test-vol",
+					CapacityRange:      stdCapRange,
+					VolumeCapabilities: stdVolCap,
+					Parameters: map[string]string{
+						"readIOPSLimit":       "1000",
+						"writeBandwidthLimit": "100Mi",
+					},
+					MutableParameters: map[string]string{
+						"readIOPSLimit": "2000",
+					},
+				}
+				expVolumeContext := map[string]string{
+					ReadIOPSLimitKey:       "2000",
+					WriteBandwidthLimitKey: "100Mi",
+				}
+
+				ctx := t.Context()
+
+				mockDisk := &cloud.Disk{
+					VolumeID:         req.GetName(),
+					AvailabilityZone: expZone,
+					CapacityGiB:      util.BytesToGiB(stdVolSize),
+				}
+
+				mockCtl := gomock.NewController(t)
+				defer mockCtl.Finish()
+
+				mockCloud := cloud.NewMockCloud(mockCtl)
+				mockCloud.EXPECT().CreateDisk(gomock.Eq(ctx), gomock.Eq(req.GetName()), gomock.Eq(&cloud.DiskOptions{
+					CapacityBytes: stdVolSize,
+					Tags: map[string]string{
+						cloud.VolumeNameTagKey:   req.GetName(),
+						cloud.AwsEbsDriverTagKey: "true",
+					},
+				})).Return(mockDisk, nil)
+
+				awsDriver := ControllerService{
+					cloud:    mockCloud,
+					inFlight: internal.NewInFlight(),
+					options:  &Options{},
+				}
+
+				resp, err := awsDriver.CreateVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if !reflect.DeepEqual(resp.GetVolume().GetVolumeContext(), expVolumeContext) {
+					t.Fatalf("Expected volume context %v, got %v", expVolumeContext, resp.GetVolume().GetVolumeContext())
+				}
+			},
+		},
+		{
+			name: "success with prewarmOnStage passed to volume context of volumes restored from snapshots",
+			testFunc: func(t *testing.T) {
+				t.Helper()
+				snapshotSource := &csi.VolumeContentSource{
+					Type: &csi.VolumeContentSource_Snapshot{
+						Snapshot: &csi.VolumeContentSource_SnapshotSource{
+							SnapshotId: "snapshot-id",
+						},
+					},
+				}
+				testCases := []struct {
+					source     *csi.VolumeContentSource
+					snapshotID string
+					expContext map[string]string
+				}{
+					{source: snapshotSource, snapshotID: "snapshot-id", expContext: map[string]string{PrewarmOnStageKey: trueStr}},
+					{expContext: map[string]string{}},
+				}
+
+				for _, tc := range testCases {
+					req := &csi.CreateVolumeRequest{
+						Name:                "test-vol",
+						CapacityRange:       stdCapRange,
+						VolumeCapabilities:  stdVolCap,
+						Parameters:          map[string]string{"prewarmOnStage": "true"},
+						VolumeContentSource: tc.source,
+					}
+					ctx := t.Context()
+
+					mockCtl := gomock.NewController(t)
+					mockCloud := cloud.NewMockCloud(mockCtl)
+					mockCloud.EXPECT().CreateDisk(gomock.Eq(ctx), gomock.Eq(req.GetName()), gomock.Eq(&cloud.DiskOptions{
+						CapacityBytes: stdVolSize,
+						SnapshotID:    tc.snapshotID,
+						Tags: map[string]string{
+							cloud.VolumeNameTagKey:   req.GetName(),
+							cloud.AwsEbsDriverTagKey: "true",
+						},
+					})).Return(&cloud.Disk{
+						VolumeID:         req.GetName(),
+						AvailabilityZone: expZone,
+						CapacityGiB:      util.BytesToGiB(stdVolSize),
+						SnapshotID:       tc.snapshotID,
+					}, nil)
+
+					awsDriver := ControllerService{
+						cloud:    mockCloud,
+						inFlight: internal.NewInFlight(),
+						options:  &Options{},
+					}
+
+					resp, err := awsDriver.CreateVolume(ctx, req)
+					if err != nil {
+						t.Fatalf("Unexpected error: %v", err)
+					}
+					if !reflect.DeepEqual(resp.GetVolume().GetVolumeContext(), tc.expContext) {
+						t.Fatalf("Expected volume context %v, got %v", tc.expContext, resp.GetVolume().GetVolumeContext())
+					}
+					mockCtl.Finish()
+				}
+			},
+		},
+		{
+			name: "fail with invalid I/O limit",
+			testFunc: func(t *testing.T) {
+				t.Helper()
+				req := &csi.CreateVolumeRequest{
+					Name:               "test-vol",
+					CapacityRange:      stdCapRange,
+					VolumeCapabilities: stdVolCap,
+					Parameters: map[string]string{
+						ReadBandwidthLimitKey: "-1Mi",
+					},
+				}
+
+				mockCtl := gomock.NewController(t)
+				defer mockCtl.Finish()
+
+				awsDriver := ControllerService{
+					cloud:    cloud.NewMockCloud(mockCtl),
+					inFlight: internal.NewInFlight(),
+					options:  &Options{},
+				}
+
+				_, err := awsDriver.CreateVolume(t.Context(), req)
+				checkExpectedErrorCode(t, err, codes.InvalidArgument)
+			},
+		},
 		{
 			name: "fail with invalid volume parameter",
 			testFunc: func(t *testing.T) {
@@ -3004,6 +3149,7 @@ func TestDeleteVolume(t *testing.T) {
 				defer mockCtl.Finish()
 
 				mockCloud := cloud.NewMockCloud(mockCtl)
+				mockCloud.EXPECT().GetDiskByID(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId())).Return(&cloud.Disk{VolumeID: req.GetVolumeId()}, nil)
 				mockCloud.EXPECT().DeleteDisk(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId())).Return(true, nil)
 				awsDriver := ControllerService{
 					cloud:    mockCloud,
@@ -3037,7 +3183,7 @@ func TestDeleteVolume(t *testing.T) {
 				defer mockCtl.Finish()
 
 				mockCloud := cloud.NewMockCloud(mockCtl)
-				mockCloud.EXPECT().DeleteDisk(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId())).Return(false, cloud.ErrNotFound)
+				mockCloud.EXPECT().GetDiskByID(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId())).Return(nil, cloud.ErrNotFound)
 				awsDriver := ControllerService{
 					cloud:    mockCloud,
 					inFlight: internal.NewInFlight(),
@@ -3056,6 +3202,35 @@ func TestDeleteVolume(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "success volume deleted concurrently",
+			testFunc: func(t *testing.T) {
+				t.Helper()
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: "vol-test",
+				}
+
+				ctx := t.Context()
+				mockCtl := gomock.NewController(t)
+				defer mockCtl.Finish()
+
+				mockCloud := cloud.NewMockCloud(mockCtl)
+				mockCloud.EXPECT().GetDiskByID(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId())).Return(&cloud.Disk{VolumeID: req.GetVolumeId()}, nil)
+				mockCloud.EXPECT().DeleteDisk(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId())).Return(false, cloud.ErrNotFound)
+				awsDriver := ControllerService{
+					cloud:    mockCloud,
+					inFlight: internal.NewInFlight(),
+					options:  &Options{},
+				}
+				resp, err := awsDriver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				if !reflect.DeepEqual(resp, &csi.DeleteVolumeResponse{}) {
+					t.Fatalf("Expected resp to be %+v, got: %+v", &csi.DeleteVolumeResponse{}, resp)
+				}
+			},
+		},
 		{
 			name: "fail delete disk",
 			testFunc: func(t *testing.T) {
@@ -3069,6 +3244,7 @@ func TestDeleteVolume(t *testing.T) {
 				defer mockCtl.Finish()
 
 				mockCloud := cloud.NewMockCloud(mockCtl)
+				mockCloud.EXPECT().GetDiskByID(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId())).Return(&cloud.Disk{VolumeID: req.GetVolumeId()}, nil)
 				mockCloud.EXPECT().DeleteDisk(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId())).Return(false, errors.New("DeleteDisk could not delete volume"))
 				awsDriver := ControllerService{
 					cloud:    mockCloud,
@@ -3093,6 +3269,219 @@ func TestDeleteVolume(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "fail deletion protection",
+			testFunc: func(t *testing.T) {
+				t.Helper()
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: "vol-test",
+				}
+
+				ctx := t.Context()
+				mockCtl := gomock.NewController(t)
+				defer mockCtl.Finish()
+
+				mockCloud := cloud.NewMockCloud(mockCtl)
+				mockCloud.EXPECT().GetDiskByID(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId())).Return(&cloud.Disk{
+					VolumeID: req.GetVolumeId(),
+					Tags:     map[string]string{DeletionProtectionTagKey: "true", FinalSnapshotTagKey: "true"},
+				}, nil)
+				awsDriver := ControllerService{
+					cloud:    mockCloud,
+					inFlight: internal.NewInFlight(),
+					options:  &Options{},
+				}
+				_, err := awsDriver.DeleteVolume(ctx, req)
+
+				checkExpectedErrorCode(t, err, codes.FailedPrecondition)
+			},
+		},
+		{
+			name: "success final snapshot",
+			testFunc: func(t *testing.T) {
+				t.Helper()
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: "vol-test",
+				}
+
+				ctx := t.Context()
+				mockCtl := gomock.NewController(t)
+				defer mockCtl.Finish()
+
+				mockCloud := cloud.NewMockCloud(mockCtl)
+				mockCloud.EXPECT().GetDiskByID(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId())).Return(&cloud.Disk{
+					VolumeID: req.GetVolumeId(),
+					Tags: map[string]string{
+						cloud.VolumeNameTagKey:          "pvc-test",
+						PVCNameTag:                      "claim",
+						"team":                          "storage",
+						FinalSnapshotTagKey:             "true",
+						FinalSnapshotLockModeTagKey:     "governance",
+						FinalSnapshotLockDurationTagKey: "7",
+					},
+				}, nil)
+				mockCloud.EXPECT().GetSnapshotByName(gomock.Eq(ctx), "final-snapshot-vol-test").Return(nil, cloud.ErrNotFound)
+				mockCloud.EXPECT().CreateSnapshot(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId()), gomock.Eq(&cloud.SnapshotOptions{
+					Tags: map[string]string{
+						cloud.SnapshotNameTagKey: "final-snapshot-vol-test",
+						cloud.AwsEbsDriverTagKey: isManagedByDriver,
+						PVCNameTag:               "claim",
+						"team":                   "storage",
+					},
+				})).Return(&cloud.Snapshot{SnapshotID: "snap-test", SourceVolumeID: req.GetVolumeId()}, nil)
+				mockCloud.EXPECT().LockSnapshot(gomock.Eq(ctx), gomock.Eq(&cloud.SnapshotLockOptions{
+					SnapshotId:   aws.String("snap-test"),
+					LockMode:     types.LockModeGovernance,
+					LockDuration: aws.Int32(7),
+				})).Return(nil)
+				mockCloud.EXPECT().DeleteDisk(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId())).Return(true, nil)
+				awsDriver := ControllerService{
+					cloud:    mockCloud,
+					inFlight: internal.NewInFlight(),
+					options:  &Options{},
+				}
+				_, err := awsDriver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name: "success final snapshot already locked",
+			testFunc: func(t *testing.T) {
+				t.Helper()
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: "vol-test",
+				}
+
+				ctx := t.Context()
+				mockCtl := gomock.NewController(t)
+				defer mockCtl.Finish()
+
+				mockCloud := cloud.NewMockCloud(mockCtl)
+				mockCloud.EXPECT().GetDiskByID(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId())).Return(&cloud.Disk{
+					VolumeID: req.GetVolumeId(),
+					Tags: map[string]string{
+						FinalSnapshotTagKey:             "true",
+						FinalSnapshotLockModeTagKey:     "governance",
+						FinalSnapshotLockDurationTagKey: "7",
+					},
+				}, nil)
+				// The snapshot was locked by a previous DeleteVolume call, which failed to delete the volume
+				mockCloud.EXPECT().GetSnapshotByName(gomock.Eq(ctx), "final-snapshot-vol-test").Return(&cloud.Snapshot{
+					SnapshotID:     "snap-test",
+					SourceVolumeID: req.GetVolumeId(),
+				}, nil)
+				mockCloud.EXPECT().DeleteDisk(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId())).Return(true, nil)
+				awsDriver := ControllerService{
+					cloud:    mockCloud,
+					inFlight: internal.NewInFlight(),
+					options:  &Options{},
+				}
+				_, err := awsDriver.DeleteVolume(ctx, req)
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+			},
+		},
+		{
+			name: "fail lock final snapshot",
+			testFunc: func(t *testing.T) {
+				t.Helper()
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: "vol-test",
+				}
+
+				ctx := t.Context()
+				mockCtl := gomock.NewController(t)
+				defer mockCtl.Finish()
+
+				mockCloud := cloud.NewMockCloud(mockCtl)
+				mockCloud.EXPECT().GetDiskByID(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId())).Return(&cloud.Disk{
+					VolumeID: req.GetVolumeId(),
+					Tags: map[string]string{
+						FinalSnapshotTagKey:             "true",
+						FinalSnapshotLockModeTagKey:     "governance",
+						FinalSnapshotLockDurationTagKey: "7",
+					},
+				}, nil)
+				mockCloud.EXPECT().GetSnapshotByName(gomock.Eq(ctx), "final-snapshot-vol-test").Return(nil, cloud.ErrNotFound)
+				mockCloud.EXPECT().CreateSnapshot(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId()), gomock.Any()).Return(&cloud.Snapshot{SnapshotID: "snap-test", SourceVolumeID: req.GetVolumeId()}, nil)
+				mockCloud.EXPECT().LockSnapshot(gomock.Eq(ctx), gomock.Any()).Return(errors.New("UnauthorizedOperation"))
+				// The unlocked snapshot is deleted, to take a locked one when DeleteVolume is retried
+				mockCloud.EXPECT().DeleteSnapshot(gomock.Eq(ctx), gomock.Eq("snap-test")).Return(true, nil)
+				awsDriver := ControllerService{
+					cloud:    mockCloud,
+					inFlight: internal.NewInFlight(),
+					options:  &Options{},
+				}
+				_, err := awsDriver.DeleteVolume(ctx, req)
+
+				checkExpectedErrorCode(t, err, codes.Internal)
+			},
+		},
+		{
+			name: "fail final snapshot in error state",
+			testFunc: func(t *testing.T) {
+				t.Helper()
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: "vol-test",
+				}
+
+				ctx := t.Context()
+				mockCtl := gomock.NewController(t)
+				defer mockCtl.Finish()
+
+				mockCloud := cloud.NewMockCloud(mockCtl)
+				mockCloud.EXPECT().GetDiskByID(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId())).Return(&cloud.Disk{
+					VolumeID: req.GetVolumeId(),
+					Tags:     map[string]string{FinalSnapshotTagKey: "true"},
+				}, nil)
+				mockCloud.EXPECT().GetSnapshotByName(gomock.Eq(ctx), "final-snapshot-vol-test").Return(&cloud.Snapshot{
+					SnapshotID:     "snap-test",
+					SourceVolumeID: req.GetVolumeId(),
+					Failed:         true,
+				}, nil)
+				mockCloud.EXPECT().DeleteSnapshot(gomock.Eq(ctx), gomock.Eq("snap-test")).Return(true, nil)
+				awsDriver := ControllerService{
+					cloud:    mockCloud,
+					inFlight: internal.NewInFlight(),
+					options:  &Options{},
+				}
+				_, err := awsDriver.DeleteVolume(ctx, req)
+
+				checkExpectedErrorCode(t, err, codes.Internal)
+			},
+		},
+		{
+			name: "fail final snapshot",
+			testFunc: func(t *testing.T) {
+				t.Helper()
+				req := &csi.DeleteVolumeRequest{
+					VolumeId: "vol-test",
+				}
+
+				ctx := t.Context()
+				mockCtl := gomock.NewController(t)
+				defer mockCtl.Finish()
+
+				mockCloud := cloud.NewMockCloud(mockCtl)
+				mockCloud.EXPECT().GetDiskByID(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId())).Return(&cloud.Disk{
+					VolumeID: req.GetVolumeId(),
+					Tags:     map[string]string{FinalSnapshotTagKey: "true"},
+				}, nil)
+				mockCloud.EXPECT().GetSnapshotByName(gomock.Eq(ctx), "final-snapshot-vol-test").Return(nil, cloud.ErrNotFound)
+				mockCloud.EXPECT().CreateSnapshot(gomock.Eq(ctx), gomock.Eq(req.GetVolumeId()), gomock.Any()).Return(nil, cloud.ErrLimitExceeded)
+				awsDriver := ControllerService{
+					cloud:    mockCloud,
+					inFlight: internal.NewInFlight(),
+					options:  &Options{},
+				}
+				_, err := awsDriver.DeleteVolume(ctx, req)
+
+				checkExpectedErrorCode(t, err, codes.Internal)
+			},
+		},
 		{
 			name: "fail another request already in-flight",
 			testFunc: func(t *testing.T) {
@@ -5360,6 +5749,29 @@ func TestControllerPublishVolume(t *testing.T) {
 				ControllerService.options.EnableNodeLocalVolumes = true
 			},
 		},
+		{
+			name:             "Success with instance store node-local volume",
+			volumeID:         InstanceStoreVolumeHandlePrefix + "raid0",
+			nodeID:           expInstanceID,
+			volumeCapability: stdVolCap,
+			expResp: &csi.ControllerPublishVolumeResponse{
+				PublishContext: map[string]string{DevicePathKey: "instance-store/raid0"},
+			},
+			errorCode: codes.OK,
+			setupFunc: func(ControllerService *ControllerService) {
+				ControllerService.options.EnableNodeLocalVolumes = true
+			},
+		},
+		{
+			name:             "Fail with instance store node-local volume invalid selector",
+			volumeID:         InstanceStoreVolumeHandlePrefix + "first",
+			nodeID:           expInstanceID,
+			volumeCapability: stdVolCap,
+			errorCode:        codes.InvalidArgument,
+			setupFunc: func(ControllerService *ControllerService) {
+				ControllerService.options.EnableNodeLocalVolumes = true
+			},
+		},
 		{
 			name:             "Fail with node-local volume when feature disabled",
 			volumeID:         NodeLocalVolumeHandlePrefix + "dev/xvdf",
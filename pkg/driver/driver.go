@@ -18,17 +18,20 @@ package driver
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 
 	"github.com/awslabs/volume-modifier-for-k8s/pkg/rpc"
 	csi "github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/kubernetes-csi/csi-lib-utils/leaderelection"
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud"
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud/metadata"
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/mounter"
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 )
@@ -64,6 +67,25 @@ var (
 	AwsAccountIDKey           string
 	AwsRegionKey              string
 	AwsOutpostIDKey           string
+
+	// Tags of the EBS volumes backing ephemeral inline volumes, identifying the pod and node they belong to.
+	EphemeralPodUIDTagKey       string
+	EphemeralPodNamespaceTagKey string
+	EphemeralPodNameTagKey      string
+	EphemeralNodeNameTagKey     string
+
+	// Tags of the EBS volumes created by volume migrations, identifying the migrated volume and its PersistentVolume,
+	// and the time the volume replaced the migrated volume.
+	MigratedFromVolumeTagKey       string
+	MigratedPersistentVolumeTagKey string
+	MigrationCompletedTagKey       string
+
+	// Tags of the EBS volumes protected from deletion, set from the parameters of their StorageClass.
+	FinalSnapshotTagKey             string
+	FinalSnapshotLockModeTagKey     string
+	FinalSnapshotLockDurationTagKey string
+	DeletionProtectionTagKey        string
+
 	// Deprecated: Use the WellKnownZoneTopologyKey instead.
 	ZoneTopologyKey string
 )
@@ -71,6 +93,8 @@ var (
 type Driver struct {
 	controller *ControllerService
 	node       *NodeService
+	ephemeral  *ephemeralVolumeServer
+	pvcTagSync *pvcTagSyncer
 	srv        *grpc.Server
 	options    *Options
 	csi.UnimplementedIdentityServer
@@ -86,6 +110,17 @@ func initVariables() {
 	// Deprecated: Use the WellKnownZoneTopologyKey instead.
 	ZoneTopologyKey = "topology." + util.GetDriverName() + "/zone"
 	AgentNotReadyNodeTaintKey = util.GetDriverName() + "/agent-not-ready"
+	EphemeralPodUIDTagKey = util.GetDriverName() + "/ephemeral-pod-uid"
+	EphemeralPodNamespaceTagKey = util.GetDriverName() + "/ephemeral-pod-namespace"
+	EphemeralPodNameTagKey = util.GetDriverName() + "/ephemeral-pod-name"
+	EphemeralNodeNameTagKey = util.GetDriverName() + "/ephemeral-node-name"
+	MigratedFromVolumeTagKey = util.GetDriverName() + "/migrated-from-volume"
+	MigratedPersistentVolumeTagKey = util.GetDriverName() + "/migrated-pv"
+	MigrationCompletedTagKey = util.GetDriverName() + "/migration-completed"
+	FinalSnapshotTagKey = util.GetDriverName() + "/final-snapshot"
+	FinalSnapshotLockModeTagKey = util.GetDriverName() + "/final-snapshot-lock-mode"
+	FinalSnapshotLockDurationTagKey = util.GetDriverName() + "/final-snapshot-lock-duration"
+	DeletionProtectionTagKey = util.GetDriverName() + "/deletion-protection"
 }
 
 func NewDriver(c cloud.Cloud, o *Options, m mounter.Mounter, md metadata.MetadataService, k kubernetes.Interface) (*Driver, error) {
@@ -102,11 +137,11 @@ func NewDriver(c cloud.Cloud, o *Options, m mounter.Mounter, md metadata.Metadat
 
 	switch o.Mode {
 	case ControllerMode:
-		driver.controller = NewControllerService(c, o)
+		driver.controller = NewControllerService(c, o, k)
 	case NodeMode:
 		driver.node = NewNodeService(o, md, m, k)
 	case AllMode:
-		driver.controller = NewControllerService(c, o)
+		driver.controller = NewControllerService(c, o, k)
 		driver.node = NewNodeService(o, md, m, k)
 	case MetadataLabelerMode:
 		return nil, fmt.Errorf("mode %s is not handled by the driver, it is handled separately in main", o.Mode)
@@ -114,6 +149,29 @@ func NewDriver(c cloud.Cloud, o *Options, m mounter.Mounter, md metadata.Metadat
 		return nil, fmt.Errorf("unknown mode: %s", o.Mode)
 	}
 
+	if driver.controller != nil && o.EphemeralVolumesListenAddress != "" {
+		if k == nil {
+			return nil, errors.New("a Kubernetes client is required to serve the ephemeral volume API")
+		}
+		driver.ephemeral = newEphemeralVolumeServer(c, o, k)
+	}
+	if driver.controller != nil && o.EnableVolumeMigration && k == nil {
+		return nil, errors.New("a Kubernetes client is required to migrate volumes")
+	}
+	if driver.controller != nil && (len(o.PVCTagSyncLabels) > 0 || len(o.PVCTagSyncAnnotations) > 0) {
+		if k == nil {
+			return nil, errors.New("a Kubernetes client is required to sync the tags of volumes with their PVC")
+		}
+		driver.pvcTagSync = newPVCTagSyncer(c, o, driver.controller.informerFactory)
+	}
+	if driver.node != nil && o.EphemeralVolumesEndpoint != "" {
+		client, err := newEphemeralVolumeClient(o.EphemeralVolumesEndpoint, o.EphemeralVolumesCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ephemeral volume API client: %w", err)
+		}
+		driver.node.ephemeral = client
+	}
+
 	return driver, nil
 }
 
@@ -129,16 +187,8 @@ func (d *Driver) Run() error {
 		return err
 	}
 
-	logErr := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
-		resp, err := handler(ctx, req)
-		if err != nil {
-			klog.ErrorS(err, "GRPC error")
-		}
-		return resp, err
-	}
-
 	opts := []grpc.ServerOption{
-		grpc.UnaryInterceptor(logErr),
+		grpc.ChainUnaryInterceptor(unaryInterceptors()...),
 	}
 
 	if d.options.EnableOtelTracing {
@@ -164,10 +214,58 @@ func (d *Driver) Run() error {
 		return fmt.Errorf("unknown mode: %s", d.options.Mode)
 	}
 
+	if d.controller != nil && d.controller.eventObjects != nil {
+		d.controller.eventObjects.start()
+	}
+	if d.ephemeral != nil {
+		if err := d.ephemeral.Start(context.Background()); err != nil {
+			return err
+		}
+	}
+
+	if tasks := d.leaderTasks(); len(tasks) > 0 {
+		go d.runLeaderTasks(tasks)
+	}
+	if d.node != nil {
+		d.node.startMountReconciler()
+	}
+
 	klog.V(4).InfoS("Listening for connections", "address", listener.Addr())
 	return d.srv.Serve(listener)
 }
 
+// leaderTasks returns the background tasks of the controller that must only run in one of its replicas.
+func (d *Driver) leaderTasks() []func(ctx context.Context) {
+	var tasks []func(ctx context.Context)
+	if d.pvcTagSync != nil {
+		tasks = append(tasks, func(ctx context.Context) {
+			if err := d.pvcTagSync.Start(ctx); err != nil {
+				klog.ErrorS(err, "Could not start PVC tag sync")
+			}
+		})
+	}
+	if d.controller != nil && d.options.EnableVolumeMigration {
+		tasks = append(tasks, func(ctx context.Context) {
+			wait.UntilWithContext(ctx, d.controller.deleteMigratedVolumes, volumeMigrationGCInterval)
+		})
+	}
+	return tasks
+}
+
+// runLeaderTasks uses leader election so that only one controller pod runs tasks.
+func (d *Driver) runLeaderTasks(tasks []func(ctx context.Context)) {
+	lockName := "controller-tasks-" + util.GetDriverName()
+	le := leaderelection.NewLeaderElection(d.controller.k8sClient, lockName, func(ctx context.Context) {
+		klog.InfoS("Became the leader of the controller background tasks")
+		for _, task := range tasks {
+			go task(ctx)
+		}
+	})
+	if err := le.Run(); err != nil {
+		klog.ErrorS(err, "Could not run leader election of the controller background tasks")
+	}
+}
+
 func (d *Driver) Stop() {
 	d.srv.Stop()
 }
@@ -30,6 +30,7 @@ func TestNewDriver(t *testing.T) {
 	defer ctrl.Finish()
 	mockCloud := cloud.NewMockCloud(ctrl)
 	mockMetadataService := metadata.NewMockMetadataService(ctrl)
+	mockMetadataService.EXPECT().IsCached().Return(false).AnyTimes()
 	mockMounter := mounter.NewMockMounter(ctrl)
 
 	fakeClient := fake.NewClientset()
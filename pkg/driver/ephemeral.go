@@ -0,0 +1,251 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// The ephemeral volume API is served by the controller to the node plugins, so that EBS volumes
+// of ephemeral inline volumes are created, attached, detached and deleted with the credentials
+// of the controller. Node plugins authenticate with a service account token bound to their pod.
+const (
+	// EphemeralAPIAudience is the audience of the service account tokens node plugins authenticate with.
+	EphemeralAPIAudience = "ebs.csi.aws.com/ephemeral-volumes"
+	// EphemeralAPITokenPath is where the node plugin reads its projected service account token from.
+	EphemeralAPITokenPath = "/var/run/secrets/ebs.csi.aws.com/ephemeral-volumes/token"
+
+	ephemeralAPIPublishPath   = "/v1/ephemeral-volumes/publish"
+	ephemeralAPIUnpublishPath = "/v1/ephemeral-volumes/unpublish"
+	ephemeralAPITimeout       = 5 * time.Minute
+)
+
+var errEphemeralVolumesDisabled = errors.New("ephemeral inline volumes are not enabled on this node")
+
+// ephemeralVolumeRequest identifies an ephemeral inline volume of a pod. The parameters of the
+// volume are read by the controller from the pod spec, not trusted from the node.
+type ephemeralVolumeRequest struct {
+	VolumeHandle string `json:"volumeHandle"`
+	PodNamespace string `json:"podNamespace,omitempty"`
+	PodName      string `json:"podName,omitempty"`
+	PodUID       string `json:"podUID,omitempty"`
+}
+
+type ephemeralPublishResponse struct {
+	VolumeID   string `json:"volumeID"`
+	DevicePath string `json:"devicePath"`
+}
+
+// ephemeralAPIError is the body of non-200 responses, carrying the gRPC code to return to kubelet.
+type ephemeralAPIError struct {
+	Code    codes.Code `json:"code"`
+	Message string     `json:"message"`
+}
+
+// ephemeralVolumeAPI is implemented by ephemeralVolumeClient.
+type ephemeralVolumeAPI interface {
+	Publish(ctx context.Context, req *ephemeralVolumeRequest) (*ephemeralPublishResponse, error)
+	Unpublish(ctx context.Context, req *ephemeralVolumeRequest) error
+}
+
+// ephemeralVolumeOptions are the parameters of an ephemeral inline volume.
+type ephemeralVolumeOptions struct {
+	CapacityBytes int64
+	VolumeType    string
+	IOPS          int32
+	Throughput    int32
+	Encrypted     bool
+	KmsKeyID      string
+}
+
+// isEphemeralVolume returns true if volumeContext is the one of a CSI ephemeral inline volume.
+func isEphemeralVolume(volumeContext map[string]string) bool {
+	return volumeContext[EphemeralKey] == trueStr
+}
+
+// isEphemeralVolumeHandle returns true if volumeID was generated by kubelet for an ephemeral inline volume.
+func isEphemeralVolumeHandle(volumeID string) bool {
+	return strings.HasPrefix(volumeID, EphemeralVolumeHandlePrefix)
+}
+
+// parseEphemeralVolumeAttributes parses the volumeAttributes of an ephemeral inline volume.
+// Keys are case insensitive, like the ones of StorageClass parameters.
+func parseEphemeralVolumeAttributes(attributes map[string]string) (*ephemeralVolumeOptions, error) {
+	opts := &ephemeralVolumeOptions{}
+	for key, value := range attributes {
+		var err error
+		switch strings.ToLower(key) {
+		case EphemeralSizeKey:
+			var q resource.Quantity
+			if q, err = resource.ParseQuantity(value); err == nil {
+				opts.CapacityBytes = q.Value()
+			}
+		case VolumeTypeKey:
+			opts.VolumeType = value
+		case IopsKey:
+			var iops int64
+			iops, err = strconv.ParseInt(value, 10, 32)
+			opts.IOPS = int32(iops)
+		case ThroughputKey:
+			var throughput int64
+			throughput, err = strconv.ParseInt(value, 10, 32)
+			opts.Throughput = int32(throughput)
+		case EncryptedKey:
+			opts.Encrypted, err = strconv.ParseBool(value)
+		case KmsKeyIDKey:
+			opts.KmsKeyID = value
+		default:
+			// Keys added by kubelet, such as the pod information, are not volume attributes
+			if strings.HasPrefix(key, "csi.storage.k8s.io/") {
+				continue
+			}
+			return nil, fmt.Errorf("invalid volume attribute %q", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not parse %s %q: %w", key, value, err)
+		}
+	}
+	if opts.CapacityBytes <= 0 {
+		return nil, fmt.Errorf("volume attribute %q must be a positive quantity", EphemeralSizeKey)
+	}
+	return opts, nil
+}
+
+// ephemeralVolumeClient calls the ephemeral volume API of the controller.
+type ephemeralVolumeClient struct {
+	endpoint   string
+	tokenPath  string
+	httpClient *http.Client
+}
+
+// newEphemeralVolumeClient returns a client of the ephemeral volume API at endpoint.
+// If caFile is non-empty, the certificate of the API is verified against it.
+func newEphemeralVolumeClient(endpoint, caFile string) (*ephemeralVolumeClient, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if caFile != "" {
+		ca, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificate found in CA file %s", caFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return &ephemeralVolumeClient{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		tokenPath:  EphemeralAPITokenPath,
+		httpClient: &http.Client{Transport: transport, Timeout: ephemeralAPITimeout},
+	}, nil
+}
+
+// Publish creates the EBS volume of an ephemeral inline volume if needed and attaches it to the node.
+func (c *ephemeralVolumeClient) Publish(ctx context.Context, req *ephemeralVolumeRequest) (*ephemeralPublishResponse, error) {
+	resp := &ephemeralPublishResponse{}
+	if err := c.call(ctx, ephemeralAPIPublishPath, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Unpublish detaches and deletes the EBS volume of an ephemeral inline volume.
+func (c *ephemeralVolumeClient) Unpublish(ctx context.Context, req *ephemeralVolumeRequest) error {
+	return c.call(ctx, ephemeralAPIUnpublishPath, req, nil)
+}
+
+// call sends in to path and decodes the response into out. Errors of the API are returned
+// as gRPC status errors with the code set by the controller.
+func (c *ephemeralVolumeClient) call(ctx context.Context, path string, in, out any) error {
+	// The projected token is rotated by kubelet, so it is read on each call
+	token, err := os.ReadFile(c.tokenPath)
+	if err != nil {
+		return status.Errorf(codes.Internal, "Could not read service account token: %v", err)
+	}
+	body, err := json.Marshal(in)
+	if err != nil {
+		return status.Errorf(codes.Internal, "Could not encode request: %v", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return status.Errorf(codes.Internal, "Could not create request: %v", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "Could not reach the ephemeral volume API: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &ephemeralAPIError{}
+		if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil || apiErr.Code == codes.OK {
+			return status.Errorf(codes.Internal, "Ephemeral volume API returned %s", resp.Status)
+		}
+		return status.Error(apiErr.Code, apiErr.Message)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return status.Errorf(codes.Internal, "Could not decode response: %v", err)
+	}
+	return nil
+}
+
+// ephemeralAPIStatusCode returns the HTTP status code of err, an error of the ephemeral volume API.
+func ephemeralAPIStatusCode(err error) (int, *ephemeralAPIError) {
+	st, ok := status.FromError(err)
+	if !ok {
+		st = status.New(codes.Internal, err.Error())
+	}
+	apiErr := &ephemeralAPIError{Code: st.Code(), Message: st.Message()}
+	switch st.Code() {
+	case codes.InvalidArgument, codes.FailedPrecondition:
+		return http.StatusBadRequest, apiErr
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized, apiErr
+	case codes.PermissionDenied:
+		return http.StatusForbidden, apiErr
+	case codes.NotFound:
+		return http.StatusNotFound, apiErr
+	case codes.Aborted:
+		return http.StatusConflict, apiErr
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests, apiErr
+	default:
+		return http.StatusInternalServerError, apiErr
+	}
+}
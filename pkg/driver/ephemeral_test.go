@@ -0,0 +1,92 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEphemeralVolumeAttributes(t *testing.T) {
+	testCases := []struct {
+		name        string
+		attributes  map[string]string
+		expected    *ephemeralVolumeOptions
+		expectedErr bool
+	}{
+		{
+			name: "supported attributes",
+			attributes: map[string]string{
+				"size":          "10Gi",
+				"type":          "io2",
+				"IOPS":          "3000",
+				"throughput":    "250",
+				"encrypted":     "true",
+				"kmsKeyId":      "arn:aws:kms:us-east-1:123456789012:key/abcd",
+				PodNameKey:      "pod",
+				PodNamespaceKey: "default",
+				EphemeralKey:    trueStr,
+			},
+			expected: &ephemeralVolumeOptions{
+				CapacityBytes: 10 * 1024 * 1024 * 1024,
+				VolumeType:    "io2",
+				IOPS:          3000,
+				Throughput:    250,
+				Encrypted:     true,
+				KmsKeyID:      "arn:aws:kms:us-east-1:123456789012:key/abcd",
+			},
+		},
+		{
+			name:        "unknown attribute",
+			attributes:  map[string]string{"size": "1Gi", "iopsPerGB": "10"},
+			expectedErr: true,
+		},
+		{
+			name:        "size missing",
+			attributes:  map[string]string{"type": "gp3"},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid size",
+			attributes:  map[string]string{"size": "ten"},
+			expectedErr: true,
+		},
+		{
+			name:        "invalid iops",
+			attributes:  map[string]string{"size": "1Gi", "iops": "many"},
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := parseEphemeralVolumeAttributes(tc.attributes)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, opts)
+		})
+	}
+}
+
+func TestIsEphemeralVolumeHandle(t *testing.T) {
+	assert.True(t, isEphemeralVolumeHandle(makeEphemeralVolumeHandle("pod-uid", "scratch")))
+	assert.False(t, isEphemeralVolumeHandle("vol-0123456789abcdef0"))
+}
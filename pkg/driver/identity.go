@@ -27,7 +27,7 @@ import (
 )
 
 func (d *Driver) GetPluginInfo(ctx context.Context, req *csi.GetPluginInfoRequest) (*csi.GetPluginInfoResponse, error) {
-	klog.V(6).InfoS("GetPluginInfo: called", "args", req)
+	klog.FromContext(ctx).V(6).Info("GetPluginInfo: called", "args", req)
 	resp := &csi.GetPluginInfoResponse{
 		Name:          util.GetDriverName(),
 		VendorVersion: driverVersion,
@@ -37,7 +37,7 @@ func (d *Driver) GetPluginInfo(ctx context.Context, req *csi.GetPluginInfoReques
 }
 
 func (d *Driver) GetPluginCapabilities(ctx context.Context, req *csi.GetPluginCapabilitiesRequest) (*csi.GetPluginCapabilitiesResponse, error) {
-	klog.V(6).InfoS("GetPluginCapabilities: called", "args", req)
+	klog.FromContext(ctx).V(6).Info("GetPluginCapabilities: called", "args", req)
 	resp := &csi.GetPluginCapabilitiesResponse{
 		Capabilities: []*csi.PluginCapability{
 			{
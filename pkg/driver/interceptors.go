@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/metrics"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/klog/v2"
+)
+
+// grpcRequestDurationBuckets covers CSI requests from a few milliseconds up to the longest
+// timeouts of the CSI sidecars.
+var grpcRequestDurationBuckets = []float64{0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600}
+
+// unaryInterceptors returns the interceptors of the CSI requests served by the driver, in order.
+func unaryInterceptors() []grpc.UnaryServerInterceptor {
+	return []grpc.UnaryServerInterceptor{
+		requestIDInterceptor,
+		metricsInterceptor,
+		logErrInterceptor,
+	}
+}
+
+// requestIDInterceptor assigns an ID to each request, which is added to the contextual logger of the
+// request and to the logs of the EC2 API calls made for the request.
+func requestIDInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	requestID := string(uuid.NewUUID())
+	ctx = util.WithRequestID(ctx, requestID)
+	ctx = klog.NewContext(ctx, klog.FromContext(ctx).WithValues("requestID", requestID))
+	return handler(ctx, req)
+}
+
+// metricsInterceptor records the duration and the status code of each request.
+func metricsInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	duration := time.Since(start)
+
+	method := path.Base(info.FullMethod)
+	code := status.Code(err).String()
+	metrics.Recorder().ObserveHistogram(metrics.GRPCRequestDuration, metrics.GRPCRequestDurationHelpText, duration.Seconds(),
+		map[string]string{"method": method}, grpcRequestDurationBuckets)
+	metrics.Recorder().IncreaseCount(metrics.GRPCRequests, metrics.GRPCRequestsHelpText, map[string]string{"method": method, "code": code})
+	klog.FromContext(ctx).V(4).Info("GRPC request completed", "method", info.FullMethod, "code", code, "duration", duration)
+	return resp, err
+}
+
+// logErrInterceptor logs the errors returned by requests.
+func logErrInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "GRPC error")
+	}
+	return resp, err
+}
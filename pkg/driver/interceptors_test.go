@@ -0,0 +1,86 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/metrics"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryInterceptors(t *testing.T) {
+	_, registry := metrics.InitializeRecorder(false)
+	info := &grpc.UnaryServerInfo{FullMethod: "/csi.v1.Controller/CreateVolume"}
+	interceptors := unaryInterceptors()
+
+	// call runs handler through the interceptors, in the order grpc.ChainUnaryInterceptor runs them
+	call := func(handler grpc.UnaryHandler) (any, error) {
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, next := interceptors[i], handler
+			handler = func(ctx context.Context, req any) (any, error) {
+				return interceptor(ctx, req, info, next)
+			}
+		}
+		return handler(context.Background(), "request")
+	}
+
+	var requestIDs []string
+	handler := func(ctx context.Context, req any) (any, error) {
+		requestIDs = append(requestIDs, util.RequestID(ctx))
+		return "response", nil
+	}
+	resp, err := call(handler)
+	require.NoError(t, err)
+	assert.Equal(t, "response", resp)
+	_, err = call(handler)
+	require.NoError(t, err)
+
+	require.Len(t, requestIDs, 2)
+	assert.NotEmpty(t, requestIDs[0])
+	assert.NotEqual(t, requestIDs[0], requestIDs[1])
+
+	_, err = call(func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.NotFound, "volume not found")
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	expected := `
+# HELP aws_ebs_csi_grpc_requests_total ` + metrics.GRPCRequestsHelpText + `
+# TYPE aws_ebs_csi_grpc_requests_total counter
+aws_ebs_csi_grpc_requests_total{code="NotFound",method="CreateVolume"} 1
+aws_ebs_csi_grpc_requests_total{code="OK",method="CreateVolume"} 2
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), metrics.GRPCRequests))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	idx := slices.IndexFunc(families, func(f *dto.MetricFamily) bool { return f.GetName() == metrics.GRPCRequestDuration })
+	require.NotEqual(t, -1, idx, "metric %s not found", metrics.GRPCRequestDuration)
+	require.Len(t, families[idx].GetMetric(), 1)
+	assert.Equal(t, uint64(3), families[idx].GetMetric()[0].GetHistogram().GetSampleCount())
+}
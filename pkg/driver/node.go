@@ -19,12 +19,14 @@ package driver
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"maps"
 	"os"
 	"path/filepath"
 	"runtime"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -40,12 +42,16 @@ import (
 	"google.golang.org/grpc/status"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 )
 
@@ -78,6 +84,8 @@ var (
 const (
 	// taintWatcherDuration is the maximum duration for the not-ready taint watcher to run.
 	taintWatcherDuration = 10 * time.Minute
+
+	eventReasonVolumeDeviceNotFound = "VolumeDeviceNotFound"
 )
 
 // NodeService represents the node service of CSI driver.
@@ -86,6 +94,14 @@ type NodeService struct {
 	mounter  mounter.Mounter
 	inFlight *internal.InFlight
 	options  *Options
+	// recorder emits events about volumes on this node, nil if there is no Kubernetes client.
+	recorder record.EventRecorder
+	// ephemeral calls the ephemeral volume API of the controller, nil if ephemeral inline volumes are disabled.
+	ephemeral ephemeralVolumeAPI
+	// formats runs the formats of large volumes in the background, nil if they are formatted synchronously.
+	formats *formatJobs
+	// prewarms holds the running pre-warms of volumes, nil if pre-warming is not supported.
+	prewarms *prewarmJobs
 	csi.UnimplementedNodeServer
 }
 
@@ -97,16 +113,58 @@ func NewNodeService(o *Options, md metadata.MetadataService, m mounter.Mounter,
 		go startNotReadyTaintWatcher(k, taintWatcherDuration)
 	}
 
-	return &NodeService{
+	d := &NodeService{
 		metadata: md,
 		mounter:  m,
 		inFlight: internal.NewInFlight(),
 		options:  o,
 	}
+	if k != nil {
+		d.recorder = newNodeEventRecorder(k)
+	}
+	if runtime.GOOS == "linux" {
+		d.prewarms = newPrewarmJobs()
+	}
+	if o.AsyncFormatMinSizeGiB > 0 {
+		if runtime.GOOS != "linux" {
+			klog.InfoS("Background formatting is only supported on Linux, formatting synchronously")
+		} else {
+			d.formats = newFormatJobs(m, o.MaxConcurrentFormats)
+		}
+	}
+
+	// The condition is reconciled even without cached metadata, to clear the condition left by a
+	// previous node plugin
+	if k != nil {
+		go d.refreshCachedMetadata(k, cachedMetadataRefreshInterval)
+	}
+
+	return d
+}
+
+// newNodeEventRecorder returns an EventRecorder that emits events on behalf of the node plugin.
+func newNodeEventRecorder(k kubernetes.Interface) record.EventRecorder {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: k.CoreV1().Events("")})
+	return broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{
+		Component: util.GetDriverName() + "-node",
+		Host:      os.Getenv("CSI_NODE_NAME"),
+	})
+}
+
+// recordNodeEvent emits an event on the Node object of this node.
+func (d *NodeService) recordNodeEvent(eventType, reason, message string) {
+	if d.recorder == nil {
+		return
+	}
+	// Events of nodes use the node name as UID
+	nodeName := os.Getenv("CSI_NODE_NAME")
+	ref := &corev1.ObjectReference{Kind: "Node", Name: nodeName, UID: k8stypes.UID(nodeName)}
+	d.recorder.Event(ref, eventType, reason, message)
 }
 
 func (d *NodeService) NodeStageVolume(ctx context.Context, req *csi.NodeStageVolumeRequest) (*csi.NodeStageVolumeResponse, error) {
-	klog.V(4).InfoS("NodeStageVolume: called", "args", util.SanitizeRequest(req))
+	klog.FromContext(ctx).V(4).Info("NodeStageVolume: called", "args", util.SanitizeRequest(req))
 
 	volumeID := req.GetVolumeId()
 	if len(volumeID) == 0 {
@@ -194,7 +252,7 @@ func (d *NodeService) NodeStageVolume(ctx context.Context, req *csi.NodeStageVol
 		return nil, status.Errorf(codes.Aborted, VolumeOperationAlreadyExists, volumeID)
 	}
 	defer func() {
-		klog.V(4).InfoS("NodeStageVolume: volume operation finished", "volumeID", volumeID)
+		klog.FromContext(ctx).V(4).Info("NodeStageVolume: volume operation finished", "volumeID", volumeID)
 		d.inFlight.Delete(volumeID)
 	}()
 
@@ -208,7 +266,7 @@ func (d *NodeService) NodeStageVolume(ctx context.Context, req *csi.NodeStageVol
 		if part != "0" {
 			partition = part
 		} else {
-			klog.InfoS("NodeStageVolume: invalid partition config, will ignore.", "partition", part)
+			klog.FromContext(ctx).Info("NodeStageVolume: invalid partition config, will ignore.", "partition", part)
 		}
 	}
 
@@ -219,12 +277,14 @@ func (d *NodeService) NodeStageVolume(ctx context.Context, req *csi.NodeStageVol
 		}
 	}
 
-	source, err := d.mounter.FindDevicePath(devicePath, effectiveVolumeID, partition, d.metadata.GetRegion())
+	source, err := d.findDevicePath(ctx, volumeID, devicePath, effectiveVolumeID, partition)
 	if err != nil {
+		d.recordVolumeEvent(stagingPVName(target), corev1.EventTypeWarning, eventReasonVolumeDeviceNotFound,
+			fmt.Sprintf("Volume %s is attached as %s, but its device was not found on the node: %v", volumeID, devicePath, err))
 		return nil, status.Errorf(codes.NotFound, "Failed to find device path %s. %v", devicePath, err)
 	}
 
-	klog.V(4).InfoS("NodeStageVolume: find device path", "devicePath", devicePath, "source", source)
+	klog.FromContext(ctx).V(4).Info("NodeStageVolume: find device path", "devicePath", devicePath, "source", source)
 	exists, err := d.mounter.PathExists(target)
 	if err != nil {
 		msg := fmt.Sprintf("failed to check if target %q exists: %v", target, err)
@@ -235,7 +295,7 @@ func (d *NodeService) NodeStageVolume(ctx context.Context, req *csi.NodeStageVol
 	// Otherwise we need to create the target directory.
 	if !exists {
 		// If target path does not exist we need to create the directory where volume will be staged
-		klog.V(4).InfoS("NodeStageVolume: creating target dir", "target", target)
+		klog.FromContext(ctx).V(4).Info("NodeStageVolume: creating target dir", "target", target)
 		if err = d.mounter.MakeDir(target); err != nil {
 			msg := fmt.Sprintf("could not create target dir %q: %v", target, err)
 			return nil, status.Error(codes.Internal, msg)
@@ -252,14 +312,14 @@ func (d *NodeService) NodeStageVolume(ctx context.Context, req *csi.NodeStageVol
 	// This operation (NodeStageVolume) MUST be idempotent.
 	// If the volume corresponding to the volume_id is already staged to the staging_target_path,
 	// and is identical to the specified volume_capability the Plugin MUST reply 0 OK.
-	klog.V(4).InfoS("NodeStageVolume: checking if volume is already staged", "device", device, "source", source, "target", target)
+	klog.FromContext(ctx).V(4).Info("NodeStageVolume: checking if volume is already staged", "device", device, "source", source, "target", target)
 	if device == source {
-		klog.V(4).InfoS("NodeStageVolume: volume already staged", "volumeID", volumeID)
+		klog.FromContext(ctx).V(4).Info("NodeStageVolume: volume already staged", "volumeID", volumeID)
 		return &csi.NodeStageVolumeResponse{}, nil
 	}
 
 	// FormatAndMount will format only if needed
-	klog.V(4).InfoS("NodeStageVolume: staging volume", "source", source, "volumeID", volumeID, "target", target, "fstype", fsType)
+	klog.FromContext(ctx).V(4).Info("NodeStageVolume: staging volume", "source", source, "volumeID", volumeID, "target", target, "fstype", fsType)
 	formatOptions := []string{}
 	if len(blockSize) > 0 {
 		if fsType == FSTypeXfs {
@@ -292,6 +352,12 @@ func (d *NodeService) NodeStageVolume(ctx context.Context, req *csi.NodeStageVol
 	if fsType == FSTypeXfs && d.options.LegacyXFSProgs {
 		formatOptions = append(formatOptions, "-m", "bigtime=0,inobtcount=0,reflink=0", "-i", "nrext64=0")
 	}
+	if d.formats != nil {
+		// Once the background format is done, FormatAndMount only mounts the volume
+		if err = d.formats.formatInBackground(volumeID, source, fsType, formatOptions, d.options.AsyncFormatMinSizeGiB*util.GiB); err != nil {
+			return nil, err
+		}
+	}
 	err = d.mounter.FormatAndMountSensitiveWithFormatOptions(source, target, fsType, mountOptions, nil, formatOptions)
 	if err != nil {
 		msg := fmt.Sprintf("could not format %q and mount it at %q: %v", source, target, err)
@@ -304,17 +370,20 @@ func (d *NodeService) NodeStageVolume(ctx context.Context, req *csi.NodeStageVol
 	}
 
 	if needResize {
-		klog.V(2).InfoS("Volume needs resizing", "source", source)
+		klog.FromContext(ctx).V(2).Info("Volume needs resizing", "source", source)
 		if _, err := d.mounter.Resize(source, target); err != nil {
 			return nil, status.Errorf(codes.Internal, "Could not resize volume %q (%q):  %v", volumeID, source, err)
 		}
 	}
-	klog.V(4).InfoS("NodeStageVolume: successfully staged volume", "source", source, "volumeID", volumeID, "target", target, "fstype", fsType)
+	if volumeContext[PrewarmOnStageKey] == trueStr {
+		d.startPrewarm(volumeID, source, target)
+	}
+	klog.FromContext(ctx).V(4).Info("NodeStageVolume: successfully staged volume", "source", source, "volumeID", volumeID, "target", target, "fstype", fsType)
 	return &csi.NodeStageVolumeResponse{}, nil
 }
 
 func (d *NodeService) NodeUnstageVolume(ctx context.Context, req *csi.NodeUnstageVolumeRequest) (*csi.NodeUnstageVolumeResponse, error) {
-	klog.V(4).InfoS("NodeUnstageVolume: called", "args", req)
+	klog.FromContext(ctx).V(4).Info("NodeUnstageVolume: called", "args", req)
 	volumeID := req.GetVolumeId()
 	if len(volumeID) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "Volume ID not provided")
@@ -329,10 +398,20 @@ func (d *NodeService) NodeUnstageVolume(ctx context.Context, req *csi.NodeUnstag
 		return nil, status.Errorf(codes.Aborted, VolumeOperationAlreadyExists, volumeID)
 	}
 	defer func() {
-		klog.V(4).InfoS("NodeUnStageVolume: volume operation finished", "volumeID", volumeID)
+		klog.FromContext(ctx).V(4).Info("NodeUnStageVolume: volume operation finished", "volumeID", volumeID)
 		d.inFlight.Delete(volumeID)
 	}()
 
+	// mkfs writes to the device, the volume must not be detached before it finishes
+	if d.formats != nil {
+		if err := d.formats.unstage(volumeID); err != nil {
+			return nil, err
+		}
+	}
+
+	// The pre-warm reads the device, stop it before the volume is detached
+	d.stopPrewarm(volumeID)
+
 	// Check if target directory is a mount point. GetDeviceNameFromMount
 	// given a mnt point, finds the device from /proc/mounts
 	// returns the device name, reference count, and error code
@@ -346,25 +425,30 @@ func (d *NodeService) NodeUnstageVolume(ctx context.Context, req *csi.NodeUnstag
 	// is not staged to the staging_target_path, the Plugin MUST
 	// reply 0 OK.
 	if refCount == 0 {
-		klog.V(5).InfoS("[Debug] NodeUnstageVolume: target not mounted", "target", target)
+		klog.FromContext(ctx).V(5).Info("[Debug] NodeUnstageVolume: target not mounted", "target", target)
 		return &csi.NodeUnstageVolumeResponse{}, nil
 	}
 
 	if refCount > 1 {
-		klog.InfoS("NodeUnstageVolume: found references to device mounted at target path", "refCount", refCount, "device", dev, "target", target)
+		klog.FromContext(ctx).Info("NodeUnstageVolume: found references to device mounted at target path", "refCount", refCount, "device", dev, "target", target)
 	}
 
-	klog.V(4).InfoS("NodeUnstageVolume: unmounting", "target", target)
+	klog.FromContext(ctx).V(4).Info("NodeUnstageVolume: unmounting", "target", target)
 	err = d.mounter.Unstage(target)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Could not unmount target %q: %v", target, err)
+		if !isBusyUnmountError(err) {
+			return nil, status.Errorf(codes.Internal, "Could not unmount target %q: %v", target, err)
+		}
+		if err = d.unstageBusyTarget(ctx, volumeID, target, err); err != nil {
+			return nil, err
+		}
 	}
-	klog.V(4).InfoS("NodeUnStageVolume: successfully unstaged volume", "volumeID", volumeID, "target", target)
+	klog.FromContext(ctx).V(4).Info("NodeUnStageVolume: successfully unstaged volume", "volumeID", volumeID, "target", target)
 	return &csi.NodeUnstageVolumeResponse{}, nil
 }
 
 func (d *NodeService) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandVolumeRequest) (*csi.NodeExpandVolumeResponse, error) {
-	klog.V(4).InfoS("NodeExpandVolume: called", "args", util.SanitizeRequest(req))
+	klog.FromContext(ctx).V(4).Info("NodeExpandVolume: called", "args", util.SanitizeRequest(req))
 	volumeID := req.GetVolumeId()
 	if len(volumeID) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "Volume ID not provided")
@@ -378,7 +462,7 @@ func (d *NodeService) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandV
 		return nil, status.Errorf(codes.Aborted, VolumeOperationAlreadyExists, volumeID)
 	}
 	defer func() {
-		klog.V(4).InfoS("NodeExpandVolume: volume operation finished", "volumeId", volumeID)
+		klog.FromContext(ctx).V(4).Info("NodeExpandVolume: volume operation finished", "volumeId", volumeID)
 		d.inFlight.Delete(volumeID)
 	}()
 
@@ -392,7 +476,7 @@ func (d *NodeService) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandV
 
 		if blk := volumeCapability.GetBlock(); blk != nil {
 			// Noop for Block NodeExpandVolume
-			klog.V(4).InfoS("NodeExpandVolume: called. Since it is a block device, ignoring...", "volumeID", volumeID, "volumePath", volumePath)
+			klog.FromContext(ctx).V(4).Info("NodeExpandVolume: called. Since it is a block device, ignoring...", "volumeID", volumeID, "volumePath", volumePath)
 			return &csi.NodeExpandVolumeResponse{}, nil
 		}
 	} else {
@@ -408,7 +492,7 @@ func (d *NodeService) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandV
 			if err != nil {
 				return nil, status.Errorf(codes.Internal, "failed to get block capacity on path %s: %v", req.GetVolumePath(), err)
 			}
-			klog.V(4).InfoS("NodeExpandVolume: called, since given volumePath is a block device, ignoring...", "volumeID", volumeID, "volumePath", volumePath)
+			klog.FromContext(ctx).V(4).Info("NodeExpandVolume: called, since given volumePath is a block device, ignoring...", "volumeID", volumeID, "volumePath", volumePath)
 			return &csi.NodeExpandVolumeResponse{CapacityBytes: bcap}, nil
 		}
 	}
@@ -417,11 +501,16 @@ func (d *NodeService) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandV
 		return nil, status.Errorf(codes.Internal, "failed to get device name from mount %s: %v", volumePath, err)
 	}
 
-	devicePath, err := d.mounter.FindDevicePath(deviceName, volumeID, "", d.metadata.GetRegion())
+	devicePath, err := d.mounter.FindDevicePath(ctx, deviceName, volumeID, "", d.metadata.GetRegion())
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "failed to find device path for device name %s for mount %s: %v", deviceName, req.GetVolumePath(), err)
 	}
 
+	// Grow the partition or LVM logical volume holding the filesystem before the filesystem itself
+	if err = d.mounter.GrowDevice(devicePath); err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not grow device %q of volume %q: %v", devicePath, volumeID, err)
+	}
+
 	if _, err = d.mounter.Resize(devicePath, volumePath); err != nil {
 		return nil, status.Errorf(codes.Internal, "Could not resize volume %q (%q): %v", volumeID, devicePath, err)
 	}
@@ -434,12 +523,16 @@ func (d *NodeService) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandV
 }
 
 func (d *NodeService) NodePublishVolume(ctx context.Context, req *csi.NodePublishVolumeRequest) (*csi.NodePublishVolumeResponse, error) {
-	klog.V(4).InfoS("NodePublishVolume: called", "args", util.SanitizeRequest(req))
+	klog.FromContext(ctx).V(4).Info("NodePublishVolume: called", "args", util.SanitizeRequest(req))
 	volumeID := req.GetVolumeId()
 	if len(volumeID) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "Volume ID not provided")
 	}
 
+	if isEphemeralVolume(req.GetVolumeContext()) {
+		return d.nodePublishEphemeralVolume(ctx, req)
+	}
+
 	source := req.GetStagingTargetPath()
 	if len(source) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "Staging target not provided")
@@ -465,11 +558,16 @@ func (d *NodeService) NodePublishVolume(ctx context.Context, req *csi.NodePublis
 		}
 	}
 
+	ioLimits, err := parseIOLimits(req.GetVolumeContext())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid I/O limits: %v", err)
+	}
+
 	if ok := d.inFlight.Insert(volumeID); !ok {
 		return nil, status.Errorf(codes.Aborted, VolumeOperationAlreadyExists, volumeID)
 	}
 	defer func() {
-		klog.V(4).InfoS("NodePublishVolume: volume operation finished", "volumeId", volumeID)
+		klog.FromContext(ctx).V(4).Info("NodePublishVolume: volume operation finished", "volumeId", volumeID)
 		d.inFlight.Delete(volumeID)
 	}()
 
@@ -480,20 +578,27 @@ func (d *NodeService) NodePublishVolume(ctx context.Context, req *csi.NodePublis
 
 	switch mode := volCap.GetAccessType().(type) {
 	case *csi.VolumeCapability_Block:
-		if err := d.nodePublishVolumeForBlock(req, mountOptions); err != nil {
+		if err := d.nodePublishVolumeForBlock(ctx, req, mountOptions); err != nil {
 			return nil, err
 		}
 	case *csi.VolumeCapability_Mount:
-		if err := d.nodePublishVolumeForFileSystem(req, mountOptions, mode); err != nil {
+		if err := d.nodePublishVolumeForFileSystem(ctx, req, mountOptions, mode); err != nil {
 			return nil, err
 		}
 	}
 
+	if !ioLimits.IsZero() {
+		klog.FromContext(ctx).V(4).Info("NodePublishVolume: setting I/O limits", "target", target, "limits", ioLimits)
+		if err := d.mounter.SetIOLimits(target, ioLimits); err != nil {
+			return nil, status.Errorf(codes.Internal, "Could not set I/O limits of %q: %v", target, err)
+		}
+	}
+
 	return &csi.NodePublishVolumeResponse{}, nil
 }
 
 func (d *NodeService) NodeUnpublishVolume(ctx context.Context, req *csi.NodeUnpublishVolumeRequest) (*csi.NodeUnpublishVolumeResponse, error) {
-	klog.V(4).InfoS("NodeUnpublishVolume: called", "args", util.SanitizeRequest(req))
+	klog.FromContext(ctx).V(4).Info("NodeUnpublishVolume: called", "args", util.SanitizeRequest(req))
 	volumeID := req.GetVolumeId()
 	if len(volumeID) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "Volume ID not provided")
@@ -509,21 +614,33 @@ func (d *NodeService) NodeUnpublishVolume(ctx context.Context, req *csi.NodeUnpu
 	}
 
 	defer func() {
-		klog.V(4).InfoS("NodeUnpublishVolume: volume operation finished", "volumeId", volumeID)
+		klog.FromContext(ctx).V(4).Info("NodeUnpublishVolume: volume operation finished", "volumeId", volumeID)
 		d.inFlight.Delete(volumeID)
 	}()
 
-	klog.V(4).InfoS("NodeUnpublishVolume: unmounting", "target", target)
+	// Limits must be cleared before unmounting, as the device is resolved from the mounted target
+	if err := d.mounter.ClearIOLimits(target); err != nil {
+		klog.FromContext(ctx).Error(err, "NodeUnpublishVolume: failed to clear I/O limits", "target", target)
+	}
+
+	klog.FromContext(ctx).V(4).Info("NodeUnpublishVolume: unmounting", "target", target)
 	err := d.mounter.Unpublish(target)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Could not unmount %q: %v", target, err)
 	}
 
+	// Without the ephemeral volume API the volume cannot have been published
+	if isEphemeralVolumeHandle(volumeID) && d.ephemeral != nil {
+		if err := d.nodeUnpublishEphemeralVolume(ctx, volumeID); err != nil {
+			return nil, err
+		}
+	}
+
 	return &csi.NodeUnpublishVolumeResponse{}, nil
 }
 
 func (d *NodeService) NodeGetVolumeStats(ctx context.Context, req *csi.NodeGetVolumeStatsRequest) (*csi.NodeGetVolumeStatsResponse, error) {
-	klog.V(4).InfoS("NodeGetVolumeStats: called", "args", req)
+	klog.FromContext(ctx).V(4).Info("NodeGetVolumeStats: called", "args", req)
 	if len(req.GetVolumeId()) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "NodeGetVolumeStats volume ID was empty")
 	}
@@ -587,7 +704,7 @@ func (d *NodeService) NodeGetVolumeStats(ctx context.Context, req *csi.NodeGetVo
 }
 
 func (d *NodeService) NodeGetCapabilities(ctx context.Context, req *csi.NodeGetCapabilitiesRequest) (*csi.NodeGetCapabilitiesResponse, error) {
-	klog.V(4).InfoS("NodeGetCapabilities: called", "args", req)
+	klog.FromContext(ctx).V(4).Info("NodeGetCapabilities: called", "args", req)
 	caps := make([]*csi.NodeServiceCapability, 0, len(nodeCaps))
 	for _, cap := range nodeCaps {
 		c := &csi.NodeServiceCapability{
@@ -603,10 +720,10 @@ func (d *NodeService) NodeGetCapabilities(ctx context.Context, req *csi.NodeGetC
 }
 
 func (d *NodeService) NodeGetInfo(ctx context.Context, req *csi.NodeGetInfoRequest) (*csi.NodeGetInfoResponse, error) {
-	klog.V(4).InfoS("NodeGetInfo: called", "args", req)
+	klog.FromContext(ctx).V(4).Info("NodeGetInfo: called", "args", req)
 
 	if err := d.metadata.UpdateMetadata(); err != nil {
-		klog.ErrorS(err, "Failed to update metadata, using cached values")
+		klog.FromContext(ctx).Error(err, "Failed to update metadata, using cached values")
 	}
 
 	zone := d.metadata.GetAvailabilityZone()
@@ -634,7 +751,7 @@ func (d *NodeService) NodeGetInfo(ctx context.Context, req *csi.NodeGetInfoReque
 
 	topology := &csi.Topology{Segments: segments}
 	maxVolumesPerNode := d.getVolumesLimit()
-	klog.V(4).InfoS("NodeGetInfo:", "maxVolumesPerNode", maxVolumesPerNode)
+	klog.FromContext(ctx).V(4).Info("NodeGetInfo:", "maxVolumesPerNode", maxVolumesPerNode)
 	return &csi.NodeGetInfoResponse{
 		NodeId:             d.metadata.GetInstanceID(),
 		MaxVolumesPerNode:  maxVolumesPerNode,
@@ -642,7 +759,7 @@ func (d *NodeService) NodeGetInfo(ctx context.Context, req *csi.NodeGetInfoReque
 	}, nil
 }
 
-func (d *NodeService) nodePublishVolumeForBlock(req *csi.NodePublishVolumeRequest, mountOptions []string) error {
+func (d *NodeService) nodePublishVolumeForBlock(ctx context.Context, req *csi.NodePublishVolumeRequest, mountOptions []string) error {
 	target := req.GetTargetPath()
 	volumeID := req.GetVolumeId()
 	volumeContext := req.GetVolumeContext()
@@ -660,7 +777,7 @@ func (d *NodeService) nodePublishVolumeForBlock(req *csi.NodePublishVolumeReques
 		if part != "0" {
 			partition = part
 		} else {
-			klog.InfoS("NodePublishVolume: invalid partition config, will ignore.", "partition", part)
+			klog.FromContext(ctx).Info("NodePublishVolume: invalid partition config, will ignore.", "partition", part)
 		}
 	}
 
@@ -671,12 +788,12 @@ func (d *NodeService) nodePublishVolumeForBlock(req *csi.NodePublishVolumeReques
 		}
 	}
 
-	source, err := d.mounter.FindDevicePath(devicePath, effectiveVolumeID, partition, d.metadata.GetRegion())
+	source, err := d.findDevicePath(ctx, volumeID, devicePath, effectiveVolumeID, partition)
 	if err != nil {
 		return status.Errorf(codes.NotFound, "Failed to find device path %s. %v", devicePath, err)
 	}
 
-	klog.V(4).InfoS("NodePublishVolume [block]: find device path", "devicePath", devicePath, "source", source)
+	klog.FromContext(ctx).V(4).Info("NodePublishVolume [block]: find device path", "devicePath", devicePath, "source", source)
 
 	globalMountPath := filepath.Dir(target)
 
@@ -697,7 +814,7 @@ func (d *NodeService) nodePublishVolumeForBlock(req *csi.NodePublishVolumeReques
 	// This implementation detail is relied upon by the NVMECollector,
 	// which discovers block devices by parsing /proc/self/mountinfo. The bind mount
 	// created here ensures block devices appear in mountinfo even without a filesystem.
-	klog.V(4).InfoS("NodePublishVolume [block]: making target file", "target", target)
+	klog.FromContext(ctx).V(4).Info("NodePublishVolume [block]: making target file", "target", target)
 	if err = d.mounter.MakeFile(target); err != nil {
 		if removeErr := os.Remove(target); removeErr != nil {
 			return status.Errorf(codes.Internal, "Could not remove mount target %q: %v", target, removeErr)
@@ -706,13 +823,13 @@ func (d *NodeService) nodePublishVolumeForBlock(req *csi.NodePublishVolumeReques
 	}
 
 	// Checking if the target file is already mounted with a device.
-	mounted, err := d.isMounted(source, target)
+	mounted, err := d.isMounted(ctx, source, target)
 	if err != nil {
 		return status.Errorf(codes.Internal, "Could not check if %q is mounted: %v", target, err)
 	}
 
 	if !mounted {
-		klog.V(4).InfoS("NodePublishVolume [block]: mounting", "source", source, "target", target)
+		klog.FromContext(ctx).V(4).Info("NodePublishVolume [block]: mounting", "source", source, "target", target)
 		if err := d.mounter.Mount(source, target, "", mountOptions); err != nil {
 			if removeErr := os.Remove(target); removeErr != nil {
 				return status.Errorf(codes.Internal, "Could not remove mount target %q: %v", target, removeErr)
@@ -720,7 +837,7 @@ func (d *NodeService) nodePublishVolumeForBlock(req *csi.NodePublishVolumeReques
 			return status.Errorf(codes.Internal, "Could not mount %q at %q: %v", source, target, err)
 		}
 	} else {
-		klog.V(4).InfoS("NodePublishVolume [block]: Target path is already mounted", "target", target)
+		klog.FromContext(ctx).V(4).Info("NodePublishVolume [block]: Target path is already mounted", "target", target)
 	}
 
 	return nil
@@ -728,7 +845,7 @@ func (d *NodeService) nodePublishVolumeForBlock(req *csi.NodePublishVolumeReques
 
 // isMounted checks if target is mounted. It does NOT return an error if target
 // doesn't exist.
-func (d *NodeService) isMounted(_ string, target string) (bool, error) {
+func (d *NodeService) isMounted(ctx context.Context, _ string, target string) (bool, error) {
 	/*
 		Checking if it's a mount point using IsLikelyNotMountPoint. There are three different return values,
 		1. true, err when the directory does not exist or corrupted.
@@ -740,7 +857,7 @@ func (d *NodeService) isMounted(_ string, target string) (bool, error) {
 		// Checking if the path exists and error is related to Corrupted Mount, in that case, the system could unmount and mount.
 		_, pathErr := d.mounter.PathExists(target)
 		if pathErr != nil && d.mounter.IsCorruptedMnt(pathErr) {
-			klog.V(4).InfoS("NodePublishVolume: Target path is a corrupted mount. Trying to unmount.", "target", target)
+			klog.FromContext(ctx).V(4).Info("NodePublishVolume: Target path is a corrupted mount. Trying to unmount.", "target", target)
 			if mntErr := d.mounter.Unpublish(target); mntErr != nil {
 				return false, status.Errorf(codes.Internal, "Unable to unmount the target %q : %v", target, mntErr)
 			}
@@ -757,18 +874,18 @@ func (d *NodeService) isMounted(_ string, target string) (bool, error) {
 	// and in others it is an error (in Linux, the target mount directory must
 	// exist before mount is called on it)
 	if err != nil && os.IsNotExist(err) {
-		klog.V(5).InfoS("[Debug] NodePublishVolume: Target path does not exist", "target", target)
+		klog.FromContext(ctx).V(5).Info("[Debug] NodePublishVolume: Target path does not exist", "target", target)
 		return false, nil
 	}
 
 	if !notMnt {
-		klog.V(4).InfoS("NodePublishVolume: Target path is already mounted", "target", target)
+		klog.FromContext(ctx).V(4).Info("NodePublishVolume: Target path is already mounted", "target", target)
 	}
 
 	return !notMnt, nil
 }
 
-func (d *NodeService) nodePublishVolumeForFileSystem(req *csi.NodePublishVolumeRequest, mountOptions []string, mode *csi.VolumeCapability_Mount) error {
+func (d *NodeService) nodePublishVolumeForFileSystem(ctx context.Context, req *csi.NodePublishVolumeRequest, mountOptions []string, mode *csi.VolumeCapability_Mount) error {
 	target := req.GetTargetPath()
 	source := req.GetStagingTargetPath()
 	if m := mode.Mount; m != nil {
@@ -784,7 +901,7 @@ func (d *NodeService) nodePublishVolumeForFileSystem(req *csi.NodePublishVolumeR
 	}
 
 	// Checking if the target directory is already mounted with a device.
-	mounted, err := d.isMounted(source, target)
+	mounted, err := d.isMounted(ctx, source, target)
 	if err != nil {
 		return status.Errorf(codes.Internal, "Could not check if %q is mounted: %v", target, err)
 	}
@@ -801,7 +918,7 @@ func (d *NodeService) nodePublishVolumeForFileSystem(req *csi.NodePublishVolumeR
 		}
 
 		mountOptions = collectMountOptions(fsType, mountOptions)
-		klog.V(4).InfoS("NodePublishVolume: mounting", "source", source, "target", target, "mountOptions", mountOptions, "fsType", fsType)
+		klog.FromContext(ctx).V(4).Info("NodePublishVolume: mounting", "source", source, "target", target, "mountOptions", mountOptions, "fsType", fsType)
 		if err := d.mounter.Mount(source, target, fsType, mountOptions); err != nil {
 			return status.Errorf(codes.Internal, "Could not mount %q at %q: %v", source, target, err)
 		}
@@ -846,6 +963,52 @@ func (d *NodeService) getVolumesLimit() int64 {
 	return int64(availableAttachments)
 }
 
+// isIOLimitKey returns true if key, in any case, is one of the I/O limit parameters.
+func isIOLimitKey(key string) bool {
+	switch strings.ToLower(key) {
+	case ReadIOPSLimitKey, WriteIOPSLimitKey, ReadBandwidthLimitKey, WriteBandwidthLimitKey:
+		return true
+	}
+	return false
+}
+
+// parseIOLimits parses the I/O limit parameters of volumeContext. IOPS limits are integers,
+// bandwidth limits are quantities in bytes per second (e.g. 100Mi).
+func parseIOLimits(volumeContext map[string]string) (mounter.IOLimits, error) {
+	var limits mounter.IOLimits
+	for key, value := range volumeContext {
+		var err error
+		switch key {
+		case ReadIOPSLimitKey:
+			limits.ReadIOPS, err = strconv.ParseUint(value, 10, 64)
+		case WriteIOPSLimitKey:
+			limits.WriteIOPS, err = strconv.ParseUint(value, 10, 64)
+		case ReadBandwidthLimitKey:
+			limits.ReadBPS, err = parseBandwidth(value)
+		case WriteBandwidthLimitKey:
+			limits.WriteBPS, err = parseBandwidth(value)
+		default:
+			continue
+		}
+		if err != nil {
+			return mounter.IOLimits{}, fmt.Errorf("could not parse %s %q: %w", key, value, err)
+		}
+	}
+	return limits, nil
+}
+
+func parseBandwidth(value string) (uint64, error) {
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, err
+	}
+	bps, ok := q.AsInt64()
+	if !ok || bps < 0 {
+		return 0, errors.New("must be a non-negative integer number of bytes per second")
+	}
+	return uint64(bps), nil
+}
+
 // hasMountOption returns a boolean indicating whether the given
 // slice already contains a mount option. This is used to prevent
 // passing duplicate option to the mount command.
@@ -853,6 +1016,19 @@ func hasMountOption(options []string, opt string) bool {
 	return slices.Contains(options, opt)
 }
 
+// findDevicePath returns the device of volumeID on this node. Node-local volumes backed by
+// instance store disks are found by their NVMe model, other volumes by their EBS volume ID.
+func (d *NodeService) findDevicePath(ctx context.Context, volumeID, devicePath, effectiveVolumeID, partition string) (string, error) {
+	if isInstanceStoreVolume(volumeID) {
+		selector, err := instanceStoreSelector(volumeID)
+		if err != nil {
+			return "", err
+		}
+		return d.mounter.FindInstanceStoreDevice(selector)
+	}
+	return d.mounter.FindDevicePath(ctx, devicePath, effectiveVolumeID, partition, d.metadata.GetRegion())
+}
+
 // collectMountOptions returns array of mount options from
 // VolumeCapability_MountVolume and special mount options for
 // given filesystem.
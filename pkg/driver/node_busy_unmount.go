@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/mounter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// Event reasons of busy unmounts in NodeUnstageVolume.
+	eventReasonVolumeUnmountBusy = "VolumeUnmountBusy"
+	eventReasonVolumeLazyUnmount = "VolumeLazilyUnmounted"
+
+	// maxReportedMountHolders caps the processes listed in errors and events.
+	maxReportedMountHolders = 10
+)
+
+// isBusyUnmountError returns true if err is the failure of umount on a busy mount (EBUSY).
+func isBusyUnmountError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "target is busy") || strings.Contains(msg, "device is busy") || strings.Contains(msg, "device or resource busy")
+}
+
+// unstageBusyTarget is called when unmounting the staging target failed because it is busy.
+// It finds the processes holding the mount and reports them in the returned error and a node
+// event. If LazyUnmountWithoutWriters is set and none of them is writing to the volume,
+// the target is lazily unmounted instead and nil is returned, which lets the volume be
+// detached while the holders still read it.
+func (d *NodeService) unstageBusyTarget(ctx context.Context, volumeID, target string, unmountErr error) error {
+	holders, err := d.mounter.FindMountHolders(target)
+	if err != nil {
+		klog.FromContext(ctx).Error(err, "NodeUnstageVolume: could not find processes holding the mount", "target", target)
+		d.recordNodeEvent(corev1.EventTypeWarning, eventReasonVolumeUnmountBusy,
+			fmt.Sprintf("Could not unmount volume %s at %s because it is busy", volumeID, target))
+		return status.Errorf(codes.Internal, "Could not unmount target %q: %v", target, unmountErr)
+	}
+
+	var writers int
+	for _, h := range holders {
+		if h.Writer {
+			writers++
+		}
+	}
+	klog.FromContext(ctx).Info("NodeUnstageVolume: target is busy", "volumeID", volumeID, "target", target, "holders", len(holders), "writers", writers)
+
+	if d.options.LazyUnmountWithoutWriters && writers == 0 {
+		err = d.mounter.LazyUnmount(target)
+		if err == nil {
+			// The volume may be detached while the holders still read it
+			d.recordVolumeEvent(stagingPVName(target), corev1.EventTypeWarning, eventReasonVolumeLazyUnmount,
+				fmt.Sprintf("Lazily unmounted busy volume %s at %s, the volume can be detached while it is still read by: %s", volumeID, target, formatMountHolders(holders)))
+			return nil
+		}
+		klog.FromContext(ctx).Error(err, "NodeUnstageVolume: lazy unmount failed", "volumeID", volumeID, "target", target)
+	}
+
+	d.recordNodeEvent(corev1.EventTypeWarning, eventReasonVolumeUnmountBusy,
+		fmt.Sprintf("Could not unmount volume %s at %s, it is held by: %s", volumeID, target, formatMountHolders(holders)))
+	return status.Errorf(codes.Internal, "Could not unmount target %q: %v; held by: %s", target, unmountErr, formatMountHolders(holders))
+}
+
+// formatMountHolders returns a human readable list of holders.
+func formatMountHolders(holders []mounter.MountHolder) string {
+	if len(holders) == 0 {
+		// Processes of other pods are only visible in the host PID namespace
+		return "no process found (is the node plugin running with hostPID?)"
+	}
+	parts := make([]string, 0, min(len(holders), maxReportedMountHolders)+1)
+	for i, h := range holders {
+		if i == maxReportedMountHolders {
+			parts = append(parts, fmt.Sprintf("and %d more", len(holders)-maxReportedMountHolders))
+			break
+		}
+		parts = append(parts, h.String())
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"strings"
+
+	csi "github.com/container-storage-interface/spec/lib/go/csi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// nodePublishEphemeralVolume publishes an ephemeral inline volume. Kubelet does not call
+// NodeStageVolume for these, so the volume is created and attached through the ephemeral volume
+// API of the controller, then formatted and mounted directly at the target path.
+func (d *NodeService) nodePublishEphemeralVolume(ctx context.Context, req *csi.NodePublishVolumeRequest) (*csi.NodePublishVolumeResponse, error) {
+	volumeID := req.GetVolumeId()
+	target := req.GetTargetPath()
+	if len(target) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "Target path not provided")
+	}
+	if d.ephemeral == nil {
+		return nil, status.Error(codes.FailedPrecondition, errEphemeralVolumesDisabled.Error())
+	}
+	if !isEphemeralVolumeHandle(volumeID) {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid ephemeral volume handle %q", volumeID)
+	}
+
+	volCap := req.GetVolumeCapability()
+	if volCap == nil {
+		return nil, status.Error(codes.InvalidArgument, "Volume capability not provided")
+	}
+	mountVolume := volCap.GetMount()
+	if mountVolume == nil {
+		return nil, status.Error(codes.InvalidArgument, "Ephemeral volumes only support the mount access type")
+	}
+	fsType := mountVolume.GetFsType()
+	if len(fsType) == 0 {
+		fsType = defaultFsType
+	}
+	if _, ok := ValidFSTypes[strings.ToLower(fsType)]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "NodePublishVolume: invalid fstype %s", fsType)
+	}
+
+	volumeContext := req.GetVolumeContext()
+	// The controller reads the attributes from the pod spec, they are parsed here to fail early
+	if _, err := parseEphemeralVolumeAttributes(volumeContext); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "Invalid ephemeral volume attributes: %v", err)
+	}
+
+	if ok := d.inFlight.Insert(volumeID); !ok {
+		return nil, status.Errorf(codes.Aborted, VolumeOperationAlreadyExists, volumeID)
+	}
+	defer func() {
+		klog.FromContext(ctx).V(4).Info("NodePublishVolume: volume operation finished", "volumeId", volumeID)
+		d.inFlight.Delete(volumeID)
+	}()
+
+	exists, err := d.mounter.PathExists(target)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to check if target %q exists: %v", target, err)
+	}
+	if exists {
+		device, _, err := d.mounter.GetDeviceNameFromMount(target)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to check if volume is already mounted: %v", err)
+		}
+		if device != "" {
+			klog.FromContext(ctx).V(4).Info("NodePublishVolume: ephemeral volume already published", "volumeID", volumeID, "target", target)
+			return &csi.NodePublishVolumeResponse{}, nil
+		}
+	}
+
+	resp, err := d.ephemeral.Publish(ctx, &ephemeralVolumeRequest{
+		VolumeHandle: volumeID,
+		PodNamespace: volumeContext[PodNamespaceKey],
+		PodName:      volumeContext[PodNameKey],
+		PodUID:       volumeContext[PodUIDKey],
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := d.mounter.FindDevicePath(ctx, resp.DevicePath, resp.VolumeID, "", d.metadata.GetRegion())
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "Failed to find device path %s. %v", resp.DevicePath, err)
+	}
+
+	if !exists {
+		klog.FromContext(ctx).V(4).Info("NodePublishVolume: creating target dir", "target", target)
+		if err := d.mounter.MakeDir(target); err != nil {
+			return nil, status.Errorf(codes.Internal, "Could not create dir %q: %v", target, err)
+		}
+	}
+
+	mountOptions := collectMountOptions(fsType, mountVolume.GetMountFlags())
+	if req.GetReadonly() {
+		mountOptions = append(mountOptions, "ro")
+	}
+	klog.FromContext(ctx).V(4).Info("NodePublishVolume: mounting ephemeral volume", "source", source, "volumeID", resp.VolumeID, "target", target, "fstype", fsType)
+	if err := d.mounter.FormatAndMountSensitiveWithFormatOptions(source, target, fsType, mountOptions, nil, nil); err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not format %q and mount it at %q: %v", source, target, err)
+	}
+	return &csi.NodePublishVolumeResponse{}, nil
+}
+
+// nodeUnpublishEphemeralVolume detaches and deletes the volume of an unmounted ephemeral inline volume.
+func (d *NodeService) nodeUnpublishEphemeralVolume(ctx context.Context, volumeID string) error {
+	klog.FromContext(ctx).V(4).Info("NodeUnpublishVolume: deleting ephemeral volume", "volumeID", volumeID)
+	return d.ephemeral.Unpublish(ctx, &ephemeralVolumeRequest{VolumeHandle: volumeID})
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"testing"
+
+	csi "github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud/metadata"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/driver/internal"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/mounter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeEphemeralVolumeAPI records the requests of the node and returns resp to Publish.
+type fakeEphemeralVolumeAPI struct {
+	resp        *ephemeralPublishResponse
+	published   []*ephemeralVolumeRequest
+	unpublished []*ephemeralVolumeRequest
+}
+
+func (f *fakeEphemeralVolumeAPI) Publish(_ context.Context, req *ephemeralVolumeRequest) (*ephemeralPublishResponse, error) {
+	f.published = append(f.published, req)
+	return f.resp, nil
+}
+
+func (f *fakeEphemeralVolumeAPI) Unpublish(_ context.Context, req *ephemeralVolumeRequest) error {
+	f.unpublished = append(f.unpublished, req)
+	return nil
+}
+
+func TestNodePublishEphemeralVolume(t *testing.T) {
+	handle := makeEphemeralVolumeHandle("pod-uid", "scratch")
+	newRequest := func() *csi.NodePublishVolumeRequest {
+		return &csi.NodePublishVolumeRequest{
+			VolumeId:   handle,
+			TargetPath: "/target/path",
+			VolumeCapability: &csi.VolumeCapability{
+				AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+			},
+			VolumeContext: map[string]string{
+				EphemeralKey:    trueStr,
+				PodNamespaceKey: "default",
+				PodNameKey:      "app",
+				PodUIDKey:       "pod-uid",
+				"size":          "4Gi",
+			},
+		}
+	}
+
+	testCases := []struct {
+		name         string
+		req          func() *csi.NodePublishVolumeRequest
+		disabled     bool
+		mounterMock  func(m *mounter.MockMounter)
+		metadataMock func(m *metadata.MockMetadataService)
+		published    bool
+		expectedCode codes.Code
+	}{
+		{
+			name: "success",
+			req:  newRequest,
+			mounterMock: func(m *mounter.MockMounter) {
+				m.EXPECT().PathExists("/target/path").Return(false, nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), "/dev/xvdba", "vol-test", "", "us-west-2").Return("/dev/nvme1n1", nil)
+				m.EXPECT().MakeDir("/target/path").Return(nil)
+				m.EXPECT().FormatAndMountSensitiveWithFormatOptions("/dev/nvme1n1", "/target/path", defaultFsType, nil, nil, nil).Return(nil)
+			},
+			metadataMock: func(m *metadata.MockMetadataService) {
+				m.EXPECT().GetRegion().Return("us-west-2")
+			},
+			published: true,
+		},
+		{
+			name: "already published",
+			req:  newRequest,
+			mounterMock: func(m *mounter.MockMounter) {
+				m.EXPECT().PathExists("/target/path").Return(true, nil)
+				m.EXPECT().GetDeviceNameFromMount("/target/path").Return("/dev/nvme1n1", 1, nil)
+			},
+		},
+		{
+			name: "invalid attributes",
+			req: func() *csi.NodePublishVolumeRequest {
+				req := newRequest()
+				delete(req.VolumeContext, "size")
+				return req
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "block access type",
+			req: func() *csi.NodePublishVolumeRequest {
+				req := newRequest()
+				req.VolumeCapability.AccessType = &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}}
+				return req
+			},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "disabled",
+			req:          newRequest,
+			disabled:     true,
+			expectedCode: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := mounter.NewMockMounter(ctrl)
+			if tc.mounterMock != nil {
+				tc.mounterMock(m)
+			}
+			md := metadata.NewMockMetadataService(ctrl)
+			if tc.metadataMock != nil {
+				tc.metadataMock(md)
+			}
+			api := &fakeEphemeralVolumeAPI{resp: &ephemeralPublishResponse{VolumeID: "vol-test", DevicePath: "/dev/xvdba"}}
+			d := &NodeService{
+				metadata: md,
+				mounter:  m,
+				inFlight: internal.NewInFlight(),
+				options:  &Options{},
+			}
+			if !tc.disabled {
+				d.ephemeral = api
+			}
+
+			_, err := d.NodePublishVolume(t.Context(), tc.req())
+			if tc.expectedCode != codes.OK {
+				require.Error(t, err)
+				assert.Equal(t, tc.expectedCode, status.Code(err), err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.published {
+				assert.Equal(t, []*ephemeralVolumeRequest{{VolumeHandle: handle, PodNamespace: "default", PodName: "app", PodUID: "pod-uid"}}, api.published)
+			} else {
+				assert.Empty(t, api.published)
+			}
+		})
+	}
+}
+
+func TestNodeUnpublishEphemeralVolume(t *testing.T) {
+	handle := makeEphemeralVolumeHandle("pod-uid", "scratch")
+	ctrl := gomock.NewController(t)
+	m := mounter.NewMockMounter(ctrl)
+	m.EXPECT().ClearIOLimits("/target/path").Return(nil)
+	m.EXPECT().Unpublish("/target/path").Return(nil)
+
+	api := &fakeEphemeralVolumeAPI{}
+	d := &NodeService{
+		mounter:   m,
+		inFlight:  internal.NewInFlight(),
+		options:   &Options{},
+		ephemeral: api,
+	}
+	_, err := d.NodeUnpublishVolume(t.Context(), &csi.NodeUnpublishVolumeRequest{VolumeId: handle, TargetPath: "/target/path"})
+	require.NoError(t, err)
+	assert.Equal(t, []*ephemeralVolumeRequest{{VolumeHandle: handle}}, api.unpublished)
+}
@@ -0,0 +1,177 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/metrics"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/mounter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/klog/v2"
+)
+
+// formatDurationBuckets covers formats from a few seconds up to a few hours.
+var formatDurationBuckets = []float64{5, 15, 30, 60, 120, 300, 600, 1200, 1800, 3600, 7200, 14400}
+
+// formatJobRetention is how long the result of a finished job is kept for NodeStageVolume to
+// poll it. The volume is checked again if NodeStageVolume is retried later.
+const formatJobRetention = time.Hour
+
+// formatJob is a filesystem format running in the background for a volume.
+type formatJob struct {
+	source   string
+	fsType   string
+	queuedAt time.Time
+	// startedAt is zero while the job waits for a free format slot.
+	startedAt time.Time
+	// finishedAt is zero until the job is done.
+	finishedAt time.Time
+	done       bool
+	err        error
+}
+
+// formatJobs runs the formats of large volumes in the background, so that NodeStageVolume
+// does not exceed the gRPC timeout of kubelet while mkfs runs. At most cap(slots) formats
+// run at the same time.
+type formatJobs struct {
+	mounter mounter.Mounter
+	mu      sync.Mutex
+	jobs    map[string]*formatJob
+	slots   chan struct{}
+}
+
+func newFormatJobs(m mounter.Mounter, maxConcurrent int) *formatJobs {
+	return &formatJobs{
+		mounter: m,
+		jobs:    make(map[string]*formatJob),
+		slots:   make(chan struct{}, maxConcurrent),
+	}
+}
+
+// formatInBackground formats source in the background if it is unformatted and at least
+// minSize bytes. It returns an Aborted error while the format is queued or running, so that
+// kubelet retries NodeStageVolume, and nil once source does not need to be formatted anymore.
+func (f *formatJobs) formatInBackground(volumeID, source, fsType string, formatOptions []string, minSize int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.pruneLocked()
+	if job, ok := f.jobs[volumeID]; ok {
+		if !job.done {
+			return status.Error(codes.Aborted, job.progress(volumeID))
+		}
+		delete(f.jobs, volumeID)
+		if job.source == source {
+			if job.err != nil {
+				return status.Errorf(codes.Internal, "could not format %q: %v", source, job.err)
+			}
+			return nil
+		}
+		// The volume was attached again at another device since the job ran, check it again
+	}
+
+	size, err := f.mounter.GetBlockSizeBytes(source)
+	if err != nil {
+		return status.Errorf(codes.Internal, "could not get size of %q: %v", source, err)
+	}
+	if size < minSize {
+		return nil
+	}
+	existingFormat, err := f.mounter.GetDiskFormat(source)
+	if err != nil {
+		return status.Errorf(codes.Internal, "could not get format of %q: %v", source, err)
+	}
+	if existingFormat != "" {
+		return nil
+	}
+
+	job := &formatJob{source: source, fsType: fsType, queuedAt: time.Now()}
+	f.jobs[volumeID] = job
+	klog.InfoS("NodeStageVolume: formatting volume in the background", "volumeID", volumeID, "source", source, "fstype", fsType, "size", size)
+	go f.run(volumeID, job, slices.Clone(formatOptions))
+	return status.Error(codes.Aborted, job.progress(volumeID))
+}
+
+// unstage returns an Aborted error while the format of volumeID is queued or running, so that the
+// volume is not detached while mkfs writes to it, and drops the result of a finished job.
+func (f *formatJobs) unstage(volumeID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.pruneLocked()
+	job, ok := f.jobs[volumeID]
+	if !ok {
+		return nil
+	}
+	if !job.done {
+		return status.Errorf(codes.Aborted, "%s, it cannot be unstaged until the format is done", job.progress(volumeID))
+	}
+	delete(f.jobs, volumeID)
+	return nil
+}
+
+// pruneLocked drops the finished jobs that were not polled for formatJobRetention. The mutex must be held.
+func (f *formatJobs) pruneLocked() {
+	for volumeID, job := range f.jobs {
+		if job.done && time.Since(job.finishedAt) > formatJobRetention {
+			klog.V(4).InfoS("Dropping the result of a background format that was not polled", "volumeID", volumeID, "source", job.source)
+			delete(f.jobs, volumeID)
+		}
+	}
+}
+
+// run waits for a free format slot and formats the source of job.
+func (f *formatJobs) run(volumeID string, job *formatJob, formatOptions []string) {
+	f.slots <- struct{}{}
+	defer func() { <-f.slots }()
+
+	f.mu.Lock()
+	job.startedAt = time.Now()
+	f.mu.Unlock()
+
+	err := f.mounter.FormatDevice(job.source, job.fsType, formatOptions)
+	duration := time.Since(job.startedAt)
+
+	f.mu.Lock()
+	job.finishedAt = time.Now()
+	job.done = true
+	job.err = err
+	f.mu.Unlock()
+
+	result := "success"
+	if err != nil {
+		result = "error"
+		klog.ErrorS(err, "Background format failed", "volumeID", volumeID, "source", job.source, "duration", duration)
+	} else {
+		klog.InfoS("Background format finished", "volumeID", volumeID, "source", job.source, "duration", duration)
+	}
+	metrics.Recorder().ObserveHistogram(metrics.FormatDuration, metrics.FormatDurationHelpText, duration.Seconds(),
+		map[string]string{"fs_type": job.fsType, "result": result}, formatDurationBuckets)
+}
+
+// progress describes the state of a job that is not done. The mutex of its formatJobs must be held.
+func (job *formatJob) progress(volumeID string) string {
+	if job.startedAt.IsZero() {
+		return fmt.Sprintf("volume %s is waiting for a free slot to be formatted in the background for %s", volumeID, time.Since(job.queuedAt).Round(time.Second))
+	}
+	return fmt.Sprintf("volume %s is being formatted as %s in the background for %s", volumeID, job.fsType, time.Since(job.startedAt).Round(time.Second))
+}
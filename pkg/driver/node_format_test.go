@@ -0,0 +1,188 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/mounter"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testFormatMinSize = 1024 * util.GiB
+
+// waitFormatDone waits until the background job of volumeID has finished.
+func waitFormatDone(t *testing.T, f *formatJobs, volumeID string) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return f.jobs[volumeID].done
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestFormatInBackground(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mounterMock func(m *mounter.MockMounter)
+		// formatErr is returned by FormatDevice if it is called.
+		formatErr    error
+		expectedCode codes.Code
+		// finalCode is the code returned once the background format is done.
+		finalCode codes.Code
+	}{
+		{
+			name: "small volume",
+			mounterMock: func(m *mounter.MockMounter) {
+				m.EXPECT().GetBlockSizeBytes("/dev/xvdba").Return(100*util.GiB, nil)
+			},
+		},
+		{
+			name: "already formatted",
+			mounterMock: func(m *mounter.MockMounter) {
+				m.EXPECT().GetBlockSizeBytes("/dev/xvdba").Return(16384*util.GiB, nil)
+				m.EXPECT().GetDiskFormat("/dev/xvdba").Return("ext4", nil)
+			},
+		},
+		{
+			name: "format succeeds",
+			mounterMock: func(m *mounter.MockMounter) {
+				m.EXPECT().GetBlockSizeBytes("/dev/xvdba").Return(16384*util.GiB, nil)
+				m.EXPECT().GetDiskFormat("/dev/xvdba").Return("", nil)
+			},
+			expectedCode: codes.Aborted,
+		},
+		{
+			name: "format fails",
+			mounterMock: func(m *mounter.MockMounter) {
+				m.EXPECT().GetBlockSizeBytes("/dev/xvdba").Return(16384*util.GiB, nil)
+				m.EXPECT().GetDiskFormat("/dev/xvdba").Return("", nil)
+			},
+			formatErr:    errors.New("mkfs failed"),
+			expectedCode: codes.Aborted,
+			finalCode:    codes.Internal,
+		},
+		{
+			name: "size unknown",
+			mounterMock: func(m *mounter.MockMounter) {
+				m.EXPECT().GetBlockSizeBytes("/dev/xvdba").Return(int64(0), errors.New("no device"))
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := mounter.NewMockMounter(ctrl)
+			tc.mounterMock(m)
+			m.EXPECT().FormatDevice("/dev/xvdba", "ext4", []string{"-N", "100000000"}).Return(tc.formatErr).MaxTimes(1)
+
+			f := newFormatJobs(m, 2)
+			err := f.formatInBackground("vol-test", "/dev/xvdba", "ext4", []string{"-N", "100000000"}, testFormatMinSize)
+			assert.Equal(t, tc.expectedCode, status.Code(err), err)
+			if tc.expectedCode != codes.Aborted {
+				return
+			}
+
+			waitFormatDone(t, f, "vol-test")
+			err = f.formatInBackground("vol-test", "/dev/xvdba", "ext4", []string{"-N", "100000000"}, testFormatMinSize)
+			assert.Equal(t, tc.finalCode, status.Code(err), err)
+			assert.Empty(t, f.jobs)
+		})
+	}
+}
+
+func TestFormatInBackgroundConcurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := mounter.NewMockMounter(ctrl)
+	m.EXPECT().GetBlockSizeBytes(gomock.Any()).Return(16384*util.GiB, nil).Times(2)
+	m.EXPECT().GetDiskFormat(gomock.Any()).Return("", nil).Times(2)
+
+	release := make(chan struct{})
+	started := make(chan string, 2)
+	m.EXPECT().FormatDevice(gomock.Any(), "xfs", gomock.Any()).DoAndReturn(func(source, _ string, _ []string) error {
+		started <- source
+		<-release
+		return nil
+	}).Times(2)
+
+	f := newFormatJobs(m, 1)
+	err := f.formatInBackground("vol-1", "/dev/xvdba", "xfs", nil, testFormatMinSize)
+	assert.Equal(t, codes.Aborted, status.Code(err))
+	assert.Equal(t, "/dev/xvdba", <-started)
+
+	err = f.formatInBackground("vol-2", "/dev/xvdbb", "xfs", nil, testFormatMinSize)
+	assert.Equal(t, codes.Aborted, status.Code(err))
+	// The second format waits until the first one releases its slot
+	err = f.formatInBackground("vol-2", "/dev/xvdbb", "xfs", nil, testFormatMinSize)
+	assert.Contains(t, err.Error(), "waiting for a free slot")
+	err = f.formatInBackground("vol-1", "/dev/xvdba", "xfs", nil, testFormatMinSize)
+	assert.Contains(t, err.Error(), "is being formatted as xfs")
+
+	release <- struct{}{}
+	assert.Equal(t, "/dev/xvdbb", <-started)
+	release <- struct{}{}
+	waitFormatDone(t, f, "vol-2")
+	assert.NoError(t, f.formatInBackground("vol-1", "/dev/xvdba", "xfs", nil, testFormatMinSize))
+	assert.NoError(t, f.formatInBackground("vol-2", "/dev/xvdbb", "xfs", nil, testFormatMinSize))
+}
+
+func TestFormatJobsUnstage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := mounter.NewMockMounter(ctrl)
+	m.EXPECT().GetBlockSizeBytes("/dev/xvdba").Return(16384*util.GiB, nil)
+	m.EXPECT().GetDiskFormat("/dev/xvdba").Return("", nil)
+	release := make(chan struct{})
+	m.EXPECT().FormatDevice("/dev/xvdba", "ext4", gomock.Any()).DoAndReturn(func(_, _ string, _ []string) error {
+		<-release
+		return nil
+	})
+
+	f := newFormatJobs(m, 1)
+	require.NoError(t, f.unstage("vol-test"))
+	err := f.formatInBackground("vol-test", "/dev/xvdba", "ext4", nil, testFormatMinSize)
+	assert.Equal(t, codes.Aborted, status.Code(err))
+
+	// The volume is not unstaged while it is formatted
+	err = f.unstage("vol-test")
+	assert.Equal(t, codes.Aborted, status.Code(err))
+	assert.Contains(t, err.Error(), "cannot be unstaged until the format is done")
+
+	release <- struct{}{}
+	waitFormatDone(t, f, "vol-test")
+	require.NoError(t, f.unstage("vol-test"))
+	assert.Empty(t, f.jobs)
+}
+
+func TestFormatJobsPrune(t *testing.T) {
+	f := newFormatJobs(nil, 1)
+	f.jobs["vol-old"] = &formatJob{source: "/dev/xvdba", done: true, finishedAt: time.Now().Add(-2 * formatJobRetention)}
+	f.jobs["vol-new"] = &formatJob{source: "/dev/xvdbb", done: true, finishedAt: time.Now()}
+	f.jobs["vol-running"] = &formatJob{source: "/dev/xvdbc", queuedAt: time.Now().Add(-2 * formatJobRetention)}
+
+	require.NoError(t, f.unstage("vol-other"))
+	assert.Len(t, f.jobs, 2)
+	assert.NotContains(t, f.jobs, "vol-old")
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// NodeConditionCachedMetadata is the type of the Node condition reporting whether the node
+	// plugin runs on instance metadata loaded from its cache file.
+	NodeConditionCachedMetadata corev1.NodeConditionType = "EBSCSIDriverCachedMetadata"
+
+	// cachedMetadataRefreshInterval is the interval between two attempts to refresh cached metadata
+	// from the metadata sources.
+	cachedMetadataRefreshInterval = time.Minute
+)
+
+// refreshCachedMetadata retries the metadata sources of cached metadata every interval until
+// one succeeds, and reports through a condition of the Node whether the node plugin runs on
+// cached metadata. It returns once the metadata is refreshed, or was not cached, and the
+// condition is cleared.
+func (d *NodeService) refreshCachedMetadata(k kubernetes.Interface, interval time.Duration) {
+	var reported *bool
+	for {
+		if d.metadata.IsCached() {
+			if err := d.metadata.UpdateMetadata(); err != nil {
+				klog.V(4).InfoS("Metadata sources are still unavailable", "err", err)
+			}
+		}
+
+		cached := d.metadata.IsCached()
+		if reported == nil || *reported != cached {
+			err := setCachedMetadataCondition(k, cached)
+			switch {
+			case apierrors.IsForbidden(err):
+				// Mutations of the Node are disabled, the condition is not reported
+				klog.V(4).InfoS("Not permitted to report cached metadata condition on node", "err", err)
+				reported = &cached
+			case err != nil:
+				klog.ErrorS(err, "Failed to report cached metadata condition on node")
+			default:
+				reported = &cached
+			}
+		}
+		if reported != nil && !*reported {
+			return
+		}
+		time.Sleep(interval)
+	}
+}
+
+// setCachedMetadataCondition sets the NodeConditionCachedMetadata condition of this node.
+func setCachedMetadataCondition(k kubernetes.Interface, cached bool) error {
+	nodeName := os.Getenv("CSI_NODE_NAME")
+	if nodeName == "" {
+		return errors.New("CSI_NODE_NAME env var not set")
+	}
+
+	now := metav1.Now()
+	condition := corev1.NodeCondition{
+		Type:               NodeConditionCachedMetadata,
+		Status:             corev1.ConditionFalse,
+		Reason:             "MetadataSourcesAvailable",
+		Message:            "The EBS CSI node plugin retrieved instance metadata from a metadata source",
+		LastHeartbeatTime:  now,
+		LastTransitionTime: now,
+	}
+	if cached {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = "MetadataSourcesUnavailable"
+		condition.Message = "The EBS CSI node plugin runs on instance metadata loaded from its cache file because no metadata source is available"
+	}
+	patch, err := json.Marshal(map[string]any{
+		"status": map[string]any{
+			"conditions": []corev1.NodeCondition{condition},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, err := k.CoreV1().Nodes().PatchStatus(ctx, nodeName, patch); err != nil {
+		return fmt.Errorf("failed to patch status of node %s: %w", nodeName, err)
+	}
+	return nil
+}
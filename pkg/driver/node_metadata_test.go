@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRefreshCachedMetadata(t *testing.T) {
+	t.Setenv("CSI_NODE_NAME", "test-node")
+	ctrl := gomock.NewController(t)
+	k := fake.NewClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}})
+
+	m := metadata.NewMockMetadataService(ctrl)
+	gomock.InOrder(
+		// First attempt fails, the node plugin keeps running on cached metadata
+		m.EXPECT().IsCached().Return(true),
+		m.EXPECT().UpdateMetadata().Return(errors.New("IMDS error")),
+		m.EXPECT().IsCached().Return(true),
+		// Second attempt succeeds
+		m.EXPECT().IsCached().Return(true),
+		m.EXPECT().UpdateMetadata().Return(nil),
+		m.EXPECT().IsCached().Return(false),
+	)
+
+	conditions := func() []corev1.NodeCondition {
+		node, err := k.CoreV1().Nodes().Get(context.Background(), "test-node", metav1.GetOptions{})
+		require.NoError(t, err)
+		return node.Status.Conditions
+	}
+
+	d := &NodeService{metadata: m}
+	done := make(chan struct{})
+	go func() {
+		d.refreshCachedMetadata(k, 100*time.Millisecond)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool {
+		c := conditions()
+		return len(c) == 1 && c[0].Status == corev1.ConditionTrue
+	}, time.Second, 10*time.Millisecond)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("refreshCachedMetadata did not return after the metadata was refreshed")
+	}
+	c := conditions()
+	require.Len(t, c, 1)
+	assert.Equal(t, NodeConditionCachedMetadata, c[0].Type)
+	assert.Equal(t, corev1.ConditionFalse, c[0].Status)
+	assert.Equal(t, "MetadataSourcesAvailable", c[0].Reason)
+}
+
+func TestRefreshCachedMetadataClearsStaleCondition(t *testing.T) {
+	t.Setenv("CSI_NODE_NAME", "test-node")
+	ctrl := gomock.NewController(t)
+	// The condition left by a previous node plugin that ran on cached metadata
+	k := fake.NewClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{
+			Type:   NodeConditionCachedMetadata,
+			Status: corev1.ConditionTrue,
+			Reason: "MetadataSourcesUnavailable",
+		}}},
+	})
+
+	m := metadata.NewMockMetadataService(ctrl)
+	m.EXPECT().IsCached().Return(false).Times(2)
+
+	d := &NodeService{metadata: m}
+	d.refreshCachedMetadata(k, time.Hour)
+
+	node, err := k.CoreV1().Nodes().Get(t.Context(), "test-node", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Len(t, node.Status.Conditions, 1)
+	assert.Equal(t, corev1.ConditionFalse, node.Status.Conditions[0].Status)
+	assert.Equal(t, "MetadataSourcesAvailable", node.Status.Conditions[0].Reason)
+}
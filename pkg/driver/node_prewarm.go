@@ -0,0 +1,151 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// Event reasons of volume pre-warms.
+	eventReasonPrewarmStarted   = "VolumePrewarmStarted"
+	eventReasonPrewarmCompleted = "VolumePrewarmCompleted"
+	eventReasonPrewarmFailed    = "VolumePrewarmFailed"
+
+	// prewarmProgressInterval is the minimum interval between two updates of the progress metric of a pre-warm.
+	prewarmProgressInterval = 10 * time.Second
+)
+
+// prewarmDurationBuckets covers pre-warms from a minute up to a day.
+var prewarmDurationBuckets = []float64{60, 300, 600, 1800, 3600, 7200, 14400, 28800, 86400}
+
+// prewarmJob is a pre-warm running in the background for a staged volume.
+type prewarmJob struct {
+	cancel context.CancelFunc
+	// done is closed when the pre-warm has stopped.
+	done chan struct{}
+}
+
+// prewarmJobs holds the running pre-warms of the node by volume ID.
+type prewarmJobs struct {
+	mu   sync.Mutex
+	jobs map[string]*prewarmJob
+}
+
+func newPrewarmJobs() *prewarmJobs {
+	return &prewarmJobs{jobs: make(map[string]*prewarmJob)}
+}
+
+// startPrewarm starts reading every block of the device source of volumeID, staged at target,
+// in the background, unless a pre-warm of the volume is already running.
+func (d *NodeService) startPrewarm(volumeID, source, target string) {
+	if d.prewarms == nil {
+		klog.InfoS("Volume pre-warming is only supported on Linux, skipping", "volumeID", volumeID)
+		return
+	}
+
+	d.prewarms.mu.Lock()
+	defer d.prewarms.mu.Unlock()
+	if _, ok := d.prewarms.jobs[volumeID]; ok {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	job := &prewarmJob{cancel: cancel, done: make(chan struct{})}
+	d.prewarms.jobs[volumeID] = job
+
+	pvName := stagingPVName(target)
+	go func() {
+		defer close(job.done)
+		d.prewarm(ctx, volumeID, source, pvName)
+		d.prewarms.mu.Lock()
+		delete(d.prewarms.jobs, volumeID)
+		d.prewarms.mu.Unlock()
+	}()
+}
+
+// stopPrewarm cancels the pre-warm of volumeID, if any, and waits for it to stop.
+func (d *NodeService) stopPrewarm(volumeID string) {
+	if d.prewarms == nil {
+		return
+	}
+	d.prewarms.mu.Lock()
+	job, ok := d.prewarms.jobs[volumeID]
+	d.prewarms.mu.Unlock()
+	if !ok {
+		return
+	}
+	klog.V(4).InfoS("Cancelling volume pre-warm", "volumeID", volumeID)
+	job.cancel()
+	<-job.done
+}
+
+func (d *NodeService) prewarm(ctx context.Context, volumeID, source, pvName string) {
+	labels := map[string]string{"volume_id": volumeID}
+	klog.InfoS("Pre-warming volume", "volumeID", volumeID, "source", source, "parallelism", d.options.PrewarmParallelism)
+	d.recordVolumeEvent(pvName, corev1.EventTypeNormal, eventReasonPrewarmStarted, fmt.Sprintf("Reading every block of volume %s in the background", volumeID))
+	metrics.Recorder().SetGauge(metrics.PrewarmProgress, metrics.PrewarmProgressHelpText, 0, labels)
+
+	start := time.Now()
+	var lastProgress atomic.Int64
+	err := d.mounter.PrewarmDevice(ctx, source, d.options.PrewarmParallelism, func(read, total int64) {
+		now := time.Now().UnixNano()
+		last := lastProgress.Load()
+		if now-last < int64(prewarmProgressInterval) || !lastProgress.CompareAndSwap(last, now) {
+			return
+		}
+		metrics.Recorder().SetGauge(metrics.PrewarmProgress, metrics.PrewarmProgressHelpText, float64(read)/float64(total), labels)
+	})
+	duration := time.Since(start)
+	metrics.Recorder().DeleteGauge(metrics.PrewarmProgress, labels)
+
+	var result string
+	switch {
+	case err == nil:
+		result = "completed"
+		klog.InfoS("Volume pre-warm completed", "volumeID", volumeID, "duration", duration)
+		d.recordVolumeEvent(pvName, corev1.EventTypeNormal, eventReasonPrewarmCompleted, fmt.Sprintf("Read every block of volume %s in %s", volumeID, duration.Round(time.Second)))
+	case errors.Is(err, context.Canceled):
+		result = "cancelled"
+		klog.InfoS("Volume pre-warm cancelled", "volumeID", volumeID, "duration", duration)
+	default:
+		result = "failed"
+		klog.ErrorS(err, "Volume pre-warm failed", "volumeID", volumeID, "duration", duration)
+		d.recordVolumeEvent(pvName, corev1.EventTypeWarning, eventReasonPrewarmFailed, fmt.Sprintf("Failed to read every block of volume %s: %v", volumeID, err))
+	}
+	metrics.Recorder().ObserveHistogram(metrics.PrewarmDuration, metrics.PrewarmDurationHelpText, duration.Seconds(), map[string]string{"result": result}, prewarmDurationBuckets)
+}
+
+// stagingPVName returns the name of the PersistentVolume staged at target, read from the volume
+// data kubelet writes next to it, or an empty string if it is unknown.
+func stagingPVName(target string) string {
+	vd, err := readVolData(filepath.Dir(target))
+	if err != nil {
+		klog.V(4).InfoS("Could not read volume data of staging target", "target", target, "err", err)
+		return ""
+	}
+	return vd.SpecVolID
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/mounter"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+)
+
+// waitPrewarmDone waits until no pre-warm is running.
+func waitPrewarmDone(t *testing.T, d *NodeService) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		d.prewarms.mu.Lock()
+		defer d.prewarms.mu.Unlock()
+		return len(d.prewarms.jobs) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestPrewarm(t *testing.T) {
+	testCases := []struct {
+		name      string
+		prewarmed error
+		events    []string
+	}{
+		{
+			name: "completed",
+			events: []string{
+				"Normal VolumePrewarmStarted Reading every block of volume vol-test in the background",
+				"Normal VolumePrewarmCompleted Read every block of volume vol-test in 0s",
+			},
+		},
+		{
+			name:      "failed",
+			prewarmed: errors.New("input/output error"),
+			events: []string{
+				"Normal VolumePrewarmStarted Reading every block of volume vol-test in the background",
+				"Warning VolumePrewarmFailed Failed to read every block of volume vol-test: input/output error",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			target := filepath.Join(t.TempDir(), "pv", "pv-test", "globalmount")
+			writeVolData(t, target, util.GetDriverName(), "vol-test", "pv-test")
+
+			ctrl := gomock.NewController(t)
+			m := mounter.NewMockMounter(ctrl)
+			m.EXPECT().PrewarmDevice(gomock.Any(), "/dev/nvme1n1", 4, gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ string, _ int, progress func(read, total int64)) error {
+					progress(1024, 1024)
+					return tc.prewarmed
+				})
+
+			recorder := record.NewFakeRecorder(10)
+			d := &NodeService{
+				mounter:  m,
+				options:  &Options{PrewarmParallelism: 4},
+				recorder: recorder,
+				prewarms: newPrewarmJobs(),
+			}
+			d.startPrewarm("vol-test", "/dev/nvme1n1", target)
+			waitPrewarmDone(t, d)
+
+			close(recorder.Events)
+			var events []string
+			for e := range recorder.Events {
+				events = append(events, e)
+			}
+			assert.Equal(t, tc.events, events)
+		})
+	}
+}
+
+func TestStopPrewarm(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := mounter.NewMockMounter(ctrl)
+	started := make(chan struct{})
+	m.EXPECT().PrewarmDevice(gomock.Any(), "/dev/nvme1n1", 4, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ string, _ int, _ func(read, total int64)) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+	d := &NodeService{
+		mounter:  m,
+		options:  &Options{PrewarmParallelism: 4},
+		prewarms: newPrewarmJobs(),
+	}
+	d.startPrewarm("vol-test", "/dev/nvme1n1", "/staging/path")
+	<-started
+	// A pre-warm of the volume is already running
+	d.startPrewarm("vol-test", "/dev/nvme1n1", "/staging/path")
+
+	d.stopPrewarm("vol-test")
+	assert.Empty(t, d.prewarms.jobs)
+	// Stopping a volume without pre-warm is a no-op
+	d.stopPrewarm("vol-other")
+}
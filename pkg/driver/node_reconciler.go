@@ -0,0 +1,260 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// volDataFileName is the file written by kubelet next to each CSI staging and publish target.
+	volDataFileName = "vol_data.json"
+
+	// Event reasons of the mount reconciler.
+	eventReasonStaleMountCleanedUp     = "StaleMountCleanedUp"
+	eventReasonStaleMountCleanupFailed = "StaleMountCleanupFailed"
+)
+
+// csiMount is a staging or publish target of this driver found in the mount table.
+type csiMount struct {
+	path     string
+	device   string
+	volumeID string
+	// pvName is the name of the PersistentVolume, if kubelet recorded it.
+	pvName  string
+	staging bool
+}
+
+// volData holds the fields of vol_data.json used by the reconciler.
+type volData struct {
+	DriverName   string `json:"driverName"`
+	VolumeHandle string `json:"volumeHandle"`
+	SpecVolID    string `json:"specVolID"`
+}
+
+// startMountReconciler reconciles the mounts of this driver in the background if enabled, so that
+// the node plugin serves requests while the mount table is inspected.
+func (d *NodeService) startMountReconciler() {
+	if !d.options.ReconcileMountsOnStartup {
+		return
+	}
+	if runtime.GOOS != "linux" {
+		klog.InfoS("Mount reconciliation is only supported on Linux, skipping")
+		return
+	}
+	go func() {
+		if err := d.reconcileMounts(context.Background()); err != nil {
+			klog.ErrorS(err, "Failed to reconcile mounts")
+		}
+	}()
+}
+
+// reconcileMounts unmounts the staging and publish targets of this driver that were left
+// behind in an unusable state, for example because the node plugin restarted or the node
+// rebooted while volumes were staged. Publish targets are handled before staging targets,
+// as they are bind mounts of the latter. It only cleans up: the mounts are not staged or
+// published again, kubelet does so when it retries NodeStageVolume and NodePublishVolume.
+func (d *NodeService) reconcileMounts(ctx context.Context) error {
+	mountPoints, err := d.mounter.List()
+	if err != nil {
+		return fmt.Errorf("failed to list mounts: %w", err)
+	}
+
+	var publishMounts, stagingMounts []csiMount
+	for _, mp := range mountPoints {
+		m, ok := parseCSIMount(mp.Path, mp.Device)
+		if !ok {
+			continue
+		}
+		if m.staging {
+			stagingMounts = append(stagingMounts, m)
+		} else {
+			publishMounts = append(publishMounts, m)
+		}
+	}
+	klog.V(4).InfoS("reconcileMounts: found mounts of this driver", "staging", len(stagingMounts), "publish", len(publishMounts))
+
+	for _, m := range append(publishMounts, stagingMounts...) {
+		reason := d.staleMountReason(ctx, m)
+		if reason == "" {
+			klog.V(5).InfoS("[Debug] reconcileMounts: mount is healthy", "path", m.path, "volumeID", m.volumeID)
+			continue
+		}
+
+		// The node plugin is already serving requests, skip the volumes kubelet is operating on
+		if ok := d.inFlight.Insert(m.volumeID); !ok {
+			klog.InfoS("reconcileMounts: skipping stale mount of a volume with an operation in progress", "path", m.path, "volumeID", m.volumeID, "reason", reason)
+			continue
+		}
+		klog.InfoS("reconcileMounts: cleaning up stale mount", "path", m.path, "device", m.device, "volumeID", m.volumeID, "reason", reason)
+		if m.staging {
+			err = d.mounter.Unstage(m.path)
+		} else {
+			err = d.mounter.Unpublish(m.path)
+		}
+		d.inFlight.Delete(m.volumeID)
+		if err != nil {
+			klog.ErrorS(err, "reconcileMounts: failed to clean up stale mount", "path", m.path, "volumeID", m.volumeID)
+			d.recordMountEvent(m, corev1.EventTypeWarning, eventReasonStaleMountCleanupFailed,
+				fmt.Sprintf("Failed to unmount stale %s of volume %s at %s (%s): %v", m.kind(), m.volumeID, m.path, reason, err))
+			continue
+		}
+		d.recordMountEvent(m, corev1.EventTypeNormal, eventReasonStaleMountCleanedUp,
+			fmt.Sprintf("Unmounted stale %s of volume %s at %s: %s", m.kind(), m.volumeID, m.path, reason))
+	}
+
+	return nil
+}
+
+// staleMountReason returns why m is stale, or an empty string if it is healthy.
+func (d *NodeService) staleMountReason(ctx context.Context, m csiMount) string {
+	if _, err := os.Stat(m.path); err != nil && d.mounter.IsCorruptedMnt(err) {
+		return fmt.Sprintf("mount point is corrupted: %v", err)
+	}
+	if _, err := os.Stat(m.device); errors.Is(err, os.ErrNotExist) {
+		return fmt.Sprintf("device %s no longer exists", m.device)
+	}
+
+	// Node-local volumes have no volume ID to verify the device against, and the handles of
+	// ephemeral inline volumes are generated by kubelet rather than being EBS volume IDs
+	if isNodeLocalVolume(m.volumeID) || isEphemeralVolumeHandle(m.volumeID) || d.metadata == nil {
+		return ""
+	}
+
+	devicePath, err := d.mounter.FindDevicePath(ctx, m.device, m.volumeID, "", d.metadata.GetRegion())
+	if err != nil {
+		return fmt.Sprintf("device %s does not belong to the volume: %v", m.device, err)
+	}
+	if !isDeviceOrPartition(canonicalPath(m.device), canonicalPath(devicePath)) {
+		return fmt.Sprintf("device %s does not belong to the volume, which is attached as %s", m.device, devicePath)
+	}
+	return ""
+}
+
+func (d *NodeService) recordMountEvent(m csiMount, eventType, reason, message string) {
+	d.recordVolumeEvent(m.pvName, eventType, reason, message)
+}
+
+// recordVolumeEvent emits an event on the PersistentVolume pvName, or on the Node object of
+// this node if pvName is empty.
+func (d *NodeService) recordVolumeEvent(pvName, eventType, reason, message string) {
+	if d.recorder == nil {
+		return
+	}
+	if pvName == "" {
+		d.recordNodeEvent(eventType, reason, message)
+		return
+	}
+	ref := &corev1.ObjectReference{Kind: "PersistentVolume", APIVersion: "v1", Name: pvName}
+	d.recorder.Event(ref, eventType, reason, message)
+}
+
+func (m csiMount) kind() string {
+	if m.staging {
+		return "staging target"
+	}
+	return "publish target"
+}
+
+// parseCSIMount returns the csiMount of the mount point at path if it is a staging target
+// (.../plugins/kubernetes.io/csi/.../globalmount) or a filesystem publish target
+// (.../pods/<pod UID>/volumes/kubernetes.io~csi/<PV>/mount) of this driver.
+// The publish targets of block volumes (.../plugins/kubernetes.io/csi/volumeDevices/publish/...)
+// are not returned: they are bind mounts of device nodes, which the mount table reports as
+// devtmpfs rather than as the device of the volume, so they cannot be verified.
+func parseCSIMount(path, device string) (csiMount, bool) {
+	var staging bool
+	switch {
+	case filepath.Base(path) == "globalmount" && strings.Contains(path, "/plugins/kubernetes.io/csi/"):
+		staging = true
+	case filepath.Base(path) == "mount" && strings.Contains(path, "/volumes/kubernetes.io~csi/"):
+		staging = false
+	default:
+		return csiMount{}, false
+	}
+
+	vd, err := readVolData(filepath.Dir(path))
+	if err != nil {
+		klog.V(4).InfoS("parseCSIMount: could not read volume data", "path", path, "err", err)
+		return csiMount{}, false
+	}
+	if vd.DriverName != util.GetDriverName() || vd.VolumeHandle == "" {
+		return csiMount{}, false
+	}
+
+	return csiMount{
+		path:     path,
+		device:   device,
+		volumeID: vd.VolumeHandle,
+		pvName:   vd.SpecVolID,
+		staging:  staging,
+	}, true
+}
+
+// readVolData reads the vol_data.json file kubelet writes in dir.
+func readVolData(dir string) (volData, error) {
+	var vd volData
+	data, err := os.ReadFile(filepath.Join(dir, volDataFileName))
+	if err != nil {
+		return vd, err
+	}
+	if err := json.Unmarshal(data, &vd); err != nil {
+		return vd, fmt.Errorf("failed to parse %s: %w", volDataFileName, err)
+	}
+	return vd, nil
+}
+
+// isDeviceOrPartition returns true if path is disk, or a partition of disk
+// (e.g. /dev/nvme1n1p1 of /dev/nvme1n1, or /dev/xvdba1 of /dev/xvdba).
+func isDeviceOrPartition(path, disk string) bool {
+	suffix, ok := strings.CutPrefix(path, disk)
+	if !ok {
+		return false
+	}
+	if suffix == "" {
+		return true
+	}
+	// Partitions of disks whose name ends with a digit have a "p" separator
+	if disk != "" && disk[len(disk)-1] >= '0' && disk[len(disk)-1] <= '9' {
+		if suffix, ok = strings.CutPrefix(suffix, "p"); !ok {
+			return false
+		}
+	}
+	_, err := strconv.Atoi(suffix)
+	return err == nil
+}
+
+// canonicalPath resolves the symlinks of path, or returns path as is if they cannot be resolved.
+func canonicalPath(path string) string {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return path
+	}
+	return resolved
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud/metadata"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/driver/internal"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/mounter"
+	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/record"
+	mountutils "k8s.io/mount-utils"
+)
+
+// writeVolData creates the directory of target and the vol_data.json kubelet writes next to it.
+func writeVolData(t *testing.T, target, driverName, volumeHandle, pvName string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(target, 0o755))
+	data := `{"driverName":"` + driverName + `","volumeHandle":"` + volumeHandle + `","specVolID":"` + pvName + `"}`
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(target), volDataFileName), []byte(data), 0o600))
+}
+
+func TestReconcileMounts(t *testing.T) {
+	kubelet := t.TempDir()
+	devices := t.TempDir()
+	driverName := util.GetDriverName()
+
+	// A healthy volume, staged and published
+	healthyDevice := filepath.Join(devices, "nvme1n1")
+	require.NoError(t, os.WriteFile(healthyDevice, nil, 0o600))
+	healthyStaging := filepath.Join(kubelet, "plugins/kubernetes.io/csi", driverName, "1111", "globalmount")
+	writeVolData(t, healthyStaging, driverName, "vol-healthy", "")
+	healthyPublish := filepath.Join(kubelet, "pods/pod-1/volumes/kubernetes.io~csi/pv-healthy/mount")
+	writeVolData(t, healthyPublish, driverName, "vol-healthy", "pv-healthy")
+
+	// A volume whose device disappeared after a reboot
+	goneDevice := filepath.Join(devices, "nvme2n1")
+	goneStaging := filepath.Join(kubelet, "plugins/kubernetes.io/csi", driverName, "2222", "globalmount")
+	writeVolData(t, goneStaging, driverName, "vol-gone", "")
+	gonePublish := filepath.Join(kubelet, "pods/pod-2/volumes/kubernetes.io~csi/pv-gone/mount")
+	writeVolData(t, gonePublish, driverName, "vol-gone", "pv-gone")
+
+	// A volume whose device name now belongs to another volume
+	changedDevice := filepath.Join(devices, "nvme3n1")
+	require.NoError(t, os.WriteFile(changedDevice, nil, 0o600))
+	changedStaging := filepath.Join(kubelet, "plugins/kubernetes.io/csi", driverName, "3333", "globalmount")
+	writeVolData(t, changedStaging, driverName, "vol-changed", "pv-changed")
+
+	// An ephemeral inline volume, whose handle is generated by kubelet
+	ephemeralDevice := filepath.Join(devices, "nvme5n1")
+	require.NoError(t, os.WriteFile(ephemeralDevice, nil, 0o600))
+	ephemeralHandle := EphemeralVolumeHandlePrefix + "0b9f7a5e6c2d4a8b9e1f123456789abc"
+	ephemeralPublish := filepath.Join(kubelet, "pods/pod-5/volumes/kubernetes.io~csi/scratch/mount")
+	writeVolData(t, ephemeralPublish, driverName, ephemeralHandle, "")
+
+	// A block volume, whose publish target is a bind mount of its device node
+	blockPublish := filepath.Join(kubelet, "plugins/kubernetes.io/csi/volumeDevices/publish/pv-block/pod-6")
+	writeVolData(t, blockPublish, driverName, "vol-block", "pv-block")
+
+	// A volume of another driver
+	otherStaging := filepath.Join(kubelet, "plugins/kubernetes.io/csi/other.csi.k8s.io/4444/globalmount")
+	writeVolData(t, otherStaging, "other.csi.k8s.io", "other-volume", "")
+
+	ctrl := gomock.NewController(t)
+	m := mounter.NewMockMounter(ctrl)
+	m.EXPECT().List().Return([]mountutils.MountPoint{
+		{Device: "/dev/root", Path: "/"},
+		{Device: healthyDevice, Path: healthyStaging},
+		{Device: healthyDevice, Path: healthyPublish},
+		{Device: goneDevice, Path: goneStaging},
+		{Device: goneDevice, Path: gonePublish},
+		{Device: changedDevice, Path: changedStaging},
+		{Device: ephemeralDevice, Path: ephemeralPublish},
+		{Device: "devtmpfs", Path: blockPublish},
+		{Device: "/dev/nvme4n1", Path: otherStaging},
+	}, nil)
+	m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq(healthyDevice), gomock.Eq("vol-healthy"), gomock.Eq(""), gomock.Eq("us-west-2")).Return(healthyDevice, nil).Times(2)
+	m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq(changedDevice), gomock.Eq("vol-changed"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("", errors.New("nvme device serial does not match"))
+
+	// Publish targets must be cleaned up before staging targets
+	gomock.InOrder(
+		m.EXPECT().Unpublish(gomock.Eq(gonePublish)).Return(nil),
+		m.EXPECT().Unstage(gomock.Eq(goneStaging)).Return(nil),
+	)
+	m.EXPECT().Unstage(gomock.Eq(changedStaging)).Return(errors.New("device busy"))
+
+	md := metadata.NewMockMetadataService(ctrl)
+	md.EXPECT().GetRegion().Return("us-west-2").AnyTimes()
+
+	recorder := record.NewFakeRecorder(10)
+	d := &NodeService{
+		metadata: md,
+		mounter:  m,
+		inFlight: internal.NewInFlight(),
+		options:  &Options{},
+		recorder: recorder,
+	}
+
+	require.NoError(t, d.reconcileMounts(t.Context()))
+
+	close(recorder.Events)
+	var events []string
+	for e := range recorder.Events {
+		events = append(events, e)
+	}
+	require.Len(t, events, 3)
+	assert.Contains(t, events[0], "Normal StaleMountCleanedUp Unmounted stale publish target of volume vol-gone")
+	assert.Contains(t, events[1], "Normal StaleMountCleanedUp Unmounted stale staging target of volume vol-gone")
+	assert.Contains(t, events[2], "Warning StaleMountCleanupFailed Failed to unmount stale staging target of volume vol-changed")
+}
+
+func TestReconcileMountsListError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	m := mounter.NewMockMounter(ctrl)
+	m.EXPECT().List().Return(nil, errors.New("failed to read mountinfo"))
+
+	d := &NodeService{mounter: m, options: &Options{}}
+	require.Error(t, d.reconcileMounts(t.Context()))
+}
+
+func TestIsDeviceOrPartition(t *testing.T) {
+	testCases := []struct {
+		path string
+		disk string
+		want bool
+	}{
+		{path: "/dev/nvme1n1", disk: "/dev/nvme1n1", want: true},
+		{path: "/dev/nvme1n1p1", disk: "/dev/nvme1n1", want: true},
+		{path: "/dev/xvdba1", disk: "/dev/xvdba", want: true},
+		{path: "/dev/nvme1n10", disk: "/dev/nvme1n1", want: false},
+		{path: "/dev/nvme2n1", disk: "/dev/nvme1n1", want: false},
+		{path: "/dev/xvdbab", disk: "/dev/xvdba", want: false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.want, isDeviceOrPartition(tc.path, tc.disk), "isDeviceOrPartition(%q, %q)", tc.path, tc.disk)
+	}
+}
@@ -51,10 +51,12 @@ func TestNewNodeService(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockMetadataService := metadata.NewMockMetadataService(ctrl)
+	mockMetadataService.EXPECT().IsCached().Return(false).AnyTimes()
 	mockMounter := mounter.NewMockMounter(ctrl)
-	fakeClient := fake.NewClientset()
+	fakeClient := fake.NewClientset(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-node"}})
 
 	t.Setenv("AWS_REGION", "us-west-2")
+	t.Setenv("CSI_NODE_NAME", "test-node")
 
 	options := &Options{}
 
@@ -75,6 +77,20 @@ func TestNewNodeService(t *testing.T) {
 	if nodeService.options != options {
 		t.Error("Expected NodeService.options to be set to the provided options")
 	}
+
+	// The cached metadata condition is reconciled at startup
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(10 * time.Millisecond) {
+		node, err := fakeClient.CoreV1().Nodes().Get(t.Context(), "test-node", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Failed to get node: %v", err)
+		}
+		if len(node.Status.Conditions) == 1 && node.Status.Conditions[0].Status == corev1.ConditionFalse {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the cached metadata condition to be false, got %v", node.Status.Conditions)
+		}
+	}
 }
 
 func TestNodeStageVolume(t *testing.T) {
@@ -106,7 +122,7 @@ func TestNodeStageVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/staging/path")).Return(true, nil)
 				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("", 1, nil)
 				m.EXPECT().FormatAndMountSensitiveWithFormatOptions(gomock.Eq("/dev/xvdba"), gomock.Eq("/staging/path"), gomock.Eq("ext4"), gomock.Nil(), gomock.Nil(), gomock.Eq([]string{})).Return(nil)
@@ -262,7 +278,7 @@ func TestNodeStageVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/staging/path")).Return(false, nil)
 				m.EXPECT().MakeDir(gomock.Eq("/staging/path")).Return(nil)
 				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("", 0, nil)
@@ -565,7 +581,7 @@ func TestNodeStageVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq("1"), gomock.Eq("us-west-2")).Return("/dev/xvdba1", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq("1"), gomock.Eq("us-west-2")).Return("/dev/xvdba1", nil)
 				m.EXPECT().PathExists(gomock.Eq("/staging/path")).Return(true, nil)
 				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("", 1, nil)
 				m.EXPECT().FormatAndMountSensitiveWithFormatOptions(gomock.Eq("/dev/xvdba1"), gomock.Eq("/staging/path"), gomock.Eq("ext4"), gomock.Nil(), gomock.Nil(), gomock.Eq([]string{})).Return(nil)
@@ -603,7 +619,7 @@ func TestNodeStageVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/staging/path")).Return(true, nil)
 				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("", 1, nil)
 				m.EXPECT().FormatAndMountSensitiveWithFormatOptions(gomock.Eq("/dev/xvdba"), gomock.Eq("/staging/path"), gomock.Eq("ext4"), gomock.Nil(), gomock.Nil(), gomock.Eq([]string{})).Return(nil)
@@ -638,7 +654,7 @@ func TestNodeStageVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("", errors.New("find device path error"))
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("", errors.New("find device path error"))
 				return m
 			},
 			metadataMock: func(ctrl *gomock.Controller) *metadata.MockMetadataService {
@@ -669,7 +685,7 @@ func TestNodeStageVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/staging/path")).Return(false, errors.New("path exists error"))
 				return m
 			},
@@ -701,7 +717,7 @@ func TestNodeStageVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/staging/path")).Return(false, nil)
 				m.EXPECT().MakeDir(gomock.Eq("/staging/path")).Return(errors.New("make dir error"))
 				return m
@@ -734,7 +750,7 @@ func TestNodeStageVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/staging/path")).Return(true, nil)
 				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("", 0, errors.New("get device name error"))
 				return m
@@ -767,7 +783,7 @@ func TestNodeStageVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/staging/path")).Return(true, nil)
 				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("/dev/xvdba", 1, nil)
 				return m
@@ -800,7 +816,7 @@ func TestNodeStageVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/staging/path")).Return(true, nil)
 				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("", 1, nil)
 				m.EXPECT().FormatAndMountSensitiveWithFormatOptions(gomock.Eq("/dev/xvdba"), gomock.Eq("/staging/path"), gomock.Eq("ext4"), gomock.Nil(), gomock.Nil(), gomock.Eq([]string{})).Return(errors.New("format and mount error"))
@@ -834,7 +850,7 @@ func TestNodeStageVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/staging/path")).Return(true, nil)
 				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("", 1, nil)
 				m.EXPECT().FormatAndMountSensitiveWithFormatOptions(gomock.Eq("/dev/xvdba"), gomock.Eq("/staging/path"), gomock.Eq("ext4"), gomock.Nil(), gomock.Nil(), gomock.Eq([]string{})).Return(nil)
@@ -869,7 +885,7 @@ func TestNodeStageVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/staging/path")).Return(true, nil)
 				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("", 1, nil)
 				m.EXPECT().FormatAndMountSensitiveWithFormatOptions(gomock.Eq("/dev/xvdba"), gomock.Eq("/staging/path"), gomock.Eq("ext4"), gomock.Nil(), gomock.Nil(), gomock.Eq([]string{})).Return(nil)
@@ -913,7 +929,7 @@ func TestNodeStageVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/staging/path")).Return(true, nil)
 				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("", 1, nil)
 				m.EXPECT().FormatAndMountSensitiveWithFormatOptions(gomock.Eq("/dev/xvdba"), gomock.Eq("/staging/path"), gomock.Eq("ext4"), gomock.Nil(), gomock.Nil(), gomock.Eq([]string{"-b", "4096", "-I", "512", "-i", "16384", "-N", "1000000", "-O", "bigalloc", "-C", "65536"})).Return(nil)
@@ -951,7 +967,7 @@ func TestNodeStageVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/staging/path")).Return(true, nil)
 				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("", 1, nil)
 				m.EXPECT().FormatAndMountSensitiveWithFormatOptions(gomock.Eq("/dev/xvdba"), gomock.Eq("/staging/path"), gomock.Eq("ext4"), gomock.Eq([]string(nil)), gomock.Eq([]string(nil)), gomock.Eq([]string{"-O", "encrypt"})).Return(nil)
@@ -990,7 +1006,7 @@ func TestNodeStageVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/staging/path")).Return(true, nil)
 				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("", 1, nil)
 				m.EXPECT().FormatAndMountSensitiveWithFormatOptions(gomock.Eq("/dev/xvdba"), gomock.Eq("/staging/path"), gomock.Eq("xfs"), gomock.Eq([]string{"nouuid"}), gomock.Nil(), gomock.Eq([]string{"-b", "size=4096", "-i", "size=512"})).Return(nil)
@@ -1028,7 +1044,7 @@ func TestNodeStageVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/staging/path")).Return(true, nil)
 				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("", 1, nil)
 				m.EXPECT().FormatAndMountSensitiveWithFormatOptions(gomock.Eq("/dev/xvdba"), gomock.Eq("/staging/path"), gomock.Eq("xfs"), gomock.Eq([]string{"nouuid"}), gomock.Nil(), gomock.Eq([]string{"-i", "size=512", "-m", "bigtime=0,inobtcount=0,reflink=0", "-i", "nrext64=0"})).Return(nil)
@@ -1065,7 +1081,7 @@ func TestNodeStageVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-real"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-real"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/staging/path")).Return(true, nil)
 				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("", 1, nil)
 				m.EXPECT().FormatAndMountSensitiveWithFormatOptions(gomock.Eq("/dev/xvdba"), gomock.Eq("/staging/path"), gomock.Eq("ext4"), gomock.Nil(), gomock.Nil(), gomock.Eq([]string{})).Return(nil)
@@ -1079,6 +1095,36 @@ func TestNodeStageVolume(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "instance_store_volume_success",
+			req: &csi.NodeStageVolumeRequest{
+				VolumeId:          "local-ebs://instance-store/raid0",
+				StagingTargetPath: "/staging/path",
+				VolumeCapability: &csi.VolumeCapability{
+					AccessType: &csi.VolumeCapability_Mount{
+						Mount: &csi.VolumeCapability_MountVolume{
+							FsType: "xfs",
+						},
+					},
+					AccessMode: &csi.VolumeCapability_AccessMode{
+						Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+					},
+				},
+				PublishContext: map[string]string{
+					DevicePathKey: "instance-store/raid0",
+				},
+			},
+			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
+				m := mounter.NewMockMounter(ctrl)
+				m.EXPECT().FindInstanceStoreDevice(gomock.Eq(mounter.InstanceStoreRAID0)).Return("/dev/md127", nil)
+				m.EXPECT().PathExists(gomock.Eq("/staging/path")).Return(true, nil)
+				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("", 1, nil)
+				m.EXPECT().FormatAndMountSensitiveWithFormatOptions(gomock.Eq("/dev/md127"), gomock.Eq("/staging/path"), gomock.Eq("xfs"), gomock.Eq([]string{"nouuid"}), gomock.Nil(), gomock.Eq([]string{})).Return(nil)
+				m.EXPECT().NeedResize(gomock.Eq("/dev/md127"), gomock.Eq("/staging/path")).Return(false, nil)
+				return m
+			},
+			expectedErr: nil,
+		},
 		{
 			name: "node_local_volume_unsupported_capability",
 			req: &csi.NodeStageVolumeRequest{
@@ -1469,7 +1515,7 @@ func TestNodePublishVolume(t *testing.T) {
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
 
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/target")).Return(true, nil)
 				m.EXPECT().MakeFile(gomock.Eq("/target/path")).Return(nil)
 				m.EXPECT().IsLikelyNotMountPoint(gomock.Eq("/target/path")).Return(true, nil)
@@ -1508,6 +1554,82 @@ func TestNodePublishVolume(t *testing.T) {
 				return m
 			},
 		},
+		{
+			name: "success_fs_io_limits",
+			req: &csi.NodePublishVolumeRequest{
+				VolumeId:          "vol-test",
+				StagingTargetPath: "/staging/path",
+				TargetPath:        "/target/path",
+				VolumeCapability: &csi.VolumeCapability{
+					AccessType: &csi.VolumeCapability_Mount{
+						Mount: &csi.VolumeCapability_MountVolume{},
+					},
+					AccessMode: &csi.VolumeCapability_AccessMode{
+						Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+					},
+				},
+				VolumeContext: map[string]string{
+					ReadIOPSLimitKey:       "1000",
+					WriteBandwidthLimitKey: "100Mi",
+				},
+			},
+			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
+				m := mounter.NewMockMounter(ctrl)
+				m.EXPECT().PreparePublishTarget(gomock.Eq("/target/path")).Return(nil)
+				m.EXPECT().IsLikelyNotMountPoint(gomock.Eq("/target/path")).Return(true, nil)
+				m.EXPECT().Mount(gomock.Eq("/staging/path"), gomock.Eq("/target/path"), gomock.Eq("ext4"), gomock.Eq([]string{"bind"})).Return(nil)
+				m.EXPECT().SetIOLimits(gomock.Eq("/target/path"), gomock.Eq(mounter.IOLimits{ReadIOPS: 1000, WriteBPS: 100 * 1024 * 1024})).Return(nil)
+				return m
+			},
+		},
+		{
+			name: "set_io_limits_failed",
+			req: &csi.NodePublishVolumeRequest{
+				VolumeId:          "vol-test",
+				StagingTargetPath: "/staging/path",
+				TargetPath:        "/target/path",
+				VolumeCapability: &csi.VolumeCapability{
+					AccessType: &csi.VolumeCapability_Mount{
+						Mount: &csi.VolumeCapability_MountVolume{},
+					},
+					AccessMode: &csi.VolumeCapability_AccessMode{
+						Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+					},
+				},
+				VolumeContext: map[string]string{
+					WriteIOPSLimitKey: "500",
+				},
+			},
+			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
+				m := mounter.NewMockMounter(ctrl)
+				m.EXPECT().PreparePublishTarget(gomock.Eq("/target/path")).Return(nil)
+				m.EXPECT().IsLikelyNotMountPoint(gomock.Eq("/target/path")).Return(true, nil)
+				m.EXPECT().Mount(gomock.Eq("/staging/path"), gomock.Eq("/target/path"), gomock.Eq("ext4"), gomock.Eq([]string{"bind"})).Return(nil)
+				m.EXPECT().SetIOLimits(gomock.Eq("/target/path"), gomock.Eq(mounter.IOLimits{WriteIOPS: 500})).Return(errors.New("cgroup not found"))
+				return m
+			},
+			expectedErr: status.Errorf(codes.Internal, "Could not set I/O limits of %q: %v", "/target/path", errors.New("cgroup not found")),
+		},
+		{
+			name: "invalid_io_limits",
+			req: &csi.NodePublishVolumeRequest{
+				VolumeId:          "vol-test",
+				StagingTargetPath: "/staging/path",
+				TargetPath:        "/target/path",
+				VolumeCapability: &csi.VolumeCapability{
+					AccessType: &csi.VolumeCapability_Mount{
+						Mount: &csi.VolumeCapability_MountVolume{},
+					},
+					AccessMode: &csi.VolumeCapability_AccessMode{
+						Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER,
+					},
+				},
+				VolumeContext: map[string]string{
+					ReadIOPSLimitKey: "fast",
+				},
+			},
+			expectedErr: status.Error(codes.InvalidArgument, `Invalid I/O limits: could not parse readiopslimit "fast": strconv.ParseUint: parsing "fast": invalid syntax`),
+		},
 		{
 			name: "volume_id_not_provided",
 			req: &csi.NodePublishVolumeRequest{
@@ -1640,7 +1762,7 @@ func TestNodePublishVolume(t *testing.T) {
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
 
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/target")).Return(true, nil)
 				m.EXPECT().MakeFile(gomock.Eq("/target/path")).Return(nil)
 				m.EXPECT().IsLikelyNotMountPoint(gomock.Eq("/target/path")).Return(true, nil)
@@ -1717,7 +1839,7 @@ func TestNodePublishVolume(t *testing.T) {
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
 
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/target")).Return(true, nil)
 				m.EXPECT().MakeFile(gomock.Eq("/target/path")).Return(nil)
 				m.EXPECT().IsLikelyNotMountPoint(gomock.Eq("/target/path")).Return(true, nil)
@@ -1754,7 +1876,7 @@ func TestNodePublishVolume(t *testing.T) {
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
 
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq("1"), gomock.Eq("us-west-2")).Return("/dev/xvdba1", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq("1"), gomock.Eq("us-west-2")).Return("/dev/xvdba1", nil)
 				m.EXPECT().PathExists(gomock.Eq("/target")).Return(true, nil)
 				m.EXPECT().MakeFile(gomock.Eq("/target/path")).Return(nil)
 				m.EXPECT().IsLikelyNotMountPoint(gomock.Eq("/target/path")).Return(true, nil)
@@ -1788,7 +1910,7 @@ func TestNodePublishVolume(t *testing.T) {
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
 
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("", errors.New("device path error"))
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("", errors.New("device path error"))
 				return m
 			},
 			metadataMock: func(ctrl *gomock.Controller) *metadata.MockMetadataService {
@@ -1819,7 +1941,7 @@ func TestNodePublishVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
-				m.EXPECT().FindDevicePath(gomock.Eq("/dev/xvdba"), gomock.Eq("vol-real"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/xvdba"), gomock.Eq("vol-real"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
 				m.EXPECT().PathExists(gomock.Eq("/target")).Return(true, nil)
 				m.EXPECT().MakeFile(gomock.Eq("/target/path")).Return(nil)
 				m.EXPECT().IsLikelyNotMountPoint(gomock.Eq("/target/path")).Return(true, nil)
@@ -1891,6 +2013,7 @@ func TestNodeUnstageVolume(t *testing.T) {
 		name        string
 		req         *csi.NodeUnstageVolumeRequest
 		mounterMock func(ctrl *gomock.Controller) *mounter.MockMounter
+		options     *Options
 		expectedErr error
 		inflight    bool
 	}{
@@ -1935,6 +2058,93 @@ func TestNodeUnstageVolume(t *testing.T) {
 			},
 			expectedErr: status.Errorf(codes.Internal, "Could not unmount target %q: %v", "/staging/path", errors.New("unstage failed")),
 		},
+		{
+			name: "unstage_busy",
+			req: &csi.NodeUnstageVolumeRequest{
+				VolumeId:          "vol-test",
+				StagingTargetPath: "/staging/path",
+			},
+			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
+				m := mounter.NewMockMounter(ctrl)
+				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("dev-test", 1, nil)
+				m.EXPECT().Unstage(gomock.Eq("/staging/path")).Return(errors.New("umount: /staging/path: target is busy."))
+				m.EXPECT().FindMountHolders(gomock.Eq("/staging/path")).Return([]mounter.MountHolder{
+					{PID: 100, Command: "postgres", ContainerID: "4f1d2c3b4a5968778695a4b3c2d1e0f9", Reason: mounter.HolderReasonOpenFile, Writer: true},
+					{PID: 200, Command: "bash", Reason: mounter.HolderReasonCwd},
+				}, nil)
+				return m
+			},
+			options:     &Options{LazyUnmountWithoutWriters: true},
+			expectedErr: status.Errorf(codes.Internal, "Could not unmount target %q: %v; held by: %s", "/staging/path", errors.New("umount: /staging/path: target is busy."), "pid 100 (postgres) in container 4f1d2c3b4a596 via open file (writer), pid 200 (bash) via working directory"),
+		},
+		{
+			name: "unstage_busy_no_holders_found",
+			req: &csi.NodeUnstageVolumeRequest{
+				VolumeId:          "vol-test",
+				StagingTargetPath: "/staging/path",
+			},
+			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
+				m := mounter.NewMockMounter(ctrl)
+				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("dev-test", 1, nil)
+				m.EXPECT().Unstage(gomock.Eq("/staging/path")).Return(errors.New("umount: /staging/path: target is busy."))
+				m.EXPECT().FindMountHolders(gomock.Eq("/staging/path")).Return(nil, nil)
+				return m
+			},
+			expectedErr: status.Errorf(codes.Internal, "Could not unmount target %q: %v; held by: %s", "/staging/path", errors.New("umount: /staging/path: target is busy."), "no process found (is the node plugin running with hostPID?)"),
+		},
+		{
+			name: "unstage_busy_find_holders_failed",
+			req: &csi.NodeUnstageVolumeRequest{
+				VolumeId:          "vol-test",
+				StagingTargetPath: "/staging/path",
+			},
+			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
+				m := mounter.NewMockMounter(ctrl)
+				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("dev-test", 1, nil)
+				m.EXPECT().Unstage(gomock.Eq("/staging/path")).Return(errors.New("umount: /staging/path: target is busy."))
+				m.EXPECT().FindMountHolders(gomock.Eq("/staging/path")).Return(nil, errors.New("failed to list processes"))
+				return m
+			},
+			options:     &Options{LazyUnmountWithoutWriters: true},
+			expectedErr: status.Errorf(codes.Internal, "Could not unmount target %q: %v", "/staging/path", errors.New("umount: /staging/path: target is busy.")),
+		},
+		{
+			name: "unstage_busy_lazy_unmount",
+			req: &csi.NodeUnstageVolumeRequest{
+				VolumeId:          "vol-test",
+				StagingTargetPath: "/staging/path",
+			},
+			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
+				m := mounter.NewMockMounter(ctrl)
+				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("dev-test", 1, nil)
+				m.EXPECT().Unstage(gomock.Eq("/staging/path")).Return(errors.New("umount: /staging/path: target is busy."))
+				m.EXPECT().FindMountHolders(gomock.Eq("/staging/path")).Return([]mounter.MountHolder{
+					{PID: 300, Command: "sleep", Reason: mounter.HolderReasonMountNamespace},
+				}, nil)
+				m.EXPECT().LazyUnmount(gomock.Eq("/staging/path")).Return(nil)
+				return m
+			},
+			options: &Options{LazyUnmountWithoutWriters: true},
+		},
+		{
+			name: "unstage_busy_lazy_unmount_failed",
+			req: &csi.NodeUnstageVolumeRequest{
+				VolumeId:          "vol-test",
+				StagingTargetPath: "/staging/path",
+			},
+			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
+				m := mounter.NewMockMounter(ctrl)
+				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/staging/path")).Return("dev-test", 1, nil)
+				m.EXPECT().Unstage(gomock.Eq("/staging/path")).Return(errors.New("umount: /staging/path: target is busy."))
+				m.EXPECT().FindMountHolders(gomock.Eq("/staging/path")).Return([]mounter.MountHolder{
+					{PID: 300, Command: "sleep", Reason: mounter.HolderReasonMountNamespace},
+				}, nil)
+				m.EXPECT().LazyUnmount(gomock.Eq("/staging/path")).Return(errors.New("permission denied"))
+				return m
+			},
+			options:     &Options{LazyUnmountWithoutWriters: true},
+			expectedErr: status.Errorf(codes.Internal, "Could not unmount target %q: %v; held by: %s", "/staging/path", errors.New("umount: /staging/path: target is busy."), "pid 300 (sleep) via mount namespace"),
+		},
 		{
 			name: "target_not_mounted",
 			req: &csi.NodeUnstageVolumeRequest{
@@ -1994,9 +2204,15 @@ func TestNodeUnstageVolume(t *testing.T) {
 				mounter = tc.mounterMock(ctrl)
 			}
 
+			options := tc.options
+			if options == nil {
+				options = &Options{}
+			}
+
 			driver := &NodeService{
 				mounter:  mounter,
 				inFlight: internal.NewInFlight(),
+				options:  options,
 			}
 
 			if tc.inflight {
@@ -2183,6 +2399,20 @@ func TestNodeUnpublishVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
+				m.EXPECT().ClearIOLimits(gomock.Eq("/target/path")).Return(nil)
+				m.EXPECT().Unpublish(gomock.Eq("/target/path")).Return(nil)
+				return m
+			},
+		},
+		{
+			name: "clear_io_limits_failed",
+			req: &csi.NodeUnpublishVolumeRequest{
+				VolumeId:   "vol-test",
+				TargetPath: "/target/path",
+			},
+			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
+				m := mounter.NewMockMounter(ctrl)
+				m.EXPECT().ClearIOLimits(gomock.Eq("/target/path")).Return(errors.New("clear failed"))
 				m.EXPECT().Unpublish(gomock.Eq("/target/path")).Return(nil)
 				return m
 			},
@@ -2209,6 +2439,7 @@ func TestNodeUnpublishVolume(t *testing.T) {
 			},
 			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
 				m := mounter.NewMockMounter(ctrl)
+				m.EXPECT().ClearIOLimits(gomock.Eq("/target/path")).Return(nil)
 				m.EXPECT().Unpublish(gomock.Eq("/target/path")).Return(errors.New("unpublish failed"))
 				return m
 			},
@@ -2272,7 +2503,8 @@ func TestNodeExpandVolume(t *testing.T) {
 				m := mounter.NewMockMounter(ctrl)
 				m.EXPECT().IsBlockDevice(gomock.Eq("/volume/path")).Return(false, nil)
 				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/volume/path")).Return("device-name", 1, nil)
-				m.EXPECT().FindDevicePath(gomock.Eq("device-name"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("device-name"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().GrowDevice(gomock.Eq("/dev/xvdba")).Return(nil)
 				m.EXPECT().Resize(gomock.Eq("/dev/xvdba"), gomock.Eq("/volume/path")).Return(true, nil)
 				m.EXPECT().GetBlockSizeBytes(gomock.Eq("/dev/xvdba")).Return(int64(1000), nil)
 				return m
@@ -2397,7 +2629,7 @@ func TestNodeExpandVolume(t *testing.T) {
 				m := mounter.NewMockMounter(ctrl)
 				m.EXPECT().IsBlockDevice(gomock.Eq("/volume/path")).Return(false, nil)
 				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/volume/path")).Return("device-name", 1, nil)
-				m.EXPECT().FindDevicePath(gomock.Eq("device-name"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("", errors.New("failed to find device path"))
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("device-name"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("", errors.New("failed to find device path"))
 				return m
 			},
 			metadataMock: func(ctrl *gomock.Controller) *metadata.MockMetadataService {
@@ -2408,6 +2640,78 @@ func TestNodeExpandVolume(t *testing.T) {
 			expectedResp: nil,
 			expectedErr:  status.Error(codes.NotFound, "failed to find device path for device name device-name for mount /volume/path: failed to find device path"),
 		},
+		{
+			name: "success_partition",
+			req: &csi.NodeExpandVolumeRequest{
+				VolumeId:   "vol-test",
+				VolumePath: "/volume/path",
+			},
+			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
+				m := mounter.NewMockMounter(ctrl)
+				m.EXPECT().IsBlockDevice(gomock.Eq("/volume/path")).Return(false, nil)
+				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/volume/path")).Return("/dev/nvme1n1p2", 1, nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/nvme1n1p2"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/nvme1n1p2", nil)
+				gomock.InOrder(
+					m.EXPECT().GrowDevice(gomock.Eq("/dev/nvme1n1p2")).Return(nil),
+					m.EXPECT().Resize(gomock.Eq("/dev/nvme1n1p2"), gomock.Eq("/volume/path")).Return(true, nil),
+				)
+				m.EXPECT().GetBlockSizeBytes(gomock.Eq("/dev/nvme1n1p2")).Return(int64(1000), nil)
+				return m
+			},
+			metadataMock: func(ctrl *gomock.Controller) *metadata.MockMetadataService {
+				m := metadata.NewMockMetadataService(ctrl)
+				m.EXPECT().GetRegion().Return("us-west-2")
+				return m
+			},
+			expectedResp: &csi.NodeExpandVolumeResponse{CapacityBytes: int64(1000)},
+		},
+		{
+			name: "success_logical_volume",
+			req: &csi.NodeExpandVolumeRequest{
+				VolumeId:   "vol-test",
+				VolumePath: "/volume/path",
+			},
+			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
+				m := mounter.NewMockMounter(ctrl)
+				m.EXPECT().IsBlockDevice(gomock.Eq("/volume/path")).Return(false, nil)
+				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/volume/path")).Return("/dev/mapper/data-lv", 1, nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("/dev/mapper/data-lv"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/dm-0", nil)
+				gomock.InOrder(
+					m.EXPECT().GrowDevice(gomock.Eq("/dev/dm-0")).Return(nil),
+					m.EXPECT().Resize(gomock.Eq("/dev/dm-0"), gomock.Eq("/volume/path")).Return(true, nil),
+				)
+				m.EXPECT().GetBlockSizeBytes(gomock.Eq("/dev/dm-0")).Return(int64(1000), nil)
+				return m
+			},
+			metadataMock: func(ctrl *gomock.Controller) *metadata.MockMetadataService {
+				m := metadata.NewMockMetadataService(ctrl)
+				m.EXPECT().GetRegion().Return("us-west-2")
+				return m
+			},
+			expectedResp: &csi.NodeExpandVolumeResponse{CapacityBytes: int64(1000)},
+		},
+		{
+			name: "grow_device_error",
+			req: &csi.NodeExpandVolumeRequest{
+				VolumeId:   "vol-test",
+				VolumePath: "/volume/path",
+			},
+			mounterMock: func(ctrl *gomock.Controller) *mounter.MockMounter {
+				m := mounter.NewMockMounter(ctrl)
+				m.EXPECT().IsBlockDevice(gomock.Eq("/volume/path")).Return(false, nil)
+				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/volume/path")).Return("device-name", 1, nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("device-name"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba1", nil)
+				m.EXPECT().GrowDevice(gomock.Eq("/dev/xvdba1")).Return(errors.New("growpart: command not found"))
+				return m
+			},
+			metadataMock: func(ctrl *gomock.Controller) *metadata.MockMetadataService {
+				m := metadata.NewMockMetadataService(ctrl)
+				m.EXPECT().GetRegion().Return("us-west-2")
+				return m
+			},
+			expectedResp: nil,
+			expectedErr:  status.Error(codes.Internal, "Could not grow device \"/dev/xvdba1\" of volume \"vol-test\": growpart: command not found"),
+		},
 		{
 			name: "resize_error",
 			req: &csi.NodeExpandVolumeRequest{
@@ -2418,7 +2722,8 @@ func TestNodeExpandVolume(t *testing.T) {
 				m := mounter.NewMockMounter(ctrl)
 				m.EXPECT().IsBlockDevice(gomock.Eq("/volume/path")).Return(false, nil)
 				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/volume/path")).Return("device-name", 1, nil)
-				m.EXPECT().FindDevicePath(gomock.Eq("device-name"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("device-name"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().GrowDevice(gomock.Eq("/dev/xvdba")).Return(nil)
 				m.EXPECT().Resize(gomock.Eq("/dev/xvdba"), gomock.Eq("/volume/path")).Return(false, errors.New("failed to resize volume"))
 				return m
 			},
@@ -2440,7 +2745,8 @@ func TestNodeExpandVolume(t *testing.T) {
 				m := mounter.NewMockMounter(ctrl)
 				m.EXPECT().IsBlockDevice(gomock.Eq("/volume/path")).Return(false, nil)
 				m.EXPECT().GetDeviceNameFromMount(gomock.Eq("/volume/path")).Return("device-name", 1, nil)
-				m.EXPECT().FindDevicePath(gomock.Eq("device-name"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().FindDevicePath(gomock.Any(), gomock.Eq("device-name"), gomock.Eq("vol-test"), gomock.Eq(""), gomock.Eq("us-west-2")).Return("/dev/xvdba", nil)
+				m.EXPECT().GrowDevice(gomock.Eq("/dev/xvdba")).Return(nil)
 				m.EXPECT().Resize(gomock.Eq("/dev/xvdba"), gomock.Eq("/volume/path")).Return(true, nil)
 				m.EXPECT().GetBlockSizeBytes(gomock.Eq("/dev/xvdba")).Return(int64(0), errors.New("failed to get block size"))
 				return m
@@ -46,6 +46,8 @@ type Options struct {
 	MetricsKeyFile string
 	// EnableOtelTracing is a flag to enable opentelemetry tracing for the driver
 	EnableOtelTracing bool
+	// EnableOtelMetrics is a flag to export the metrics of the driver to an opentelemetry collector
+	EnableOtelMetrics bool
 
 	// #### Controller options ####
 
@@ -74,6 +76,29 @@ type Options struct {
 	DeprecatedMetrics bool
 	// flag to enable node-local volume support
 	EnableNodeLocalVolumes bool
+	// EphemeralVolumesListenAddress is the TCP network address where the ephemeral volume API
+	// for node plugins will listen. If empty, ephemeral inline volumes are disabled.
+	EphemeralVolumesListenAddress string
+	// EphemeralVolumesNodeServiceAccount is the <namespace>/<name> of the service account of the
+	// node plugin, which is the only one allowed to call the ephemeral volume API.
+	EphemeralVolumesNodeServiceAccount string
+	// EphemeralVolumesCertFile is the location of the certificate for serving the ephemeral volume API over HTTPS.
+	// The API carries service account tokens and is never served over plain HTTP.
+	EphemeralVolumesCertFile string
+	// EphemeralVolumesKeyFile is the location of the key for serving the ephemeral volume API over HTTPS
+	EphemeralVolumesKeyFile string
+	// EnableVolumeMigration enables the migration of volumes to a new volume restored from their
+	// snapshot, for the modifications that cannot be made in place.
+	EnableVolumeMigration bool
+	// VolumeMigrationRetention is how long the original volume of a migrated volume is kept.
+	VolumeMigrationRetention time.Duration
+	// PVCTagSyncLabels are the keys of the PVC labels mirrored to the tags of their volume and snapshots.
+	PVCTagSyncLabels []string
+	// PVCTagSyncAnnotations are the keys of the PVC annotations mirrored to the tags of their volume and snapshots.
+	PVCTagSyncAnnotations []string
+	// PVCTagSyncInterval is how often the tags of all the volumes of PVCs are synced, in addition to
+	// the syncs triggered by changes of their PVC.
+	PVCTagSyncInterval time.Duration
 
 	// #### Node options #####
 
@@ -102,6 +127,36 @@ type Options struct {
 	// The driver will attempt to rely on each source in order until one succeeds.
 	// Valid options include 'imds' and 'kubernetes'.
 	MetadataSources []string
+	// DeviceWatcherTimeout, if non-zero, enables watching kernel uevents for attached NVMe devices
+	// and is the maximum duration to wait for the device of a volume to appear.
+	DeviceWatcherTimeout time.Duration
+	// EnableNVMeMetricsKubernetesLabels adds PersistentVolume, PersistentVolumeClaim and pod labels
+	// to NVMe metrics, resolved through informers on the Kubernetes API.
+	EnableNVMeMetricsKubernetesLabels bool
+	// NVMeMetricsNativeHistograms emits NVMe latency and queue length distributions as Prometheus native histograms.
+	NVMeMetricsNativeHistograms bool
+	// ReconcileMountsOnStartup cleans up staging and publish mounts of the driver whose
+	// devices are gone or now belong to another volume when the node plugin starts. It never
+	// mounts them again.
+	ReconcileMountsOnStartup bool
+	// LazyUnmountWithoutWriters retries a busy unmount in NodeUnstageVolume with a lazy unmount
+	// when no process holding the mount has a file open for writing.
+	LazyUnmountWithoutWriters bool
+	// EphemeralVolumesEndpoint is the URL of the ephemeral volume API of the controller.
+	// If empty, ephemeral inline volumes are disabled.
+	EphemeralVolumesEndpoint string
+	// EphemeralVolumesCAFile is the location of the CA certificate verifying the ephemeral volume API
+	EphemeralVolumesCAFile string
+	// AsyncFormatMinSizeGiB is the minimum size of an unformatted volume that NodeStageVolume formats
+	// in the background. If zero, volumes are always formatted synchronously.
+	AsyncFormatMinSizeGiB int64
+	// MaxConcurrentFormats is the maximum number of background formats running at the same time.
+	MaxConcurrentFormats int
+	// PrewarmParallelism is the number of concurrent reads of each volume pre-warm.
+	PrewarmParallelism int
+	// MetadataCacheFile is the file the node plugin persists instance metadata to, and starts from
+	// when no metadata source is available. If empty, instance metadata is not persisted.
+	MetadataCacheFile string
 }
 
 func (o *Options) AddFlags(f *flag.FlagSet) {
@@ -113,7 +168,8 @@ func (o *Options) AddFlags(f *flag.FlagSet) {
 	f.StringVar(&o.MetricsCertFile, "metrics-cert-file", "", "The path to a certificate to use for serving the metrics server over HTTPS. If the certificate is signed by a certificate authority, this file should be the concatenation of the server's certificate, any intermediates, and the CA's certificate. If this is non-empty, --http-endpoint and --metrics-key-file MUST also be non-empty.")
 	f.StringVar(&o.MetricsKeyFile, "metrics-key-file", "", "The path to a key to use for serving the metrics server over HTTPS. If this is non-empty, --http-endpoint and --metrics-cert-file MUST also be non-empty.")
 	f.BoolVar(&o.EnableOtelTracing, "enable-otel-tracing", false, "To enable opentelemetry tracing for the driver. The tracing is disabled by default. Configure the exporter endpoint with OTEL_EXPORTER_OTLP_ENDPOINT and other env variables, see https://opentelemetry.io/docs/specs/otel/configuration/sdk-environment-variables/#general-sdk-configuration.")
-	f.StringSliceVar(&o.MetadataSources, "metadata-sources", metadata.DefaultMetadataSources, "Dictates which sources are used to retrieve instance metadata. The driver will attempt to rely on each source in order until one succeeds. Valid options include 'imds', 'kubernetes', and (ALPHA) 'metadata-labeler'.")
+	f.BoolVar(&o.EnableOtelMetrics, "enable-otel-metrics", false, "To export the metrics of the driver through OTLP, in addition to the Prometheus endpoint enabled by --http-endpoint. The export is disabled by default. Configure the exporter with the same env variables as --enable-otel-tracing, and the export interval with OTEL_METRIC_EXPORT_INTERVAL.")
+	f.StringSliceVar(&o.MetadataSources, "metadata-sources", metadata.DefaultMetadataSources, "Dictates which sources are used to retrieve instance metadata. The driver will attempt to rely on each source in order until one succeeds. Valid options include 'imds', 'ec2', 'kubernetes', and (ALPHA) 'metadata-labeler'.")
 
 	// AWS SDK options, shared by all modes that create a cloud client
 	if o.Mode == AllMode || o.Mode == ControllerMode || o.Mode == MetadataLabelerMode {
@@ -130,7 +186,16 @@ func (o *Options) AddFlags(f *flag.FlagSet) {
 		f.BoolVar(&o.Batching, "batching", false, "To enable batching of API calls. This is especially helpful for improving performance in workloads that are sensitive to EC2 rate limits.")
 		f.DurationVar(&o.ModifyVolumeRequestHandlerTimeout, "modify-volume-request-handler-timeout", DefaultModifyVolumeRequestHandlerTimeout, "Timeout for the window in which volume modification calls must be received in order for them to coalesce into a single volume modification call to AWS. This must be lower than the csi-resizer and volumemodifier timeouts")
 		f.BoolVar(&o.DeprecatedMetrics, "deprecated-metrics", false, "DEPRECATED: To enable deprecated metrics. This parameter is only for backward compatibility and may be removed in a future release.")
-		f.BoolVar(&o.EnableNodeLocalVolumes, "enable-node-local-volumes", false, "Enable support for node-local volumes that use pre-attached EBS volumes.")
+		f.BoolVar(&o.EnableNodeLocalVolumes, "enable-node-local-volumes", false, "Enable support for node-local volumes that use pre-attached EBS volumes or instance store disks.")
+		f.StringVar(&o.EphemeralVolumesListenAddress, "ephemeral-volumes-listen-address", "", "ALPHA: The TCP network address where the ephemeral volume API for node plugins will listen (example: `:8443`). Enables CSI ephemeral inline volumes, which are created, attached, detached and deleted by the controller on behalf of the node plugins. Requires --k8s-tag-cluster-id, --ephemeral-volumes-node-service-account, --ephemeral-volumes-cert-file and --ephemeral-volumes-key-file. Disabled by default.")
+		f.StringVar(&o.EphemeralVolumesNodeServiceAccount, "ephemeral-volumes-node-service-account", "", "ALPHA: The <namespace>/<name> of the service account of the node plugin, whose tokens are accepted by the ephemeral volume API.")
+		f.StringVar(&o.EphemeralVolumesCertFile, "ephemeral-volumes-cert-file", "", "ALPHA: The path to a certificate to use for serving the ephemeral volume API over HTTPS. Required with --ephemeral-volumes-listen-address.")
+		f.StringVar(&o.EphemeralVolumesKeyFile, "ephemeral-volumes-key-file", "", "ALPHA: The path to a key to use for serving the ephemeral volume API over HTTPS. Required with --ephemeral-volumes-listen-address.")
+		f.BoolVar(&o.EnableVolumeMigration, "enable-volume-migration", false, "ALPHA: To migrate detached volumes to a new volume restored from their snapshot when a VolumeAttributesClass requests a change that cannot be made in place, such as encrypting the volume. Requires --extra-modify-metadata on the external-resizer. Disabled by default.")
+		f.DurationVar(&o.VolumeMigrationRetention, "volume-migration-retention", DefaultVolumeMigrationRetention, "ALPHA: How long the original volume of a migrated volume is kept before it is deleted.")
+		f.StringSliceVar(&o.PVCTagSyncLabels, "pvc-tag-sync-labels", nil, "ALPHA: Comma separated keys of the PVC labels to mirror to the tags of their volume and snapshots. Tags with these keys are removed when the label is removed.")
+		f.StringSliceVar(&o.PVCTagSyncAnnotations, "pvc-tag-sync-annotations", nil, "ALPHA: Comma separated keys of the PVC annotations to mirror to the tags of their volume and snapshots. Tags with these keys are removed when the annotation is removed.")
+		f.DurationVar(&o.PVCTagSyncInterval, "pvc-tag-sync-interval", DefaultPVCTagSyncInterval, "ALPHA: How often the tags of all the volumes are synced with their PVC when --pvc-tag-sync-labels or --pvc-tag-sync-annotations is set, in addition to the syncs triggered by changes of the PVCs.")
 	}
 	// Node options
 	if o.Mode == AllMode || o.Mode == NodeMode {
@@ -139,6 +204,17 @@ func (o *Options) AddFlags(f *flag.FlagSet) {
 		f.BoolVar(&o.WindowsHostProcess, "windows-host-process", false, "ALPHA: Indicates whether the driver is running in a Windows privileged container")
 		f.BoolVar(&o.LegacyXFSProgs, "legacy-xfs", false, "Warning: This option will be removed in a future version of EBS CSI Driver. Formats XFS volumes with `bigtime=0,inobtcount=0,reflink=0,nrext64=0`, so that they can be mounted onto nodes with linux kernel ≤ v5.4. Volumes formatted with this option may experience issues after 2038, and will be unable to use some XFS features (for example, reflinks).")
 		f.StringVar(&o.CsiMountPointPath, "csi-mount-point-prefix", "", "A prefix of the mountpoints of all CSI-managed volumes. If this value is non-empty, all volumes mounted to a path beginning with the provided value are assumed to be CSI volumes owned by the EBS CSI Driver and safe to treat as such (for example, by exposing volume metrics).")
+		f.BoolVar(&o.EnableNVMeMetricsKubernetesLabels, "enable-nvme-metrics-kubernetes-labels", false, "ALPHA: Add persistentvolume, persistentvolumeclaim_namespace and persistentvolumeclaim labels to NVMe metrics, and collect metrics for block volumes outside of --csi-mount-point-prefix. Requires the node service account to list and watch PersistentVolumes.")
+		f.BoolVar(&o.NVMeMetricsNativeHistograms, "nvme-metrics-native-histograms", false, "ALPHA: Emit NVMe latency histograms as Prometheus native histograms instead of classic histograms, and add a native histogram of the volume queue length sampled at each scrape. Native histograms are only exposed in the protobuf exposition format.")
+		f.DurationVar(&o.DeviceWatcherTimeout, "device-watcher-timeout", 0, "ALPHA: If non-zero, the driver watches kernel uevents for attached NVMe devices and waits up to this duration for the device of a volume to appear during NodeStageVolume and NodePublishVolume. Requires the node plugin to run with hostNetwork. Disabled by default.")
+		f.BoolVar(&o.ReconcileMountsOnStartup, "reconcile-mounts-on-startup", false, "ALPHA: When the node plugin starts, unmount the staging and publish targets of this driver whose devices no longer exist or no longer match their volume ID, and report each action as a Kubernetes event. The targets are only cleaned up, never mounted again. Linux only.")
+		f.BoolVar(&o.LazyUnmountWithoutWriters, "lazy-unmount-without-writers", false, "ALPHA: If unmounting a staging target in NodeUnstageVolume fails because it is busy, and none of the processes holding it has a file open for writing, retry with a lazy unmount (umount -l). WARNING: NodeUnstageVolume then succeeds while the remaining processes still hold the filesystem, so the volume can be detached while they read it. Linux only.")
+		f.StringVar(&o.EphemeralVolumesEndpoint, "ephemeral-volumes-endpoint", "", "ALPHA: The URL of the ephemeral volume API of the controller (example: `https://ebs-csi-controller-ephemeral.kube-system.svc:8443`). Enables CSI ephemeral inline volumes on this node. Disabled by default.")
+		f.StringVar(&o.EphemeralVolumesCAFile, "ephemeral-volumes-ca-file", "", "ALPHA: The path to the CA certificate used to verify the ephemeral volume API. If empty, the system CA certificates are used.")
+		f.Int64Var(&o.AsyncFormatMinSizeGiB, "async-format-min-size-gib", 0, "ALPHA: If non-zero, NodeStageVolume formats unformatted volumes of at least this size (in GiB) in the background and returns Aborted with the progress of the format until it completes, instead of exceeding the gRPC timeout of kubelet. Linux only. Disabled by default.")
+		f.IntVar(&o.PrewarmParallelism, "prewarm-parallelism", 8, "ALPHA: The number of concurrent 1 MiB reads of each volume pre-warmed because of the prewarmOnStage StorageClass parameter.")
+		f.IntVar(&o.MaxConcurrentFormats, "max-concurrent-formats", 2, "ALPHA: The maximum number of background formats running at the same time on the node. Formats of further volumes wait for a free slot. Only used with --async-format-min-size-gib.")
+		f.StringVar(&o.MetadataCacheFile, "metadata-cache-file", "", "ALPHA: The path to a file on the host where the node plugin persists the last retrieved instance metadata. If all --metadata-sources are unavailable when the node plugin starts, it starts from this file if it was written during the current boot of the host, and retries the metadata sources every minute. Disabled by default.")
 	}
 }
 
@@ -147,6 +223,33 @@ func (o *Options) Validate() error {
 		if o.VolumeAttachLimit != -1 && o.ReservedVolumeAttachments != -1 {
 			return errors.New("only one of --volume-attach-limit and --reserved-volume-attachments may be specified")
 		}
+		if o.EphemeralVolumesEndpoint != "" && !strings.HasPrefix(o.EphemeralVolumesEndpoint, "https://") {
+			return errors.New("--ephemeral-volumes-endpoint MUST be an https:// URL, the ephemeral volume API carries service account tokens")
+		}
+		if o.DeviceWatcherTimeout < 0 {
+			return errors.New("--device-watcher-timeout must not be negative")
+		}
+		if o.AsyncFormatMinSizeGiB < 0 {
+			return errors.New("--async-format-min-size-gib must not be negative")
+		}
+		if o.AsyncFormatMinSizeGiB > 0 && o.MaxConcurrentFormats < 1 {
+			return errors.New("--max-concurrent-formats must be at least 1")
+		}
+		if o.PrewarmParallelism < 1 {
+			return errors.New("--prewarm-parallelism must be at least 1")
+		}
+	}
+
+	if (o.Mode == AllMode || o.Mode == ControllerMode) && o.EphemeralVolumesListenAddress != "" {
+		namespace, name, ok := strings.Cut(o.EphemeralVolumesNodeServiceAccount, "/")
+		switch {
+		case o.KubernetesClusterID == "":
+			return errors.New("--k8s-tag-cluster-id MUST be specified when using the ephemeral volume API")
+		case !ok || namespace == "" || name == "":
+			return errors.New("--ephemeral-volumes-node-service-account MUST be specified as <namespace>/<name> when using the ephemeral volume API")
+		case o.EphemeralVolumesCertFile == "" || o.EphemeralVolumesKeyFile == "":
+			return errors.New("--ephemeral-volumes-cert-file and --ephemeral-volumes-key-file MUST be specified when using the ephemeral volume API")
+		}
 	}
 
 	if o.MetricsCertFile != "" || o.MetricsKeyFile != "" {
@@ -163,7 +266,7 @@ func (o *Options) Validate() error {
 	for i, s := range o.MetadataSources {
 		s = strings.ToLower(strings.TrimSpace(s))
 		switch s {
-		case metadata.SourceIMDS, metadata.SourceK8s, metadata.SourceMetadataLabeler:
+		case metadata.SourceIMDS, metadata.SourceEC2, metadata.SourceK8s, metadata.SourceMetadataLabeler:
 			o.MetadataSources[i] = s
 		default:
 			return metadata.InvalidSourceErr(o.MetadataSources, s)
@@ -15,6 +15,7 @@
 package driver
 
 import (
+	"slices"
 	"testing"
 	"time"
 
@@ -44,6 +45,9 @@ func TestAddFlags(t *testing.T) {
 	if err := f.Set("enable-otel-tracing", "true"); err != nil {
 		t.Errorf("error setting enable-otel-tracing: %v", err)
 	}
+	if err := f.Set("enable-otel-metrics", "true"); err != nil {
+		t.Errorf("error setting enable-otel-metrics: %v", err)
+	}
 	if err := f.Set("extra-tags", "key1=value1,key2=value2"); err != nil {
 		t.Errorf("error setting extra-tags: %v", err)
 	}
@@ -84,6 +88,57 @@ func TestAddFlags(t *testing.T) {
 	if err := f.Set("csi-mount-point-prefix", "/var/lib/kubelet"); err != nil {
 		t.Errorf("error setting csi-mount-point-prefix: %v", err)
 	}
+	if err := f.Set("enable-nvme-metrics-kubernetes-labels", "true"); err != nil {
+		t.Errorf("error setting enable-nvme-metrics-kubernetes-labels: %v", err)
+	}
+	if err := f.Set("nvme-metrics-native-histograms", "true"); err != nil {
+		t.Errorf("error setting nvme-metrics-native-histograms: %v", err)
+	}
+	if err := f.Set("device-watcher-timeout", "30s"); err != nil {
+		t.Errorf("error setting device-watcher-timeout: %v", err)
+	}
+	if err := f.Set("reconcile-mounts-on-startup", "true"); err != nil {
+		t.Errorf("error setting reconcile-mounts-on-startup: %v", err)
+	}
+	if err := f.Set("lazy-unmount-without-writers", "true"); err != nil {
+		t.Errorf("error setting lazy-unmount-without-writers: %v", err)
+	}
+	if err := f.Set("ephemeral-volumes-listen-address", ":8443"); err != nil {
+		t.Errorf("error setting ephemeral-volumes-listen-address: %v", err)
+	}
+	if err := f.Set("ephemeral-volumes-node-service-account", "kube-system/ebs-csi-node-sa"); err != nil {
+		t.Errorf("error setting ephemeral-volumes-node-service-account: %v", err)
+	}
+	if err := f.Set("ephemeral-volumes-endpoint", "https://ebs-csi-controller:8443"); err != nil {
+		t.Errorf("error setting ephemeral-volumes-endpoint: %v", err)
+	}
+	if err := f.Set("enable-volume-migration", "true"); err != nil {
+		t.Errorf("error setting enable-volume-migration: %v", err)
+	}
+	if err := f.Set("volume-migration-retention", "72h"); err != nil {
+		t.Errorf("error setting volume-migration-retention: %v", err)
+	}
+	if err := f.Set("pvc-tag-sync-labels", "team,cost-center"); err != nil {
+		t.Errorf("error setting pvc-tag-sync-labels: %v", err)
+	}
+	if err := f.Set("pvc-tag-sync-annotations", "example.com/owner"); err != nil {
+		t.Errorf("error setting pvc-tag-sync-annotations: %v", err)
+	}
+	if err := f.Set("pvc-tag-sync-interval", "30m"); err != nil {
+		t.Errorf("error setting pvc-tag-sync-interval: %v", err)
+	}
+	if err := f.Set("async-format-min-size-gib", "4096"); err != nil {
+		t.Errorf("error setting async-format-min-size-gib: %v", err)
+	}
+	if err := f.Set("max-concurrent-formats", "4"); err != nil {
+		t.Errorf("error setting max-concurrent-formats: %v", err)
+	}
+	if err := f.Set("prewarm-parallelism", "16"); err != nil {
+		t.Errorf("error setting prewarm-parallelism: %v", err)
+	}
+	if err := f.Set("metadata-cache-file", "/csi/instance-metadata.json"); err != nil {
+		t.Errorf("error setting metadata-cache-file: %v", err)
+	}
 
 	if o.Endpoint != "custom-endpoint" {
 		t.Errorf("unexpected Endpoint: got %s, want custom-endpoint", o.Endpoint)
@@ -94,6 +149,9 @@ func TestAddFlags(t *testing.T) {
 	if !o.EnableOtelTracing {
 		t.Error("unexpected EnableOtelTracing: got false, want true")
 	}
+	if !o.EnableOtelMetrics {
+		t.Error("unexpected EnableOtelMetrics: got false, want true")
+	}
 	if len(o.ExtraTags) != 2 || o.ExtraTags["key1"] != "value1" || o.ExtraTags["key2"] != "value2" {
 		t.Errorf("unexpected ExtraTags: got %v, want map[key1:value1 key2:value2]", o.ExtraTags)
 	}
@@ -127,6 +185,57 @@ func TestAddFlags(t *testing.T) {
 	if !o.EnableNodeLocalVolumes {
 		t.Error("unexpected EnableNodeLocalVolumes: got false, want true")
 	}
+	if !o.EnableNVMeMetricsKubernetesLabels {
+		t.Error("unexpected EnableNVMeMetricsKubernetesLabels: got false, want true")
+	}
+	if !o.NVMeMetricsNativeHistograms {
+		t.Error("unexpected NVMeMetricsNativeHistograms: got false, want true")
+	}
+	if o.DeviceWatcherTimeout != 30*time.Second {
+		t.Errorf("unexpected DeviceWatcherTimeout: got %v, want 30s", o.DeviceWatcherTimeout)
+	}
+	if !o.ReconcileMountsOnStartup {
+		t.Error("unexpected ReconcileMountsOnStartup: got false, want true")
+	}
+	if !o.LazyUnmountWithoutWriters {
+		t.Error("unexpected LazyUnmountWithoutWriters: got false, want true")
+	}
+	if o.EphemeralVolumesListenAddress != ":8443" {
+		t.Errorf("unexpected EphemeralVolumesListenAddress: got %s, want :8443", o.EphemeralVolumesListenAddress)
+	}
+	if o.EphemeralVolumesNodeServiceAccount != "kube-system/ebs-csi-node-sa" {
+		t.Errorf("unexpected EphemeralVolumesNodeServiceAccount: got %s, want kube-system/ebs-csi-node-sa", o.EphemeralVolumesNodeServiceAccount)
+	}
+	if o.EphemeralVolumesEndpoint != "https://ebs-csi-controller:8443" {
+		t.Errorf("unexpected EphemeralVolumesEndpoint: got %s, want https://ebs-csi-controller:8443", o.EphemeralVolumesEndpoint)
+	}
+	if !o.EnableVolumeMigration {
+		t.Error("unexpected EnableVolumeMigration: got false, want true")
+	}
+	if o.VolumeMigrationRetention != 72*time.Hour {
+		t.Errorf("unexpected VolumeMigrationRetention: got %v, want 72h", o.VolumeMigrationRetention)
+	}
+	if !slices.Equal(o.PVCTagSyncLabels, []string{"team", "cost-center"}) {
+		t.Errorf("unexpected PVCTagSyncLabels: got %v, want [team cost-center]", o.PVCTagSyncLabels)
+	}
+	if !slices.Equal(o.PVCTagSyncAnnotations, []string{"example.com/owner"}) {
+		t.Errorf("unexpected PVCTagSyncAnnotations: got %v, want [example.com/owner]", o.PVCTagSyncAnnotations)
+	}
+	if o.PVCTagSyncInterval != 30*time.Minute {
+		t.Errorf("unexpected PVCTagSyncInterval: got %v, want 30m", o.PVCTagSyncInterval)
+	}
+	if o.AsyncFormatMinSizeGiB != 4096 {
+		t.Errorf("unexpected AsyncFormatMinSizeGiB: got %d, want 4096", o.AsyncFormatMinSizeGiB)
+	}
+	if o.MaxConcurrentFormats != 4 {
+		t.Errorf("unexpected MaxConcurrentFormats: got %d, want 4", o.MaxConcurrentFormats)
+	}
+	if o.PrewarmParallelism != 16 {
+		t.Errorf("unexpected PrewarmParallelism: got %d, want 16", o.PrewarmParallelism)
+	}
+	if o.MetadataCacheFile != "/csi/instance-metadata.json" {
+		t.Errorf("unexpected MetadataCacheFile: got %s, want /csi/instance-metadata.json", o.MetadataCacheFile)
+	}
 }
 
 func TestAddFlagsMetadataLabelerMode(t *testing.T) {
@@ -289,6 +398,151 @@ func TestValidateMetricsHTTPS(t *testing.T) {
 	}
 }
 
+func TestValidateEphemeralVolumes(t *testing.T) {
+	tests := []struct {
+		name           string
+		listenAddress  string
+		serviceAccount string
+		clusterID      string
+		certFile       string
+		keyFile        string
+		endpoint       string
+		expectError    bool
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:     "node plugin",
+			endpoint: "https://ebs-csi-controller-ephemeral-volumes.kube-system.svc:8443",
+		},
+		{
+			name:        "node plugin over http",
+			endpoint:    "http://ebs-csi-controller-ephemeral-volumes.kube-system.svc:8443",
+			expectError: true,
+		},
+		{
+			name:           "enabled",
+			listenAddress:  ":8443",
+			serviceAccount: "kube-system/ebs-csi-node-sa",
+			clusterID:      "cluster-123",
+			certFile:       "/https.crt",
+			keyFile:        "/https.key",
+		},
+		{
+			name:           "cluster ID missing",
+			listenAddress:  ":8443",
+			serviceAccount: "kube-system/ebs-csi-node-sa",
+			certFile:       "/https.crt",
+			keyFile:        "/https.key",
+			expectError:    true,
+		},
+		{
+			name:          "service account missing",
+			listenAddress: ":8443",
+			clusterID:     "cluster-123",
+			certFile:      "/https.crt",
+			keyFile:       "/https.key",
+			expectError:   true,
+		},
+		{
+			name:           "service account without namespace",
+			listenAddress:  ":8443",
+			serviceAccount: "ebs-csi-node-sa",
+			clusterID:      "cluster-123",
+			certFile:       "/https.crt",
+			keyFile:        "/https.key",
+			expectError:    true,
+		},
+		{
+			name:           "certificate missing",
+			listenAddress:  ":8443",
+			serviceAccount: "kube-system/ebs-csi-node-sa",
+			clusterID:      "cluster-123",
+			expectError:    true,
+		},
+		{
+			name:           "key missing",
+			listenAddress:  ":8443",
+			serviceAccount: "kube-system/ebs-csi-node-sa",
+			clusterID:      "cluster-123",
+			certFile:       "/https.crt",
+			expectError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Options{}
+			o.Mode = ControllerMode
+			if tt.endpoint != "" {
+				o.Mode = NodeMode
+			}
+			f := flag.NewFlagSet("test", flag.ExitOnError)
+			o.AddFlags(f)
+
+			o.EphemeralVolumesEndpoint = tt.endpoint
+			o.EphemeralVolumesListenAddress = tt.listenAddress
+			o.EphemeralVolumesNodeServiceAccount = tt.serviceAccount
+			o.KubernetesClusterID = tt.clusterID
+			o.EphemeralVolumesCertFile = tt.certFile
+			o.EphemeralVolumesKeyFile = tt.keyFile
+
+			err := o.Validate()
+			if (err != nil) != tt.expectError {
+				t.Errorf("Options.Validate() error = %v, wantErr %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestValidateAsyncFormat(t *testing.T) {
+	tests := []struct {
+		name                 string
+		minSizeGiB           int64
+		maxConcurrentFormats int
+		expectError          bool
+	}{
+		{
+			name:                 "disabled",
+			maxConcurrentFormats: 2,
+		},
+		{
+			name:                 "enabled",
+			minSizeGiB:           1024,
+			maxConcurrentFormats: 2,
+		},
+		{
+			name:                 "negative size",
+			minSizeGiB:           -1,
+			maxConcurrentFormats: 2,
+			expectError:          true,
+		},
+		{
+			name:        "no concurrent format",
+			minSizeGiB:  1024,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Options{}
+			o.Mode = NodeMode
+			f := flag.NewFlagSet("test", flag.ExitOnError)
+			o.AddFlags(f)
+
+			o.AsyncFormatMinSizeGiB = tt.minSizeGiB
+			o.MaxConcurrentFormats = tt.maxConcurrentFormats
+
+			err := o.Validate()
+			if (err != nil) != tt.expectError {
+				t.Errorf("Options.Validate() error = %v, wantErr %v", err, tt.expectError)
+			}
+		})
+	}
+}
+
 func TestValidateMetadataSources(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -307,6 +561,10 @@ func TestValidateMetadataSources(t *testing.T) {
 			name:            "success: kubernetes",
 			metadataSources: []string{metadata.SourceK8s},
 		},
+		{
+			name:            "success: ec2",
+			metadataSources: []string{metadata.SourceEC2},
+		},
 		{
 			name:            "success: all sources reversed",
 			metadataSources: []string{"kubernetes", "imds"},
@@ -0,0 +1,43 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/prometheus/client_golang/prometheus"
+	otelprometheus "go.opentelemetry.io/contrib/bridges/prometheus"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/sdk/metric"
+)
+
+// InitOtelMetrics periodically exports the metrics gathered by gatherer to an OTLP collector, in
+// addition to serving them to Prometheus. Like the trace exporter, the metric exporter is configured
+// with the OTEL_EXPORTER_OTLP_* environment variables, and the export interval with OTEL_METRIC_EXPORT_INTERVAL.
+// The returned MeterProvider exports the pending metrics when it is shut down.
+func InitOtelMetrics(gatherer prometheus.Gatherer) (*metric.MeterProvider, error) {
+	ctx := context.Background()
+	exporter, err := otlpmetricgrpc.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the OTLP metric exporter: %w", err)
+	}
+
+	reader := metric.NewPeriodicReader(exporter,
+		metric.WithProducer(otelprometheus.NewMetricProducer(otelprometheus.WithGatherer(gatherer))))
+	return metric.NewMeterProvider(metric.WithReader(reader), metric.WithResource(otelResource(ctx))), nil
+}
@@ -33,8 +33,19 @@ func InitOtelTracing() (*otlptrace.Exporter, error) {
 		return nil, fmt.Errorf("failed to create the OTLP exporter: %w", err)
 	}
 
-	// Resource will auto populate spans with common attributes
-	resource, err := resource.New(ctx,
+	// Create a trace provider with the exporter.
+	// Use propagator and sampler defined in environment variables.
+	traceProvider := trace.NewTracerProvider(trace.WithBatcher(exporter), trace.WithResource(otelResource(ctx)))
+
+	// Register the trace provider as global.
+	otel.SetTracerProvider(traceProvider)
+
+	return exporter, nil
+}
+
+// otelResource returns the resource that auto populates spans and metrics with common attributes.
+func otelResource(ctx context.Context) *resource.Resource {
+	r, err := resource.New(ctx,
 		resource.WithFromEnv(), // pull attributes from OTEL_RESOURCE_ATTRIBUTES and OTEL_SERVICE_NAME environment variables
 		resource.WithProcess(),
 		resource.WithOS(),
@@ -42,15 +53,7 @@ func InitOtelTracing() (*otlptrace.Exporter, error) {
 		resource.WithHost(),
 	)
 	if err != nil {
-		klog.ErrorS(err, "failed to create the OTLP resource, spans will lack some metadata")
+		klog.ErrorS(err, "failed to create the OTLP resource, spans and metrics will lack some metadata")
 	}
-
-	// Create a trace provider with the exporter.
-	// Use propagator and sampler defined in environment variables.
-	traceProvider := trace.NewTracerProvider(trace.WithBatcher(exporter), trace.WithResource(resource))
-
-	// Register the trace provider as global.
-	otel.SetTracerProvider(traceProvider)
-
-	return exporter, nil
+	return r
 }
@@ -19,6 +19,7 @@ package driver
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/kubernetes-sigs/aws-ebs-csi-driver/pkg/cloud"
@@ -31,6 +32,10 @@ func ValidateDriverOptions(options *Options) error {
 		return fmt.Errorf("invalid extra tags: %w", err)
 	}
 
+	if err := validatePVCTagSyncKeys(slices.Concat(options.PVCTagSyncLabels, options.PVCTagSyncAnnotations)); err != nil {
+		return fmt.Errorf("invalid PVC tag sync keys: %w", err)
+	}
+
 	if err := validateMode(options.Mode); err != nil {
 		return fmt.Errorf("invalid mode: %w", err)
 	}
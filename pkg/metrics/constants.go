@@ -28,4 +28,18 @@ const (
 	DeprecatedAPIRequestDuration          = "cloudprovider_aws_api_request_duration_seconds"
 	DeprecatedAPIRequestErrors            = "cloudprovider_aws_api_request_errors"
 	DeprecatedAPIRequestThrottles         = "cloudprovider_aws_api_throttled_requests_total"
+	CachedMetadata                        = "aws_ebs_csi_cached_metadata"
+	CachedMetadataHelpText                = "1 if the node plugin runs on instance metadata loaded from its cache file because no metadata source is available, 0 otherwise"
+	GRPCRequestDuration                   = "aws_ebs_csi_grpc_request_duration_seconds"
+	GRPCRequestDurationHelpText           = "Duration of CSI gRPC requests served by the driver in seconds, by method"
+	GRPCRequests                          = "aws_ebs_csi_grpc_requests_total"
+	GRPCRequestsHelpText                  = "Total number of CSI gRPC requests served by the driver, by method and gRPC status code"
+	FormatDuration                        = "aws_ebs_csi_format_duration_seconds"
+	FormatDurationHelpText                = "Duration of filesystem formats run in the background by NodeStageVolume in seconds, by filesystem type and result"
+	PrewarmProgress                       = "aws_ebs_csi_prewarm_progress_ratio"
+	PrewarmProgressHelpText               = "Fraction of the blocks of a volume read by the running pre-warm of the volume"
+	PrewarmDuration                       = "aws_ebs_csi_prewarm_duration_seconds"
+	PrewarmDurationHelpText               = "Duration of volume pre-warms in seconds, by result"
+	DeferredModification                  = "aws_ebs_csi_deferred_modification_timestamp_seconds"
+	DeferredModificationHelpText          = "Unix time after which the deferred modification of a volume is applied"
 )
@@ -79,8 +79,10 @@ func InitializeRecorder(deprecatedMetrics bool) (*MetricRecorder, *prometheus.Re
 }
 
 // InitializeNVME registers the NVMe collector for gathering metrics from NVMe devices.
-func (m *MetricRecorder) InitializeNVME(csiMountPointPath, instanceID string) {
-	registerNVMECollector(r, csiMountPointPath, instanceID)
+// resolver is optional and, if provided, is used to add Kubernetes labels to the metrics.
+// If nativeHistograms is true, latency histograms are emitted as Prometheus native histograms.
+func (m *MetricRecorder) InitializeNVME(csiMountPointPath, instanceID string, resolver VolumeResolver, nativeHistograms bool) {
+	registerNVMECollector(r, csiMountPointPath, instanceID, resolver, nativeHistograms)
 }
 
 // InitializeAsyncEC2Metrics initializes and registers AsyncEC2Collector for gathering metrics on async EC2 operations.
@@ -173,6 +175,46 @@ func (m *MetricRecorder) ObserveHistogram(name string, helpText string, value fl
 	}
 }
 
+// SetGauge sets the gauge metric to the given value.
+func (m *MetricRecorder) SetGauge(name string, helpText string, value float64, labels map[string]string) {
+	if m == nil {
+		return // recorder is not initialized
+	}
+
+	m.mu.RLock()
+	metric, ok := m.metrics[name]
+	m.mu.RUnlock()
+
+	if !ok {
+		klog.V(4).InfoS("Metric not found, registering", "name", name, "labels", labels)
+		m.registerGaugeVec(name, helpText, getLabelNames(labels))
+		m.SetGauge(name, helpText, value, labels)
+		return
+	}
+
+	metricAsGaugeVec, ok := metric.(*prometheus.GaugeVec)
+	if ok {
+		metricAsGaugeVec.With(labels).Set(value)
+	} else {
+		klog.V(4).InfoS("Could not assert metric as metrics.GaugeVec. Metric update may have been skipped")
+	}
+}
+
+// DeleteGauge removes the series of the gauge metric with the given labels.
+func (m *MetricRecorder) DeleteGauge(name string, labels map[string]string) {
+	if m == nil {
+		return // recorder is not initialized
+	}
+
+	m.mu.RLock()
+	metric, ok := m.metrics[name]
+	m.mu.RUnlock()
+
+	if metricAsGaugeVec, isGaugeVec := metric.(*prometheus.GaugeVec); ok && isGaugeVec {
+		metricAsGaugeVec.Delete(labels)
+	}
+}
+
 // rateLimitMiddleware applies rate limiting to metric HTTP requests.
 func rateLimitMiddleware(limiter *rate.Limiter, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -259,6 +301,23 @@ func (m *MetricRecorder) registerCounterVec(name, help string, labels []string)
 	m.registry.MustRegister(counter)
 }
 
+func (m *MetricRecorder) registerGaugeVec(name, help string, labels []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, exists := m.metrics[name]; exists {
+		return
+	}
+	gauge := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: name,
+			Help: help,
+		},
+		labels,
+	)
+	m.metrics[name] = gauge
+	m.registry.MustRegister(gauge)
+}
+
 func getLabelNames(labels map[string]string) []string {
 	names := make([]string, 0, len(labels))
 	for n := range labels {
@@ -60,6 +60,21 @@ test_count{key="value"} 1
 			`,
 			recorder: true,
 		},
+		{
+			name: "TestMetricRecorder: SetGaugeMetric",
+			exec: func(m *MetricRecorder) {
+				m.SetGauge("test_ratio", "help text", 0.25, map[string]string{"key": "value1"})
+				m.SetGauge("test_ratio", "help text", 0.5, map[string]string{"key": "value1"})
+				m.SetGauge("test_ratio", "help text", 1, map[string]string{"key": "value2"})
+				m.DeleteGauge("test_ratio", map[string]string{"key": "value2"})
+			},
+			expected: `
+# HELP test_ratio help text
+# TYPE test_ratio gauge
+test_ratio{key="value1"} 0.5
+			`,
+			recorder: true,
+		},
 		{
 			name: "TestMetricRecorder: Re-register metric",
 			exec: func(m *MetricRecorder) {
@@ -52,15 +52,20 @@ const (
 	nvmeCollectorErrors   = namespace + "nvme_collector_errors_total"
 
 	// Gauge metrics.
-	metricVolumeQueueLength = namespace + "volume_queue_length"
+	metricVolumeQueueLength   = namespace + "volume_queue_length"
+	metricReadLatencyAverage  = namespace + "read_io_latency_average_seconds"
+	metricWriteLatencyAverage = namespace + "write_io_latency_average_seconds"
 
 	// Histogram metrics.
 	metricReadLatency     = namespace + "read_io_latency_seconds"
 	metricWriteLatency    = namespace + "write_io_latency_seconds"
 	nvmeCollectorDuration = namespace + "nvme_collector_duration_seconds"
 
-	// Conversion factor.
-	microsecondsInSeconds = 1e6
+	// Native histogram metrics.
+	metricVolumeQueueLengthSamples = namespace + "volume_queue_length_samples"
+
+	// MicrosecondsInSeconds converts the durations of the EBS log page, in microseconds, to seconds.
+	MicrosecondsInSeconds = 1e6
 )
 
 // EBSMetrics represents the parsed metrics from the NVMe log page.
@@ -119,6 +124,9 @@ type NVMECollector struct {
 	metrics            map[string]*prometheus.Desc
 	csiMountPointPath  string
 	instanceID         string
+	resolver           VolumeResolver
+	nativeHistograms   bool
+	accumulator        *nvmeAccumulator
 	collectionDuration prometheus.Histogram
 	scrapesTotal       prometheus.Counter
 	scrapeErrorsTotal  prometheus.Counter
@@ -130,30 +138,42 @@ var (
 )
 
 // NewNVMECollector creates a new instance of NVMECollector.
-func NewNVMECollector(path, instanceID string) *NVMECollector {
+// If resolver is non-nil, metrics are additionally labeled with the
+// PersistentVolume and PersistentVolumeClaim of each volume. The pods using a
+// volume are not added as a label to keep the cardinality of the metrics bounded.
+// If nativeHistograms is true, latency histograms are emitted as native histograms.
+func NewNVMECollector(path, instanceID string, resolver VolumeResolver, nativeHistograms bool) *NVMECollector {
 	variableLabels := []string{"volume_id"}
+	if resolver != nil {
+		variableLabels = append(variableLabels, kubernetesVolumeLabels...)
+	}
 	constLabels := prometheus.Labels{"instance_id": instanceID}
 
-	return &NVMECollector{
+	collector := &NVMECollector{
 		metrics: map[string]*prometheus.Desc{
-			metricReadOps:           prometheus.NewDesc(metricReadOps, "The total number of completed read operations.", variableLabels, constLabels),
-			metricWriteOps:          prometheus.NewDesc(metricWriteOps, "The total number of completed write operations.", variableLabels, constLabels),
-			metricReadBytes:         prometheus.NewDesc(metricReadBytes, "The total number of read bytes transferred.", variableLabels, constLabels),
-			metricWriteBytes:        prometheus.NewDesc(metricWriteBytes, "The total number of write bytes transferred.", variableLabels, constLabels),
-			metricReadOpsSeconds:    prometheus.NewDesc(metricReadOpsSeconds, "The total time spent, in seconds, by all completed read operations.", variableLabels, constLabels),
-			metricWriteOpsSeconds:   prometheus.NewDesc(metricWriteOpsSeconds, "The total time spent, in seconds, by all completed write operations.", variableLabels, constLabels),
-			metricExceededIOPS:      prometheus.NewDesc(metricExceededIOPS, "The total time, in seconds, that IOPS demand exceeded the volume's provisioned IOPS performance.", variableLabels, constLabels),
-			metricExceededTP:        prometheus.NewDesc(metricExceededTP, "The total time, in seconds, that throughput demand exceeded the volume's provisioned throughput performance.", variableLabels, constLabels),
-			metricEC2ExceededIOPS:   prometheus.NewDesc(metricEC2ExceededIOPS, "The total time, in seconds, that the EBS volume exceeded the attached Amazon EC2 instance's maximum IOPS performance.", variableLabels, constLabels),
-			metricEC2ExceededTP:     prometheus.NewDesc(metricEC2ExceededTP, "The total time, in seconds, that the EBS volume exceeded the attached Amazon EC2 instance's maximum throughput performance.", variableLabels, constLabels),
-			metricVolumeQueueLength: prometheus.NewDesc(metricVolumeQueueLength, "The number of read and write operations waiting to be completed.", variableLabels, constLabels),
-			metricReadLatency:       prometheus.NewDesc(metricReadLatency, "The number of read operations completed within each latency bin, in seconds.", variableLabels, constLabels),
-			metricWriteLatency:      prometheus.NewDesc(metricWriteLatency, "The number of write operations completed within each latency bin, in seconds.", variableLabels, constLabels),
+			metricReadOps:             prometheus.NewDesc(metricReadOps, "The total number of completed read operations.", variableLabels, constLabels),
+			metricWriteOps:            prometheus.NewDesc(metricWriteOps, "The total number of completed write operations.", variableLabels, constLabels),
+			metricReadBytes:           prometheus.NewDesc(metricReadBytes, "The total number of read bytes transferred.", variableLabels, constLabels),
+			metricWriteBytes:          prometheus.NewDesc(metricWriteBytes, "The total number of write bytes transferred.", variableLabels, constLabels),
+			metricReadOpsSeconds:      prometheus.NewDesc(metricReadOpsSeconds, "The total time spent, in seconds, by all completed read operations.", variableLabels, constLabels),
+			metricWriteOpsSeconds:     prometheus.NewDesc(metricWriteOpsSeconds, "The total time spent, in seconds, by all completed write operations.", variableLabels, constLabels),
+			metricExceededIOPS:        prometheus.NewDesc(metricExceededIOPS, "The total time, in seconds, that IOPS demand exceeded the volume's provisioned IOPS performance.", variableLabels, constLabels),
+			metricExceededTP:          prometheus.NewDesc(metricExceededTP, "The total time, in seconds, that throughput demand exceeded the volume's provisioned throughput performance.", variableLabels, constLabels),
+			metricEC2ExceededIOPS:     prometheus.NewDesc(metricEC2ExceededIOPS, "The total time, in seconds, that the EBS volume exceeded the attached Amazon EC2 instance's maximum IOPS performance.", variableLabels, constLabels),
+			metricEC2ExceededTP:       prometheus.NewDesc(metricEC2ExceededTP, "The total time, in seconds, that the EBS volume exceeded the attached Amazon EC2 instance's maximum throughput performance.", variableLabels, constLabels),
+			metricVolumeQueueLength:   prometheus.NewDesc(metricVolumeQueueLength, "The number of read and write operations waiting to be completed.", variableLabels, constLabels),
+			metricReadLatency:         prometheus.NewDesc(metricReadLatency, "The number of read operations completed within each latency bin, in seconds.", variableLabels, constLabels),
+			metricWriteLatency:        prometheus.NewDesc(metricWriteLatency, "The number of write operations completed within each latency bin, in seconds.", variableLabels, constLabels),
+			metricReadLatencyAverage:  prometheus.NewDesc(metricReadLatencyAverage, "The average latency, in seconds, of the read operations completed since the previous scrape.", variableLabels, constLabels),
+			metricWriteLatencyAverage: prometheus.NewDesc(metricWriteLatencyAverage, "The average latency, in seconds, of the write operations completed since the previous scrape.", variableLabels, constLabels),
 		},
 		// Clean CSI mount point path to normalize path
 		// Add trailing slash back that Clean prunes
 		csiMountPointPath: filepath.Clean(path) + "/",
 		instanceID:        instanceID,
+		resolver:          resolver,
+		nativeHistograms:  nativeHistograms,
+		accumulator:       newNVMEAccumulator(),
 		collectionDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
 			Name:        nvmeCollectorDuration,
 			Help:        "Histogram of NVMe collector scrape duration in seconds.",
@@ -171,10 +191,14 @@ func NewNVMECollector(path, instanceID string) *NVMECollector {
 			ConstLabels: constLabels,
 		}),
 	}
+	if nativeHistograms {
+		collector.metrics[metricVolumeQueueLengthSamples] = prometheus.NewDesc(metricVolumeQueueLengthSamples, "The distribution of the number of read and write operations waiting to be completed, sampled at each collection by any scraper.", variableLabels, constLabels)
+	}
+	return collector
 }
 
-func registerNVMECollector(r *MetricRecorder, csiMountPointPath, instanceID string) {
-	collector := NewNVMECollector(csiMountPointPath, instanceID)
+func registerNVMECollector(r *MetricRecorder, csiMountPointPath, instanceID string, resolver VolumeResolver, nativeHistograms bool) {
+	collector := NewNVMECollector(csiMountPointPath, instanceID, resolver, nativeHistograms)
 	r.registry.MustRegister(collector)
 }
 
@@ -201,23 +225,16 @@ func (c *NVMECollector) Collect(ch chan<- prometheus.Metric) {
 		ch <- c.scrapeErrorsTotal
 	}()
 
-	devicePaths, err := getCSIManagedDevices(c.csiMountPointPath)
+	devices, err := c.getDevices()
 	if err != nil {
 		klog.Errorf("Error getting NVMe devices: %v", err)
 		c.scrapeErrorsTotal.Inc()
 		return
-	} else if len(devicePaths) == 0 {
+	} else if len(devices) == 0 {
 		klog.V(8).InfoS("No NVMe devices found")
 		return
 	}
 
-	devices, err := fetchDevicePathToVolumeIDMapping(devicePaths)
-	if err != nil {
-		klog.Errorf("Error getting volume IDs: %v", err)
-		c.scrapeErrorsTotal.Inc()
-		return
-	}
-
 	for devicePath, volumeID := range devices {
 		data, err := getNVMEMetrics(devicePath)
 		if err != nil {
@@ -226,25 +243,40 @@ func (c *NVMECollector) Collect(ch chan<- prometheus.Metric) {
 			continue
 		}
 
-		metrics, err := parseLogPage(data)
+		sample, err := parseLogPage(data)
 		if err != nil {
 			klog.Errorf("Error parsing metrics for device %s: %v", devicePath, err)
 			c.scrapeErrorsTotal.Inc()
 			continue
 		}
 
+		// Counters are emitted from accumulated deltas, so that they stay monotonic
+		// when the device counters are reset by a re-attachment of the volume
+		counters := c.accumulator.update(volumeID, sample, start)
+		metrics := counters.total
+		labels := c.labelValues(volumeID)
+
 		// Send all collected metrics to Prometheus
-		ch <- prometheus.MustNewConstMetric(c.metrics[metricReadOps], prometheus.CounterValue, float64(metrics.ReadOps), volumeID)
-		ch <- prometheus.MustNewConstMetric(c.metrics[metricWriteOps], prometheus.CounterValue, float64(metrics.WriteOps), volumeID)
-		ch <- prometheus.MustNewConstMetric(c.metrics[metricReadBytes], prometheus.CounterValue, float64(metrics.ReadBytes), volumeID)
-		ch <- prometheus.MustNewConstMetric(c.metrics[metricWriteBytes], prometheus.CounterValue, float64(metrics.WriteBytes), volumeID)
-		ch <- prometheus.MustNewConstMetric(c.metrics[metricReadOpsSeconds], prometheus.CounterValue, float64(metrics.TotalReadTime)/microsecondsInSeconds, volumeID)
-		ch <- prometheus.MustNewConstMetric(c.metrics[metricWriteOpsSeconds], prometheus.CounterValue, float64(metrics.TotalWriteTime)/microsecondsInSeconds, volumeID)
-		ch <- prometheus.MustNewConstMetric(c.metrics[metricExceededIOPS], prometheus.CounterValue, float64(metrics.EBSIOPSExceeded)/microsecondsInSeconds, volumeID)
-		ch <- prometheus.MustNewConstMetric(c.metrics[metricExceededTP], prometheus.CounterValue, float64(metrics.EBSThroughputExceeded)/microsecondsInSeconds, volumeID)
-		ch <- prometheus.MustNewConstMetric(c.metrics[metricEC2ExceededIOPS], prometheus.CounterValue, float64(metrics.EC2IOPSExceeded)/microsecondsInSeconds, volumeID)
-		ch <- prometheus.MustNewConstMetric(c.metrics[metricEC2ExceededTP], prometheus.CounterValue, float64(metrics.EC2ThroughputExceeded)/microsecondsInSeconds, volumeID)
-		ch <- prometheus.MustNewConstMetric(c.metrics[metricVolumeQueueLength], prometheus.GaugeValue, float64(metrics.QueueLength), volumeID)
+		ch <- prometheus.MustNewConstMetric(c.metrics[metricReadOps], prometheus.CounterValue, float64(metrics.ReadOps), labels...)
+		ch <- prometheus.MustNewConstMetric(c.metrics[metricWriteOps], prometheus.CounterValue, float64(metrics.WriteOps), labels...)
+		ch <- prometheus.MustNewConstMetric(c.metrics[metricReadBytes], prometheus.CounterValue, float64(metrics.ReadBytes), labels...)
+		ch <- prometheus.MustNewConstMetric(c.metrics[metricWriteBytes], prometheus.CounterValue, float64(metrics.WriteBytes), labels...)
+		ch <- prometheus.MustNewConstMetric(c.metrics[metricReadOpsSeconds], prometheus.CounterValue, float64(metrics.TotalReadTime)/MicrosecondsInSeconds, labels...)
+		ch <- prometheus.MustNewConstMetric(c.metrics[metricWriteOpsSeconds], prometheus.CounterValue, float64(metrics.TotalWriteTime)/MicrosecondsInSeconds, labels...)
+		ch <- prometheus.MustNewConstMetric(c.metrics[metricExceededIOPS], prometheus.CounterValue, float64(metrics.EBSIOPSExceeded)/MicrosecondsInSeconds, labels...)
+		ch <- prometheus.MustNewConstMetric(c.metrics[metricExceededTP], prometheus.CounterValue, float64(metrics.EBSThroughputExceeded)/MicrosecondsInSeconds, labels...)
+		ch <- prometheus.MustNewConstMetric(c.metrics[metricEC2ExceededIOPS], prometheus.CounterValue, float64(metrics.EC2IOPSExceeded)/MicrosecondsInSeconds, labels...)
+		ch <- prometheus.MustNewConstMetric(c.metrics[metricEC2ExceededTP], prometheus.CounterValue, float64(metrics.EC2ThroughputExceeded)/MicrosecondsInSeconds, labels...)
+		ch <- prometheus.MustNewConstMetric(c.metrics[metricVolumeQueueLength], prometheus.GaugeValue, float64(metrics.QueueLength), labels...)
+		if counters.hasLatencyAverage {
+			ch <- prometheus.MustNewConstMetric(c.metrics[metricReadLatencyAverage], prometheus.GaugeValue, counters.readLatencyAverage, labels...)
+			ch <- prometheus.MustNewConstMetric(c.metrics[metricWriteLatencyAverage], prometheus.GaugeValue, counters.writeLatencyAverage, labels...)
+		}
+
+		if c.nativeHistograms {
+			c.collectNativeHistograms(ch, counters, labels)
+			continue
+		}
 
 		// Read Latency Histogram
 		readCount, readBuckets := convertHistogram(metrics.ReadLatency)
@@ -253,7 +285,7 @@ func (c *NVMECollector) Collect(ch chan<- prometheus.Metric) {
 			readCount,
 			0,
 			readBuckets,
-			volumeID,
+			labels...,
 		)
 
 		// Write Latency Histogram
@@ -263,9 +295,109 @@ func (c *NVMECollector) Collect(ch chan<- prometheus.Metric) {
 			writeCount,
 			0,
 			writeBuckets,
-			volumeID,
+			labels...,
 		)
 	}
+
+	c.accumulator.prune(start)
+}
+
+// collectNativeHistograms sends the latency and queue length distributions of a volume as native histograms.
+func (c *NVMECollector) collectNativeHistograms(ch chan<- prometheus.Metric, counters volumeCounters, labels []string) {
+	metrics := counters.total
+
+	readCount, readBuckets, readZero := convertNativeHistogram(metrics.ReadLatency)
+	ch <- prometheus.MustNewConstNativeHistogram(
+		c.metrics[metricReadLatency],
+		readCount,
+		float64(metrics.TotalReadTime)/MicrosecondsInSeconds,
+		readBuckets,
+		nil,
+		readZero,
+		nativeHistogramSchema,
+		0,
+		counters.created,
+		labels...,
+	)
+
+	writeCount, writeBuckets, writeZero := convertNativeHistogram(metrics.WriteLatency)
+	ch <- prometheus.MustNewConstNativeHistogram(
+		c.metrics[metricWriteLatency],
+		writeCount,
+		float64(metrics.TotalWriteTime)/MicrosecondsInSeconds,
+		writeBuckets,
+		nil,
+		writeZero,
+		nativeHistogramSchema,
+		0,
+		counters.created,
+		labels...,
+	)
+
+	ch <- prometheus.MustNewConstNativeHistogram(
+		c.metrics[metricVolumeQueueLengthSamples],
+		counters.queueLengthCount,
+		counters.queueLengthSum,
+		counters.queueLengthBuckets,
+		nil,
+		counters.queueLengthZero,
+		nativeHistogramSchema,
+		0,
+		counters.created,
+		labels...,
+	)
+}
+
+// getDevices returns a map of device paths to volume IDs of the devices to collect metrics from.
+func (c *NVMECollector) getDevices() (map[string]string, error) {
+	devicePaths, err := getCSIManagedDevices(c.csiMountPointPath)
+	if err != nil {
+		return nil, err
+	}
+	if len(devicePaths) == 0 && c.resolver == nil {
+		return map[string]string{}, nil
+	}
+
+	output, err := executeLsblk()
+	if err != nil {
+		return nil, fmt.Errorf("getDevices: %w", err)
+	}
+	devices, err := mapDevicePathsToVolumeIDs(devicePaths, output)
+	if err != nil {
+		return nil, fmt.Errorf("getDevices: %w", err)
+	}
+
+	if c.resolver != nil {
+		// Block volumes are published outside of csiMountPointPath, so also
+		// include every EBS device that backs a PersistentVolume of this driver
+		ebsDevices, err := mapEBSDevicesToVolumeIDs(output)
+		if err != nil {
+			return nil, fmt.Errorf("getDevices: %w", err)
+		}
+		found := make(map[string]bool, len(devices))
+		for _, volumeID := range devices {
+			found[volumeID] = true
+		}
+		for devicePath, volumeID := range ebsDevices {
+			if found[volumeID] {
+				continue
+			}
+			if _, ok := c.resolver.Resolve(volumeID); ok {
+				devices[devicePath] = volumeID
+			}
+		}
+	}
+
+	return devices, nil
+}
+
+// labelValues returns the variable label values of the metrics of volumeID.
+func (c *NVMECollector) labelValues(volumeID string) []string {
+	if c.resolver == nil {
+		return []string{volumeID}
+	}
+	info, _ := c.resolver.Resolve(volumeID)
+	return []string{volumeID, info.PersistentVolume, info.PVCNamespace, info.PVCName}
 }
 
 // convertHistogram converts the Histogram structure to a format suitable for Prometheus histogram metrics.
@@ -275,12 +407,30 @@ func convertHistogram(hist Histogram) (uint64, map[float64]uint64) {
 
 	for i := uint64(0); i < hist.BinCount && i < 64; i++ {
 		count += hist.Bins[i].Count
-		buckets[float64(hist.Bins[i].Upper)/microsecondsInSeconds] = count
+		buckets[float64(hist.Bins[i].Upper)/MicrosecondsInSeconds] = count
 	}
 
 	return count, buckets
 }
 
+// ReadEBSMetrics reads and parses the EBS log page of the NVMe device at devicePath.
+func ReadEBSMetrics(devicePath string) (EBSMetrics, error) {
+	data, err := getNVMEMetrics(devicePath)
+	if err != nil {
+		return EBSMetrics{}, err
+	}
+	return parseLogPage(data)
+}
+
+// ListEBSDevices returns a map of device paths to volume IDs of all EBS devices attached to the node.
+func ListEBSDevices() (map[string]string, error) {
+	output, err := executeLsblk()
+	if err != nil {
+		return nil, fmt.Errorf("ListEBSDevices: %w", err)
+	}
+	return mapEBSDevicesToVolumeIDs(output)
+}
+
 // getNVMEMetrics retrieves NVMe metrics by reading the log page from the NVMe device at the given path.
 func getNVMEMetrics(devicePath string) ([]byte, error) {
 	bufferLen := binary.Size(EBSMetrics{})
@@ -425,6 +575,29 @@ func mapDevicePathsToVolumeIDs(devicePaths []string, lsblkOutput []byte) (map[st
 	return m, nil
 }
 
+// mapEBSDevicesToVolumeIDs takes lsblk output, and returns a map of device paths to volume IDs of all EBS devices.
+func mapEBSDevicesToVolumeIDs(lsblkOutput []byte) (map[string]string, error) {
+	m := make(map[string]string)
+
+	var lsblkData LsblkOutput
+	if err := json.Unmarshal(lsblkOutput, &lsblkData); err != nil {
+		return nil, fmt.Errorf("mapEBSDevicesToVolumeIDs: error unmarshaling JSON: %w", err)
+	}
+
+	for _, device := range lsblkData.BlockDevices {
+		if !strings.HasPrefix(device.Serial, "vol") {
+			continue
+		}
+		volumeID := device.Serial
+		if !strings.HasPrefix(volumeID, "vol-") {
+			volumeID = "vol-" + volumeID[3:]
+		}
+		m["/dev/"+device.Name] = volumeID
+	}
+
+	return m, nil
+}
+
 func executeLsblk() ([]byte, error) {
 	// TODO: Pass context down from Prometheus handler
 	cmd := exec.CommandContext(context.TODO(), "lsblk", "-nd", "--json", "-o", "NAME,SERIAL")
@@ -434,12 +607,3 @@ func executeLsblk() ([]byte, error) {
 	}
 	return output, nil
 }
-
-func fetchDevicePathToVolumeIDMapping(devicePaths []string) (map[string]string, error) {
-	output, err := executeLsblk()
-	if err != nil {
-		return nil, fmt.Errorf("fetchDevicePathToVolumeIDMapping: %w", err)
-	}
-
-	return mapDevicePathsToVolumeIDs(devicePaths, output)
-}
@@ -0,0 +1,243 @@
+//go:build linux
+
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+const (
+	// nativeHistogramSchema is the schema of the emitted native histograms,
+	// each power of two is split into 2^nativeHistogramSchema buckets.
+	nativeHistogramSchema = 3
+
+	// volumeCountersRetention is how long the counters of a volume that is no
+	// longer attached are kept, so they continue if the volume is re-attached.
+	volumeCountersRetention = time.Hour
+)
+
+// volumeCounters holds the state of a single volume across scrapes.
+type volumeCounters struct {
+	// last is the last raw sample read from the device
+	last EBSMetrics
+	// total accumulates the deltas between samples, so that it is monotonic
+	// even when the device counters are reset by a re-attachment
+	total    EBSMetrics
+	created  time.Time
+	lastSeen time.Time
+
+	// Average latency of the operations completed between the last two samples,
+	// only set once there are two samples
+	hasLatencyAverage   bool
+	readLatencyAverage  float64
+	writeLatencyAverage float64
+
+	// Distribution of the queue length observed at each sample, which is taken at
+	// each collection, so that it counts the scrapes of every scraper
+	queueLengthBuckets map[int]int64
+	queueLengthZero    uint64
+	queueLengthCount   uint64
+	queueLengthSum     float64
+}
+
+// nvmeAccumulator computes per-scrape deltas of the NVMe log page of each volume.
+type nvmeAccumulator struct {
+	mu      sync.Mutex
+	volumes map[string]*volumeCounters
+}
+
+func newNVMEAccumulator() *nvmeAccumulator {
+	return &nvmeAccumulator{
+		volumes: make(map[string]*volumeCounters),
+	}
+}
+
+// update records a new sample of volumeID and returns a snapshot of its counters.
+func (a *nvmeAccumulator) update(volumeID string, sample EBSMetrics, now time.Time) volumeCounters {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	c, ok := a.volumes[volumeID]
+	if !ok {
+		c = &volumeCounters{
+			created:            now,
+			queueLengthBuckets: make(map[int]int64),
+		}
+		a.volumes[volumeID] = c
+	}
+
+	delta := sample
+	if ok {
+		delta = EBSMetricsDelta(c.last, sample)
+	}
+	addMetrics(&c.total, delta)
+	c.total.QueueLength = sample.QueueLength
+	// The first sample holds the operations since the volume was attached, not since a previous sample
+	c.hasLatencyAverage = ok
+	if ok {
+		c.readLatencyAverage = AverageSeconds(delta.TotalReadTime, delta.ReadOps)
+		c.writeLatencyAverage = AverageSeconds(delta.TotalWriteTime, delta.WriteOps)
+	}
+
+	c.queueLengthCount++
+	c.queueLengthSum += float64(sample.QueueLength)
+	if sample.QueueLength == 0 {
+		c.queueLengthZero++
+	} else {
+		c.queueLengthBuckets[nativeBucketIndex(float64(sample.QueueLength))]++
+	}
+
+	c.last = sample
+	c.lastSeen = now
+
+	snapshot := *c
+	snapshot.queueLengthBuckets = make(map[int]int64, len(c.queueLengthBuckets))
+	for k, v := range c.queueLengthBuckets {
+		snapshot.queueLengthBuckets[k] = v
+	}
+	return snapshot
+}
+
+// prune drops the counters of volumes that have not been seen within volumeCountersRetention.
+func (a *nvmeAccumulator) prune(now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for volumeID, c := range a.volumes {
+		if now.Sub(c.lastSeen) > volumeCountersRetention {
+			delete(a.volumes, volumeID)
+		}
+	}
+}
+
+// EBSMetricsDelta returns the counters accumulated between the samples prev and cur.
+// If the counters were reset in between, cur is returned as is.
+func EBSMetricsDelta(prev, cur EBSMetrics) EBSMetrics {
+	if isCounterReset(prev, cur) {
+		return cur
+	}
+	return subtractMetrics(cur, prev)
+}
+
+// isCounterReset returns true if any cumulative counter of cur is lower than in prev,
+// which happens when the volume was detached and re-attached between samples.
+func isCounterReset(prev, cur EBSMetrics) bool {
+	if cur.ReadOps < prev.ReadOps || cur.WriteOps < prev.WriteOps ||
+		cur.ReadBytes < prev.ReadBytes || cur.WriteBytes < prev.WriteBytes ||
+		cur.TotalReadTime < prev.TotalReadTime || cur.TotalWriteTime < prev.TotalWriteTime ||
+		cur.EBSIOPSExceeded < prev.EBSIOPSExceeded || cur.EBSThroughputExceeded < prev.EBSThroughputExceeded ||
+		cur.EC2IOPSExceeded < prev.EC2IOPSExceeded || cur.EC2ThroughputExceeded < prev.EC2ThroughputExceeded {
+		return true
+	}
+	for i := range cur.ReadLatency.Bins {
+		if cur.ReadLatency.Bins[i].Count < prev.ReadLatency.Bins[i].Count ||
+			cur.WriteLatency.Bins[i].Count < prev.WriteLatency.Bins[i].Count {
+			return true
+		}
+	}
+	return false
+}
+
+// subtractMetrics returns the counters of cur minus those of prev. Callers must
+// ensure that there was no counter reset between prev and cur.
+func subtractMetrics(cur, prev EBSMetrics) EBSMetrics {
+	delta := cur
+	delta.ReadOps -= prev.ReadOps
+	delta.WriteOps -= prev.WriteOps
+	delta.ReadBytes -= prev.ReadBytes
+	delta.WriteBytes -= prev.WriteBytes
+	delta.TotalReadTime -= prev.TotalReadTime
+	delta.TotalWriteTime -= prev.TotalWriteTime
+	delta.EBSIOPSExceeded -= prev.EBSIOPSExceeded
+	delta.EBSThroughputExceeded -= prev.EBSThroughputExceeded
+	delta.EC2IOPSExceeded -= prev.EC2IOPSExceeded
+	delta.EC2ThroughputExceeded -= prev.EC2ThroughputExceeded
+	for i := range delta.ReadLatency.Bins {
+		delta.ReadLatency.Bins[i].Count -= prev.ReadLatency.Bins[i].Count
+		delta.WriteLatency.Bins[i].Count -= prev.WriteLatency.Bins[i].Count
+	}
+	return delta
+}
+
+// addMetrics adds the counters of delta to total. Histogram bin bounds are taken from delta.
+func addMetrics(total *EBSMetrics, delta EBSMetrics) {
+	total.EBSMagic = delta.EBSMagic
+	total.ReadOps += delta.ReadOps
+	total.WriteOps += delta.WriteOps
+	total.ReadBytes += delta.ReadBytes
+	total.WriteBytes += delta.WriteBytes
+	total.TotalReadTime += delta.TotalReadTime
+	total.TotalWriteTime += delta.TotalWriteTime
+	total.EBSIOPSExceeded += delta.EBSIOPSExceeded
+	total.EBSThroughputExceeded += delta.EBSThroughputExceeded
+	total.EC2IOPSExceeded += delta.EC2IOPSExceeded
+	total.EC2ThroughputExceeded += delta.EC2ThroughputExceeded
+	addHistogram(&total.ReadLatency, delta.ReadLatency)
+	addHistogram(&total.WriteLatency, delta.WriteLatency)
+}
+
+func addHistogram(total *Histogram, delta Histogram) {
+	total.BinCount = delta.BinCount
+	for i := range delta.Bins {
+		total.Bins[i].Lower = delta.Bins[i].Lower
+		total.Bins[i].Upper = delta.Bins[i].Upper
+		total.Bins[i].Count += delta.Bins[i].Count
+	}
+}
+
+// AverageSeconds returns the average duration in seconds of ops operations that took totalMicroseconds.
+func AverageSeconds(totalMicroseconds, ops uint64) float64 {
+	if ops == 0 {
+		return 0
+	}
+	return float64(totalMicroseconds) / MicrosecondsInSeconds / float64(ops)
+}
+
+// convertNativeHistogram converts the Histogram structure to the sparse buckets of a native histogram.
+// The observations of each bin are assigned to the native bucket containing the bin's upper bound.
+func convertNativeHistogram(hist Histogram) (count uint64, buckets map[int]int64, zeroCount uint64) {
+	buckets = make(map[int]int64)
+
+	for i := uint64(0); i < hist.BinCount && i < 64; i++ {
+		bin := hist.Bins[i]
+		if bin.Count == 0 {
+			continue
+		}
+		count += bin.Count
+		if bin.Upper == 0 {
+			zeroCount += bin.Count
+			continue
+		}
+		//nolint:gosec // Bin counts are far below MaxInt64
+		buckets[nativeBucketIndex(float64(bin.Upper)/MicrosecondsInSeconds)] += int64(bin.Count)
+	}
+
+	return count, buckets, zeroCount
+}
+
+// nativeBucketIndex returns the index of the native histogram bucket of nativeHistogramSchema containing v.
+// Bucket i contains the values in (2^((i-1)/2^schema), 2^(i/2^schema)].
+func nativeBucketIndex(v float64) int {
+	frac, exp := math.Frexp(v)
+	// Exact powers of two are the upper bound of their bucket
+	if frac == 0.5 {
+		return (exp - 1) << nativeHistogramSchema
+	}
+	return int(math.Ceil(math.Log2(v) * (1 << nativeHistogramSchema)))
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package metrics
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNVMEAccumulatorUpdate(t *testing.T) {
+	now := time.Now()
+	a := newNVMEAccumulator()
+
+	sample := EBSMetrics{ReadOps: 10, TotalReadTime: 1000, WriteOps: 4, TotalWriteTime: 8000, QueueLength: 3}
+	sample.ReadLatency.BinCount = 1
+	sample.ReadLatency.Bins[0] = HistogramBin{Lower: 0, Upper: 100, Count: 10}
+
+	got := a.update("vol-1", sample, now)
+	if got.total.ReadOps != 10 || got.total.ReadLatency.Bins[0].Count != 10 {
+		t.Errorf("first update: total = %+v", got.total)
+	}
+	if got.hasLatencyAverage {
+		t.Errorf("first update: latency average set from a single sample")
+	}
+
+	// Regular increase
+	sample.ReadOps = 15
+	sample.TotalReadTime = 2000
+	sample.ReadLatency.Bins[0].Count = 15
+	sample.QueueLength = 0
+	got = a.update("vol-1", sample, now.Add(time.Minute))
+	if got.total.ReadOps != 15 || got.total.ReadLatency.Bins[0].Count != 15 {
+		t.Errorf("second update: total = %+v", got.total)
+	}
+	if !got.hasLatencyAverage {
+		t.Errorf("second update: latency average not set")
+	}
+	if got.readLatencyAverage != 0.0002 {
+		t.Errorf("second update: readLatencyAverage = %v, want 0.0002", got.readLatencyAverage)
+	}
+	if got.writeLatencyAverage != 0 {
+		t.Errorf("second update: writeLatencyAverage = %v, want 0", got.writeLatencyAverage)
+	}
+
+	// Counters reset after a re-attachment
+	sample = EBSMetrics{ReadOps: 2, TotalReadTime: 100}
+	sample.ReadLatency.BinCount = 1
+	sample.ReadLatency.Bins[0] = HistogramBin{Lower: 0, Upper: 100, Count: 2}
+	got = a.update("vol-1", sample, now.Add(2*time.Minute))
+	if got.total.ReadOps != 17 || got.total.TotalReadTime != 2100 || got.total.ReadLatency.Bins[0].Count != 17 {
+		t.Errorf("update after reset: total = %+v", got.total)
+	}
+	if got.total.WriteOps != 4 {
+		t.Errorf("update after reset: WriteOps = %v, want 4", got.total.WriteOps)
+	}
+	if !got.created.Equal(now) {
+		t.Errorf("update after reset: created = %v, want %v", got.created, now)
+	}
+
+	if got.queueLengthCount != 3 || got.queueLengthZero != 2 || got.queueLengthSum != 3 {
+		t.Errorf("queue length distribution: count = %v, zero = %v, sum = %v", got.queueLengthCount, got.queueLengthZero, got.queueLengthSum)
+	}
+
+	a.prune(now.Add(2*time.Minute + volumeCountersRetention + time.Second))
+	if len(a.volumes) != 0 {
+		t.Errorf("prune: %d volumes remaining, want 0", len(a.volumes))
+	}
+}
+
+func TestConvertNativeHistogram(t *testing.T) {
+	hist := Histogram{
+		BinCount: 4,
+		Bins: [64]HistogramBin{
+			{Lower: 0, Upper: 0, Count: 1},
+			{Lower: 0, Upper: 1024, Count: 5},
+			{Lower: 1024, Upper: 2048, Count: 3},
+			{Lower: 2048, Upper: 4096, Count: 0},
+		},
+	}
+
+	count, buckets, zero := convertNativeHistogram(hist)
+	if count != 9 {
+		t.Errorf("convertNativeHistogram() count = %v, want 9", count)
+	}
+	if zero != 1 {
+		t.Errorf("convertNativeHistogram() zero = %v, want 1", zero)
+	}
+	want := map[int]int64{
+		nativeBucketIndex(1024 / 1e6): 5,
+		nativeBucketIndex(2048 / 1e6): 3,
+	}
+	if !reflect.DeepEqual(buckets, want) {
+		t.Errorf("convertNativeHistogram() buckets = %v, want %v", buckets, want)
+	}
+}
+
+func TestNativeBucketIndex(t *testing.T) {
+	tests := []struct {
+		value float64
+		want  int
+	}{
+		{value: 1, want: 0},
+		{value: 2, want: 8},
+		{value: 0.5, want: -8},
+		{value: 1.05, want: 1},
+		{value: 1.5, want: 5},
+	}
+	for _, tt := range tests {
+		if got := nativeBucketIndex(tt.value); got != tt.want {
+			t.Errorf("nativeBucketIndex(%v) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
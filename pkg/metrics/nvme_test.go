@@ -30,7 +30,7 @@ func TestNewNVMECollector(t *testing.T) {
 	expectedPath := "/test/path/"
 	testInstanceID := "test-instance-1"
 
-	collector := NewNVMECollector(testPath, testInstanceID)
+	collector := NewNVMECollector(testPath, testInstanceID, nil, false)
 
 	if collector == nil {
 		t.Fatal("NewNVMECollector returned nil")
@@ -59,6 +59,8 @@ func TestNewNVMECollector(t *testing.T) {
 		metricVolumeQueueLength,
 		metricReadLatency,
 		metricWriteLatency,
+		metricReadLatencyAverage,
+		metricWriteLatencyAverage,
 	}
 
 	for _, metricName := range expectedMetrics {
@@ -329,3 +331,65 @@ func TestMapDevicePathsToVolumeIDs(t *testing.T) {
 		})
 	}
 }
+
+type fakeVolumeResolver map[string]VolumeInfo
+
+func (f fakeVolumeResolver) Resolve(volumeID string) (VolumeInfo, bool) {
+	info, ok := f[volumeID]
+	return info, ok
+}
+
+func TestMapEBSDevicesToVolumeIDs(t *testing.T) {
+	lsblkOutput, err := json.Marshal(LsblkOutput{
+		BlockDevices: []BlockDevice{
+			{Name: "nvme0n1", Serial: "vol111111111"},
+			{Name: "nvme1n1", Serial: "vol-222222222"},
+			{Name: "nvme2n1", Serial: "AWS1234567890"},
+			{Name: "loop0", Serial: ""},
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to create test data: %v", err)
+	}
+
+	got, err := mapEBSDevicesToVolumeIDs(lsblkOutput)
+	if err != nil {
+		t.Fatalf("mapEBSDevicesToVolumeIDs() error = %v", err)
+	}
+	want := map[string]string{
+		"/dev/nvme0n1": "vol-111111111",
+		"/dev/nvme1n1": "vol-222222222",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mapEBSDevicesToVolumeIDs() = %v, want %v", got, want)
+	}
+
+	if _, err := mapEBSDevicesToVolumeIDs([]byte(`invalid json`)); err == nil {
+		t.Error("mapEBSDevicesToVolumeIDs() expected error for invalid json")
+	}
+}
+
+func TestNVMECollectorLabelValues(t *testing.T) {
+	resolver := fakeVolumeResolver{
+		"vol-111111111": {
+			PersistentVolume: "pv-1",
+			PVCNamespace:     "default",
+			PVCName:          "claim-1",
+		},
+	}
+
+	collector := NewNVMECollector("/var/lib/kubelet", "i-1234", nil, false)
+	if got := collector.labelValues("vol-111111111"); !reflect.DeepEqual(got, []string{"vol-111111111"}) {
+		t.Errorf("labelValues() without resolver = %v", got)
+	}
+
+	collector = NewNVMECollector("/var/lib/kubelet", "i-1234", resolver, false)
+	want := []string{"vol-111111111", "pv-1", "default", "claim-1"}
+	if got := collector.labelValues("vol-111111111"); !reflect.DeepEqual(got, want) {
+		t.Errorf("labelValues() = %v, want %v", got, want)
+	}
+	want = []string{"vol-222222222", "", "", ""}
+	if got := collector.labelValues("vol-222222222"); !reflect.DeepEqual(got, want) {
+		t.Errorf("labelValues() for unknown volume = %v, want %v", got, want)
+	}
+}
@@ -18,6 +18,6 @@ package metrics
 
 import "k8s.io/klog/v2"
 
-func registerNVMECollector(_ *MetricRecorder, _, _ string) {
+func registerNVMECollector(_ *MetricRecorder, _, _ string, _ VolumeResolver, _ bool) {
 	klog.InfoS("NVMe metric collection is not supported on this platform")
 }
@@ -0,0 +1,111 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	volumeHandleIndex = "volumeHandle"
+
+	volumeResolverResync = 10 * time.Minute
+)
+
+// kubernetesVolumeLabels are the labels added to per-volume metrics when a VolumeResolver is configured.
+// The namespace of the claim is not exposed as "namespace", which is the label of the scraped pod.
+// There is no pod label: pods come and go far more often than volumes, each of them would start a
+// new series, and they can be joined from kube-state-metrics instead.
+var kubernetesVolumeLabels = []string{"persistentvolume", "persistentvolumeclaim_namespace", "persistentvolumeclaim"}
+
+// VolumeInfo identifies the Kubernetes objects that use an EBS volume.
+type VolumeInfo struct {
+	PersistentVolume string
+	PVCNamespace     string
+	PVCName          string
+}
+
+// VolumeResolver resolves EBS volume IDs to the Kubernetes objects that use them.
+type VolumeResolver interface {
+	// Resolve returns the VolumeInfo of volumeID, or false if no PersistentVolume
+	// of this driver references volumeID.
+	Resolve(volumeID string) (VolumeInfo, bool)
+}
+
+// KubernetesVolumeResolver implements VolumeResolver using an informer cache of PersistentVolumes.
+type KubernetesVolumeResolver struct {
+	pvIndexer cache.Indexer
+}
+
+// NewKubernetesVolumeResolver starts a PersistentVolume informer and returns a
+// KubernetesVolumeResolver backed by it. The informer runs until ctx is done.
+func NewKubernetesVolumeResolver(ctx context.Context, clientset kubernetes.Interface, driverName string) (*KubernetesVolumeResolver, error) {
+	factory := informers.NewSharedInformerFactory(clientset, volumeResolverResync)
+	pvInformer := factory.Core().V1().PersistentVolumes().Informer()
+	if err := pvInformer.AddIndexers(cache.Indexers{volumeHandleIndex: volumeHandleIndexFunc(driverName)}); err != nil {
+		return nil, fmt.Errorf("failed to add PersistentVolume indexer: %w", err)
+	}
+
+	factory.Start(ctx.Done())
+	go func() {
+		if !cache.WaitForCacheSync(ctx.Done(), pvInformer.HasSynced) {
+			klog.ErrorS(nil, "Volume resolver: cache sync failed, NVMe metrics may be missing Kubernetes labels")
+			return
+		}
+		klog.V(4).InfoS("Volume resolver: caches synced")
+	}()
+
+	return &KubernetesVolumeResolver{
+		pvIndexer: pvInformer.GetIndexer(),
+	}, nil
+}
+
+// Resolve implements VolumeResolver.
+func (r *KubernetesVolumeResolver) Resolve(volumeID string) (VolumeInfo, bool) {
+	objs, err := r.pvIndexer.ByIndex(volumeHandleIndex, volumeID)
+	if err != nil || len(objs) == 0 {
+		return VolumeInfo{}, false
+	}
+	pv, ok := objs[0].(*corev1.PersistentVolume)
+	if !ok {
+		return VolumeInfo{}, false
+	}
+
+	info := VolumeInfo{PersistentVolume: pv.Name}
+	if claimRef := pv.Spec.ClaimRef; claimRef != nil {
+		info.PVCNamespace = claimRef.Namespace
+		info.PVCName = claimRef.Name
+	}
+	return info, true
+}
+
+// volumeHandleIndexFunc indexes PersistentVolumes of driverName by their volume handle.
+func volumeHandleIndexFunc(driverName string) cache.IndexFunc {
+	return func(obj any) ([]string, error) {
+		pv, ok := obj.(*corev1.PersistentVolume)
+		if !ok || pv.Spec.CSI == nil || pv.Spec.CSI.Driver != driverName {
+			return nil, nil
+		}
+		return []string{pv.Spec.CSI.VolumeHandle}, nil
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 The Kubernetes Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the 'License');
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an 'AS IS' BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestKubernetesVolumeResolver(t *testing.T) {
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{Driver: "ebs.csi.aws.com", VolumeHandle: "vol-111111111"},
+			},
+			ClaimRef: &corev1.ObjectReference{Namespace: "default", Name: "claim-1"},
+		},
+	}
+	otherDriverPV := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-2"},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{Driver: "other.csi.aws.com", VolumeHandle: "vol-222222222"},
+			},
+		},
+	}
+	client := fake.NewClientset(pv, otherDriverPV)
+	resolver, err := NewKubernetesVolumeResolver(t.Context(), client, "ebs.csi.aws.com")
+	if err != nil {
+		t.Fatalf("NewKubernetesVolumeResolver() error = %v", err)
+	}
+
+	var info VolumeInfo
+	err = wait.PollUntilContextTimeout(t.Context(), 10*time.Millisecond, 5*time.Second, true, func(_ context.Context) (bool, error) {
+		var ok bool
+		info, ok = resolver.Resolve("vol-111111111")
+		return ok, nil
+	})
+	if err != nil {
+		t.Fatalf("volume was not resolved: %v", err)
+	}
+
+	want := VolumeInfo{PersistentVolume: "pv-1", PVCNamespace: "default", PVCName: "claim-1"}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("Resolve() = %v, want %v", info, want)
+	}
+	if _, ok := resolver.Resolve("vol-222222222"); ok {
+		t.Error("Resolve() resolved a volume of another driver")
+	}
+}
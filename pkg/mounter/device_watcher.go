@@ -0,0 +1,141 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mounter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// DeviceWatcher maintains an index of EBS volume IDs to the NVMe devices they
+// are attached as. The index is kept up to date by a platform-specific event
+// source (kernel uevents on Linux), so callers can wait for a device to appear
+// instead of polling for it.
+type DeviceWatcher struct {
+	mu sync.Mutex
+	// devices maps volume ID (in vol-xxx form) to device path
+	devices map[string]string
+	// volumes maps device path to volume ID, used when a device is removed
+	volumes map[string]string
+	// waiters are notified with the device path once a volume ID is indexed
+	waiters map[string][]chan string
+}
+
+func newDeviceWatcher() *DeviceWatcher {
+	return &DeviceWatcher{
+		devices: make(map[string]string),
+		volumes: make(map[string]string),
+		waiters: make(map[string][]chan string),
+	}
+}
+
+// Lookup returns the device path currently indexed for volumeID.
+func (w *DeviceWatcher) Lookup(volumeID string) (string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	devicePath, ok := w.devices[normalizeVolumeID(volumeID)]
+	return devicePath, ok
+}
+
+// WaitForDevice returns the device path of volumeID, blocking until the device
+// is indexed or ctx is done.
+func (w *DeviceWatcher) WaitForDevice(ctx context.Context, volumeID string) (string, error) {
+	volumeID = normalizeVolumeID(volumeID)
+
+	w.mu.Lock()
+	if devicePath, ok := w.devices[volumeID]; ok {
+		w.mu.Unlock()
+		return devicePath, nil
+	}
+	ch := make(chan string, 1)
+	w.waiters[volumeID] = append(w.waiters[volumeID], ch)
+	w.mu.Unlock()
+
+	select {
+	case devicePath := <-ch:
+		return devicePath, nil
+	case <-ctx.Done():
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		// The device may have been indexed between ctx expiring and acquiring the lock
+		select {
+		case devicePath := <-ch:
+			return devicePath, nil
+		default:
+		}
+		w.waiters[volumeID] = removeWaiter(w.waiters[volumeID], ch)
+		if len(w.waiters[volumeID]) == 0 {
+			delete(w.waiters, volumeID)
+		}
+		return "", fmt.Errorf("timed out waiting for device of volume %q: %w", volumeID, ctx.Err())
+	}
+}
+
+// addDevice indexes devicePath as the device of volumeID and wakes up any waiters.
+func (w *DeviceWatcher) addDevice(devicePath, volumeID string) {
+	volumeID = normalizeVolumeID(volumeID)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// A device name may be reused by a different volume after a detach
+	if oldVolumeID, ok := w.volumes[devicePath]; ok && oldVolumeID != volumeID {
+		delete(w.devices, oldVolumeID)
+	}
+	w.devices[volumeID] = devicePath
+	w.volumes[devicePath] = volumeID
+
+	for _, ch := range w.waiters[volumeID] {
+		ch <- devicePath
+	}
+	delete(w.waiters, volumeID)
+}
+
+// removeDevice drops devicePath from the index.
+func (w *DeviceWatcher) removeDevice(devicePath string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	volumeID, ok := w.volumes[devicePath]
+	if !ok {
+		return
+	}
+	delete(w.volumes, devicePath)
+	if w.devices[volumeID] == devicePath {
+		delete(w.devices, volumeID)
+	}
+}
+
+func removeWaiter(waiters []chan string, ch chan string) []chan string {
+	for i, waiter := range waiters {
+		if waiter == ch {
+			return append(waiters[:i], waiters[i+1:]...)
+		}
+	}
+	return waiters
+}
+
+// normalizeVolumeID converts an NVMe serial such as vol0123 to a volume ID such as vol-0123.
+func normalizeVolumeID(volumeID string) string {
+	volumeID = strings.TrimSpace(volumeID)
+	if strings.HasPrefix(volumeID, "vol") && !strings.HasPrefix(volumeID, "vol-") {
+		return "vol-" + volumeID[3:]
+	}
+	return volumeID
+}
@@ -0,0 +1,171 @@
+//go:build linux
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mounter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+	"k8s.io/klog/v2"
+)
+
+const (
+	sysBlockPath = "/sys/block"
+	ebsNVMeModel = "Amazon Elastic Block Store"
+
+	// ueventKernelGroup is the netlink multicast group the kernel broadcasts uevents to.
+	ueventKernelGroup = 1
+	ueventBufferSize  = 64 * 1024
+)
+
+// StartDeviceWatcher subscribes to kernel block device uevents and returns a
+// DeviceWatcher whose index is kept up to date until ctx is done.
+//
+// Kernel uevents are only broadcast to the host network namespace, so the node
+// plugin must run with hostNetwork for the watcher to observe attachments.
+func StartDeviceWatcher(ctx context.Context) (*DeviceWatcher, error) {
+	fd, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_KOBJECT_UEVENT)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create uevent socket: %w", err)
+	}
+	if err = unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: ueventKernelGroup}); err != nil {
+		_ = unix.Close(fd)
+		return nil, fmt.Errorf("failed to bind uevent socket: %w", err)
+	}
+	// Wake up periodically so the watcher notices ctx being done
+	if err = unix.SetsockoptTimeval(fd, unix.SOL_SOCKET, unix.SO_RCVTIMEO, &unix.Timeval{Sec: 1}); err != nil {
+		_ = unix.Close(fd)
+		return nil, fmt.Errorf("failed to set uevent socket timeout: %w", err)
+	}
+
+	w := newDeviceWatcher()
+	// Scan after subscribing so that devices added in between are not missed
+	w.scan(sysBlockPath)
+	go w.run(ctx, fd)
+	return w, nil
+}
+
+// run reads uevents from fd and updates the index until ctx is done.
+func (w *DeviceWatcher) run(ctx context.Context, fd int) {
+	defer func() {
+		if err := unix.Close(fd); err != nil {
+			klog.ErrorS(err, "Failed to close uevent socket")
+		}
+	}()
+
+	buf := make([]byte, ueventBufferSize)
+	for ctx.Err() == nil {
+		n, _, err := unix.Recvfrom(fd, buf, 0)
+		if err != nil {
+			switch {
+			case errors.Is(err, unix.EAGAIN), errors.Is(err, unix.EINTR):
+			case errors.Is(err, unix.ENOBUFS):
+				// The socket overflowed and events were dropped, rebuild the index from sysfs
+				klog.V(4).InfoS("Uevent socket overflowed, rescanning block devices")
+				w.scan(sysBlockPath)
+			default:
+				klog.ErrorS(err, "Failed to read uevent")
+			}
+			continue
+		}
+		w.handleUevent(parseUevent(buf[:n]), sysBlockPath)
+	}
+}
+
+// handleUevent updates the index from a single parsed uevent.
+func (w *DeviceWatcher) handleUevent(env map[string]string, sysPath string) {
+	if env["SUBSYSTEM"] != "block" || env["DEVTYPE"] != "disk" {
+		return
+	}
+	name := env["DEVNAME"]
+	if !strings.HasPrefix(name, "nvme") {
+		return
+	}
+
+	switch env["ACTION"] {
+	case "add", "change":
+		w.indexDevice(sysPath, name)
+	case "remove":
+		klog.V(5).InfoS("[Debug] NVMe device removed", "device", name)
+		w.removeDevice(filepath.Join("/dev", name))
+	}
+}
+
+// scan indexes every EBS NVMe device currently present under sysPath.
+func (w *DeviceWatcher) scan(sysPath string) {
+	entries, err := os.ReadDir(sysPath)
+	if err != nil {
+		klog.ErrorS(err, "Failed to list block devices", "path", sysPath)
+		return
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "nvme") {
+			w.indexDevice(sysPath, entry.Name())
+		}
+	}
+}
+
+// indexDevice reads the model and serial of an NVMe namespace from sysfs and
+// indexes it if it is an EBS volume.
+func (w *DeviceWatcher) indexDevice(sysPath, name string) {
+	deviceDir := filepath.Join(sysPath, name, "device")
+	model, err := os.ReadFile(filepath.Join(deviceDir, "model"))
+	if err != nil {
+		klog.V(5).InfoS("[Debug] Failed to read NVMe device model", "device", name, "err", err)
+		return
+	}
+	if strings.TrimSpace(string(model)) != ebsNVMeModel {
+		return
+	}
+	serial, err := os.ReadFile(filepath.Join(deviceDir, "serial"))
+	if err != nil {
+		klog.V(5).InfoS("[Debug] Failed to read NVMe device serial", "device", name, "err", err)
+		return
+	}
+
+	volumeID := normalizeVolumeID(string(serial))
+	if !strings.HasPrefix(volumeID, "vol-") {
+		return
+	}
+	devicePath := filepath.Join("/dev", name)
+	klog.V(5).InfoS("[Debug] Indexed NVMe device", "device", devicePath, "volumeID", volumeID)
+	w.addDevice(devicePath, volumeID)
+}
+
+// parseUevent parses a kernel uevent message of the form
+// "action@devpath\0KEY=VALUE\0KEY=VALUE\0..." into its environment.
+func parseUevent(msg []byte) map[string]string {
+	env := make(map[string]string)
+	for i, field := range bytes.Split(msg, []byte{0}) {
+		// The first field is the "action@devpath" header, which is repeated in the environment
+		if i == 0 {
+			continue
+		}
+		if key, value, ok := strings.Cut(string(field), "="); ok {
+			env[key] = value
+		}
+	}
+	return env
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mounter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceWatcherWaitForDevice(t *testing.T) {
+	t.Run("already indexed", func(t *testing.T) {
+		w := newDeviceWatcher()
+		w.addDevice("/dev/nvme1n1", "vol11111111111111111")
+
+		devicePath, err := w.WaitForDevice(t.Context(), "vol-11111111111111111")
+		require.NoError(t, err)
+		assert.Equal(t, "/dev/nvme1n1", devicePath)
+	})
+
+	t.Run("indexed while waiting", func(t *testing.T) {
+		w := newDeviceWatcher()
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			w.addDevice("/dev/nvme2n1", "vol-22222222222222222")
+		}()
+
+		ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+		defer cancel()
+		devicePath, err := w.WaitForDevice(ctx, "vol-22222222222222222")
+		require.NoError(t, err)
+		assert.Equal(t, "/dev/nvme2n1", devicePath)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		w := newDeviceWatcher()
+		ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := w.WaitForDevice(ctx, "vol-33333333333333333")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Empty(t, w.waiters)
+	})
+}
+
+func TestDeviceWatcherRemoveDevice(t *testing.T) {
+	w := newDeviceWatcher()
+	w.addDevice("/dev/nvme1n1", "vol-11111111111111111")
+	w.removeDevice("/dev/nvme1n1")
+
+	_, ok := w.Lookup("vol-11111111111111111")
+	assert.False(t, ok)
+
+	// A reused device name must not resolve to the previous volume
+	w.addDevice("/dev/nvme1n1", "vol-11111111111111111")
+	w.addDevice("/dev/nvme1n1", "vol-22222222222222222")
+	_, ok = w.Lookup("vol-11111111111111111")
+	assert.False(t, ok)
+	devicePath, ok := w.Lookup("vol-22222222222222222")
+	assert.True(t, ok)
+	assert.Equal(t, "/dev/nvme1n1", devicePath)
+}
+
+func TestNormalizeVolumeID(t *testing.T) {
+	testCases := map[string]string{
+		"vol11111111111111111":        "vol-11111111111111111",
+		"vol-11111111111111111":       "vol-11111111111111111",
+		"vol11111111111111111     \n": "vol-11111111111111111",
+		"AWS1234":                     "AWS1234",
+	}
+	for input, expected := range testCases {
+		assert.Equal(t, expected, normalizeVolumeID(input))
+	}
+}
@@ -0,0 +1,122 @@
+//go:build linux
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mounter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// lvmUUIDPrefix is the prefix of the device-mapper UUID of LVM logical volumes.
+	lvmUUIDPrefix = "LVM-"
+
+	// Outputs of growpart and lvextend when there is no space to grow into. Both commands
+	// exit with an error in that case, which is expected when NodeExpandVolume is retried.
+	growpartNoChange      = "NOCHANGE"
+	lvextendNoChange      = "matches existing size"
+	lvextendNoFreeExtents = "insufficient free space"
+)
+
+// GrowDevice grows the partition or LVM logical volume devicePath, and the partitions and
+// LVM physical volumes below it, to fill the space added to the underlying disks.
+// It is a no-op for whole disks.
+func (m *NodeMounter) GrowDevice(devicePath string) error {
+	return growDevice(devicePath, sysBlockPath, execRunner)
+}
+
+func growDevice(devicePath, sysBlock string, execRunner func(string, ...string) ([]byte, error)) error {
+	resolved, err := filepath.EvalSymlinks(devicePath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", devicePath, err)
+	}
+	name := filepath.Base(resolved)
+
+	if !strings.HasPrefix(name, "dm-") {
+		return growPartition(name, sysBlock, execRunner)
+	}
+
+	uuid, err := os.ReadFile(filepath.Join(sysBlock, name, "dm", "uuid"))
+	if err != nil {
+		return fmt.Errorf("failed to read device-mapper UUID of %s: %w", devicePath, err)
+	}
+	if !strings.HasPrefix(string(uuid), lvmUUIDPrefix) {
+		klog.V(4).InfoS("Device is not an LVM logical volume, not growing it", "devicePath", devicePath)
+		return nil
+	}
+	dmName, err := os.ReadFile(filepath.Join(sysBlock, name, "dm", "name"))
+	if err != nil {
+		return fmt.Errorf("failed to read device-mapper name of %s: %w", devicePath, err)
+	}
+	// LVM tools do not accept /dev/dm-N paths
+	lv := "/dev/mapper/" + strings.TrimSpace(string(dmName))
+
+	slaves, err := os.ReadDir(filepath.Join(sysBlock, name, "slaves"))
+	if err != nil {
+		return fmt.Errorf("failed to list physical volumes of %s: %w", lv, err)
+	}
+	pvs := make([]string, 0, len(slaves))
+	for _, slave := range slaves {
+		if err := growPartition(slave.Name(), sysBlock, execRunner); err != nil {
+			return err
+		}
+		pv := "/dev/" + slave.Name()
+		klog.V(4).InfoS("Resizing LVM physical volume", "pv", pv)
+		if output, err := execRunner("pvresize", pv); err != nil {
+			return fmt.Errorf("failed to resize physical volume %s: %w, output: %s", pv, err, string(output))
+		}
+		pvs = append(pvs, pv)
+	}
+
+	// Only allocate the free extents of the physical volumes of lv, other physical volumes
+	// of the volume group may belong to other volumes
+	klog.V(4).InfoS("Extending LVM logical volume", "lv", lv, "pvs", pvs)
+	output, err := execRunner("lvextend", append([]string{"--extents", "+100%FREE", lv}, pvs...)...)
+	if err != nil && !strings.Contains(string(output), lvextendNoChange) && !strings.Contains(string(output), lvextendNoFreeExtents) {
+		return fmt.Errorf("failed to extend logical volume %s: %w, output: %s", lv, err, string(output))
+	}
+	return nil
+}
+
+// growPartition grows the partition name to the end of its disk, or up to the next partition.
+// It is a no-op if name is not a partition.
+func growPartition(name, sysBlock string, execRunner func(string, ...string) ([]byte, error)) error {
+	// Partitions are listed in the directory of their disk
+	matches, err := filepath.Glob(filepath.Join(sysBlock, "*", name, "partition"))
+	if err != nil || len(matches) == 0 {
+		return nil
+	}
+	number, err := os.ReadFile(matches[0])
+	if err != nil {
+		return fmt.Errorf("failed to read partition number of %s: %w", name, err)
+	}
+	disk := "/dev/" + filepath.Base(filepath.Dir(filepath.Dir(matches[0])))
+	partition := strings.TrimSpace(string(number))
+
+	klog.V(4).InfoS("Growing partition", "disk", disk, "partition", partition)
+	output, err := execRunner("growpart", disk, partition)
+	if err != nil && !strings.Contains(string(output), growpartNoChange) {
+		return fmt.Errorf("failed to grow partition %s of %s: %w, output: %s", partition, disk, err, string(output))
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+//go:build linux
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mounter
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrowDevice(t *testing.T) {
+	testCases := []struct {
+		name        string
+		device      string
+		outputs     map[string]string
+		failing     map[string]bool
+		expectedErr string
+		expected    []string
+	}{
+		{
+			name:   "whole disk",
+			device: "nvme1n1",
+		},
+		{
+			name:     "partition",
+			device:   "nvme1n1p2",
+			expected: []string{"growpart /dev/nvme1n1 2"},
+		},
+		{
+			name:     "partition already grown",
+			device:   "nvme1n1p2",
+			outputs:  map[string]string{"growpart": "NOCHANGE: partition 2 is size 2095104. it cannot be grown"},
+			failing:  map[string]bool{"growpart": true},
+			expected: []string{"growpart /dev/nvme1n1 2"},
+		},
+		{
+			name:        "partition error",
+			device:      "nvme1n1p2",
+			outputs:     map[string]string{"growpart": "FAILED: sfdisk not found"},
+			failing:     map[string]bool{"growpart": true},
+			expectedErr: "FAILED: sfdisk not found",
+			expected:    []string{"growpart /dev/nvme1n1 2"},
+		},
+		{
+			name:   "logical volume",
+			device: "dm-0",
+			expected: []string{
+				"growpart /dev/nvme1n1 2",
+				"pvresize /dev/nvme1n1p2",
+				"lvextend --extents +100%FREE /dev/mapper/data-lv /dev/nvme1n1p2",
+			},
+		},
+		{
+			name:    "logical volume already extended",
+			device:  "dm-0",
+			outputs: map[string]string{"lvextend": "New size (255 extents) matches existing size (255 extents)."},
+			failing: map[string]bool{"lvextend": true},
+			expected: []string{
+				"growpart /dev/nvme1n1 2",
+				"pvresize /dev/nvme1n1p2",
+				"lvextend --extents +100%FREE /dev/mapper/data-lv /dev/nvme1n1p2",
+			},
+		},
+		{
+			name:        "physical volume error",
+			device:      "dm-0",
+			outputs:     map[string]string{"pvresize": "Can't open /dev/nvme1n1p2 exclusively."},
+			failing:     map[string]bool{"pvresize": true},
+			expectedErr: "failed to resize physical volume /dev/nvme1n1p2",
+			expected: []string{
+				"growpart /dev/nvme1n1 2",
+				"pvresize /dev/nvme1n1p2",
+			},
+		},
+		{
+			name:   "device-mapper device that is not a logical volume",
+			device: "dm-1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			sysBlock := filepath.Join(root, "sys")
+			dev := filepath.Join(root, "dev")
+			writeFile := func(path, content string) {
+				require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+				require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+			}
+			writeFile(filepath.Join(sysBlock, "nvme1n1", "nvme1n1p2", "partition"), "2\n")
+			writeFile(filepath.Join(sysBlock, "dm-0", "dm", "uuid"), "LVM-0123456789abcdef\n")
+			writeFile(filepath.Join(sysBlock, "dm-0", "dm", "name"), "data-lv\n")
+			writeFile(filepath.Join(sysBlock, "dm-0", "slaves", "nvme1n1p2"), "")
+			writeFile(filepath.Join(sysBlock, "dm-1", "dm", "uuid"), "CRYPT-LUKS2-0123456789abcdef\n")
+			writeFile(filepath.Join(dev, tc.device), "")
+
+			var calls []string
+			runner := func(name string, args ...string) ([]byte, error) {
+				calls = append(calls, strings.Join(append([]string{name}, args...), " "))
+				if tc.failing[name] {
+					return []byte(tc.outputs[name]), errors.New("exit status 1")
+				}
+				return []byte(tc.outputs[name]), nil
+			}
+
+			err := growDevice(filepath.Join(dev, tc.device), sysBlock, runner)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.expected, calls)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+//go:build linux
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mounter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// instanceStoreModel is the NVMe model number of EC2 instance store disks.
+	instanceStoreModel = "Amazon EC2 NVMe Instance Storage"
+	// instanceStoreRAIDDevice is the md array assembled from the instance store disks.
+	instanceStoreRAIDDevice = "/dev/md/ebs-csi-instance-store"
+)
+
+// instanceStoreRAIDMutex serializes the creation of the RAID0 array by concurrent NodeStageVolume calls.
+var instanceStoreRAIDMutex sync.Mutex
+
+// instanceStoreDisk is an instance store NVMe disk of the node.
+type instanceStoreDisk struct {
+	// Device is the path of the disk, for example /dev/nvme1n1.
+	Device string
+	Serial string
+}
+
+// FindInstanceStoreDevice returns the device of the instance store disks selected by selector:
+// the index of a disk in the disks of the node ordered by serial, or InstanceStoreRAID0 for a
+// RAID0 array of all of them, which is created if it does not exist yet.
+func (m *NodeMounter) FindInstanceStoreDevice(selector string) (string, error) {
+	disks, err := findInstanceStoreDisks(sysBlockPath)
+	if err != nil {
+		return "", err
+	}
+	if len(disks) == 0 {
+		return "", errors.New("no instance store disk found")
+	}
+
+	if selector != InstanceStoreRAID0 {
+		index, err := strconv.Atoi(selector)
+		if err != nil || index < 0 {
+			return "", fmt.Errorf("invalid instance store selector %q", selector)
+		}
+		if index >= len(disks) {
+			return "", fmt.Errorf("instance store disk %d not found, the node has %d", index, len(disks))
+		}
+		return disks[index].Device, nil
+	}
+
+	if len(disks) == 1 {
+		return disks[0].Device, nil
+	}
+	return ensureInstanceStoreRAID0(disks, execRunner)
+}
+
+// findInstanceStoreDisks returns the instance store disks found in sysBlock, ordered by serial.
+func findInstanceStoreDisks(sysBlock string) ([]instanceStoreDisk, error) {
+	entries, err := os.ReadDir(sysBlock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list block devices: %w", err)
+	}
+
+	var disks []instanceStoreDisk
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, "nvme") {
+			continue
+		}
+		model, err := os.ReadFile(filepath.Join(sysBlock, name, "device", "model"))
+		if err != nil || strings.TrimSpace(string(model)) != instanceStoreModel {
+			continue
+		}
+		serial, err := os.ReadFile(filepath.Join(sysBlock, name, "device", "serial"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read serial of %s: %w", name, err)
+		}
+		disks = append(disks, instanceStoreDisk{Device: "/dev/" + name, Serial: strings.TrimSpace(string(serial))})
+	}
+	slices.SortFunc(disks, func(a, b instanceStoreDisk) int {
+		return strings.Compare(a.Serial, b.Serial)
+	})
+	return disks, nil
+}
+
+// ensureInstanceStoreRAID0 returns the RAID0 array of disks, creating it with mdadm if needed.
+// Instance store disks are erased when an instance stops, so the array is never reassembled.
+func ensureInstanceStoreRAID0(disks []instanceStoreDisk, execRunner func(string, ...string) ([]byte, error)) (string, error) {
+	instanceStoreRAIDMutex.Lock()
+	defer instanceStoreRAIDMutex.Unlock()
+
+	if device, err := filepath.EvalSymlinks(instanceStoreRAIDDevice); err == nil {
+		return device, nil
+	}
+
+	args := []string{"--create", instanceStoreRAIDDevice, "--run", "--level=0", "--raid-devices=" + strconv.Itoa(len(disks))}
+	for _, disk := range disks {
+		args = append(args, disk.Device)
+	}
+	klog.InfoS("Creating RAID0 array of instance store disks", "device", instanceStoreRAIDDevice, "disks", len(disks))
+	if output, err := execRunner("mdadm", args...); err != nil {
+		return "", fmt.Errorf("failed to create RAID0 array of instance store disks: %w; output: %s", err, output)
+	}
+
+	device, err := filepath.EvalSymlinks(instanceStoreRAIDDevice)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", instanceStoreRAIDDevice, err)
+	}
+	return device, nil
+}
@@ -0,0 +1,60 @@
+//go:build linux
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mounter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindInstanceStoreDisks(t *testing.T) {
+	sysBlock := t.TempDir()
+	fakeDisk := func(name, model, serial string) {
+		dir := filepath.Join(sysBlock, name, "device")
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "model"), []byte(model+"                    \n"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "serial"), []byte(serial+"\n"), 0o600))
+	}
+	fakeDisk("nvme0n1", "Amazon Elastic Block Store", "vol0123456789abcdef0")
+	fakeDisk("nvme1n1", instanceStoreModel, "AWS2B1C1A0E9F5D4E3C2")
+	fakeDisk("nvme2n1", instanceStoreModel, "AWS1A0E9F5D4E3C2B1C1")
+	require.NoError(t, os.MkdirAll(filepath.Join(sysBlock, "loop0"), 0o755))
+
+	disks, err := findInstanceStoreDisks(sysBlock)
+	require.NoError(t, err)
+	assert.Equal(t, []instanceStoreDisk{
+		{Device: "/dev/nvme2n1", Serial: "AWS1A0E9F5D4E3C2B1C1"},
+		{Device: "/dev/nvme1n1", Serial: "AWS2B1C1A0E9F5D4E3C2"},
+	}, disks)
+}
+
+func TestEnsureInstanceStoreRAID0Error(t *testing.T) {
+	var gotArgs []string
+	runner := func(name string, args ...string) ([]byte, error) {
+		gotArgs = append([]string{name}, args...)
+		return []byte("mdadm: cannot open /dev/nvme1n1: Device or resource busy"), os.ErrPermission
+	}
+	_, err := ensureInstanceStoreRAID0([]instanceStoreDisk{{Device: "/dev/nvme1n1"}, {Device: "/dev/nvme2n1"}}, runner)
+	require.ErrorContains(t, err, "Device or resource busy")
+	assert.Equal(t, []string{"mdadm", "--create", instanceStoreRAIDDevice, "--run", "--level=0", "--raid-devices=2", "/dev/nvme1n1", "/dev/nvme2n1"}, gotArgs)
+}
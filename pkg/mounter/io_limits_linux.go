@@ -0,0 +1,217 @@
+//go:build linux
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mounter
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// cgroupRoot is where the host's cgroup v2 hierarchy is expected to be mounted.
+	cgroupRoot = "/sys/fs/cgroup"
+	ioMaxFile  = "io.max"
+
+	// maxPodCgroupDepth is how deep below the kubepods cgroup the pod cgroup is searched,
+	// pods of the Burstable and BestEffort QoS classes are nested in a per-class cgroup.
+	maxPodCgroupDepth = 2
+)
+
+var (
+	// Filesystem volumes are published to /var/lib/kubelet/pods/<pod UID>/volumes/kubernetes.io~csi/<PV>/mount.
+	filesystemTargetPodUID = regexp.MustCompile(`/pods/([0-9a-f-]{36})/volumes/`)
+	// Block volumes are published to /var/lib/kubelet/plugins/kubernetes.io/csi/volumeDevices/publish/<PV>/<pod UID>.
+	blockTargetPodUID = regexp.MustCompile(`/volumeDevices/publish/[^/]+/([0-9a-f-]{36})$`)
+
+	errPodCgroupNotFound = errors.New("pod cgroup not found")
+	errUnknownTargetPath = errors.New("could not determine pod UID from target path")
+)
+
+// SetIOLimits limits the I/O of the pod that target is published to on the device backing target,
+// by writing the limits to the io.max file of the pod's cgroup.
+func (m *NodeMounter) SetIOLimits(target string, limits IOLimits) error {
+	return setIOLimits(cgroupRoot, target, limits)
+}
+
+// ClearIOLimits removes the I/O limits set by SetIOLimits. It is a no-op if no limits are set,
+// or if the pod's cgroup no longer exists.
+func (m *NodeMounter) ClearIOLimits(target string) error {
+	return clearIOLimits(cgroupRoot, target)
+}
+
+func setIOLimits(root, target string, limits IOLimits) error {
+	ioMax, device, err := resolveIOMax(root, target)
+	if err != nil {
+		if errors.Is(err, errPodCgroupNotFound) {
+			return fmt.Errorf("%w: is the host cgroup v2 filesystem mounted at %s?", err, root)
+		}
+		return err
+	}
+
+	line := fmt.Sprintf("%s riops=%s wiops=%s rbps=%s wbps=%s", device,
+		ioMaxValue(limits.ReadIOPS), ioMaxValue(limits.WriteIOPS), ioMaxValue(limits.ReadBPS), ioMaxValue(limits.WriteBPS))
+	klog.V(4).InfoS("SetIOLimits: writing io.max", "path", ioMax, "limits", line)
+	if err := os.WriteFile(ioMax, []byte(line), 0); err != nil {
+		return fmt.Errorf("failed to write %q to %s: %w", line, ioMax, err)
+	}
+	return nil
+}
+
+func clearIOLimits(root, target string) error {
+	ioMax, device, err := resolveIOMax(root, target)
+	if err != nil {
+		if errors.Is(err, errUnknownTargetPath) || errors.Is(err, errPodCgroupNotFound) || errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	data, err := os.ReadFile(ioMax)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", ioMax, err)
+	}
+	if !hasIOMaxEntry(string(data), device) {
+		return nil
+	}
+
+	line := device + " riops=max wiops=max rbps=max wbps=max"
+	klog.V(4).InfoS("ClearIOLimits: writing io.max", "path", ioMax, "limits", line)
+	if err := os.WriteFile(ioMax, []byte(line), 0); err != nil {
+		return fmt.Errorf("failed to write %q to %s: %w", line, ioMax, err)
+	}
+	return nil
+}
+
+// resolveIOMax returns the io.max file of the cgroup of the pod that target is published to,
+// and the major:minor of the disk backing target.
+func resolveIOMax(root, target string) (string, string, error) {
+	podUID, err := podUIDFromTargetPath(target)
+	if err != nil {
+		return "", "", err
+	}
+	cgroup, err := findPodCgroup(root, podUID)
+	if err != nil {
+		return "", "", err
+	}
+	device, err := diskDeviceNumber(target)
+	if err != nil {
+		return "", "", err
+	}
+	return filepath.Join(cgroup, ioMaxFile), device, nil
+}
+
+// podUIDFromTargetPath extracts the UID of the pod from a kubelet publish target path.
+func podUIDFromTargetPath(target string) (string, error) {
+	for _, re := range []*regexp.Regexp{filesystemTargetPodUID, blockTargetPodUID} {
+		if match := re.FindStringSubmatch(target); match != nil {
+			return match[1], nil
+		}
+	}
+	return "", fmt.Errorf("%w %s", errUnknownTargetPath, target)
+}
+
+// findPodCgroup returns the cgroup directory of the pod with podUID, for both
+// the systemd (kubepods-burstable-pod<UID>.slice) and cgroupfs (pod<UID>) cgroup drivers.
+func findPodCgroup(root, podUID string) (string, error) {
+	cgroupfsName := "pod" + podUID
+	systemdSuffix := "pod" + strings.ReplaceAll(podUID, "-", "_") + ".slice"
+
+	for _, kubepods := range []string{"kubepods.slice", "kubepods"} {
+		base := filepath.Join(root, kubepods)
+		var found string
+		err := filepath.WalkDir(base, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() {
+				return nil
+			}
+			if d.Name() == cgroupfsName || strings.HasSuffix(d.Name(), systemdSuffix) {
+				found = path
+				return filepath.SkipAll
+			}
+			if path != base && strings.Count(strings.TrimPrefix(path, base), string(filepath.Separator)) >= maxPodCgroupDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("failed to search cgroup of pod %s: %w", podUID, err)
+		}
+		if found != "" {
+			return found, nil
+		}
+	}
+	return "", fmt.Errorf("%w: pod %s", errPodCgroupNotFound, podUID)
+}
+
+// diskDeviceNumber returns the major:minor of the disk backing target, which is either
+// a block device or a path on a mounted filesystem. Partitions are resolved to their
+// parent disk, as io.max only accepts whole disks.
+func diskDeviceNumber(target string) (string, error) {
+	var st unix.Stat_t
+	if err := unix.Stat(target, &st); err != nil {
+		return "", fmt.Errorf("failed to stat %s: %w", target, err)
+	}
+	dev := uint64(st.Dev) //nolint:unconvert // Dev is uint32 on some architectures
+	if st.Mode&unix.S_IFMT == unix.S_IFBLK {
+		dev = uint64(st.Rdev) //nolint:unconvert // Rdev is uint32 on some architectures
+	}
+	device := fmt.Sprintf("%d:%d", unix.Major(dev), unix.Minor(dev))
+
+	sysPath := filepath.Join("/sys/dev/block", device)
+	if _, err := os.Stat(filepath.Join(sysPath, "partition")); err != nil {
+		return device, nil
+	}
+	resolved, err := filepath.EvalSymlinks(sysPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", sysPath, err)
+	}
+	parent, err := os.ReadFile(filepath.Join(filepath.Dir(resolved), "dev"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read parent device of partition %s: %w", device, err)
+	}
+	return strings.TrimSpace(string(parent)), nil
+}
+
+// hasIOMaxEntry returns true if the io.max content has limits for device.
+func hasIOMaxEntry(ioMax, device string) bool {
+	for line := range strings.Lines(ioMax) {
+		if strings.HasPrefix(line, device+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+func ioMaxValue(limit uint64) string {
+	if limit == 0 {
+		return "max"
+	}
+	return strconv.FormatUint(limit, 10)
+}
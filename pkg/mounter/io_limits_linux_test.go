@@ -0,0 +1,136 @@
+//go:build linux
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mounter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPodUID = "0b9f7a5e-6c2d-4a8b-9e1f-123456789abc"
+
+func TestPodUIDFromTargetPath(t *testing.T) {
+	testCases := []struct {
+		name    string
+		target  string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "filesystem",
+			target: "/var/lib/kubelet/pods/" + testPodUID + "/volumes/kubernetes.io~csi/pvc-1/mount",
+			want:   testPodUID,
+		},
+		{
+			name:   "block",
+			target: "/var/lib/kubelet/plugins/kubernetes.io/csi/volumeDevices/publish/pvc-1/" + testPodUID,
+			want:   testPodUID,
+		},
+		{
+			name:    "unknown",
+			target:  "/target/path",
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := podUIDFromTargetPath(tc.target)
+			if tc.wantErr {
+				require.ErrorIs(t, err, errUnknownTargetPath)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestFindPodCgroup(t *testing.T) {
+	testCases := []struct {
+		name   string
+		cgroup string
+	}{
+		{
+			name:   "systemd guaranteed",
+			cgroup: "kubepods.slice/kubepods-pod0b9f7a5e_6c2d_4a8b_9e1f_123456789abc.slice",
+		},
+		{
+			name:   "systemd burstable",
+			cgroup: "kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod0b9f7a5e_6c2d_4a8b_9e1f_123456789abc.slice",
+		},
+		{
+			name:   "cgroupfs besteffort",
+			cgroup: "kubepods/besteffort/pod" + testPodUID,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			want := filepath.Join(root, tc.cgroup)
+			require.NoError(t, os.MkdirAll(want, 0o755))
+
+			got, err := findPodCgroup(root, testPodUID)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := findPodCgroup(t.TempDir(), testPodUID)
+		require.ErrorIs(t, err, errPodCgroupNotFound)
+	})
+}
+
+func TestSetAndClearIOLimits(t *testing.T) {
+	root := t.TempDir()
+	cgroup := filepath.Join(root, "kubepods", "pod"+testPodUID)
+	require.NoError(t, os.MkdirAll(cgroup, 0o755))
+	ioMax := filepath.Join(cgroup, ioMaxFile)
+	require.NoError(t, os.WriteFile(ioMax, nil, 0o644))
+
+	target := filepath.Join(t.TempDir(), "pods", testPodUID, "volumes", "kubernetes.io~csi", "pvc-1", "mount")
+	require.NoError(t, os.MkdirAll(target, 0o755))
+	device, err := diskDeviceNumber(target)
+	require.NoError(t, err)
+
+	// Clearing without limits set is a no-op
+	require.NoError(t, clearIOLimits(root, target))
+	data, err := os.ReadFile(ioMax)
+	require.NoError(t, err)
+	assert.Empty(t, data)
+
+	require.NoError(t, setIOLimits(root, target, IOLimits{ReadIOPS: 1000, WriteBPS: 1 << 20}))
+	data, err = os.ReadFile(ioMax)
+	require.NoError(t, err)
+	assert.Equal(t, device+" riops=1000 wiops=max rbps=max wbps=1048576", string(data))
+
+	require.NoError(t, clearIOLimits(root, target))
+	data, err = os.ReadFile(ioMax)
+	require.NoError(t, err)
+	assert.Equal(t, device+" riops=max wiops=max rbps=max wbps=max", string(data))
+
+	// The pod cgroup is removed after the pod terminates
+	require.NoError(t, os.RemoveAll(cgroup))
+	require.NoError(t, clearIOLimits(root, target))
+	require.ErrorIs(t, setIOLimits(root, target, IOLimits{ReadIOPS: 1}), errPodCgroupNotFound)
+}
@@ -5,6 +5,7 @@
 package mounter
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -48,19 +49,63 @@ func (mr *MockMounterMockRecorder) CanSafelySkipMountPointCheck() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CanSafelySkipMountPointCheck", reflect.TypeOf((*MockMounter)(nil).CanSafelySkipMountPointCheck))
 }
 
+// ClearIOLimits mocks base method.
+func (m *MockMounter) ClearIOLimits(target string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearIOLimits", target)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearIOLimits indicates an expected call of ClearIOLimits.
+func (mr *MockMounterMockRecorder) ClearIOLimits(target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearIOLimits", reflect.TypeOf((*MockMounter)(nil).ClearIOLimits), target)
+}
+
 // FindDevicePath mocks base method.
-func (m *MockMounter) FindDevicePath(devicePath, volumeID, partition, region string) (string, error) {
+func (m *MockMounter) FindDevicePath(ctx context.Context, devicePath, volumeID, partition, region string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindDevicePath", devicePath, volumeID, partition, region)
+	ret := m.ctrl.Call(m, "FindDevicePath", ctx, devicePath, volumeID, partition, region)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindDevicePath indicates an expected call of FindDevicePath.
-func (mr *MockMounterMockRecorder) FindDevicePath(devicePath, volumeID, partition, region interface{}) *gomock.Call {
+func (mr *MockMounterMockRecorder) FindDevicePath(ctx, devicePath, volumeID, partition, region interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDevicePath", reflect.TypeOf((*MockMounter)(nil).FindDevicePath), devicePath, volumeID, partition, region)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDevicePath", reflect.TypeOf((*MockMounter)(nil).FindDevicePath), ctx, devicePath, volumeID, partition, region)
+}
+
+// FindInstanceStoreDevice mocks base method.
+func (m *MockMounter) FindInstanceStoreDevice(selector string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindInstanceStoreDevice", selector)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindInstanceStoreDevice indicates an expected call of FindInstanceStoreDevice.
+func (mr *MockMounterMockRecorder) FindInstanceStoreDevice(selector interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindInstanceStoreDevice", reflect.TypeOf((*MockMounter)(nil).FindInstanceStoreDevice), selector)
+}
+
+// FindMountHolders mocks base method.
+func (m *MockMounter) FindMountHolders(target string) ([]MountHolder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMountHolders", target)
+	ret0, _ := ret[0].([]MountHolder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMountHolders indicates an expected call of FindMountHolders.
+func (mr *MockMounterMockRecorder) FindMountHolders(target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMountHolders", reflect.TypeOf((*MockMounter)(nil).FindMountHolders), target)
 }
 
 // FormatAndMountSensitiveWithFormatOptions mocks base method.
@@ -77,6 +122,20 @@ func (mr *MockMounterMockRecorder) FormatAndMountSensitiveWithFormatOptions(sour
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FormatAndMountSensitiveWithFormatOptions", reflect.TypeOf((*MockMounter)(nil).FormatAndMountSensitiveWithFormatOptions), source, target, fstype, options, sensitiveOptions, formatOptions)
 }
 
+// FormatDevice mocks base method.
+func (m *MockMounter) FormatDevice(source, fstype string, formatOptions []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FormatDevice", source, fstype, formatOptions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FormatDevice indicates an expected call of FormatDevice.
+func (mr *MockMounterMockRecorder) FormatDevice(source, fstype, formatOptions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FormatDevice", reflect.TypeOf((*MockMounter)(nil).FormatDevice), source, fstype, formatOptions)
+}
+
 // GetBlockSizeBytes mocks base method.
 func (m *MockMounter) GetBlockSizeBytes(devicePath string) (int64, error) {
 	m.ctrl.T.Helper()
@@ -108,6 +167,21 @@ func (mr *MockMounterMockRecorder) GetDeviceNameFromMount(mountPath interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeviceNameFromMount", reflect.TypeOf((*MockMounter)(nil).GetDeviceNameFromMount), mountPath)
 }
 
+// GetDiskFormat mocks base method.
+func (m *MockMounter) GetDiskFormat(disk string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDiskFormat", disk)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDiskFormat indicates an expected call of GetDiskFormat.
+func (mr *MockMounterMockRecorder) GetDiskFormat(disk interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDiskFormat", reflect.TypeOf((*MockMounter)(nil).GetDiskFormat), disk)
+}
+
 // GetMountRefs mocks base method.
 func (m *MockMounter) GetMountRefs(pathname string) ([]string, error) {
 	m.ctrl.T.Helper()
@@ -138,6 +212,20 @@ func (mr *MockMounterMockRecorder) GetVolumeStats(volumePath interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVolumeStats", reflect.TypeOf((*MockMounter)(nil).GetVolumeStats), volumePath)
 }
 
+// GrowDevice mocks base method.
+func (m *MockMounter) GrowDevice(devicePath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrowDevice", devicePath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GrowDevice indicates an expected call of GrowDevice.
+func (mr *MockMounterMockRecorder) GrowDevice(devicePath interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrowDevice", reflect.TypeOf((*MockMounter)(nil).GrowDevice), devicePath)
+}
+
 // IsBlockDevice mocks base method.
 func (m *MockMounter) IsBlockDevice(fullPath string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -197,6 +285,20 @@ func (mr *MockMounterMockRecorder) IsMountPoint(file interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsMountPoint", reflect.TypeOf((*MockMounter)(nil).IsMountPoint), file)
 }
 
+// LazyUnmount mocks base method.
+func (m *MockMounter) LazyUnmount(target string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LazyUnmount", target)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LazyUnmount indicates an expected call of LazyUnmount.
+func (mr *MockMounterMockRecorder) LazyUnmount(target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LazyUnmount", reflect.TypeOf((*MockMounter)(nil).LazyUnmount), target)
+}
+
 // List mocks base method.
 func (m *MockMounter) List() ([]mount_utils.MountPoint, error) {
 	m.ctrl.T.Helper()
@@ -340,6 +442,20 @@ func (mr *MockMounterMockRecorder) PreparePublishTarget(target interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreparePublishTarget", reflect.TypeOf((*MockMounter)(nil).PreparePublishTarget), target)
 }
 
+// PrewarmDevice mocks base method.
+func (m *MockMounter) PrewarmDevice(ctx context.Context, devicePath string, parallelism int, progress func(int64, int64)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PrewarmDevice", ctx, devicePath, parallelism, progress)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PrewarmDevice indicates an expected call of PrewarmDevice.
+func (mr *MockMounterMockRecorder) PrewarmDevice(ctx, devicePath, parallelism, progress interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PrewarmDevice", reflect.TypeOf((*MockMounter)(nil).PrewarmDevice), ctx, devicePath, parallelism, progress)
+}
+
 // Resize mocks base method.
 func (m *MockMounter) Resize(devicePath, deviceMountPath string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -355,6 +471,20 @@ func (mr *MockMounterMockRecorder) Resize(devicePath, deviceMountPath interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resize", reflect.TypeOf((*MockMounter)(nil).Resize), devicePath, deviceMountPath)
 }
 
+// SetIOLimits mocks base method.
+func (m *MockMounter) SetIOLimits(target string, limits IOLimits) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetIOLimits", target, limits)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetIOLimits indicates an expected call of SetIOLimits.
+func (mr *MockMounterMockRecorder) SetIOLimits(target, limits interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetIOLimits", reflect.TypeOf((*MockMounter)(nil).SetIOLimits), target, limits)
+}
+
 // Unmount mocks base method.
 func (m *MockMounter) Unmount(target string) error {
 	m.ctrl.T.Helper()
@@ -22,6 +22,11 @@ limitations under the License.
 package mounter
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
 	mountutils "k8s.io/mount-utils"
 )
 
@@ -34,6 +39,8 @@ type Mounter interface {
 	mountutils.Interface
 
 	FormatAndMountSensitiveWithFormatOptions(source string, target string, fstype string, options []string, sensitiveOptions []string, formatOptions []string) error
+	GetDiskFormat(disk string) (string, error)
+	FormatDevice(source string, fstype string, formatOptions []string) error
 	IsCorruptedMnt(err error) bool
 	GetDeviceNameFromMount(mountPath string) (string, int, error)
 	MakeFile(path string) error
@@ -43,11 +50,76 @@ type Mounter interface {
 	Unpublish(path string) error
 	Unstage(path string) error
 	Resize(devicePath, deviceMountPath string) (bool, error)
-	FindDevicePath(devicePath, volumeID, partition, region string) (string, error)
+	GrowDevice(devicePath string) error
+	FindDevicePath(ctx context.Context, devicePath, volumeID, partition, region string) (string, error)
+	FindInstanceStoreDevice(selector string) (string, error)
 	PreparePublishTarget(target string) error
 	IsBlockDevice(fullPath string) (bool, error)
 	GetBlockSizeBytes(devicePath string) (int64, error)
 	GetVolumeStats(volumePath string) (VolumeStats, error)
+	SetIOLimits(target string, limits IOLimits) error
+	ClearIOLimits(target string) error
+	FindMountHolders(target string) ([]MountHolder, error)
+	LazyUnmount(target string) error
+	PrewarmDevice(ctx context.Context, devicePath string, parallelism int, progress func(read, total int64)) error
+}
+
+// InstanceStoreRAID0 is the selector of FindInstanceStoreDevice for a RAID0 array of all the
+// instance store disks of the node.
+const InstanceStoreRAID0 = "raid0"
+
+// Reasons a MountHolder keeps a mount busy.
+const (
+	HolderReasonOpenFile       = "open file"
+	HolderReasonCwd            = "working directory"
+	HolderReasonRoot           = "root directory"
+	HolderReasonMountNamespace = "mount namespace"
+)
+
+// MountHolder is a process that keeps a mount busy, as returned by FindMountHolders.
+type MountHolder struct {
+	PID     int
+	Command string
+	// ContainerID and PodUID are empty if the process does not run in a Kubernetes pod.
+	ContainerID string
+	PodUID      string
+	// Reason is one of the HolderReason constants.
+	Reason string
+	// Writer is true if the process has a file on the mount open for writing, or if its mount
+	// namespace holds a read-write copy of the mount.
+	Writer bool
+}
+
+func (h MountHolder) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "pid %d (%s)", h.PID, h.Command)
+	if h.ContainerID != "" {
+		// Container IDs are shortened like crictl does
+		fmt.Fprintf(&b, " in container %.13s", h.ContainerID)
+	}
+	if h.PodUID != "" {
+		fmt.Fprintf(&b, " of pod %s", h.PodUID)
+	}
+	b.WriteString(" via " + h.Reason)
+	if h.Writer {
+		b.WriteString(" (writer)")
+	}
+	return b.String()
+}
+
+// IOLimits holds the I/O limits applied to the cgroup of the pod a volume is published to.
+// A zero value means the corresponding operation is not limited.
+type IOLimits struct {
+	ReadIOPS  uint64
+	WriteIOPS uint64
+	// ReadBPS and WriteBPS are in bytes per second.
+	ReadBPS  uint64
+	WriteBPS uint64
+}
+
+// IsZero returns true if no limit is set.
+func (l IOLimits) IsZero() bool {
+	return l == IOLimits{}
 }
 
 // VolumeStats holds volume stats returned by GetVolumeStats.
@@ -65,10 +137,18 @@ type VolumeStats struct {
 // A superstruct of SafeFormatAndMount.
 type NodeMounter struct {
 	*mountutils.SafeFormatAndMount
+
+	// deviceWatcher, if non-nil, is used by FindDevicePath to wait up to
+	// deviceWatcherTimeout for a device to be attached.
+	deviceWatcher        *DeviceWatcher
+	deviceWatcherTimeout time.Duration
 }
 
 // NewNodeMounter returns a new intsance of NodeMounter.
-func NewNodeMounter(hostprocess bool) (Mounter, error) {
+// If deviceWatcherTimeout is non-zero, a DeviceWatcher is started, which runs
+// until ctx is done, and FindDevicePath waits up to deviceWatcherTimeout for
+// devices to appear.
+func NewNodeMounter(ctx context.Context, hostprocess bool, deviceWatcherTimeout time.Duration) (Mounter, error) {
 	var safeMounter *mountutils.SafeFormatAndMount
 	var err error
 
@@ -81,5 +161,14 @@ func NewNodeMounter(hostprocess bool) (Mounter, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &NodeMounter{safeMounter}, nil
+	m := &NodeMounter{SafeFormatAndMount: safeMounter}
+	if deviceWatcherTimeout > 0 {
+		w, err := StartDeviceWatcher(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start device watcher: %w", err)
+		}
+		m.deviceWatcher = w
+		m.deviceWatcherTimeout = deviceWatcherTimeout
+	}
+	return m, nil
 }
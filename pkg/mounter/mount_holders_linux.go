@@ -0,0 +1,306 @@
+//go:build linux
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mounter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+	"k8s.io/klog/v2"
+)
+
+// procRoot is the proc filesystem inspected by FindMountHolders. The node plugin
+// only sees the processes of other pods when it runs in the host PID namespace.
+const procRoot = "/proc"
+
+var (
+	containerIDPattern = regexp.MustCompile(`([0-9a-f]{64})`)
+	podUIDPattern      = regexp.MustCompile(`pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})`)
+)
+
+// FindMountHolders returns the processes that keep the mount at target busy: processes with
+// open files, or their working or root directory, on the mounted device, and processes whose
+// mount namespace holds a copy of the mount. Copies that are peers or slaves of the mount at
+// target are not reported, as unmounting target propagates to them.
+func (m *NodeMounter) FindMountHolders(target string) ([]MountHolder, error) {
+	return findMountHolders(procRoot, target)
+}
+
+// LazyUnmount flushes the filesystem mounted at target, detaches it from the filesystem hierarchy
+// (umount -l) and removes target. The filesystem is cleaned up by the kernel once it is no longer
+// busy, the flush makes sure the data written so far reaches the volume before it is detached.
+func (m *NodeMounter) LazyUnmount(target string) error {
+	klog.V(4).InfoS("LazyUnmount: unmounting", "target", target)
+	if err := syncFilesystem(target); err != nil {
+		return err
+	}
+	// EINVAL is returned if target is not a mount point
+	if err := unix.Unmount(target, unix.MNT_DETACH); err != nil && !errors.Is(err, unix.EINVAL) {
+		return fmt.Errorf("failed to lazily unmount %s: %w", target, err)
+	}
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", target, err)
+	}
+	return nil
+}
+
+func findMountHolders(proc, target string) ([]MountHolder, error) {
+	var st unix.Stat_t
+	if err := unix.Stat(target, &st); err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", target, err)
+	}
+	dev := uint64(st.Dev) //nolint:unconvert // Dev is uint32 on some architectures
+
+	entries, err := os.ReadDir(proc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %w", err)
+	}
+
+	// Every process of a mount namespace sees the same mounts, so each namespace is checked once.
+	// The namespace of the node plugin is skipped, as target is expected to be mounted there.
+	seenNamespaces := map[string]bool{}
+	if ns, err := os.Readlink(filepath.Join(proc, "self", "ns", "mnt")); err == nil {
+		seenNamespaces[ns] = true
+	}
+	peerGroup := findPeerGroup(filepath.Join(proc, "self", "mountinfo"), target, dev)
+
+	var holders []MountHolder
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		// Processes may exit while they are inspected, errors are ignored
+		if holder, ok := inspectProcess(proc, pid, dev, peerGroup, seenNamespaces); ok {
+			holders = append(holders, holder)
+		}
+	}
+	return holders, nil
+}
+
+// inspectProcess returns the MountHolder of pid if the process keeps the device dev busy. Copies of
+// the mount in the peer group peerGroup are ignored.
+func inspectProcess(proc string, pid int, dev uint64, peerGroup string, seenNamespaces map[string]bool) (MountHolder, bool) {
+	pidDir := filepath.Join(proc, strconv.Itoa(pid))
+	holder := MountHolder{PID: pid}
+
+	fds, _ := os.ReadDir(filepath.Join(pidDir, "fd"))
+	for _, fd := range fds {
+		if !isOnDevice(filepath.Join(pidDir, "fd", fd.Name()), dev) {
+			continue
+		}
+		holder.Reason = HolderReasonOpenFile
+		if isWritableFd(filepath.Join(pidDir, "fdinfo", fd.Name())) {
+			holder.Writer = true
+			break
+		}
+	}
+
+	if holder.Reason == "" {
+		switch {
+		case isOnDevice(filepath.Join(pidDir, "cwd"), dev):
+			holder.Reason = HolderReasonCwd
+		case isOnDevice(filepath.Join(pidDir, "root"), dev):
+			holder.Reason = HolderReasonRoot
+		default:
+			ns, err := os.Readlink(filepath.Join(pidDir, "ns", "mnt"))
+			if err != nil || seenNamespaces[ns] {
+				return MountHolder{}, false
+			}
+			seenNamespaces[ns] = true
+			found, readWrite := findMountCopy(filepath.Join(pidDir, "mountinfo"), dev, peerGroup)
+			if !found {
+				return MountHolder{}, false
+			}
+			holder.Reason = HolderReasonMountNamespace
+			// Writes through a read-write copy are not visible as open files of this process
+			holder.Writer = readWrite
+		}
+	}
+
+	if comm, err := os.ReadFile(filepath.Join(pidDir, "comm")); err == nil {
+		holder.Command = strings.TrimSpace(string(comm))
+	}
+	if cgroup, err := os.ReadFile(filepath.Join(pidDir, "cgroup")); err == nil {
+		holder.ContainerID, holder.PodUID = parseProcessCgroup(string(cgroup))
+	}
+	return holder, true
+}
+
+// isOnDevice returns true if path is a file on the filesystem of dev, or is the block device dev.
+func isOnDevice(path string, dev uint64) bool {
+	var st unix.Stat_t
+	if err := unix.Stat(path, &st); err != nil {
+		return false
+	}
+	if st.Mode&unix.S_IFMT == unix.S_IFBLK {
+		return uint64(st.Rdev) == dev //nolint:unconvert // Rdev is uint32 on some architectures
+	}
+	return uint64(st.Dev) == dev //nolint:unconvert // Dev is uint32 on some architectures
+}
+
+// isWritableFd returns true if the fdinfo file at path reports the file as open for writing.
+func isWritableFd(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		value, ok := strings.CutPrefix(scanner.Text(), "flags:")
+		if !ok {
+			continue
+		}
+		flags, err := strconv.ParseUint(strings.TrimSpace(value), 8, 64)
+		if err != nil {
+			return false
+		}
+		mode := flags & unix.O_ACCMODE
+		return mode == unix.O_WRONLY || mode == unix.O_RDWR
+	}
+	return false
+}
+
+// mountinfoEntry holds the fields of a line of a mountinfo file used to find copies of a mount.
+type mountinfoEntry struct {
+	majorMinor string
+	mountPoint string
+	readWrite  bool
+	// propagation holds the optional fields of the mount, e.g. shared:1 or master:2.
+	propagation []string
+}
+
+// readMountinfo returns the entries of the mountinfo file at path.
+func readMountinfo(path string) ([]mountinfoEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []mountinfoEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Format: mount ID, parent ID, major:minor, root, mount point, mount options,
+		// optional fields..., "-", filesystem type, source, super options
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 7 {
+			continue
+		}
+		entry := mountinfoEntry{
+			majorMinor: fields[2],
+			mountPoint: fields[4],
+			readWrite:  slices.Contains(strings.Split(fields[5], ","), "rw"),
+		}
+		for _, field := range fields[6:] {
+			if field == "-" {
+				break
+			}
+			entry.propagation = append(entry.propagation, field)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+func formatMajorMinor(dev uint64) string {
+	return fmt.Sprintf("%d:%d", unix.Major(dev), unix.Minor(dev))
+}
+
+// findPeerGroup returns the ID of the shared peer group of the mount of dev at target in the
+// mountinfo file at path, or an empty string if the mount is private.
+func findPeerGroup(path, target string, dev uint64) string {
+	entries, err := readMountinfo(path)
+	if err != nil {
+		return ""
+	}
+	majorMinor := formatMajorMinor(dev)
+	for _, entry := range entries {
+		if entry.majorMinor != majorMinor || entry.mountPoint != filepath.Clean(target) {
+			continue
+		}
+		for _, field := range entry.propagation {
+			if group, ok := strings.CutPrefix(field, "shared:"); ok {
+				return group
+			}
+		}
+	}
+	return ""
+}
+
+// findMountCopy returns whether the mountinfo file at path has a mount of dev outside of the peer
+// group peerGroup, and whether one of them is read-write. Mounts in the peer group, or slaves of
+// it, are unmounted along with the mount of the node plugin and do not keep dev busy.
+func findMountCopy(path string, dev uint64, peerGroup string) (found, readWrite bool) {
+	entries, err := readMountinfo(path)
+	if err != nil {
+		return false, false
+	}
+	majorMinor := formatMajorMinor(dev)
+	for _, entry := range entries {
+		if entry.majorMinor != majorMinor {
+			continue
+		}
+		if peerGroup != "" && (slices.Contains(entry.propagation, "shared:"+peerGroup) || slices.Contains(entry.propagation, "master:"+peerGroup)) {
+			continue
+		}
+		found = true
+		readWrite = readWrite || entry.readWrite
+	}
+	return found, readWrite
+}
+
+// syncFilesystem flushes the filesystem mounted at target to its device (syncfs). Nothing is done
+// if target does not exist.
+func syncFilesystem(target string) error {
+	f, err := os.Open(target)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", target, err)
+	}
+	defer f.Close()
+	if err := unix.Syncfs(int(f.Fd())); err != nil {
+		return fmt.Errorf("failed to sync filesystem at %s: %w", target, err)
+	}
+	return nil
+}
+
+// parseProcessCgroup returns the container ID and pod UID found in the content of /proc/<pid>/cgroup,
+// for both the systemd and cgroupfs cgroup drivers.
+func parseProcessCgroup(cgroup string) (containerID, podUID string) {
+	if matches := containerIDPattern.FindAllString(cgroup, -1); len(matches) > 0 {
+		containerID = matches[len(matches)-1]
+	}
+	if match := podUIDPattern.FindStringSubmatch(cgroup); match != nil {
+		podUID = strings.ReplaceAll(match[1], "_", "-")
+	}
+	return containerID, podUID
+}
@@ -0,0 +1,126 @@
+//go:build linux
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mounter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+const testContainerID = "4f1d2c3b4a5968778695a4b3c2d1e0f9e8d7c6b5a4938271605f4e3d2c1b0a99"
+
+// fakeProcess creates /proc/<pid> below proc with the given files and symlinks.
+func fakeProcess(t *testing.T, proc string, pid int, files, links map[string]string) {
+	t.Helper()
+	dir := filepath.Join(proc, fmt.Sprint(pid))
+	for _, sub := range []string{"fd", "fdinfo", "ns"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, sub), 0o755))
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	for name, dest := range links {
+		require.NoError(t, os.Symlink(dest, filepath.Join(dir, name)))
+	}
+}
+
+func TestFindMountHolders(t *testing.T) {
+	proc := t.TempDir()
+	target := t.TempDir()
+	file := filepath.Join(target, "data")
+	require.NoError(t, os.WriteFile(file, nil, 0o600))
+
+	var st unix.Stat_t
+	require.NoError(t, unix.Stat(target, &st))
+	majorMinor := fmt.Sprintf("%d:%d", unix.Major(uint64(st.Dev)), unix.Minor(uint64(st.Dev))) //nolint:unconvert // Dev is uint32 on some architectures
+
+	cgroup := "0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod0b9f7a5e_6c2d_4a8b_9e1f_123456789abc.slice/cri-containerd-" + testContainerID + ".scope\n"
+	fakeProcess(t, proc, 0,
+		map[string]string{"mountinfo": "1 0 " + majorMinor + " / " + target + " rw shared:7 - ext4 /dev/nvme1n1 rw\n"},
+		map[string]string{"ns/mnt": "mnt:[1]"})
+	require.NoError(t, os.Symlink("0", filepath.Join(proc, "self")))
+
+	// A container writing to the volume
+	fakeProcess(t, proc, 100,
+		map[string]string{"comm": "postgres\n", "cgroup": cgroup, "fdinfo/3": "pos:\t0\nflags:\t0100002\n", "fdinfo/4": "pos:\t0\nflags:\t0100000\n"},
+		map[string]string{"fd/3": file, "fd/4": file, "cwd": "/proc", "root": "/proc", "ns/mnt": "mnt:[2]"})
+	// A host shell with its working directory on the volume
+	fakeProcess(t, proc, 200,
+		map[string]string{"comm": "bash\n", "cgroup": "0::/user.slice/user-0.slice/session-1.scope\n"},
+		map[string]string{"cwd": target, "root": "/proc", "ns/mnt": "mnt:[1]"})
+	// Two processes of another mount namespace with a read-write copy of the mount, only reported once
+	for _, pid := range []int{300, 301} {
+		fakeProcess(t, proc, pid,
+			map[string]string{"comm": "sleep\n", "cgroup": "0::/kubepods/besteffort/pod0b9f7a5e-6c2d-4a8b-9e1f-123456789abc/" + testContainerID + "\n", "mountinfo": "1 0 " + majorMinor + " / /data rw - ext4 /dev/nvme1n1 rw\n"},
+			map[string]string{"cwd": "/proc", "root": "/proc", "ns/mnt": "mnt:[3]"})
+	}
+	// A process of a mount namespace with a read-only copy of the mount
+	fakeProcess(t, proc, 310,
+		map[string]string{"comm": "cat\n", "mountinfo": "1 0 " + majorMinor + " / /data ro,relatime - ext4 /dev/nvme1n1 ro\n"},
+		map[string]string{"cwd": "/proc", "root": "/proc", "ns/mnt": "mnt:[5]"})
+	// Processes of mount namespaces where the mount was propagated, which are unmounted along with target
+	fakeProcess(t, proc, 320,
+		map[string]string{"comm": "kubelet\n", "mountinfo": "1 0 " + majorMinor + " / " + target + " rw shared:7 - ext4 /dev/nvme1n1 rw\n"},
+		map[string]string{"cwd": "/proc", "root": "/proc", "ns/mnt": "mnt:[6]"})
+	fakeProcess(t, proc, 330,
+		map[string]string{"comm": "agent\n", "mountinfo": "1 0 " + majorMinor + " / /host" + target + " rw master:7 - ext4 /dev/nvme1n1 rw\n"},
+		map[string]string{"cwd": "/proc", "root": "/proc", "ns/mnt": "mnt:[7]"})
+	// An unrelated process
+	fakeProcess(t, proc, 400,
+		map[string]string{"comm": "sshd\n", "mountinfo": "1 0 0:1 / / rw - proc proc rw\n"},
+		map[string]string{"fd/0": "/proc", "cwd": "/proc", "root": "/proc", "ns/mnt": "mnt:[4]"})
+
+	holders, err := findMountHolders(proc, target)
+	require.NoError(t, err)
+	assert.Equal(t, []MountHolder{
+		{PID: 100, Command: "postgres", ContainerID: testContainerID, PodUID: testPodUID, Reason: HolderReasonOpenFile, Writer: true},
+		{PID: 200, Command: "bash", Reason: HolderReasonCwd},
+		{PID: 300, Command: "sleep", ContainerID: testContainerID, PodUID: testPodUID, Reason: HolderReasonMountNamespace, Writer: true},
+		{PID: 310, Command: "cat", Reason: HolderReasonMountNamespace},
+	}, holders)
+
+	assert.Equal(t, "pid 100 (postgres) in container 4f1d2c3b4a596 of pod "+testPodUID+" via open file (writer)", holders[0].String())
+	assert.Equal(t, "pid 200 (bash) via working directory", holders[1].String())
+}
+
+func TestIsWritableFd(t *testing.T) {
+	testCases := []struct {
+		name  string
+		flags string
+		want  bool
+	}{
+		{name: "read only", flags: "0100000", want: false},
+		{name: "write only", flags: "0100001", want: true},
+		{name: "read write", flags: "02", want: true},
+		{name: "invalid", flags: "rw", want: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "fdinfo")
+			require.NoError(t, os.WriteFile(path, []byte("pos:\t0\nflags:\t"+tc.flags+"\nmnt_id:\t1\n"), 0o600))
+			assert.Equal(t, tc.want, isWritableFd(path))
+		})
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -41,6 +42,9 @@ const (
 	diskPartitionSuffix     = ""
 )
 
+// dmDeviceRegex matches the names of device-mapper devices, like the LVM logical volumes.
+var dmDeviceRegex = regexp.MustCompile(`^dm-[0-9]+$`)
+
 func NewSafeMounter() (*mountutils.SafeFormatAndMount, error) {
 	return &mountutils.SafeFormatAndMount{
 		Interface: mountutils.New(""),
@@ -55,7 +59,7 @@ func NewSafeMounterV2() (*mountutils.SafeFormatAndMount, error) {
 // FindDevicePath finds path of device and verifies its existence
 // if the device is not nvme, return the path directly
 // if the device is nvme, finds and returns the nvme device path eg. /dev/nvme1n1.
-func (m *NodeMounter) FindDevicePath(devicePath, volumeID, partition, region string) (string, error) {
+func (m *NodeMounter) FindDevicePath(ctx context.Context, devicePath, volumeID, partition, region string) (string, error) {
 	strippedVolumeName := strings.ReplaceAll(volumeID, "-", "")
 	canonicalDevicePath := ""
 
@@ -86,7 +90,7 @@ func (m *NodeMounter) FindDevicePath(devicePath, volumeID, partition, region str
 		}
 
 		klog.V(5).InfoS("[Debug] The canonical device path was resolved", "devicePath", devicePath, "cacanonicalDevicePath", canonicalDevicePath)
-		if err = verifyVolumeSerialMatch(canonicalDevicePath, strippedVolumeName, execRunner); err != nil {
+		if err = verifyVolumeSerialMatch(canonicalDevicePath, strippedVolumeName, sysBlockPath, execRunner); err != nil {
 			return "", err
 		}
 		return m.appendPartition(canonicalDevicePath, partition), nil
@@ -106,7 +110,7 @@ func (m *NodeMounter) FindDevicePath(devicePath, volumeID, partition, region str
 	if err == nil {
 		klog.V(5).InfoS("[Debug] successfully resolved", "nvmeName", nvmeName, "nvmeDevicePath", nvmeDevicePath)
 		canonicalDevicePath = nvmeDevicePath
-		if err = verifyVolumeSerialMatch(canonicalDevicePath, strippedVolumeName, execRunner); err != nil {
+		if err = verifyVolumeSerialMatch(canonicalDevicePath, strippedVolumeName, sysBlockPath, execRunner); err != nil {
 			return "", err
 		}
 		return m.appendPartition(canonicalDevicePath, partition), nil
@@ -114,6 +118,24 @@ func (m *NodeMounter) FindDevicePath(devicePath, volumeID, partition, region str
 		klog.V(5).InfoS("[Debug] error searching for nvme path", "nvmeName", nvmeName, "err", err)
 	}
 
+	// The device may not have appeared yet if the attachment completed very
+	// recently, so wait for the device watcher to observe it
+	if m.deviceWatcher != nil {
+		klog.V(5).InfoS("[Debug] Waiting for device watcher", "volumeID", volumeID, "timeout", m.deviceWatcherTimeout)
+		// The wait ends early if the request is canceled, so that the volume is not held in flight
+		ctx, cancel := context.WithTimeout(ctx, m.deviceWatcherTimeout)
+		defer cancel()
+		nvmeDevicePath, err = m.deviceWatcher.WaitForDevice(ctx, volumeID)
+		if err != nil {
+			return "", fmt.Errorf("no device path for device %q volume %q found: %w", devicePath, volumeID, err)
+		}
+		klog.V(5).InfoS("[Debug] device watcher resolved", "volumeID", volumeID, "nvmeDevicePath", nvmeDevicePath)
+		if err = verifyVolumeSerialMatch(nvmeDevicePath, strippedVolumeName, sysBlockPath, execRunner); err != nil {
+			return "", err
+		}
+		return m.appendPartition(nvmeDevicePath, partition), nil
+	}
+
 	if canonicalDevicePath == "" {
 		return "", fmt.Errorf("no device path for device %q volume %q found", devicePath, volumeID)
 	}
@@ -161,35 +183,100 @@ func execRunner(name string, arg ...string) ([]byte, error) {
 }
 
 // verifyVolumeSerialMatch checks the volume serial of the device against the expected volume.
-func verifyVolumeSerialMatch(canonicalDevicePath string, strippedVolumeName string, execRunner func(string, ...string) ([]byte, error)) error {
-	// As a security precaution, check the device name looks like a real device name before passing anything to exec
+// Device-mapper devices, such as LVM logical volumes, have no serial: the serials of the disks
+// below them are checked instead.
+func verifyVolumeSerialMatch(canonicalDevicePath string, strippedVolumeName string, sysBlock string, execRunner func(string, ...string) ([]byte, error)) error {
 	cleanDevice := filepath.Clean(canonicalDevicePath)
+	if name := strings.TrimPrefix(cleanDevice, "/dev/"); dmDeviceRegex.MatchString(name) {
+		return verifyDeviceMapperSerialMatch(name, strippedVolumeName, sysBlock, execRunner)
+	}
+
+	volumes, err := deviceSerialVolumes(cleanDevice, canonicalDevicePath, execRunner)
+	if err != nil {
+		return err
+	}
+	for _, volume := range volumes {
+		klog.V(6).InfoS("Comparing volume serial", "cleanDevice", cleanDevice, "expected", strippedVolumeName, "actual", volume)
+		if volume != strippedVolumeName {
+			return fmt.Errorf("refusing to mount %s because it claims to be %s but should be %s", cleanDevice, volume, strippedVolumeName)
+		}
+	}
+	return nil
+}
+
+// verifyDeviceMapperSerialMatch checks that the expected volume is one of the disks below the
+// device-mapper device name. A device-mapper device may span several volumes, or other devices.
+func verifyDeviceMapperSerialMatch(name string, strippedVolumeName string, sysBlock string, execRunner func(string, ...string) ([]byte, error)) error {
+	disks, err := deviceMapperDisks(name, sysBlock)
+	if err != nil {
+		return err
+	}
+	var found []string
+	for _, disk := range disks {
+		volumes, err := deviceSerialVolumes("/dev/"+disk, disk, execRunner)
+		if err != nil {
+			return err
+		}
+		klog.V(6).InfoS("Comparing volume serial", "device", name, "disk", disk, "expected", strippedVolumeName, "actual", volumes)
+		if slices.Contains(volumes, strippedVolumeName) {
+			return nil
+		}
+		found = append(found, volumes...)
+	}
+	// If no volume ID is found (non-Nitro instances, lsblk failures, etc) silently proceed
+	if len(found) > 0 {
+		return fmt.Errorf("refusing to mount /dev/%s because it is backed by %v but should be backed by %s", name, found, strippedVolumeName)
+	}
+	return nil
+}
+
+// deviceMapperDisks returns the disks below the device-mapper device name, through its partitions
+// and the device-mapper devices it is stacked on.
+func deviceMapperDisks(name string, sysBlock string) ([]string, error) {
+	slaves, err := os.ReadDir(filepath.Join(sysBlock, name, "slaves"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the devices below /dev/%s: %w", name, err)
+	}
+	var disks []string
+	for _, slave := range slaves {
+		if dmDeviceRegex.MatchString(slave.Name()) {
+			below, err := deviceMapperDisks(slave.Name(), sysBlock)
+			if err != nil {
+				return nil, err
+			}
+			disks = append(disks, below...)
+			continue
+		}
+		// Partitions are listed in the directory of their disk
+		if matches, _ := filepath.Glob(filepath.Join(sysBlock, "*", slave.Name(), "partition")); len(matches) > 0 {
+			disks = append(disks, filepath.Base(filepath.Dir(filepath.Dir(matches[0]))))
+			continue
+		}
+		disks = append(disks, slave.Name())
+	}
+	return disks, nil
+}
+
+// deviceSerialVolumes returns the EBS volume IDs in the serial of the device cleanDevice.
+func deviceSerialVolumes(cleanDevice string, rawDevice string, execRunner func(string, ...string) ([]byte, error)) ([]string, error) {
+	// As a security precaution, check the device name looks like a real device name before passing anything to exec
 	if !regexp.MustCompile(`^/dev/[A-Za-z0-9]+$`).MatchString(cleanDevice) {
-		return fmt.Errorf("refusing to mount %s (raw: %s) because it does not appear to be a valid device", cleanDevice, canonicalDevicePath)
+		return nil, fmt.Errorf("refusing to mount %s (raw: %s) because it does not appear to be a valid device", cleanDevice, rawDevice)
 	}
 
 	// In some rare cases, a race condition can lead to the /dev/disk/by-id/ symlink becoming out of date
 	// See https://github.com/kubernetes-sigs/aws-ebs-csi-driver/issues/1224 for more info
 	// Attempt to use lsblk to double check that the nvme device selected was the correct volume
 	output, err := execRunner("lsblk", "--noheadings", "--ascii", "--nodeps", "--output", "SERIAL", "--", cleanDevice)
-
-	if err == nil {
-		// Look for an EBS volume ID in the output, compare all matches against what we expect
-		// (in some rare cases there may be multiple matches due to lsblk printing partitions)
-		// If no volume ID is in the output (non-Nitro instances, SBE devices, etc) silently proceed
-		volumeRegex := regexp.MustCompile(`vol[a-z0-9]+`)
-		for _, volume := range volumeRegex.FindAllString(string(output), -1) {
-			klog.V(6).InfoS("Comparing volume serial", "cleanDevice", cleanDevice, "expected", strippedVolumeName, "actual", volume)
-			if volume != strippedVolumeName {
-				return fmt.Errorf("refusing to mount %s because it claims to be %s but should be %s", cleanDevice, volume, strippedVolumeName)
-			}
-		}
-	} else {
+	if err != nil {
 		// If the command fails (for example, because lsblk is not available), silently ignore the error and proceed
-		klog.V(5).ErrorS(err, "Ignoring lsblk failure", "cleanDevice", cleanDevice, "strippedVolumeName", strippedVolumeName)
+		klog.V(5).ErrorS(err, "Ignoring lsblk failure", "cleanDevice", cleanDevice)
+		return nil, nil
 	}
-
-	return nil
+	// Look for an EBS volume ID in the output, compare all matches against what we expect
+	// (in some rare cases there may be multiple matches due to lsblk printing partitions)
+	// If no volume ID is in the output (non-Nitro instances, SBE devices, etc) silently proceed
+	return regexp.MustCompile(`vol[a-z0-9]+`).FindAllString(string(output), -1), nil
 }
 
 // PreparePublishTarget creates the target directory for the volume to be mounted.
@@ -282,6 +369,27 @@ func (m *NodeMounter) PathExists(path string) (bool, error) {
 	return mountutils.PathExists(path)
 }
 
+// FormatDevice creates a filesystem of type fstype on source with the same arguments
+// FormatAndMountSensitiveWithFormatOptions would use, without mounting it.
+// It must only be called on unformatted devices.
+func (m *NodeMounter) FormatDevice(source string, fstype string, formatOptions []string) error {
+	args := []string{source}
+	switch fstype {
+	case "ext3", "ext4":
+		args = []string{"-F", "-m0", source}
+	case "xfs":
+		args = []string{"-f", source}
+	}
+	args = append(formatOptions, args...)
+
+	klog.InfoS("Formatting device", "source", source, "fstype", fstype, "args", args)
+	output, err := m.Exec.Command("mkfs."+fstype, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("format of disk %q failed: type:(%q) errcode:(%w) output:(%s)", source, fstype, err, string(output))
+	}
+	return nil
+}
+
 // Resize resizes the filesystem of the given devicePath.
 func (m *NodeMounter) Resize(devicePath, deviceMountPath string) (bool, error) {
 	return mountutils.NewResizeFs(m.Exec).Resize(devicePath, deviceMountPath)
@@ -19,9 +19,12 @@ limitations under the License.
 package mounter
 
 import (
+	"context"
 	"errors"
+	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -69,7 +72,7 @@ func TestNeedResize(t *testing.T) {
 				Interface: mount.New(""),
 				Exec:      &fexec,
 			}
-			fakeMounter := NodeMounter{&safe}
+			fakeMounter := NodeMounter{SafeFormatAndMount: &safe}
 
 			needResize, err := fakeMounter.NeedResize(test.devicePath, test.deviceMountPath)
 			if needResize != test.expectResult {
@@ -88,7 +91,7 @@ func TestMakeDir(t *testing.T) {
 
 	targetPath := filepath.Join(dir, "targetdir")
 
-	mountObj, err := NewNodeMounter(false)
+	mountObj, err := NewNodeMounter(t.Context(), false, 0)
 	if err != nil {
 		t.Fatalf("error creating mounter %v", err)
 	}
@@ -112,7 +115,7 @@ func TestMakeFile(t *testing.T) {
 
 	targetPath := filepath.Join(dir, "targetfile")
 
-	mountObj, err := NewNodeMounter(false)
+	mountObj, err := NewNodeMounter(t.Context(), false, 0)
 	if err != nil {
 		t.Fatalf("error creating mounter %v", err)
 	}
@@ -136,7 +139,7 @@ func TestPathExists(t *testing.T) {
 
 	targetPath := filepath.Join(dir, "notafile")
 
-	mountObj, err := NewNodeMounter(false)
+	mountObj, err := NewNodeMounter(t.Context(), false, 0)
 	if err != nil {
 		t.Fatalf("error creating mounter %v", err)
 	}
@@ -158,7 +161,7 @@ func TestGetDeviceName(t *testing.T) {
 
 	targetPath := filepath.Join(dir, "notafile")
 
-	mountObj, err := NewNodeMounter(false)
+	mountObj, err := NewNodeMounter(t.Context(), false, 0)
 	if err != nil {
 		t.Fatalf("error creating mounter %v", err)
 	}
@@ -178,6 +181,8 @@ func TestVerifyVolumeSerialMatch(t *testing.T) {
 		path        string
 		execError   error
 		expectError bool
+		// serials are the outputs of lsblk by device, instead of execOutput
+		serials map[string]string
 	}
 	testCases := []testCase{
 		{
@@ -214,6 +219,27 @@ func TestVerifyVolumeSerialMatch(t *testing.T) {
 			execOutput:  fakeVolumeName + "\n" + fakeIncorrectVolumeName,
 			expectError: true,
 		},
+		{
+			name:    "success: logical volume",
+			path:    "/dev/dm-0",
+			serials: map[string]string{"/dev/nvme1n1": fakeIncorrectVolumeName, "/dev/nvme2n1": fakeVolumeName},
+		},
+		{
+			name:    "success: logical volume on device-mapper device",
+			path:    "/dev/dm-1",
+			serials: map[string]string{"/dev/nvme1n1": fakeIncorrectVolumeName, "/dev/nvme2n1": fakeVolumeName},
+		},
+		{
+			name:       "success: logical volume without serials",
+			path:       "/dev/dm-0",
+			execOutput: "",
+		},
+		{
+			name:        "failure: logical volume of other volumes",
+			path:        "/dev/dm-0",
+			serials:     map[string]string{"/dev/nvme1n1": fakeIncorrectVolumeName, "/dev/nvme2n1": fakeIncorrectVolumeName},
+			expectError: true,
+		},
 		{
 			name:        "failure: bad path (malicious argument)",
 			path:        "--fake-malicious-path=do_evil",
@@ -226,9 +252,19 @@ func TestVerifyVolumeSerialMatch(t *testing.T) {
 		},
 	}
 
+	// dm-0 is a logical volume on a partition of nvme1n1 and on nvme2n1, dm-1 is stacked on dm-0
+	sysBlock := t.TempDir()
+	for _, path := range []string{"nvme1n1/nvme1n1p2/partition", "dm-0/slaves/nvme1n1p2", "dm-0/slaves/nvme2n1", "dm-1/slaves/dm-0"} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(sysBlock, path)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(sysBlock, path), nil, 0o600))
+	}
+
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			mockExecRunner := func(_ string, _ ...string) ([]byte, error) {
+			mockExecRunner := func(_ string, args ...string) ([]byte, error) {
+				if tc.serials != nil {
+					return []byte(tc.serials[args[len(args)-1]]), nil
+				}
 				return []byte(tc.execOutput), tc.execError
 			}
 
@@ -236,7 +272,7 @@ func TestVerifyVolumeSerialMatch(t *testing.T) {
 			if tc.path != "" {
 				path = tc.path
 			}
-			result := verifyVolumeSerialMatch(path, fakeVolumeName, mockExecRunner)
+			result := verifyVolumeSerialMatch(path, fakeVolumeName, sysBlock, mockExecRunner)
 			if tc.expectError {
 				assert.Error(t, result)
 			} else {
@@ -245,3 +281,51 @@ func TestVerifyVolumeSerialMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestFindDevicePathCanceled(t *testing.T) {
+	m := &NodeMounter{deviceWatcher: newDeviceWatcher(), deviceWatcherTimeout: time.Hour}
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	// The wait for the device ends with the request rather than after deviceWatcherTimeout
+	_, err := m.FindDevicePath(ctx, filepath.Join(t.TempDir(), "xvdba"), "vol-11111111111111111", "", "us-west-2")
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestDeviceWatcherHandleUevent(t *testing.T) {
+	sysPath := t.TempDir()
+	writeDevice := func(name, model, serial string) {
+		deviceDir := filepath.Join(sysPath, name, "device")
+		require.NoError(t, os.MkdirAll(deviceDir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(deviceDir, "model"), []byte(model+"\n"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(deviceDir, "serial"), []byte(serial+"\n"), 0644))
+	}
+	writeDevice("nvme0n1", ebsNVMeModel, fakeVolumeName)
+	writeDevice("nvme1n1", "Amazon EC2 NVMe Instance Storage", "AWS1234")
+	writeDevice("nvme2n1", ebsNVMeModel, fakeIncorrectVolumeName)
+
+	w := newDeviceWatcher()
+	w.scan(sysPath)
+
+	devicePath, ok := w.Lookup(fakeVolumeName)
+	assert.True(t, ok)
+	assert.Equal(t, "/dev/nvme0n1", devicePath)
+	assert.Len(t, w.devices, 2)
+
+	msg := "remove@/devices/pci0000:00/nvme/nvme2/nvme2n1\x00ACTION=remove\x00SUBSYSTEM=block\x00DEVNAME=nvme2n1\x00DEVTYPE=disk\x00"
+	w.handleUevent(parseUevent([]byte(msg)), sysPath)
+	_, ok = w.Lookup(fakeIncorrectVolumeName)
+	assert.False(t, ok)
+
+	// Partitions are ignored
+	msg = "add@/devices/pci0000:00/nvme/nvme2/nvme2n1/nvme2n1p1\x00ACTION=add\x00SUBSYSTEM=block\x00DEVNAME=nvme2n1p1\x00DEVTYPE=partition\x00"
+	w.handleUevent(parseUevent([]byte(msg)), sysPath)
+	_, ok = w.Lookup(fakeIncorrectVolumeName)
+	assert.False(t, ok)
+
+	msg = "add@/devices/pci0000:00/nvme/nvme2/nvme2n1\x00ACTION=add\x00SUBSYSTEM=block\x00DEVNAME=nvme2n1\x00DEVTYPE=disk\x00"
+	w.handleUevent(parseUevent([]byte(msg)), sysPath)
+	devicePath, ok = w.Lookup(fakeIncorrectVolumeName)
+	assert.True(t, ok)
+	assert.Equal(t, "/dev/nvme2n1", devicePath)
+}
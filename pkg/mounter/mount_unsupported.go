@@ -19,6 +19,7 @@ limitations under the License.
 package mounter
 
 import (
+	"context"
 	"errors"
 
 	mountutils "k8s.io/mount-utils"
@@ -41,7 +42,11 @@ func NewSafeMounterV2() (*mountutils.SafeFormatAndMount, error) {
 	return nil, errors.New("NewSafeMounterV2 is not supported on this platform")
 }
 
-func (m *NodeMounter) FindDevicePath(devicePath, volumeID, partition, region string) (string, error) {
+func (m *NodeMounter) FindDevicePath(_ context.Context, devicePath, volumeID, partition, region string) (string, error) {
+	return stubMessage, errors.New(stubMessage)
+}
+
+func (m *NodeMounter) FindInstanceStoreDevice(selector string) (string, error) {
 	return stubMessage, errors.New(stubMessage)
 }
 
@@ -81,6 +86,10 @@ func (m *NodeMounter) Resize(devicePath, deviceMountPath string) (bool, error) {
 	return false, errors.New(stubMessage)
 }
 
+func (m *NodeMounter) GrowDevice(devicePath string) error {
+	return errors.New(stubMessage)
+}
+
 func (m *NodeMounter) NeedResize(devicePath string, deviceMountPath string) (bool, error) {
 	return false, errors.New(stubMessage)
 }
@@ -96,3 +105,31 @@ func (m *NodeMounter) Unstage(path string) error {
 func (m *NodeMounter) GetVolumeStats(volumePath string) (VolumeStats, error) {
 	return VolumeStats{}, errors.New(stubMessage)
 }
+
+func (m *NodeMounter) SetIOLimits(target string, limits IOLimits) error {
+	return errors.New(stubMessage)
+}
+
+func (m *NodeMounter) ClearIOLimits(target string) error {
+	return errors.New(stubMessage)
+}
+
+func (m *NodeMounter) FindMountHolders(target string) ([]MountHolder, error) {
+	return nil, errors.New(stubMessage)
+}
+
+func (m *NodeMounter) GetDiskFormat(disk string) (string, error) {
+	return stubMessage, errors.New(stubMessage)
+}
+
+func (m *NodeMounter) FormatDevice(source string, fstype string, formatOptions []string) error {
+	return errors.New(stubMessage)
+}
+
+func (m *NodeMounter) LazyUnmount(target string) error {
+	return errors.New(stubMessage)
+}
+
+func (m *NodeMounter) PrewarmDevice(ctx context.Context, devicePath string, parallelism int, progress func(read, total int64)) error {
+	return errors.New(stubMessage)
+}
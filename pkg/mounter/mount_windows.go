@@ -19,6 +19,7 @@ limitations under the License.
 package mounter
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
@@ -34,7 +35,7 @@ var (
 	ErrUnsupportedMounter = errors.New("unsupported mounter type")
 )
 
-func (m *NodeMounter) FindDevicePath(devicePath, volumeID, _, _ string) (string, error) {
+func (m *NodeMounter) FindDevicePath(_ context.Context, devicePath, volumeID, _, _ string) (string, error) {
 	switch proxyMounter := m.SafeFormatAndMount.Interface.(type) {
 	case *CSIProxyMounterV2:
 		return proxyMounter.FindDevicePath(devicePath, volumeID, "", "")
@@ -320,3 +321,48 @@ func (m *NodeMounter) GetVolumeStats(volumePath string) (VolumeStats, error) {
 
 	return stats, nil
 }
+
+// SetIOLimits is not supported on Windows.
+func (m *NodeMounter) SetIOLimits(_ string, _ IOLimits) error {
+	return errors.New("volume I/O limits are not supported on Windows")
+}
+
+// ClearIOLimits is a no-op on Windows, as limits can never be set.
+func (m *NodeMounter) ClearIOLimits(_ string) error {
+	return nil
+}
+
+// FindInstanceStoreDevice is not supported on Windows.
+func (m *NodeMounter) FindInstanceStoreDevice(_ string) (string, error) {
+	return "", errors.New("instance store volumes are not supported on Windows")
+}
+
+// FindMountHolders is not supported on Windows.
+func (m *NodeMounter) FindMountHolders(_ string) ([]MountHolder, error) {
+	return nil, errors.New("finding processes holding a mount is not supported on Windows")
+}
+
+// GetDiskFormat is not supported on Windows.
+func (m *NodeMounter) GetDiskFormat(_ string) (string, error) {
+	return "", errors.New("GetDiskFormat is not supported on Windows")
+}
+
+// FormatDevice is not supported on Windows.
+func (m *NodeMounter) FormatDevice(_ string, _ string, _ []string) error {
+	return errors.New("formatting without mounting is not supported on Windows")
+}
+
+// GrowDevice is not supported on Windows.
+func (m *NodeMounter) GrowDevice(_ string) error {
+	return errors.New("growing partitions and logical volumes is not supported on Windows")
+}
+
+// LazyUnmount is not supported on Windows.
+func (m *NodeMounter) LazyUnmount(_ string) error {
+	return errors.New("lazy unmount is not supported on Windows")
+}
+
+// PrewarmDevice is not supported on Windows.
+func (m *NodeMounter) PrewarmDevice(_ context.Context, _ string, _ int, _ func(read, total int64)) error {
+	return errors.New("volume pre-warming is not supported on Windows")
+}
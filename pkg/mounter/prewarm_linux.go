@@ -0,0 +1,126 @@
+//go:build linux
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mounter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/sys/unix"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// prewarmChunkSize is the size of each read of a pre-warm.
+	prewarmChunkSize = 1024 * 1024
+
+	// I/O priority of pre-warm reads: the lowest level of the best-effort class, so that
+	// pre-warms yield to the I/O of the workloads without being starved like the idle class.
+	ioprioWhoProcess   = 1
+	ioprioClassShift   = 13
+	ioprioClassBE      = 2
+	prewarmIOPrioLevel = 7
+)
+
+// PrewarmDevice reads every block of devicePath with parallelism concurrent readers, so that
+// the blocks of a volume restored from a snapshot are fetched from S3 before the workload reads
+// them. Reads bypass the page cache and use a low I/O priority. progress is called after each
+// read with the number of bytes read so far and the size of the device, possibly concurrently.
+// PrewarmDevice stops early and returns the error of ctx when ctx is cancelled.
+func (m *NodeMounter) PrewarmDevice(ctx context.Context, devicePath string, parallelism int, progress func(read, total int64)) error {
+	return prewarmDevice(ctx, devicePath, parallelism, progress)
+}
+
+func prewarmDevice(ctx context.Context, devicePath string, parallelism int, progress func(read, total int64)) error {
+	f, err := os.Open(devicePath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", devicePath, err)
+	}
+	size, err := f.Seek(0, io.SeekEnd)
+	f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to get size of %s: %w", devicePath, err)
+	}
+
+	var next, read atomic.Int64
+	errs := make([]error, parallelism)
+	var wg sync.WaitGroup
+	for i := range parallelism {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = prewarmWorker(ctx, devicePath, size, &next, func(n int64) {
+				progress(read.Add(n), size)
+			})
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+// prewarmWorker reads the chunks of devicePath whose index it takes from next until the end of the device.
+func prewarmWorker(ctx context.Context, devicePath string, size int64, next *atomic.Int64, done func(n int64)) error {
+	// The I/O priority is set on the thread, which exits with the goroutine as it is never unlocked
+	runtime.LockOSThread()
+	prio := ioprioClassBE<<ioprioClassShift | prewarmIOPrioLevel
+	if _, _, errno := unix.Syscall(unix.SYS_IOPRIO_SET, ioprioWhoProcess, 0, uintptr(prio)); errno != 0 {
+		klog.V(4).InfoS("Could not lower the I/O priority of pre-warm reads", "err", errno)
+	}
+
+	fd, err := unix.Open(devicePath, unix.O_RDONLY|unix.O_DIRECT|unix.O_CLOEXEC, 0)
+	if errors.Is(err, unix.EINVAL) {
+		// Not all filesystems support O_DIRECT, this only happens with regular files
+		fd, err = unix.Open(devicePath, unix.O_RDONLY|unix.O_CLOEXEC, 0)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", devicePath, err)
+	}
+	defer unix.Close(fd)
+
+	// O_DIRECT requires a buffer aligned to the logical block size, mmap returns page-aligned memory
+	buf, err := unix.Mmap(-1, 0, prewarmChunkSize, unix.PROT_READ|unix.PROT_WRITE, unix.MAP_ANON|unix.MAP_PRIVATE)
+	if err != nil {
+		return fmt.Errorf("failed to allocate read buffer: %w", err)
+	}
+	defer func() { _ = unix.Munmap(buf) }()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		offset := (next.Add(1) - 1) * prewarmChunkSize
+		if offset >= size {
+			return nil
+		}
+		n, err := unix.Pread(fd, buf, offset)
+		if err != nil {
+			return fmt.Errorf("failed to read %s at offset %d: %w", devicePath, offset, err)
+		}
+		done(int64(n))
+	}
+}
@@ -0,0 +1,67 @@
+//go:build linux
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mounter
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrewarmDevice(t *testing.T) {
+	// A size that is not a multiple of the chunk size, so that the last read is short
+	const size = 3*prewarmChunkSize + 4096
+	device := filepath.Join(t.TempDir(), "device")
+	require.NoError(t, os.WriteFile(device, make([]byte, size), 0o600))
+
+	var mu sync.Mutex
+	var maxRead, calls int64
+	err := prewarmDevice(t.Context(), device, 3, func(read, total int64) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, int64(size), total)
+		maxRead = max(maxRead, read)
+		calls++
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(size), maxRead)
+	assert.Equal(t, int64(4), calls)
+}
+
+func TestPrewarmDeviceCancelled(t *testing.T) {
+	device := filepath.Join(t.TempDir(), "device")
+	require.NoError(t, os.WriteFile(device, make([]byte, 2*prewarmChunkSize), 0o600))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	err := prewarmDevice(ctx, device, 2, func(_, _ int64) {
+		t.Error("unexpected read of a cancelled pre-warm")
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestPrewarmDeviceNotFound(t *testing.T) {
+	err := prewarmDevice(t.Context(), filepath.Join(t.TempDir(), "missing"), 2, func(_, _ int64) {})
+	require.Error(t, err)
+}
@@ -15,13 +15,17 @@
 package template
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"html/template"
+	"regexp"
 	"strings"
+	"text/template"
 )
 
-// Disable functions.
+// Disable functions. Templates are evaluated with text/template, their values are tag values
+// rather than HTML and must not be escaped.
 func html(...any) (string, error) {
 	return "", errors.New("cannot call 'html' function")
 }
@@ -70,18 +74,65 @@ func lastIndex(arg1, arg2 string) int {
 	return strings.LastIndex(arg2, arg1)
 }
 
+func replace(old, replacement, arg string) string {
+	return strings.ReplaceAll(arg, old, replacement)
+}
+
+func regexMatch(pattern, arg string) (bool, error) {
+	return regexp.MatchString(pattern, arg)
+}
+
+// defaultValue returns value if arg is empty.
+func defaultValue(value, arg string) string {
+	if arg == "" {
+		return value
+	}
+	return arg
+}
+
+// truncate returns the first length characters of arg, such as 256 for the maximum length of a tag value.
+func truncate(length int, arg string) string {
+	if length < 0 {
+		return arg
+	}
+	runes := []rune(arg)
+	if len(runes) <= length {
+		return arg
+	}
+	return string(runes[:length])
+}
+
+// hash returns the hex encoded SHA-256 hash of arg.
+func hash(arg string) string {
+	sum := sha256.Sum256([]byte(arg))
+	return hex.EncodeToString(sum[:])
+}
+
+// get returns the value of key in m, for the keys that cannot be accessed as fields like app.kubernetes.io/name.
+func get(key string, m map[string]string) string {
+	return m[key]
+}
+
 func newFuncMap() template.FuncMap {
 	return template.FuncMap{
-		"html":      html,
-		"js":        js,
-		"call":      call,
-		"urlquery":  urlquery,
-		"contains":  contains,
-		"toUpper":   strings.ToUpper,
-		"toLower":   strings.ToLower,
-		"substring": substring,
-		"field":     field,
-		"index":     index,
-		"lastIndex": lastIndex,
+		"html":       html,
+		"js":         js,
+		"call":       call,
+		"urlquery":   urlquery,
+		"contains":   contains,
+		"toUpper":    strings.ToUpper,
+		"toLower":    strings.ToLower,
+		"substring":  substring,
+		"field":      field,
+		"index":      index,
+		"lastIndex":  lastIndex,
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"replace":    replace,
+		"regexMatch": regexMatch,
+		"default":    defaultValue,
+		"truncate":   truncate,
+		"hash":       hash,
+		"get":        get,
 	}
 }
@@ -26,6 +26,13 @@ type PVProps struct {
 	PVCName      string
 	PVCNamespace string
 	PVName       string
+	// PVCLabels, PVCAnnotations, StorageClassName and NamespaceLabels are only populated for the
+	// templates referencing them, from the PVC and its namespace.
+	PVCLabels        map[string]string
+	PVCAnnotations   map[string]string
+	StorageClassName string
+	NamespaceLabels  map[string]string
+	ClusterID        string
 }
 
 type VolumeSnapshotProps struct {
@@ -44,7 +51,8 @@ func Evaluate(tm []string, props any, warnOnly bool) (map[string]string, error)
 
 		key, value := st[0], st[1]
 
-		t := template.New("tmpl").Funcs(newFuncMap())
+		// The missing keys of labels and annotations are empty strings
+		t := template.New("tmpl").Funcs(newFuncMap()).Option("missingkey=zero")
 		val, err := execTemplate(value, props, t)
 		if err != nil {
 			if warnOnly {
@@ -15,6 +15,7 @@
 package template
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -226,6 +227,111 @@ func TestEvaluate(t *testing.T) {
 	}
 }
 
+func TestEvaluateKubernetesMetadata(t *testing.T) {
+	props := &PVProps{
+		PVCName:          "ebs-claim",
+		PVCNamespace:     "team-a-prod",
+		PVCLabels:        map[string]string{"team": "Storage", "app.kubernetes.io/name": "db", "department": "R&D <a>"},
+		PVCAnnotations:   map[string]string{"description": strings.Repeat("é", 300)},
+		StorageClassName: "gp3",
+		NamespaceLabels:  map[string]string{"cost-center": "42"},
+		ClusterID:        "prod-cluster",
+	}
+
+	testCases := []struct {
+		name         string
+		input        []string
+		expectErr    bool
+		expectedTags map[string]string
+	}{
+		{
+			name: "kubernetes metadata",
+			input: []string{
+				`team={{ .PVCLabels.team }}`,
+				`app={{ .PVCLabels | get "app.kubernetes.io/name" }}`,
+				`storage-class={{ .StorageClassName }}`,
+				`cost-center={{ .NamespaceLabels | get "cost-center" }}`,
+				`cluster={{ .ClusterID }}`,
+			},
+			expectedTags: map[string]string{
+				"team":          "Storage",
+				"app":           "db",
+				"storage-class": "gp3",
+				"cost-center":   "42",
+				"cluster":       "prod-cluster",
+			},
+		},
+		{
+			name: "missing label",
+			input: []string{
+				`owner={{ .PVCLabels.owner }}`,
+				`owner-default={{ .PVCLabels.owner | default "unknown" }}`,
+				`team-default={{ .PVCLabels.team | default "unknown" }}`,
+			},
+			expectedTags: map[string]string{
+				"owner":         "",
+				"owner-default": "unknown",
+				"team-default":  "Storage",
+			},
+		},
+		{
+			name: "string functions",
+			input: []string{
+				`lower={{ .PVCLabels.team | lower }}`,
+				`upper={{ .PVCLabels.team | upper }}`,
+				`replace={{ .PVCNamespace | replace "-" "_" }}`,
+				`prod={{ .PVCNamespace | regexMatch "-prod$" }}`,
+				`truncated={{ .PVCAnnotations.description | truncate 256 }}`,
+				`hash={{ .PVCName | hash | substring 0 8 }}`,
+			},
+			expectedTags: map[string]string{
+				"lower":     "storage",
+				"upper":     "STORAGE",
+				"replace":   "team_a_prod",
+				"prod":      "true",
+				"truncated": strings.Repeat("é", 256),
+				"hash":      "c213fd29",
+			},
+		},
+		{
+			name: "special characters are not escaped",
+			input: []string{
+				`department={{ .PVCLabels.department }}`,
+				`truncated={{ .PVCLabels.department | truncate 5 }}`,
+			},
+			expectedTags: map[string]string{
+				"department": "R&D <a>",
+				"truncated":  "R&D <",
+			},
+		},
+		{
+			name: "invalid regex",
+			input: []string{
+				`prod={{ .PVCNamespace | regexMatch "(" }}`,
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tags, err := Evaluate(tc.input, props, false)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error; got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err is not nil; err = %v", err)
+			}
+			if diff := cmp.Diff(tc.expectedTags, tags); diff != "" {
+				t.Fatalf("tags are different; diff = %v", diff)
+			}
+		})
+	}
+}
+
 func TestEvaluateVolumeSnapshotTemplate(t *testing.T) {
 	testCases := []struct {
 		name                      string
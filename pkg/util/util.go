@@ -227,3 +227,17 @@ func WaitUntilTimeOrContext(ctx context.Context, wakeup time.Time) {
 func IsHyperPodNode(nodeID string) bool {
 	return strings.HasPrefix(nodeID, "hyperpod-")
 }
+
+// requestIDKey is the context key of the ID of the CSI request being handled.
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the ID of the CSI request being handled.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the ID of the CSI request ctx was created for, or an empty string.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
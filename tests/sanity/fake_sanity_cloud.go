@@ -92,6 +92,10 @@ func (d *fakeCloud) GetDiskByID(ctx context.Context, volumeID string) (*cloud.Di
 	return disk, nil
 }
 
+func (d *fakeCloud) ListDisks(ctx context.Context, tags map[string]string) ([]*cloud.Disk, error) {
+	return nil, nil
+}
+
 func (d *fakeCloud) CreateSnapshot(ctx context.Context, volumeID string, opts *cloud.SnapshotOptions) (*cloud.Snapshot, error) {
 	snapshotID := fmt.Sprintf("snap-%d", rand.New(rand.NewSource(time.Now().UnixNano())).Uint64())
 
@@ -142,6 +146,14 @@ func (d *fakeCloud) GetInstancesPatching(ctx context.Context, nodeIDs []string)
 	return []*types.Instance{}, nil
 }
 
+func (d *fakeCloud) GetInstanceTypesPatching(ctx context.Context, instanceTypes []string) ([]types.InstanceTypeInfo, error) {
+	return []types.InstanceTypeInfo{}, nil
+}
+
+func (d *fakeCloud) GetAttachedVolumesPatching(ctx context.Context, nodeIDs []string) ([]*types.Volume, error) {
+	return []*types.Volume{}, nil
+}
+
 func (d *fakeCloud) ListSnapshots(ctx context.Context, sourceVolumeID string, maxResults int32, nextToken string) (*cloud.ListSnapshotsResponse, error) {
 	var s []*cloud.Snapshot
 	startIndex := 0
@@ -222,6 +234,10 @@ func (d *fakeCloud) ModifyTags(ctx context.Context, volumeID string, tagOptions
 	return nil
 }
 
+func (d *fakeCloud) ModifyResourceTags(ctx context.Context, resourceIDs []string, tagOptions cloud.ModifyTagsOptions) error {
+	return nil
+}
+
 func (d *fakeCloud) WaitForAttachmentState(ctx context.Context, expectedState types.VolumeAttachmentState, volumeID string, expectedInstance string, expectedDevice string, alreadyAssigned bool, expectedCardIndex *int32) (*types.VolumeAttachment, error) {
 	return &types.VolumeAttachment{}, nil
 }
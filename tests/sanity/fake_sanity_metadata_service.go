@@ -65,3 +65,7 @@ func (m *fakeMetadataService) GetNumBlockDeviceMappings() int {
 func (m *fakeMetadataService) GetOutpostArn() arn.ARN {
 	return m.outpostArn
 }
+
+func (m *fakeMetadataService) IsCached() bool {
+	return false
+}
@@ -15,6 +15,7 @@
 package sanity
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -33,7 +34,7 @@ func newFakeMounter() *fakeMounter {
 	}
 }
 
-func (m *fakeMounter) FindDevicePath(devicePath, volumeID, partition, region string) (string, error) {
+func (m *fakeMounter) FindDevicePath(_ context.Context, devicePath, volumeID, partition, region string) (string, error) {
 	if len(devicePath) == 0 {
 		return devicePath, cloud.ErrNotFound
 	}
@@ -89,6 +90,10 @@ func (m *fakeMounter) Resize(devicePath, deviceMountPath string) (bool, error) {
 	return false, nil
 }
 
+func (m *fakeMounter) GrowDevice(devicePath string) error {
+	return nil
+}
+
 func (m *fakeMounter) NeedResize(devicePath string, deviceMountPath string) (bool, error) {
 	return false, nil
 }
@@ -115,6 +120,14 @@ func (m *fakeMounter) FormatAndMountSensitiveWithFormatOptions(source, target, f
 	return nil
 }
 
+func (m *fakeMounter) GetDiskFormat(disk string) (string, error) {
+	return "", nil
+}
+
+func (m *fakeMounter) FormatDevice(source, fstype string, formatOptions []string) error {
+	return nil
+}
+
 func (m *fakeMounter) GetMountRefs(pathname string) ([]string, error) {
 	return nil, nil
 }
@@ -150,3 +163,27 @@ func (m *fakeMounter) Unmount(target string) error {
 func (m *fakeMounter) GetVolumeStats(volumePath string) (mounter.VolumeStats, error) {
 	return mounter.VolumeStats{}, nil
 }
+
+func (m *fakeMounter) SetIOLimits(target string, limits mounter.IOLimits) error {
+	return nil
+}
+
+func (m *fakeMounter) ClearIOLimits(target string) error {
+	return nil
+}
+
+func (m *fakeMounter) FindInstanceStoreDevice(selector string) (string, error) {
+	return "/dev/md/" + selector, nil
+}
+
+func (m *fakeMounter) FindMountHolders(target string) ([]mounter.MountHolder, error) {
+	return nil, nil
+}
+
+func (m *fakeMounter) LazyUnmount(target string) error {
+	return nil
+}
+
+func (m *fakeMounter) PrewarmDevice(ctx context.Context, devicePath string, parallelism int, progress func(read, total int64)) error {
+	return nil
+}